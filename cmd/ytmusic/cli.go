@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/config"
+)
+
+// command is a subcommand that runs without the TUI
+type command struct {
+	args  string // Positional arguments and the command's own flags, for the usage line
+	desc  string
+	flags func(flags *flag.FlagSet) // Registers the command's own flags, if it has any
+	run   func(ctx context.Context, env *commandEnv, args []string) error
+}
+
+// commandEnv is what commands run with
+type commandEnv struct {
+	api *api.YouTubeMusicAPI
+	cfg *config.Config
+	out output
+}
+
+// commands are the subcommands, by name
+var commands = map[string]command{
+	"status": {
+		args:  "[-format line] [-follow] [-waybar]",
+		desc:  "Show what a running daemon is playing, for status bars",
+		flags: statusFlags,
+		run:   runStatus,
+	},
+	"search": {
+		args: "<query>",
+		desc: "Search for songs",
+		run:  runSearch,
+	},
+	"export": {
+		args:  "<playlist id> [-format m3u|csv|json] [-o file]",
+		desc:  "Write a playlist's tracks to an M3U, CSV or JSON file",
+		flags: exportFlags,
+		run:   runExport,
+	},
+	"download": {
+		args:  "<video id|url|search terms> | -playlist <id> | -album <id> [-format opus|m4a|mp3] [-dir directory]",
+		desc:  "Save tracks for offline playback with yt-dlp, converted and tagged",
+		flags: downloadFlags,
+		run:   runDownload,
+	},
+	"import": {
+		args:  "<file> [-title name] [-yes]",
+		desc:  "Create a playlist from an M3U, CSV or JSON file, such as a Spotify export, finding each track on YouTube Music",
+		flags: importFlags,
+		run:   runImport,
+	},
+	"login": {
+		args:  "-oauth | -cookie <cookie|-> | -from-browser <browser>",
+		desc:  "Log in without the interface, which picks the login up even while it runs",
+		flags: loginFlags,
+		run:   runLogin,
+	},
+	"logout": {
+		desc: "Log out, removing the saved cookies and ytmusicapi credentials",
+		run:  runLogout,
+	},
+	"playlists": {
+		desc: "List your library's playlists",
+		run:  runPlaylists,
+	},
+	"play": {
+		args: "<video id|url|search terms>",
+		desc: "Play a track, showing its progress until it ends",
+		run:  runPlay,
+	},
+	"remote": {
+		args: "<action> [track]",
+		desc: "Control a running daemon: toggle, play [track], pause, next, prev, stop, status, or queue add <track>",
+		run:  runRemote,
+	},
+	"playlist-tracks": {
+		args: "<playlist id>",
+		desc: "List the tracks of a playlist",
+		run:  runPlaylistTracks,
+	},
+}
+
+// errUsage means a command was given the wrong arguments
+var errUsage = errors.New("wrong arguments")
+
+// commandNames returns the subcommands in alphabetical order
+func commandNames() []string {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// commandUsage returns the usage line of a subcommand
+func commandUsage(name string) string {
+	return strings.Join(strings.Fields(name+" "+commands[name].args+" [-json]"), " ")
+}
+
+// commandHelp describes the subcommands for -help
+func commandHelp() string {
+	var lines []string
+	for _, name := range commandNames() {
+		lines = append(lines, "  "+commandUsage(name), "      "+commands[name].desc)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// newAPI creates an API client configured like the TUI's
+func newAPI(cfg *config.Config) *api.YouTubeMusicAPI {
+	ytApi := api.NewYouTubeMusicAPI(debugMode)
+	ytApi.SetStreamPreferences(api.StreamQuality(cfg.Audio.Quality), api.StreamCodec(cfg.Audio.Codec))
+	ytApi.SetMaxBitrate(cfg.Audio.MaxBitrate())
+	ytApi.SetMaxRate(cfg.Audio.MaxRate)
+	ytApi.SetLocale(cfg.Locale.Language, cfg.Locale.Region)
+	ytApi.SetCacheSize(cfg.Cache.SizeMB)
+	ytApi.SetBackends(cfg.Backends)
+	return ytApi
+}
+
+// runCommand runs a subcommand and returns the exit code
+func runCommand(cfg *config.Config, args []string) int {
+	name := args[0]
+	cmd, ok := commands[name]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Unknown command %q, use one of: %s\n", name, strings.Join(commandNames(), ", "))
+		return 2
+	}
+
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	jsonOutput := flags.Bool("json", false, "Print JSON instead of text")
+	if cmd.flags != nil {
+		cmd.flags(flags)
+	}
+	positional, err := parseInterleaved(flags, args[1:])
+	if err != nil {
+		return 2
+	}
+
+	// Ctrl+C cancels the request in flight
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ytApi := newAPI(cfg)
+	defer ytApi.Close()
+
+	env := &commandEnv{api: ytApi, cfg: cfg, out: output{w: os.Stdout, json: *jsonOutput}}
+	err = cmd.run(ctx, env, positional)
+	switch {
+	case err == nil:
+		return 0
+	case errors.Is(err, errUsage):
+		fmt.Fprintf(os.Stderr, "Usage: ytmusic %s\n", commandUsage(name))
+		return 2
+	case errors.Is(err, api.ErrNotLoggedIn), errors.Is(err, api.ErrSessionExpired):
+		fmt.Fprintf(os.Stderr, "ytmusic %s: %v - run ytmusic login to log in\n", name, err)
+	default:
+		fmt.Fprintf(os.Stderr, "ytmusic %s: %v\n", name, err)
+	}
+	return 1
+}
+
+// parseInterleaved parses flags that come before, after or between positional
+// arguments, which the flag package alone stops at, and returns the positional ones
+func parseInterleaved(flags *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := flags.Parse(args); err != nil {
+			return nil, err
+		}
+		args = flags.Args()
+		if len(args) == 0 {
+			return positional, nil
+		}
+		positional = append(positional, args[0])
+		args = args[1:]
+	}
+}
+
+func runSearch(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+	tracks, err := env.api.Search(ctx, strings.Join(args, " "))
+	if err != nil {
+		return err
+	}
+	return env.out.tracks(tracks)
+}
+
+func runPlaylists(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	playlists, err := env.api.GetUserPlaylists(ctx)
+	if err != nil {
+		return err
+	}
+	return env.out.playlists(playlists)
+}
+
+func runPlaylistTracks(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	tracks, err := env.api.GetPlaylistTracks(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	return env.out.tracks(tracks)
+}
+
+// output prints results as tab-separated text or as JSON
+type output struct {
+	w    io.Writer
+	json bool
+}
+
+// trackJSON is a track as printed by -json
+type trackJSON struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Artist    string `json:"artist"`
+	ArtistID  string `json:"artist_id,omitempty"`
+	Album     string `json:"album,omitempty"`
+	AlbumID   string `json:"album_id,omitempty"`
+	Year      string `json:"year,omitempty"`
+	Duration  int    `json:"duration"`
+	Explicit  bool   `json:"explicit,omitempty"`
+	Thumbnail string `json:"thumbnail,omitempty"`
+	URL       string `json:"url"`
+}
+
+// playlistJSON is a playlist as printed by -json
+type playlistJSON struct {
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Author      string `json:"author,omitempty"`
+	TrackCount  int    `json:"track_count"`
+}
+
+// newTrackJSON converts a track for -json
+func newTrackJSON(t api.Track) trackJSON {
+	return trackJSON{
+		ID:        t.ID,
+		Title:     t.TrackTitle,
+		Artist:    t.Artist,
+		ArtistID:  t.ArtistID,
+		Album:     t.Album,
+		AlbumID:   t.AlbumID,
+		Year:      t.Year,
+		Duration:  t.Duration,
+		Explicit:  t.Explicit,
+		Thumbnail: t.ThumbnailURL,
+		URL:       t.URL(),
+	}
+}
+
+func (o output) tracks(tracks []api.Track) error {
+	if o.json {
+		list := make([]trackJSON, 0, len(tracks))
+		for _, t := range tracks {
+			list = append(list, newTrackJSON(t))
+		}
+		return o.encode(list)
+	}
+
+	for _, t := range tracks {
+		fmt.Fprintf(o.w, "%s\t%s\t%s\t%s\t%s\n", t.ID, t.TrackTitle, t.Artist, t.Album, formatDuration(t.Duration))
+	}
+	return nil
+}
+
+func (o output) playlists(playlists []api.Playlist) error {
+	if o.json {
+		list := make([]playlistJSON, 0, len(playlists))
+		for _, p := range playlists {
+			list = append(list, playlistJSON{
+				ID:          p.ID,
+				Title:       p.PlaylistTitle,
+				Description: p.PlaylistDesc,
+				Author:      p.Author,
+				TrackCount:  p.TrackCount,
+			})
+		}
+		return o.encode(list)
+	}
+
+	for _, p := range playlists {
+		fmt.Fprintf(o.w, "%s\t%s\t%d tracks\n", p.ID, p.PlaylistTitle, p.TrackCount)
+	}
+	return nil
+}
+
+func (o output) encode(v interface{}) error {
+	encoder := json.NewEncoder(o.w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// formatDuration formats seconds as m:ss, or "" when unknown
+func formatDuration(seconds int) string {
+	if seconds <= 0 {
+		return ""
+	}
+	return formatClock(seconds)
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"ytmusic/internal/config"
+	"ytmusic/internal/daemon"
+	"ytmusic/internal/download"
+	"ytmusic/internal/player"
+)
+
+// runDaemon plays music without the TUI, taking commands on the control socket
+// until interrupted, and returns the exit code
+func runDaemon(cfg *config.Config) int {
+	ytApi := newAPI(cfg)
+	defer ytApi.Close()
+
+	musicPlayer := player.NewPlayer(debugMode)
+	musicPlayer.MPVPath = cfg.MPV.Path
+	musicPlayer.MPVArgs = cfg.MPV.Args
+	musicPlayer.YtdlFormat = cfg.MPV.YtdlFormat
+	musicPlayer.MaxRate = cfg.Audio.MaxRate
+	musicPlayer.Durations = ytApi
+	musicPlayer.Queue.SmartShuffle = cfg.Shuffle == "smart"
+	musicPlayer.Queue.SkipDuplicates = cfg.SkipDuplicates
+	downloads, err := download.LoadIndex()
+	if err != nil {
+		musicPlayer.LogDebug("Error loading downloads: %v", err)
+	}
+	musicPlayer.Downloads = downloads
+	if err := musicPlayer.RestoreState(); err != nil {
+		musicPlayer.LogDebug("Error restoring queue: %v", err)
+	}
+
+	listener, err := daemon.Listen()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ytmusic daemon: %v\n", err)
+		return 1
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := daemon.NewServer(ytApi, musicPlayer)
+	defer server.Close()
+
+	fmt.Fprintf(os.Stderr, "ytmusic daemon listening on %s\n", daemon.Address())
+	if cfg.HTTP.Listen != "" {
+		// Other machines can reach the API, so it takes a token
+		token := ""
+		if daemon.NeedsToken(cfg.HTTP.Listen) {
+			if token, err = daemon.Token(); err != nil {
+				fmt.Fprintf(os.Stderr, "ytmusic daemon: REST API: %v\n", err)
+				return 1
+			}
+		}
+		go func() {
+			if err := server.ServeREST(ctx, cfg.HTTP.Listen, token); err != nil {
+				fmt.Fprintf(os.Stderr, "ytmusic daemon: REST API: %v\n", err)
+			}
+		}()
+		if token != "" {
+			fmt.Fprintf(os.Stderr, "REST API on http://%s/#token=%s\n", cfg.HTTP.Listen, token)
+		} else {
+			fmt.Fprintf(os.Stderr, "REST API on http://%s/\n", cfg.HTTP.Listen)
+		}
+	}
+	if err := server.Serve(ctx, listener); err != nil {
+		fmt.Fprintf(os.Stderr, "ytmusic daemon: %v\n", err)
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"expvar"
+	"log"
+	"net"
+	"net/http"
+	_ "net/http/pprof" // Registers /debug/pprof on the default mux
+	"runtime"
+	"time"
+)
+
+// debugAddress is where a -debug run serves pprof and its metrics, when the port is free
+const debugAddress = "127.0.0.1:6060"
+
+var started = time.Now()
+
+func init() {
+	expvar.Publish("goroutines", expvar.Func(func() interface{} {
+		return runtime.NumGoroutine()
+	}))
+	expvar.Publish("uptime_seconds", expvar.Func(func() interface{} {
+		return int64(time.Since(started).Seconds())
+	}))
+}
+
+// serveDebug serves net/http/pprof at /debug/pprof and the runtime and request
+// metrics at /debug/vars on localhost, for profiling a slow or leaking run
+func serveDebug() {
+	listener, err := net.Listen("tcp", debugAddress)
+	if err != nil {
+		// Another ytmusic has the port; any free one will do
+		listener, err = net.Listen("tcp", "127.0.0.1:0")
+	}
+	if err != nil {
+		log.Printf("Could not serve pprof: %v", err)
+		return
+	}
+	log.Printf("Serving pprof at http://%s/debug/pprof/ and metrics at http://%s/debug/vars", listener.Addr(), listener.Addr())
+
+	server := &http.Server{Handler: http.DefaultServeMux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil {
+			log.Printf("pprof server stopped: %v", err)
+		}
+	}()
+}
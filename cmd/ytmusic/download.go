@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/download"
+)
+
+// downloadOptions are the download command's flags
+var downloadOptions struct {
+	playlist string
+	album    string
+	format   string
+	dir      string
+}
+
+func downloadFlags(flags *flag.FlagSet) {
+	flags.StringVar(&downloadOptions.playlist, "playlist", "", "Download every track of this playlist ID")
+	flags.StringVar(&downloadOptions.album, "album", "", "Download every track of this album browse ID (MPREb_...)")
+	flags.StringVar(&downloadOptions.format, "format", "opus", "Audio format: "+strings.Join(download.Formats, ", "))
+	flags.StringVar(&downloadOptions.dir, "dir", "", "Directory to save the files in (default "+download.Dir()+")")
+}
+
+// downloadJSON is the outcome for a track, as printed by -json
+type downloadJSON struct {
+	ID      string `json:"id"`
+	Title   string `json:"title"`
+	Artist  string `json:"artist"`
+	Path    string `json:"path,omitempty"`
+	Skipped bool   `json:"skipped,omitempty"` // Already downloaded
+	Error   string `json:"error,omitempty"`
+}
+
+func runDownload(ctx context.Context, env *commandEnv, args []string) error {
+	given := 0
+	for _, set := range []bool{downloadOptions.playlist != "", downloadOptions.album != "", len(args) > 0} {
+		if set {
+			given++
+		}
+	}
+	if given != 1 {
+		return errUsage
+	}
+	if !download.ValidFormat(downloadOptions.format) {
+		fmt.Fprintf(os.Stderr, "Unknown format %q, use one of: %s\n", downloadOptions.format, strings.Join(download.Formats, ", "))
+		return errUsage
+	}
+	if err := download.CheckTools(); err != nil {
+		return err
+	}
+
+	dir := download.Dir()
+	if downloadOptions.dir != "" {
+		var err error
+		if dir, err = filepath.Abs(downloadOptions.dir); err != nil {
+			return fmt.Errorf("invalid -dir %q: %v", downloadOptions.dir, err)
+		}
+	}
+
+	// A download index that can't be read would be overwritten without the tracks in it
+	downloads, err := download.LoadIndex()
+	if err != nil {
+		return fmt.Errorf("%v - fix or remove it before downloading", err)
+	}
+
+	tracks, err := downloadTracks(ctx, env.api, args)
+	if err != nil {
+		return err
+	}
+
+	var results []downloadJSON
+	failed := 0
+	for i, track := range tracks {
+		result := downloadJSON{ID: track.ID, Title: track.TrackTitle, Artist: track.Artist}
+		if !env.out.json {
+			fmt.Fprintf(env.out.w, "[%d/%d] %s - %s", i+1, len(tracks), track.Artist, track.TrackTitle)
+		}
+
+		if path := downloads.Path(track.ID); path != "" {
+			result.Path, result.Skipped = path, true
+		} else if path, err := download.Fetch(ctx, track, downloadOptions.format, dir, env.cfg.Audio.MaxRate); err != nil {
+			if ctx.Err() != nil {
+				if !env.out.json {
+					fmt.Fprintln(env.out.w)
+				}
+				return ctx.Err()
+			}
+			result.Error = err.Error()
+			failed++
+		} else {
+			result.Path = path
+			downloads.Add(download.Entry{Track: track, Path: path, Format: downloadOptions.format, Added: time.Now()})
+			// Saved after each track, so an interrupted batch keeps what it finished
+			if err := downloads.Save(); err != nil {
+				return err
+			}
+		}
+
+		results = append(results, result)
+		if env.out.json {
+			continue
+		}
+		switch {
+		case result.Skipped:
+			fmt.Fprintf(env.out.w, ": already downloaded to %s\n", result.Path)
+		case result.Error != "":
+			fmt.Fprintf(env.out.w, ": %s\n", result.Error)
+		default:
+			fmt.Fprintf(env.out.w, ": %s\n", result.Path)
+		}
+	}
+
+	if env.out.json {
+		if err := env.out.encode(results); err != nil {
+			return err
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d tracks failed to download", failed, len(tracks))
+	}
+	return nil
+}
+
+// downloadTracks looks up the tracks the download command was given: a
+// playlist's, an album's, or a single one by video ID, link or search terms
+func downloadTracks(ctx context.Context, ytApi *api.YouTubeMusicAPI, args []string) ([]api.Track, error) {
+	switch {
+	case downloadOptions.playlist != "":
+		return ytApi.GetPlaylistTracks(ctx, downloadOptions.playlist)
+
+	case downloadOptions.album != "":
+		album, err := ytApi.GetAlbum(ctx, downloadOptions.album)
+		if err != nil {
+			return nil, err
+		}
+		// Album track lists leave out what the album page already says
+		tracks := make([]api.Track, len(album.Tracks))
+		for i, track := range album.Tracks {
+			if track.Album == "" {
+				track.Album, track.AlbumID = album.AlbumTitle, album.ID
+			}
+			if track.Year == "" {
+				track.Year = album.Year
+			}
+			if track.Artist == "" {
+				track.Artist = album.Artist
+			}
+			tracks[i] = track
+		}
+		return tracks, nil
+	}
+
+	track, err := ytApi.ResolveTrack(ctx, strings.Join(args, " "))
+	if err != nil {
+		return nil, err
+	}
+	return []api.Track{track}, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/playlistfile"
+)
+
+// exportOptions are the export command's flags
+var exportOptions struct {
+	format string
+	output string
+}
+
+func exportFlags(flags *flag.FlagSet) {
+	flags.StringVar(&exportOptions.format, "format", "", "File format: "+strings.Join(playlistfile.Formats, ", ")+" (default from -o's extension, or m3u)")
+	flags.StringVar(&exportOptions.output, "o", "", "File to write instead of standard output")
+}
+
+func runExport(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+
+	format := exportOptions.format
+	if format == "" {
+		format = playlistfile.FormatOf(exportOptions.output)
+	}
+	if format == "" && env.out.json {
+		format = playlistfile.FormatJSON
+	}
+	if format == "" {
+		format = playlistfile.FormatM3U
+	}
+	if !playlistfile.ValidFormat(format) {
+		fmt.Fprintf(os.Stderr, "Unknown format %q, use one of: %s\n", format, strings.Join(playlistfile.Formats, ", "))
+		return errUsage
+	}
+
+	tracks, err := env.api.GetPlaylistTracks(ctx, args[0])
+	if err != nil {
+		return err
+	}
+	playlist := findPlaylist(ctx, env.api, args[0])
+
+	if exportOptions.output == "" {
+		return playlistfile.Write(env.out.w, format, playlist, tracks)
+	}
+	if err := playlistfile.WriteFile(exportOptions.output, format, playlist, tracks); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Exported %d tracks to %s\n", len(tracks), exportOptions.output)
+	return nil
+}
+
+// findPlaylist looks a playlist up in the library for its title. Playlists
+// outside the library, or a failed lookup, leave only the ID known.
+func findPlaylist(ctx context.Context, ytApi *api.YouTubeMusicAPI, id string) api.Playlist {
+	playlists, err := ytApi.GetUserPlaylists(ctx)
+	if err != nil {
+		ytApi.LogDebug("Could not look up the title of playlist %s: %v", id, err)
+	}
+	for _, p := range playlists {
+		if p.ID == id || "VL"+p.ID == id || p.ID == "VL"+id {
+			return p
+		}
+	}
+	return api.Playlist{ID: id}
+}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/playlistfile"
+)
+
+// importCandidates is how many search results an ambiguous match offers
+const importCandidates = 5
+
+// importOptions are the import command's flags
+var importOptions struct {
+	title string
+	yes   bool
+}
+
+func importFlags(flags *flag.FlagSet) {
+	flags.StringVar(&importOptions.title, "title", "", "Title of the new playlist (default from the file)")
+	flags.BoolVar(&importOptions.yes, "yes", false, "Take the best match for ambiguous tracks instead of asking")
+}
+
+// importResult is the import command's -json output
+type importResult struct {
+	PlaylistID string        `json:"playlist_id"`
+	Title      string        `json:"title"`
+	Added      int           `json:"added"`
+	Skipped    []skippedJSON `json:"skipped"`
+}
+
+// skippedJSON is a track the import found no match for, or the user skipped
+type skippedJSON struct {
+	Title  string `json:"title"`
+	Artist string `json:"artist,omitempty"`
+	Reason string `json:"reason"`
+}
+
+func runImport(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) != 1 {
+		return errUsage
+	}
+	if !env.api.IsLoggedIn {
+		return api.ErrNotLoggedIn
+	}
+
+	file, err := playlistfile.ReadFile(args[0])
+	if err != nil {
+		return err
+	}
+	if len(file.Entries) == 0 {
+		return fmt.Errorf("no tracks in %s", args[0])
+	}
+	title := importOptions.title
+	if title == "" {
+		title = file.Title
+	}
+
+	// Ambiguous matches are asked about only when someone can answer
+	var answers <-chan string
+	if !importOptions.yes && isTerminal(os.Stdin) {
+		answers = readLines(os.Stdin)
+	}
+
+	result := importResult{Title: title, Skipped: []skippedJSON{}}
+	var videoIDs []string
+	for i, entry := range file.Entries {
+		fmt.Fprintf(os.Stderr, "[%d/%d] %s\n", i+1, len(file.Entries), entry)
+
+		id, reason, err := matchEntry(ctx, env.api, entry, answers)
+		if err != nil {
+			return err
+		}
+		if id == "" {
+			fmt.Fprintf(os.Stderr, "  Skipped: %s\n", reason)
+			result.Skipped = append(result.Skipped, skippedJSON{Title: entry.Title, Artist: entry.Artist, Reason: reason})
+			continue
+		}
+		videoIDs = append(videoIDs, id)
+	}
+	if len(videoIDs) == 0 {
+		return fmt.Errorf("none of the %d tracks were found", len(file.Entries))
+	}
+
+	result.PlaylistID, err = env.api.CreatePlaylist(ctx, title, "Imported from "+filepath.Base(args[0]))
+	if err != nil {
+		return err
+	}
+	if err := env.api.AddPlaylistItems(ctx, result.PlaylistID, videoIDs); err != nil {
+		return fmt.Errorf("created playlist %s but could not add its tracks: %v", result.PlaylistID, err)
+	}
+	result.Added = len(videoIDs)
+
+	if env.out.json {
+		return env.out.encode(result)
+	}
+	fmt.Fprintf(env.out.w, "Created playlist %s (%s) with %d tracks\n", title, result.PlaylistID, result.Added)
+	if len(result.Skipped) > 0 {
+		fmt.Fprintf(env.out.w, "Skipped %d tracks:\n", len(result.Skipped))
+		for _, s := range result.Skipped {
+			fmt.Fprintf(env.out.w, "  %s (%s)\n", playlistfile.Entry{Title: s.Title, Artist: s.Artist}, s.Reason)
+		}
+	}
+	return nil
+}
+
+// matchEntry finds the video of a playlist file entry, asking on answers when
+// the search results are ambiguous. It returns "" and why for skipped entries.
+func matchEntry(ctx context.Context, ytApi *api.YouTubeMusicAPI, entry playlistfile.Entry, answers <-chan string) (string, string, error) {
+	if entry.VideoID != "" {
+		return entry.VideoID, "", nil
+	}
+
+	results, err := ytApi.Search(ctx, entry.Query())
+	if ctx.Err() != nil {
+		return "", "", ctx.Err()
+	}
+	if err != nil {
+		ytApi.LogDebug("Search for %q failed: %v", entry.Query(), err)
+		return "", "search failed: " + err.Error(), nil
+	}
+	if len(results) > importCandidates {
+		results = results[:importCandidates]
+	}
+
+	best, sure := playlistfile.Match(entry, results)
+	switch {
+	case best < 0:
+		return "", "no results", nil
+	case sure || importOptions.yes:
+		return results[best].ID, "", nil
+	case answers == nil:
+		return "", "no sure match, use -yes to take the best one", nil
+	}
+
+	pick, err := askMatch(ctx, entry, results, best, answers)
+	if err != nil {
+		return "", "", err
+	}
+	if pick < 0 {
+		return "", "skipped", nil
+	}
+	return results[pick].ID, "", nil
+}
+
+// askMatch shows the candidates for an entry and returns the index of the one
+// picked, or -1 to skip it
+func askMatch(ctx context.Context, entry playlistfile.Entry, results []api.Track, best int, answers <-chan string) (int, error) {
+	fmt.Fprintf(os.Stderr, "  Which one is %s", entry)
+	if entry.Duration > 0 {
+		fmt.Fprintf(os.Stderr, " (%s)", formatClock(entry.Duration))
+	}
+	fmt.Fprintln(os.Stderr, "?")
+	for i, t := range results {
+		line := fmt.Sprintf("    %d) %s - %s", i+1, t.Artist, t.TrackTitle)
+		if t.Album != "" {
+			line += " [" + t.Album + "]"
+		}
+		if t.Duration > 0 {
+			line += " " + formatClock(t.Duration)
+		}
+		fmt.Fprintln(os.Stderr, line)
+	}
+
+	for {
+		fmt.Fprintf(os.Stderr, "  Pick 1-%d, Enter for %d, s to skip: ", len(results), best+1)
+		var answer string
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(os.Stderr)
+			return -1, ctx.Err()
+		case line, ok := <-answers:
+			if !ok {
+				// Standard input closed: skip the rest of the questions
+				fmt.Fprintln(os.Stderr)
+				return -1, nil
+			}
+			answer = strings.TrimSpace(line)
+		}
+
+		switch answer {
+		case "":
+			return best, nil
+		case "s", "S":
+			return -1, nil
+		}
+		if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(results) {
+			return n - 1, nil
+		}
+	}
+}
+
+// readLines sends the lines of r on the returned channel, closing it at the
+// end, so waiting for an answer can be cancelled with Ctrl+C
+func readLines(r io.Reader) <-chan string {
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(r)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	return lines
+}
+
+// isTerminal reports whether a file is an interactive terminal rather than a pipe or file
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
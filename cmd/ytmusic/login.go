@@ -0,0 +1,110 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// loginOptions are the login command's flags, of which exactly one is given
+var loginOptions struct {
+	oauth   bool
+	cookie  string
+	browser string
+}
+
+func loginFlags(flags *flag.FlagSet) {
+	flags.BoolVar(&loginOptions.oauth, "oauth", false, "Sign in with a Google device code, through the ytmusicapi command")
+	flags.StringVar(&loginOptions.cookie, "cookie", "", "Cookie header or __Secure-3PSID value copied from music.youtube.com, or - to read it from standard input")
+	flags.StringVar(&loginOptions.browser, "from-browser", "", "Browser to take the cookies of, such as firefox or chrome, read with yt-dlp")
+}
+
+// loginResultJSON is the outcome of login and logout, as printed by -json
+type loginResultJSON struct {
+	LoggedIn bool   `json:"logged_in"`
+	Method   string `json:"method,omitempty"`
+}
+
+func runLogin(ctx context.Context, env *commandEnv, args []string) error {
+	given := 0
+	for _, set := range []bool{loginOptions.oauth, loginOptions.cookie != "", loginOptions.browser != ""} {
+		if set {
+			given++
+		}
+	}
+	if len(args) != 0 || given != 1 {
+		return errUsage
+	}
+
+	var method string
+	switch {
+	case loginOptions.oauth:
+		method = "oauth"
+		cmd, err := env.api.OAuthLoginCommand(ctx)
+		if err != nil {
+			return err
+		}
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stderr, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("ytmusicapi oauth failed: %v", err)
+		}
+		if err := env.api.FinishOAuthLogin(); err != nil {
+			return err
+		}
+
+	case loginOptions.cookie != "":
+		method = "cookie"
+		cookie := loginOptions.cookie
+		if cookie == "-" {
+			// Keeps the cookie out of the shell history and the process list
+			data, err := io.ReadAll(os.Stdin)
+			if err != nil {
+				return fmt.Errorf("could not read the cookie: %v", err)
+			}
+			cookie = string(data)
+		}
+		if err := env.api.LoginWithCookie(ctx, cookie); err != nil {
+			return err
+		}
+
+	default:
+		method = "browser"
+		cookie, err := env.api.BrowserCookie(ctx, loginOptions.browser)
+		if err != nil {
+			return err
+		}
+		if err := env.api.LoginWithCookie(ctx, cookie); err != nil {
+			return err
+		}
+	}
+
+	if env.out.json {
+		return env.out.encode(loginResultJSON{LoggedIn: true, Method: method})
+	}
+	switch method {
+	case "oauth":
+		fmt.Fprintln(env.out.w, "Logged in with OAuth")
+	case "cookie":
+		fmt.Fprintln(env.out.w, "Logged in with the cookie")
+	default:
+		fmt.Fprintf(env.out.w, "Logged in with the cookies of %s\n", strings.SplitN(loginOptions.browser, ":", 2)[0])
+	}
+	return nil
+}
+
+func runLogout(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+	if err := env.api.Logout(); err != nil {
+		return err
+	}
+	if env.out.json {
+		return env.out.encode(loginResultJSON{LoggedIn: false})
+	}
+	fmt.Fprintln(env.out.w, "Logged out")
+	return nil
+}
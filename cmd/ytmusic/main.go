@@ -5,9 +5,17 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strings"
+	"syscall"
 	"time"
 
+	"ytmusic/internal/api"
+	"ytmusic/internal/config"
+	"ytmusic/internal/crash"
+	"ytmusic/internal/logs"
+	"ytmusic/internal/paths"
 	"ytmusic/internal/ui"
 	"ytmusic/internal/utils"
 
@@ -19,11 +27,47 @@ var debugMode bool
 
 func main() {
 	// Parse command line flags
-	var showHelp bool
-	flag.BoolVar(&debugMode, "debug", false, "Enable debug logging")
+	var showHelp, offline, daemonMode bool
+	var quality, codec, lang, region, httpAddr, configDir string
+	flag.BoolVar(&debugMode, "debug", false, "Enable debug logging, and serve pprof and metrics on localhost")
+	flag.StringVar(&quality, "quality", "high", "Audio quality: low, medium (or normal), or high")
+	flag.StringVar(&codec, "codec", "any", "Preferred audio codec: any, opus, or m4a")
+	flag.BoolVar(&offline, "offline", false, "Play only downloaded tracks, without connecting")
+	flag.StringVar(&lang, "lang", "", "Language of titles and shelves, such as en or de")
+	flag.StringVar(&region, "region", "", "Two-letter country code for recommendations and charts")
+	flag.BoolVar(&daemonMode, "daemon", false, "Play without the interface, taking commands on a control socket")
+	flag.StringVar(&httpAddr, "http", "", "With -daemon, serve the REST API on this address, such as 127.0.0.1:8787")
+	flag.StringVar(&configDir, "config-dir", "", "Keep all settings and data in this directory instead of the XDG directories")
 	flag.BoolVar(&showHelp, "help", false, "Show help information")
 	flag.Parse()
 	
+	if configDir != "" {
+		dir, err := filepath.Abs(configDir)
+		if err != nil {
+			fmt.Printf("Invalid -config-dir %q: %v\n", configDir, err)
+			os.Exit(2)
+		}
+		paths.SetConfigDir(dir)
+	}
+	
+	// Files from before the XDG directories move over on the first run
+	if moved, err := paths.Migrate(); err != nil {
+		fmt.Fprintf(os.Stderr, "Could not move all files out of %s: %v\n", paths.Legacy(), err)
+	} else if moved > 0 {
+		fmt.Fprintf(os.Stderr, "Moved %d items from %s to the XDG directories\n", moved, paths.Legacy())
+	}
+	
+	// Settings from the config file; invalid ones fall back to defaults and are shown in the UI
+	cfg, cfgErr := config.Load(config.Path())
+	
+	// The interface reports its own crashes; this catches the commands and the daemon
+	defer func() {
+		if r := recover(); r != nil {
+			reportCrash(crash.Recovered(r), cfg)
+			os.Exit(2)
+		}
+	}()
+	
 	// Show help if requested
 	if showHelp {
 		fmt.Println("YouTube Music TUI")
@@ -32,26 +76,80 @@ func main() {
 		fmt.Println("")
 		fmt.Println("Usage:")
 		fmt.Println("  ytmusic [options]")
+		fmt.Println("  ytmusic [options] <command> [arguments]")
 		fmt.Println("")
 		fmt.Println("Options:")
-		fmt.Println("  -debug    Enable debug logging")
+		fmt.Println("  -debug    Enable debug logging, and serve pprof and metrics on " + debugAddress)
+		fmt.Println("  -quality  Audio quality: low (~48kbps), medium or normal (~128kbps), or high (default high)")
+		fmt.Println("  -codec    Preferred audio codec: any, opus, or m4a (default any)")
+		fmt.Println("  -offline  Play only downloaded tracks, without connecting")
+		fmt.Println("  -lang     Language of titles and shelves, such as en or de")
+		fmt.Println("  -region   Two-letter country code for recommendations and charts")
+		fmt.Println("  -daemon   Play without the interface, taking commands on a control socket")
+		fmt.Println("  -http     With -daemon, serve the REST API on this address, such as 127.0.0.1:8787")
+		fmt.Println("  -config-dir  Keep all settings and data in this directory instead of the XDG directories")
 		fmt.Println("  -help     Show this help message")
 		fmt.Println("")
-		fmt.Println("Controls:")
-		fmt.Println("  q         Quit")
-		fmt.Println("  l         Login (when not logged in)")
-		fmt.Println("  r         Reset cookies/credentials")
-		fmt.Println("  /         Search")
-		fmt.Println("  Enter     Play selected track")
-		fmt.Println("  Space     Pause/resume playback")
-		fmt.Println("  ↑/↓       Navigate up/down")
+		fmt.Println("Commands, which print results without starting the interface:")
+		fmt.Println(commandHelp())
+		fmt.Println("")
+		fmt.Println("Settings are read from " + config.Path())
 		fmt.Println("")
+		fmt.Println("Controls:")
+		fmt.Println(ui.HelpText(cfg))
 		return
 	}
 	
+	// Flags given on the command line override the config file
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "quality":
+			if quality == "normal" {
+				quality = string(api.QualityMedium)
+			}
+			cfg.Audio.Quality = quality
+		case "codec":
+			cfg.Audio.Codec = codec
+		case "offline":
+			cfg.Offline = offline
+		case "lang":
+			cfg.Locale.Language = lang
+		case "region":
+			cfg.Locale.Region = strings.ToUpper(region)
+		case "http":
+			cfg.HTTP.Listen = httpAddr
+		}
+	})
+	
+	switch api.StreamQuality(quality) {
+	case api.QualityLow, api.QualityMedium, api.QualityHigh:
+	default:
+		fmt.Printf("Invalid -quality %q: use low, medium, or high\n", quality)
+		os.Exit(2)
+	}
+	switch api.StreamCodec(codec) {
+	case api.CodecAny, api.CodecOpus, api.CodecM4A:
+	default:
+		fmt.Printf("Invalid -codec %q: use any, opus, or m4a\n", codec)
+		os.Exit(2)
+	}
+	
+	if !config.ValidLanguage(cfg.Locale.Language) {
+		fmt.Printf("Invalid -lang %q: use a language code such as en or pt-BR\n", cfg.Locale.Language)
+		os.Exit(2)
+	}
+	if !config.ValidRegion(cfg.Locale.Region) {
+		fmt.Printf("Invalid -region %q: use a two-letter country code such as US\n", cfg.Locale.Region)
+		os.Exit(2)
+	}
+	
+	if cfg.HTTP.Listen != "" && !config.ValidListenAddress(cfg.HTTP.Listen) {
+		fmt.Printf("Invalid -http %q: use a host:port address such as 127.0.0.1:8787\n", cfg.HTTP.Listen)
+		os.Exit(2)
+	}
+	
 	if debugMode {
-		configDir, _ := os.UserHomeDir()
-		logPath := filepath.Join(configDir, ".ytmusic", "logs")
+		logPath := logs.Dir()
 		if _, err := os.Stat(logPath); os.IsNotExist(err) {
 			os.MkdirAll(logPath, 0755)
 		}
@@ -65,14 +163,65 @@ func main() {
 			log.SetFlags(log.Ldate | log.Ltime | log.Lshortfile)
 			log.Printf("Starting YouTube Music TUI with debug mode enabled")
 		}
+		
+		// Commands finish too quickly to be worth profiling
+		if daemonMode || len(flag.Args()) == 0 {
+			serveDebug()
+		}
+	}
+	
+	if daemonMode {
+		os.Exit(runDaemon(cfg))
+	}
+	
+	// A command runs on its own, for scripts
+	if args := flag.Args(); len(args) > 0 {
+		os.Exit(runCommand(cfg, args))
 	}
 	
 	// Clear terminal
 	utils.ClearScreen()
 	
-	p := tea.NewProgram(ui.InitialModel(debugMode), tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
+	model := ui.InitialModel(debugMode, cfg, cfgErr)
+	p := tea.NewProgram(model, tea.WithAltScreen())
+	
+	// Bubble Tea quits on SIGINT and SIGTERM; closing the terminal sends SIGHUP,
+	// which would otherwise end the process without stopping mpv
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	go func() {
+		<-hangup
+		p.Quit()
+	}()
+	
+	_, err := p.Run()
+	
+	// Always stop mpv and persist the queue, even if the UI failed
+	model.Shutdown()
+	
+	// Bubble Tea has restored the terminal and printed the panic by now
+	if model.CrashReport != "" {
+		fmt.Printf("ytmusic crashed. A report was saved to %s\nPlease include it when reporting the bug.\n", model.CrashReport)
+		os.Exit(1)
+	}
+	if model.CrashErr != nil {
+		fmt.Printf("ytmusic crashed, and the crash report couldn't be saved: %v\n", model.CrashErr)
+		os.Exit(1)
+	}
+	
+	if err != nil {
 		fmt.Printf("Error running program: %v", err)
 		os.Exit(1)
 	}
 }
+
+// reportCrash prints a panic that ended a command or the daemon, and saves a report of it
+func reportCrash(report crash.Panic, cfg *config.Config) {
+	fmt.Fprintf(os.Stderr, "panic: %v\n\n%s\n", report.Value, report.Stack)
+	path, err := crash.Write(report, cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ytmusic crashed, and the crash report couldn't be saved: %v\n", err)
+		return
+	}
+	fmt.Fprintf(os.Stderr, "ytmusic crashed. A report was saved to %s\nPlease include it when reporting the bug.\n", path)
+}
@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/daemon"
+	"ytmusic/internal/download"
+	"ytmusic/internal/player"
+)
+
+func runPlay(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	// A running daemon plays it instead, so the command returns right away
+	arg := strings.Join(args, " ")
+	status, err := daemon.Send(daemon.Request{Command: daemon.CommandPlay, Track: arg})
+	if err == nil {
+		if env.out.json {
+			return env.out.encode(status)
+		}
+		if status.Track != nil {
+			fmt.Fprintf(env.out.w, "Playing %s - %s in the daemon\n", status.Track.Title, status.Track.Artist)
+		}
+		return nil
+	}
+	if !errors.Is(err, daemon.ErrNotRunning) {
+		return err
+	}
+
+	track, err := env.api.ResolveTrack(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	musicPlayer := player.NewPlayer(debugMode)
+	musicPlayer.MPVPath = env.cfg.MPV.Path
+	musicPlayer.MPVArgs = env.cfg.MPV.Args
+	musicPlayer.YtdlFormat = env.cfg.MPV.YtdlFormat
+	musicPlayer.MaxRate = env.cfg.Audio.MaxRate
+	musicPlayer.Durations = env.api
+	downloads, err := download.LoadIndex()
+	if err != nil {
+		musicPlayer.LogDebug("Error loading downloads: %v", err)
+	}
+
+	// Downloaded tracks play from disk, like they do in the TUI
+	source, duration := downloads.Path(track.ID), track.Duration
+	if source == "" {
+		info, err := env.api.GetStreamInfo(ctx, track.ID)
+		if err != nil {
+			return err
+		}
+		source = info.URL
+		if info.Duration > 0 {
+			duration = info.Duration
+		}
+	}
+
+	musicPlayer.Queue.SetTracks([]api.Track{track})
+	musicPlayer.Queue.PlayTrack(0)
+	if err := musicPlayer.Play(source, duration); err != nil {
+		return fmt.Errorf("could not start mpv: %v", err)
+	}
+	defer musicPlayer.Stop()
+
+	if env.out.json {
+		if err := env.out.encode(newTrackJSON(track)); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintf(env.out.w, "Playing %s - %s\n", track.TrackTitle, track.Artist)
+	}
+
+	play := api.NewPlay(track)
+	session, err := env.api.StartPlayback(ctx, track.ID)
+	if err != nil {
+		env.api.LogDebug("Could not report playback of %s: %v", track.ID, err)
+	}
+
+	waitForTrack(ctx, musicPlayer, env.out)
+	if err := env.api.RecordPlay(play, musicPlayer.CurrentPos); err != nil {
+		env.api.LogDebug("Error recording play: %v", err)
+	}
+
+	if session != nil {
+		// Reported even after Ctrl+C, so it can't use the cancelled context
+		if err := session.ReportWatchtime(context.Background(), musicPlayer.CurrentPos); err != nil {
+			env.api.LogDebug("Error reporting watchtime: %v", err)
+		}
+	}
+	return nil
+}
+
+// waitForTrack shows the playing track's progress until it ends or ctx is cancelled
+func waitForTrack(ctx context.Context, musicPlayer *player.Player, out output) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			if !out.json {
+				fmt.Fprintln(out.w)
+			}
+			return
+
+		case <-musicPlayer.Events():
+			if !out.json {
+				fmt.Fprintln(out.w)
+			}
+			return
+
+		case <-ticker.C:
+			musicPlayer.SyncPosition()
+			if !out.json {
+				fmt.Fprintf(out.w, "\r%s / %s ", formatClock(musicPlayer.CurrentPos), formatClock(musicPlayer.Duration))
+			}
+		}
+	}
+}
+
+// formatClock formats seconds as m:ss, with 0:00 for unknown times
+func formatClock(seconds int) string {
+	if seconds < 0 {
+		seconds = 0
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"ytmusic/internal/daemon"
+)
+
+// remoteCommands maps the remote command's actions to daemon commands
+var remoteCommands = map[string]string{
+	"status": daemon.CommandStatus,
+	"play":   daemon.CommandPlay,
+	"pause":  daemon.CommandPause,
+	"toggle": daemon.CommandToggle,
+	"next":   daemon.CommandNext,
+	"prev":   daemon.CommandPrevious,
+	"stop":   daemon.CommandStop,
+}
+
+func runRemote(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) == 0 {
+		return errUsage
+	}
+
+	var req daemon.Request
+	switch {
+	case args[0] == "queue":
+		// queue add <track>
+		if len(args) < 3 || args[1] != "add" {
+			return errUsage
+		}
+		req = daemon.Request{Command: daemon.CommandQueueAdd, Track: strings.Join(args[2:], " ")}
+	case remoteCommands[args[0]] != "":
+		req = daemon.Request{Command: remoteCommands[args[0]], Track: strings.Join(args[1:], " ")}
+		if req.Track != "" && req.Command != daemon.CommandPlay {
+			return errUsage
+		}
+	default:
+		return errUsage
+	}
+
+	status, err := daemon.Send(req)
+	if err != nil {
+		return err
+	}
+	if env.out.json {
+		return env.out.encode(status)
+	}
+	fmt.Fprintln(env.out.w, describeStatus(status))
+	return nil
+}
+
+// describeStatus sums up the daemon's state in one line
+func describeStatus(status *daemon.Status) string {
+	if status.Track == nil {
+		return "Stopped, the queue is empty"
+	}
+
+	line := fmt.Sprintf("%s - %s", status.Track.Title, status.Track.Artist)
+	switch status.State {
+	case daemon.StatePlaying:
+		line = fmt.Sprintf("Playing %s  %s / %s", line, formatClock(status.Position), formatClock(status.Duration))
+	case daemon.StatePaused:
+		line = fmt.Sprintf("Paused %s  %s / %s", line, formatClock(status.Position), formatClock(status.Duration))
+	default:
+		line = "Stopped at " + line
+	}
+	return fmt.Sprintf("%s  (%d of %d in the queue)", line, status.QueueIndex+1, status.QueueLength)
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"ytmusic/internal/daemon"
+)
+
+// defaultStatusFormat is the line status prints unless given -format
+const defaultStatusFormat = "{artist} - {title} [{elapsed}/{duration}]"
+
+// statusPollInterval is how often -follow asks the daemon for its state
+const statusPollInterval = time.Second
+
+// statusOptions are the status command's flags
+var statusOptions struct {
+	format string
+	follow bool
+	waybar bool
+}
+
+func statusFlags(flags *flag.FlagSet) {
+	flags.StringVar(&statusOptions.format, "format", defaultStatusFormat, "Line to print, with {title}, {artist}, {album}, {state}, {elapsed}, {duration}, {position}, {length}, {id} and {url}")
+	flags.BoolVar(&statusOptions.follow, "follow", false, "Keep running, printing a line whenever it changes")
+	flags.BoolVar(&statusOptions.waybar, "waybar", false, "Print waybar's JSON instead of plain lines")
+}
+
+func runStatus(ctx context.Context, env *commandEnv, args []string) error {
+	if len(args) != 0 {
+		return errUsage
+	}
+
+	if !statusOptions.follow {
+		status, err := daemon.Send(daemon.Request{Command: daemon.CommandStatus})
+		if err != nil {
+			return err
+		}
+		return printStatus(env.out, status)
+	}
+
+	// A status bar keeps the last line shown, so a stopped daemon prints an empty one
+	ticker := time.NewTicker(statusPollInterval)
+	defer ticker.Stop()
+	last := ""
+	for {
+		status, err := daemon.Send(daemon.Request{Command: daemon.CommandStatus})
+		if err != nil && !errors.Is(err, daemon.ErrNotRunning) {
+			return err
+		}
+
+		line, err := statusLine(env.out, status)
+		if err != nil {
+			return err
+		}
+		if line != last {
+			fmt.Fprintln(env.out.w, line)
+			last = line
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// printStatus prints the daemon's state once
+func printStatus(out output, status *daemon.Status) error {
+	line, err := statusLine(out, status)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(out.w, line)
+	return nil
+}
+
+// statusLine renders the daemon's state as the output options ask; a nil
+// status, from a daemon that isn't running, renders as nothing
+func statusLine(out output, status *daemon.Status) (string, error) {
+	text := ""
+	if status != nil && status.Track != nil {
+		text = formatStatus(statusOptions.format, status)
+	}
+
+	switch {
+	case out.json:
+		data, err := json.Marshal(status)
+		return string(data), err
+
+	case statusOptions.waybar:
+		reply := struct {
+			Text    string `json:"text"`
+			Tooltip string `json:"tooltip"`
+			Class   string `json:"class"`
+			Alt     string `json:"alt"`
+		}{Text: text, Tooltip: text, Class: daemon.StateStopped, Alt: daemon.StateStopped}
+		if status != nil {
+			reply.Class, reply.Alt = status.State, status.State
+			if status.Track != nil {
+				reply.Tooltip = fmt.Sprintf("%s\n%s\n%s", status.Track.Title, status.Track.Artist, status.Track.Album)
+			}
+		}
+		data, err := json.Marshal(reply)
+		return string(data), err
+	}
+	return text, nil
+}
+
+// formatStatus fills in the placeholders of a -format line
+func formatStatus(format string, status *daemon.Status) string {
+	track := status.Track
+	return strings.NewReplacer(
+		"{title}", track.Title,
+		"{artist}", track.Artist,
+		"{album}", track.Album,
+		"{id}", track.ID,
+		"{url}", "https://music.youtube.com/watch?v="+track.ID,
+		"{state}", status.State,
+		"{elapsed}", formatClock(status.Position),
+		"{duration}", formatClock(status.Duration),
+		"{position}", strconv.Itoa(status.QueueIndex+1),
+		"{length}", strconv.Itoa(status.QueueLength),
+	).Replace(format)
+}
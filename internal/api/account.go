@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+)
+
+// Account is an identity the signed-in user can browse as: their own
+// account or a brand account (channel) they manage
+type Account struct {
+	Name     string
+	Handle   string // Channel handle such as "@name", if the account has a channel
+	AuthUser string // X-Goog-AuthUser index of the Google account
+	PageID   string // Brand account ID sent as onBehalfOfUser, empty for the Google account itself
+	Selected bool   // Whether requests are currently made as this account
+}
+
+// FilterValue implements list.Item interface for filtering
+func (a Account) FilterValue() string {
+	return a.Name + " " + a.Handle
+}
+
+// Title implements list.Item interface for displaying in the list
+func (a Account) Title() string {
+	if a.Selected {
+		return a.Name + " (current)"
+	}
+	return a.Name
+}
+
+// Description implements list.Item interface for displaying in the list
+func (a Account) Description() string {
+	kind := "Google account"
+	if a.PageID != "" {
+		kind = "Brand account"
+	}
+	if a.Handle != "" {
+		return kind + " · " + a.Handle
+	}
+	return kind
+}
+
+// accountText is text innertube sends either plain or as runs
+type accountText struct {
+	SimpleText string `json:"simpleText"`
+	Runs
+}
+
+func (t accountText) text() string {
+	if t.SimpleText != "" {
+		return t.SimpleText
+	}
+	return t.Runs.Text()
+}
+
+// accountsListResponse is the subset of the account/accounts_list response we use
+type accountsListResponse struct {
+	Actions []struct {
+		GetMultiPageMenuAction struct {
+			Menu struct {
+				MultiPageMenuRenderer struct {
+					Sections []struct {
+						AccountSectionListRenderer struct {
+							Contents []struct {
+								AccountItemSectionRenderer struct {
+									Contents []struct {
+										AccountItem *accountItem `json:"accountItem"`
+									} `json:"contents"`
+								} `json:"accountItemSectionRenderer"`
+							} `json:"contents"`
+						} `json:"accountSectionListRenderer"`
+					} `json:"sections"`
+				} `json:"multiPageMenuRenderer"`
+			} `json:"menu"`
+		} `json:"getMultiPageMenuAction"`
+	} `json:"actions"`
+}
+
+// accountItem is one entry of the account switcher
+type accountItem struct {
+	AccountName     accountText `json:"accountName"`
+	ChannelHandle   accountText `json:"channelHandle"`
+	IsSelected      bool        `json:"isSelected"`
+	IsDisabled      bool        `json:"isDisabled"`
+	ServiceEndpoint struct {
+		SelectActiveIdentityEndpoint struct {
+			SupportedTokens []struct {
+				PageIDToken *struct {
+					PageID string `json:"pageId"`
+				} `json:"pageIdToken"`
+				AccountSigninToken *struct {
+					SigninURL string `json:"signinUrl"`
+				} `json:"accountSigninToken"`
+			} `json:"supportedTokens"`
+		} `json:"selectActiveIdentityEndpoint"`
+	} `json:"serviceEndpoint"`
+}
+
+// toAccount converts a switcher entry, taking the Google account index from its sign-in URL
+func (item *accountItem) toAccount(defaultAuthUser string) Account {
+	account := Account{
+		Name:     item.AccountName.text(),
+		Handle:   item.ChannelHandle.text(),
+		AuthUser: defaultAuthUser,
+	}
+	for _, token := range item.ServiceEndpoint.SelectActiveIdentityEndpoint.SupportedTokens {
+		if token.PageIDToken != nil {
+			account.PageID = token.PageIDToken.PageID
+		}
+		if token.AccountSigninToken != nil {
+			if signin, err := url.Parse(token.AccountSigninToken.SigninURL); err == nil {
+				if authUser := signin.Query().Get("authuser"); authUser != "" {
+					account.AuthUser = authUser
+				}
+			}
+		}
+	}
+	return account
+}
+
+// savedAccount is the identity remembered in account.json
+type savedAccount struct {
+	AuthUser string `json:"auth_user"`
+	PageID   string `json:"page_id,omitempty"`
+}
+
+// accountPath returns the location of the chosen identity
+func (api *YouTubeMusicAPI) accountPath() string {
+	return filepath.Join(api.configPath, "account.json")
+}
+
+// loadAccount restores the identity chosen in an earlier session
+func (api *YouTubeMusicAPI) loadAccount() {
+	data, err := os.ReadFile(api.accountPath())
+	if err != nil {
+		return
+	}
+
+	var saved savedAccount
+	if err := json.Unmarshal(data, &saved); err != nil {
+		api.LogDebug("Error parsing account file: %v", err)
+		return
+	}
+	if saved.AuthUser != "" {
+		api.authUser = saved.AuthUser
+	}
+	api.onBehalfOf = saved.PageID
+	api.accountChosen = true
+	api.LogDebug("Using account %s, brand account %q", api.authUser, api.onBehalfOf)
+}
+
+// GetAccounts lists the identities the signed-in user can switch between
+func (api *YouTubeMusicAPI) GetAccounts(ctx context.Context) ([]Account, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+	if api.getSAPISID() == "" && api.oauthAccessToken() == "" {
+		return nil, withKind(ErrBackendUnavailable, "switching accounts requires a SAPISID cookie or OAuth login")
+	}
+
+	var response accountsListResponse
+	if err := api.sendRequest(ctx, "account/accounts_list", nil, &response); err != nil {
+		return nil, err
+	}
+
+	var accounts []Account
+	for _, action := range response.Actions {
+		for _, section := range action.GetMultiPageMenuAction.Menu.MultiPageMenuRenderer.Sections {
+			for _, group := range section.AccountSectionListRenderer.Contents {
+				for _, content := range group.AccountItemSectionRenderer.Contents {
+					item := content.AccountItem
+					if item == nil || item.IsDisabled {
+						continue
+					}
+					account := item.toAccount(api.authUser)
+					account.Selected = account.AuthUser == api.authUser && account.PageID == api.onBehalfOf
+					accounts = append(accounts, account)
+				}
+			}
+		}
+	}
+
+	api.LogDebug("Found %d accounts", len(accounts))
+	return accounts, nil
+}
+
+// SetAccount makes requests as account from now on and remembers the choice
+func (api *YouTubeMusicAPI) SetAccount(account Account) error {
+	api.authUser = account.AuthUser
+	if api.authUser == "" {
+		api.authUser = "0"
+	}
+	api.onBehalfOf = account.PageID
+	api.accountChosen = true
+	api.LogDebug("Switched to account %s (%s, brand account %q)", account.Name, api.authUser, api.onBehalfOf)
+
+	data, err := json.Marshal(savedAccount{AuthUser: api.authUser, PageID: api.onBehalfOf})
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(api.accountPath(), data, 0600); err != nil {
+		return fmt.Errorf("could not save account choice: %v", err)
+	}
+	return os.Chmod(api.accountPath(), 0600)
+}
+
+// AccountChosen reports whether the user picked an identity, now or in an earlier session
+func (api *YouTubeMusicAPI) AccountChosen() bool {
+	return api.accountChosen
+}
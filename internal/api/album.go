@@ -0,0 +1,165 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Album represents a YouTube Music album
+type Album struct {
+	ID         string // Browse ID (MPREb_...)
+	AlbumTitle string
+	Artist     string
+	Year       string
+	Tracks     []Track // Tracks on the album, loaded on demand
+}
+
+// FilterValue implements list.Item interface for filtering
+func (a Album) FilterValue() string {
+	return a.AlbumTitle + " " + a.Artist
+}
+
+// Title implements list.Item interface for displaying in the list
+func (a Album) Title() string {
+	return a.AlbumTitle
+}
+
+// Description implements list.Item interface for displaying in the list
+func (a Album) Description() string {
+	if a.Year == "" {
+		return a.Artist
+	}
+	return a.Artist + " • " + a.Year
+}
+
+// toAlbum converts a library card into an Album, returning false for cards that aren't albums
+func (r *MusicTwoRowItemRenderer) toAlbum() (Album, bool) {
+	if r.NavigationEndpoint == nil || r.NavigationEndpoint.BrowseEndpoint == nil {
+		return Album{}, false
+	}
+
+	album := Album{
+		ID:         r.NavigationEndpoint.BrowseEndpoint.BrowseID,
+		AlbumTitle: r.Title.First(),
+	}
+
+	// The subtitle reads like "Album • Artist • 2019"
+	var artists []string
+	for _, run := range r.Subtitle.Runs {
+		if run.NavigationEndpoint != nil && run.NavigationEndpoint.BrowseEndpoint != nil {
+			if run.NavigationEndpoint.BrowseEndpoint.PageType() == pageTypeArtist {
+				artists = append(artists, run.Text)
+			}
+			continue
+		}
+		if isYear(run.Text) {
+			album.Year = run.Text
+		}
+	}
+	album.Artist = strings.Join(artists, ", ")
+
+	return album, true
+}
+
+// isYear reports whether s looks like a four-digit release year
+func isYear(s string) bool {
+	if len(s) != 4 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// GetAlbum fetches an album's details and track list
+func (api *YouTubeMusicAPI) GetAlbum(ctx context.Context, browseID string) (*Album, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching album: %s", browseID)
+
+	cacheKey := api.cacheKey("album", browseID)
+	if cached := new(Album); api.cache.get(cacheKey, albumCacheTTL, cached) {
+		return cached, nil
+	}
+
+	// Album pages are public, so innertube works without a signed session
+	if !api.bridge.IsAvailable() {
+		api.LogDebug("Python bridge not available, fetching album via innertube")
+		album, err := api.getAlbumNative(ctx, browseID)
+		if err == nil {
+			api.cache.put(cacheKey, album)
+		}
+		return album, err
+	}
+
+	album, err := api.bridge.GetAlbum(ctx, browseID)
+	if err != nil {
+		api.LogDebug("Python bridge get album failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d album tracks via Python bridge", len(album.Tracks))
+	api.cache.put(cacheKey, album)
+	return album, nil
+}
+
+// getAlbumNative fetches an album page directly from innertube
+func (api *YouTubeMusicAPI) getAlbumNative(ctx context.Context, browseID string) (*Album, error) {
+	var response BrowseResponse
+	err := api.sendRequest(ctx, "browse", map[string]interface{}{
+		"browseId": browseID,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+	return response.album(browseID)
+}
+
+// album reads an album page's details and tracks
+func (r *BrowseResponse) album(browseID string) (*Album, error) {
+	album := &Album{ID: browseID}
+	if header := r.header(); header != nil {
+		album.AlbumTitle = header.Title.Text()
+
+		// Artists are in the strapline on newer layouts and in the subtitle on older ones
+		var artists []string
+		for _, run := range append(header.StraplineTextOne.Runs, header.Subtitle.Runs...) {
+			if run.NavigationEndpoint != nil && run.NavigationEndpoint.BrowseEndpoint != nil {
+				if run.NavigationEndpoint.BrowseEndpoint.PageType() == pageTypeArtist {
+					artists = append(artists, run.Text)
+				}
+				continue
+			}
+			if isYear(run.Text) {
+				album.Year = run.Text
+			}
+		}
+		if len(artists) == 0 {
+			artists = append(artists, header.StraplineTextOne.Text())
+		}
+		album.Artist = strings.Join(artists, ", ")
+	}
+
+	album.Tracks = tracksFromShelves(r.sectionList().shelves())
+	if len(album.Tracks) == 0 && album.AlbumTitle == "" {
+		return nil, fmt.Errorf("no album found for %s", browseID)
+	}
+
+	// Album rows usually omit the artist, since it's the album's
+	for i := range album.Tracks {
+		if album.Tracks[i].Artist == "" {
+			album.Tracks[i].Artist = album.Artist
+		}
+		album.Tracks[i].AlbumID = browseID
+		album.Tracks[i].Album = album.AlbumTitle
+		album.Tracks[i].Year = album.Year
+	}
+
+	return album, nil
+}
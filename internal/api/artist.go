@@ -0,0 +1,146 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Artist represents a YouTube Music artist or channel
+type Artist struct {
+	ID       string // Channel browse ID (UC...)
+	Name     string
+	Subtitle string  // e.g. "1.2M subscribers" or "12 songs"
+	RadioID  string  // Playlist ID of the artist radio, loaded with the artist page
+	TopSongs []Track // Loaded with the artist page
+	Albums   []Album // Loaded with the artist page
+	Singles  []Album // Loaded with the artist page
+}
+
+// FilterValue implements list.Item interface for filtering
+func (a Artist) FilterValue() string {
+	return a.Name
+}
+
+// Title implements list.Item interface for displaying in the list
+func (a Artist) Title() string {
+	return a.Name
+}
+
+// Description implements list.Item interface for displaying in the list
+func (a Artist) Description() string {
+	return a.Subtitle
+}
+
+// toArtist converts a library row into an Artist, returning false for rows without a channel
+func (r *MusicResponsiveListItemRenderer) toArtist() (Artist, bool) {
+	if r.NavigationEndpoint == nil || r.NavigationEndpoint.BrowseEndpoint == nil {
+		return Artist{}, false
+	}
+
+	return Artist{
+		ID:       r.NavigationEndpoint.BrowseEndpoint.BrowseID,
+		Name:     r.column(0).First(),
+		Subtitle: r.column(1).Text(),
+	}, true
+}
+
+// GetArtist fetches an artist page with top songs, albums, and singles
+func (api *YouTubeMusicAPI) GetArtist(ctx context.Context, channelID string) (*Artist, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching artist: %s", channelID)
+
+	cacheKey := api.cacheKey("artist", channelID)
+	if cached := new(Artist); api.cache.get(cacheKey, artistCacheTTL, cached) {
+		return cached, nil
+	}
+
+	// Artist pages are public, so innertube works without a signed session
+	if !api.bridge.IsAvailable() {
+		api.LogDebug("Python bridge not available, fetching artist via innertube")
+		var artist *Artist
+		var err error
+		if isUpload(channelID) {
+			artist, err = api.getUploadedArtistNative(ctx, channelID)
+		} else {
+			artist, err = api.getArtistNative(ctx, channelID)
+		}
+		if err == nil {
+			api.cache.put(cacheKey, artist)
+		}
+		return artist, err
+	}
+
+	artist, err := api.bridge.GetArtist(ctx, channelID)
+	if err != nil {
+		api.LogDebug("Python bridge get artist failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found artist %s via Python bridge", artist.Name)
+	api.cache.put(cacheKey, artist)
+	return artist, nil
+}
+
+// getArtistNative fetches an artist page directly from innertube
+func (api *YouTubeMusicAPI) getArtistNative(ctx context.Context, channelID string) (*Artist, error) {
+	var response BrowseResponse
+	err := api.sendRequest(ctx, "browse", map[string]interface{}{
+		"browseId": channelID,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	header := response.header()
+	if header == nil {
+		return nil, fmt.Errorf("no artist found for %s", channelID)
+	}
+
+	artist := &Artist{
+		ID:       channelID,
+		Name:     header.Title.Text(),
+		Subtitle: header.SubscriptionButton.SubscribeButtonRenderer.SubscriberCountText.Text(),
+	}
+	if artist.Subtitle != "" {
+		artist.Subtitle += " subscribers"
+	}
+	if watch := header.StartRadioButton.ButtonRenderer.NavigationEndpoint.WatchPlaylistEndpoint; watch != nil {
+		artist.RadioID = watch.PlaylistID
+	}
+
+	sections := response.sectionList()
+	artist.TopSongs = tracksFromShelves(sections.shelves())
+
+	// Carousels are identified only by their title
+	for _, carousel := range sections.carousels() {
+		title := strings.ToLower(carousel.Header.MusicCarouselShelfBasicHeaderRenderer.Title.Text())
+
+		var albums []Album
+		for _, item := range carousel.Contents {
+			if item.MusicTwoRowItemRenderer == nil {
+				continue
+			}
+			album, ok := item.MusicTwoRowItemRenderer.toAlbum()
+			if !ok {
+				continue
+			}
+			if album.Artist == "" {
+				album.Artist = artist.Name
+			}
+			albums = append(albums, album)
+		}
+
+		switch {
+		case strings.HasPrefix(title, "albums"):
+			artist.Albums = albums
+		case strings.HasPrefix(title, "singles"):
+			artist.Singles = albums
+		}
+	}
+
+	return artist, nil
+}
@@ -1,6 +1,8 @@
 package api
 
 import (
+	"context"
+	"crypto/sha1"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -8,10 +10,12 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
-	
-	"ytmusic/internal/utils"
+	"strings"
+	"time"
 )
 
+const ytMusicOrigin = "https://music.youtube.com"
+
 // loadCookies loads cookies from the config file
 func (api *YouTubeMusicAPI) loadCookies() {
 	cookiePath := filepath.Join(api.configPath, "cookies.json")
@@ -35,12 +39,9 @@ func (api *YouTubeMusicAPI) loadCookies() {
 	}
 
 	// Check if we have the required cookies
-	for _, cookie := range cookies {
-		if cookie.Name == "__Secure-3PSID" {
-			api.IsLoggedIn = true
-			api.LogDebug("Found valid __Secure-3PSID cookie, setting logged in")
-			break
-		}
+	if hasLoginCookie(cookies) {
+		api.IsLoggedIn = true
+		api.LogDebug("Found login cookies, setting logged in")
 	}
 
 	// Set cookies on client
@@ -51,6 +52,48 @@ func (api *YouTubeMusicAPI) loadCookies() {
 	}
 }
 
+// getSAPISID returns the SAPISID cookie used to sign authenticated requests
+func (api *YouTubeMusicAPI) getSAPISID() string {
+	ytMusicURL, _ := url.Parse(ytMusicOrigin)
+	
+	// Browsers only expose __Secure-3PAPISID on some accounts, and it carries the same value
+	var fallback string
+	for _, cookie := range api.client.Jar.Cookies(ytMusicURL) {
+		switch cookie.Name {
+		case "SAPISID":
+			return cookie.Value
+		case "__Secure-3PAPISID":
+			fallback = cookie.Value
+		}
+	}
+	
+	return fallback
+}
+
+// sapisidHash computes the SAPISIDHASH authorization value for the given origin
+func sapisidHash(sapisid, origin string, now time.Time) string {
+	timestamp := now.Unix()
+	sum := sha1.Sum([]byte(fmt.Sprintf("%d %s %s", timestamp, sapisid, origin)))
+	return fmt.Sprintf("SAPISIDHASH %d_%x", timestamp, sum)
+}
+
+// signRequest adds the origin and authorization headers innertube expects
+func (api *YouTubeMusicAPI) signRequest(req *http.Request) {
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", innertubeUserAgent)
+	req.Header.Set("Origin", ytMusicOrigin)
+	req.Header.Set("X-Origin", ytMusicOrigin)
+	req.Header.Set("Referer", ytMusicOrigin+"/")
+	req.Header.Set("X-Goog-AuthUser", api.authUser)
+	
+	// Each request gets a fresh hash because the timestamp is part of the signature
+	if sapisid := api.getSAPISID(); sapisid != "" {
+		req.Header.Set("Authorization", sapisidHash(sapisid, ytMusicOrigin, time.Now()))
+	} else if token := api.oauthAccessToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+}
+
 // saveCookies saves cookies to the config file
 func (api *YouTubeMusicAPI) saveCookies() error {
 	ytMusicURL, _ := url.Parse("https://music.youtube.com")
@@ -65,7 +108,11 @@ func (api *YouTubeMusicAPI) saveCookies() error {
 	}
 	
 	cookiePath := filepath.Join(api.configPath, "cookies.json")
-	return os.WriteFile(cookiePath, data, 0644)
+	if err := os.WriteFile(cookiePath, data, 0600); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of a file saved by an older version
+	return os.Chmod(cookiePath, 0600)
 }
 
 // ResetCookies removes saved cookies and resets login state
@@ -74,8 +121,15 @@ func (api *YouTubeMusicAPI) ResetCookies() error {
 	
 	// Clear cookies in the client
 	api.client.Jar, _ = cookiejar.New(nil)
+	api.oauth = nil
 	api.IsLoggedIn = false
 	
+	// The next login may be a different user, with different brand accounts
+	api.authUser = "0"
+	api.onBehalfOf = ""
+	api.accountChosen = false
+	os.Remove(api.accountPath())
+	
 	// Remove the cookies file
 	cookiePath := filepath.Join(api.configPath, "cookies.json")
 	if _, err := os.Stat(cookiePath); !os.IsNotExist(err) {
@@ -90,17 +144,18 @@ func (api *YouTubeMusicAPI) ResetCookies() error {
 	return nil
 }
 
-// ManualLogin handles manual login with a provided cookie
+// ManualLogin handles manual login with a provided cookie: either the value of
+// __Secure-3PSID, or a whole Cookie header copied from the browser, which also
+// carries SAPISID and the other cookies signed innertube requests need
 func (api *YouTubeMusicAPI) ManualLogin(cookie string) error {
+	cookie = strings.TrimSpace(cookie)
 	if cookie == "" {
 		return fmt.Errorf("no cookie provided")
 	}
 	
 	api.LogDebug("Manual login attempt with cookie length: %d", len(cookie))
 	
-	// Set the cookie
-	ytMusicURL, _ := url.Parse("https://music.youtube.com")
-	api.client.Jar.SetCookies(ytMusicURL, []*http.Cookie{
+	cookies := []*http.Cookie{
 		{
 			Name:   "__Secure-3PSID",
 			Value:  cookie,
@@ -108,84 +163,278 @@ func (api *YouTubeMusicAPI) ManualLogin(cookie string) error {
 			Path:   "/",
 			Secure: true,
 		},
-	})
+	}
+	if strings.Contains(cookie, "=") {
+		// Browsers copy the header with its name when the whole line is selected
+		if name, value, found := strings.Cut(cookie, ":"); found && strings.EqualFold(strings.TrimSpace(name), "cookie") {
+			cookie = value
+		}
+		cookies = parseCookieHeader(cookie)
+		if !hasLoginCookie(cookies) {
+			return fmt.Errorf("the pasted cookies have neither __Secure-3PSID nor SAPISID - copy them from music.youtube.com")
+		}
+		api.LogDebug("Parsed %d cookies from the pasted header", len(cookies))
+	}
+	
+	ytMusicURL, _ := url.Parse("https://music.youtube.com")
+	api.client.Jar.SetCookies(ytMusicURL, cookies)
 	
 	api.IsLoggedIn = true
 	return api.saveCookies()
 }
 
-// InitiateLogin starts the login process
-func (api *YouTubeMusicAPI) InitiateLogin() error {
-    api.LogDebug("Initiating login process")
-    
-    // Try to open browser to YouTube Music
-    utils.ClearScreen()
-    fmt.Println("┌───────────────────────────────────────────────────────────────────┐")
-    fmt.Println("│ Attempting to open YouTube Music in your browser...               │")
-    fmt.Println("└───────────────────────────────────────────────────────────────────┘")
-    
-    browserOpened := utils.OpenBrowser("https://music.youtube.com")
-    
-    if !browserOpened {
-        utils.ClearScreen()
-        fmt.Println("┌───────────────────────────────────────────────────────────────────┐")
-        fmt.Println("│ Could not open browser automatically.                             │")
-        fmt.Println("└───────────────────────────────────────────────────────────────────┘")
-        fmt.Println("")
-        fmt.Println("To get your cookie manually:")
-        fmt.Println("  1. Open https://music.youtube.com in your browser")
-        fmt.Println("  2. Log in if you're not already logged in")
-        fmt.Println("  3. Open developer tools (F12 or right-click > Inspect)")
-        fmt.Println("  4. Go to Application/Storage tab > Cookies > music.youtube.com")
-        fmt.Println("  5. Find the '__Secure-3PSID' cookie and copy its value")
-    } else {
-        utils.ClearScreen()
-        fmt.Println("┌───────────────────────────────────────────────────────────────────┐")
-        fmt.Println("│ Browser opened to YouTube Music.                                  │")
-        fmt.Println("└───────────────────────────────────────────────────────────────────┘")
-        fmt.Println("")
-        fmt.Println("Please follow these steps:")
-        fmt.Println("  1. Log in if you're not already logged in")
-        fmt.Println("  2. Open developer tools (F12 or right-click > Inspect)")
-        fmt.Println("  3. Go to Application/Storage tab > Cookies > music.youtube.com")
-        fmt.Println("  4. Find the '__Secure-3PSID' cookie and copy its value")
-    }
-    
-    fmt.Println("")
-    fmt.Println("IMPORTANT: Make sure you're getting the cookie from music.youtube.com,")
-    fmt.Println("not from google.com. The correct domain is .youtube.com")
-    fmt.Println("")
-    fmt.Println("┌───────────────────────────────────────────────────────────────────┐")
-    fmt.Print("│ Paste the cookie value here: ")
-	
-    var cookie string
-    fmt.Scanln(&cookie)
-    
-    if cookie == "" {
-        api.LogDebug("No cookie provided during login")
-        return fmt.Errorf("no cookie provided")
-    }
-    
-    api.LogDebug("Received cookie input with length: %d", len(cookie))
-    
-    fmt.Println("└─────────────────────────────────────────────────────────┘")
-    
-    // Set the cookie
-    ytMusicURL, _ := url.Parse("https://music.youtube.com")
-    api.client.Jar.SetCookies(ytMusicURL, []*http.Cookie{
-        {
-            Name:   "__Secure-3PSID",
-            Value:  cookie,
-            Domain: ".youtube.com",
-            Path:   "/",
-            Secure: true,
-        },
-    })
-    
-    api.IsLoggedIn = true
-    utils.ClearScreen()
-    fmt.Println("┌─────────────────────────────────────────────────────────┐")
-    fmt.Println("│ Login successful! Press any key to continue.            │")
-    fmt.Println("└─────────────────────────────────────────────────────────┘")
-    return api.saveCookies()
+// hasLoginCookie reports whether cookies include one that identifies a signed-in user
+func hasLoginCookie(cookies []*http.Cookie) bool {
+	for _, cookie := range cookies {
+		switch cookie.Name {
+		case "__Secure-3PSID", "SAPISID", "__Secure-3PAPISID":
+			return true
+		}
+	}
+	return false
+}
+
+// oauthToken mirrors the oauth.json file written by `ytmusicapi oauth`
+type oauthToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	Scope        string `json:"scope"`
+	ExpiresAt    int64  `json:"expires_at"`
+	ExpiresIn    int64  `json:"expires_in"`
+	
+	path string // File the token was loaded from, so refreshes can be written back
+}
+
+// loadYTMusicAPICredentials imports credential files created by the ytmusicapi CLI
+func (api *YouTubeMusicAPI) loadYTMusicAPICredentials() {
+	if api.loadBrowserHeaders(filepath.Join(api.configPath, "headers_auth.json")) {
+		return
+	}
+	
+	for _, name := range []string{"oauth_auth.json", "oauth.json"} {
+		if api.loadOAuthToken(filepath.Join(api.configPath, name)) {
+			return
+		}
+	}
+}
+
+// ImportCredentials looks again for saved cookies and ytmusicapi credential
+// files, such as ones written since start-up, and reports whether the user is
+// logged in
+func (api *YouTubeMusicAPI) ImportCredentials() bool {
+	if !api.IsLoggedIn {
+		api.loadCookies()
+	}
+	if !api.IsLoggedIn {
+		api.loadYTMusicAPICredentials()
+	}
+	return api.IsLoggedIn
+}
+
+// loadBrowserHeaders reads a `ytmusicapi browser` headers file and imports its cookies
+func (api *YouTubeMusicAPI) loadBrowserHeaders(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			api.LogDebug("Error reading headers file %s: %v", path, err)
+		}
+		return false
+	}
+	
+	var headers map[string]string
+	if err := json.Unmarshal(data, &headers); err != nil {
+		api.LogDebug("Error parsing headers file %s: %v", path, err)
+		return false
+	}
+	
+	// Header names are case-insensitive and ytmusicapi stores whatever the browser sent
+	var cookieHeader string
+	for name, value := range headers {
+		switch strings.ToLower(name) {
+		case "cookie":
+			cookieHeader = value
+		case "x-goog-authuser":
+			api.authUser = value
+		}
+	}
+	
+	cookies := parseCookieHeader(cookieHeader)
+	if len(cookies) == 0 {
+		api.LogDebug("Headers file %s has no cookies", path)
+		return false
+	}
+	
+	ytMusicURL, _ := url.Parse(ytMusicOrigin)
+	api.client.Jar.SetCookies(ytMusicURL, cookies)
+	
+	if api.getSAPISID() == "" {
+		api.LogDebug("Headers file %s has no SAPISID cookie", path)
+		return false
+	}
+	
+	api.IsLoggedIn = true
+	api.LogDebug("Imported %d cookies from %s", len(cookies), path)
+	return true
+}
+
+// loadOAuthToken reads a `ytmusicapi oauth` token file
+func (api *YouTubeMusicAPI) loadOAuthToken(path string) bool {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			api.LogDebug("Error reading OAuth file %s: %v", path, err)
+		}
+		return false
+	}
+	
+	var token oauthToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		api.LogDebug("Error parsing OAuth file %s: %v", path, err)
+		return false
+	}
+	
+	if token.AccessToken == "" && token.RefreshToken == "" {
+		api.LogDebug("OAuth file %s has no tokens", path)
+		return false
+	}
+	
+	token.path = path
+	api.oauth = &token
+	api.IsLoggedIn = true
+	api.LogDebug("Imported OAuth token from %s", path)
+	return true
+}
+
+// oauthAccessToken returns a usable OAuth access token, refreshing it when expired
+func (api *YouTubeMusicAPI) oauthAccessToken() string {
+	if api.oauth == nil {
+		return ""
+	}
+	
+	if api.oauth.ExpiresAt > 0 && time.Now().Unix() > api.oauth.ExpiresAt-60 {
+		if err := api.refreshOAuthToken(); err != nil {
+			api.LogDebug("OAuth token refresh failed: %v", err)
+		}
+	}
+	
+	return api.oauth.AccessToken
+}
+
+// refreshOAuthToken exchanges the refresh token for a new access token using client_secret.json
+func (api *YouTubeMusicAPI) refreshOAuthToken() error {
+	if api.oauth.RefreshToken == "" {
+		return fmt.Errorf("no refresh token")
+	}
+	
+	clientID, clientSecret, err := api.loadClientSecret()
+	if err != nil {
+		return err
+	}
+	
+	ctx, cancel := context.WithTimeout(context.Background(), shortTimeout)
+	defer cancel()
+	form := url.Values{
+		"client_id":     {clientID},
+		"client_secret": {clientSecret},
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {api.oauth.RefreshToken},
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", "https://oauth2.googleapis.com/token", strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	
+	resp, err := api.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+	
+	var refreshed oauthToken
+	if err := json.NewDecoder(resp.Body).Decode(&refreshed); err != nil {
+		return err
+	}
+	
+	api.oauth.AccessToken = refreshed.AccessToken
+	api.oauth.ExpiresIn = refreshed.ExpiresIn
+	api.oauth.ExpiresAt = time.Now().Unix() + refreshed.ExpiresIn
+	api.LogDebug("Refreshed OAuth token, expires in %d seconds", refreshed.ExpiresIn)
+	
+	// Write the new token back so ytmusicapi and the bridge see it too
+	if err := api.saveOAuthToken(); err != nil {
+		api.LogDebug("Could not save the refreshed OAuth token: %v", err)
+	}
+	
+	return nil
+}
+
+// saveOAuthToken writes a refreshed token into the file it was loaded from,
+// keeping the fields ytmusicapi wrote that oauthToken doesn't model
+func (api *YouTubeMusicAPI) saveOAuthToken() error {
+	fields := map[string]interface{}{}
+	if data, err := os.ReadFile(api.oauth.path); err == nil {
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return fmt.Errorf("could not parse %s: %v", filepath.Base(api.oauth.path), err)
+		}
+	}
+	fields["access_token"] = api.oauth.AccessToken
+	fields["expires_in"] = api.oauth.ExpiresIn
+	fields["expires_at"] = api.oauth.ExpiresAt
+	
+	data, err := json.MarshalIndent(fields, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(api.oauth.path, data, 0600)
+}
+
+// loadClientSecret reads the OAuth client credentials downloaded from Google Cloud Console
+func (api *YouTubeMusicAPI) loadClientSecret() (string, string, error) {
+	data, err := os.ReadFile(filepath.Join(api.configPath, "client_secret.json"))
+	if err != nil {
+		return "", "", fmt.Errorf("client_secret.json not found: %v", err)
+	}
+	
+	// Accept both the Console download format and a bare {client_id, client_secret} object
+	var credentials struct {
+		Installed *struct {
+			ClientID     string `json:"client_id"`
+			ClientSecret string `json:"client_secret"`
+		} `json:"installed"`
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+	}
+	if err := json.Unmarshal(data, &credentials); err != nil {
+		return "", "", fmt.Errorf("invalid client_secret.json: %v", err)
+	}
+	
+	if credentials.Installed != nil {
+		return credentials.Installed.ClientID, credentials.Installed.ClientSecret, nil
+	}
+	return credentials.ClientID, credentials.ClientSecret, nil
+}
+
+// parseCookieHeader splits a "name=value; name2=value2" Cookie header into cookies
+func parseCookieHeader(header string) []*http.Cookie {
+	var cookies []*http.Cookie
+	for _, part := range strings.Split(header, ";") {
+		name, value, found := strings.Cut(strings.TrimSpace(part), "=")
+		if !found || name == "" {
+			continue
+		}
+		cookies = append(cookies, &http.Cookie{
+			Name:   name,
+			Value:  value,
+			Domain: ".youtube.com",
+			Path:   "/",
+			Secure: true,
+		})
+	}
+	return cookies
 }
@@ -0,0 +1,196 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// MusicBackend is a source of search results and playlists
+type MusicBackend interface {
+	Name() string
+	IsAvailable() bool
+	Search(ctx context.Context, query string) ([]Track, error)
+	GetPlaylists(ctx context.Context) ([]Playlist, error)
+	GetPlaylistTracks(ctx context.Context, playlistID string) ([]Track, error)
+}
+
+// Backend names accepted by SetBackends
+const (
+	BackendNative = "native" // Innertube requests made by this client
+	BackendBridge = "bridge" // The Python bridge to ytmusicapi
+	BackendMock   = "mock"   // Made-up demo data, only used when asked for
+)
+
+// BackendNames lists the backends SetBackends accepts
+var BackendNames = []string{BackendNative, BackendBridge, BackendMock}
+
+// DefaultBackends is the order backends are tried in unless the config says otherwise
+var DefaultBackends = []string{BackendNative, BackendBridge}
+
+// errNoSAPISID means a native request needs the SAPISID cookie to be signed
+var errNoSAPISID = errors.New("signed innertube requests need the SAPISID cookie")
+
+// nativeBackend serves requests through innertube
+type nativeBackend struct {
+	api *YouTubeMusicAPI
+}
+
+func (b nativeBackend) Name() string      { return BackendNative }
+func (b nativeBackend) IsAvailable() bool { return true }
+
+func (b nativeBackend) Search(ctx context.Context, query string) ([]Track, error) {
+	return b.api.searchNative(ctx, query)
+}
+
+func (b nativeBackend) GetPlaylists(ctx context.Context) ([]Playlist, error) {
+	if b.api.getSAPISID() == "" {
+		return nil, errNoSAPISID
+	}
+	return b.api.getLibraryPlaylistsNative(ctx)
+}
+
+func (b nativeBackend) GetPlaylistTracks(ctx context.Context, playlistID string) ([]Track, error) {
+	if b.api.getSAPISID() == "" {
+		return nil, errNoSAPISID
+	}
+	return b.api.getPlaylistTracksNative(ctx, playlistID)
+}
+
+// Name identifies the Python bridge as a backend
+func (pb *PythonBridge) Name() string { return BackendBridge }
+
+// mockBackend serves demo data, for trying the interface without an account
+type mockBackend struct{}
+
+func (mockBackend) Name() string      { return BackendMock }
+func (mockBackend) IsAvailable() bool { return true }
+
+func (mockBackend) Search(ctx context.Context, query string) ([]Track, error) {
+	return []Track{
+		{ID: "dQw4w9WgXcQ", TrackTitle: "Demo: " + query, Artist: "Demo data", Album: "Mock backend", Duration: 213},
+		{ID: "xvFZjo5PgG0", TrackTitle: "Demo: " + query + " (live)", Artist: "Demo data", Album: "Mock backend", Duration: 240},
+	}, nil
+}
+
+func (mockBackend) GetPlaylists(ctx context.Context) ([]Playlist, error) {
+	return []Playlist{
+		{ID: "MOCK_PLAYLIST", PlaylistTitle: "Demo Playlist", PlaylistDesc: "Demo data from the mock backend", TrackCount: 2, Author: "Demo data"},
+	}, nil
+}
+
+func (m mockBackend) GetPlaylistTracks(ctx context.Context, playlistID string) ([]Track, error) {
+	return m.Search(ctx, "playlist")
+}
+
+// backendChain tries backends in order, remembering which one served the last request
+type backendChain struct {
+	mu       sync.Mutex
+	backends []MusicBackend
+	last     string
+}
+
+// SetBackends sets the order backends are tried in, by name
+func (api *YouTubeMusicAPI) SetBackends(names []string) error {
+	var backends []MusicBackend
+	for _, name := range names {
+		switch name {
+		case BackendNative:
+			backends = append(backends, nativeBackend{api: api})
+		case BackendBridge:
+			backends = append(backends, api.bridge)
+		case BackendMock:
+			backends = append(backends, mockBackend{})
+		default:
+			return fmt.Errorf("unknown backend %q", name)
+		}
+	}
+
+	api.backends.mu.Lock()
+	defer api.backends.mu.Unlock()
+	api.backends.backends = backends
+	api.LogDebug("Backend order: %s", strings.Join(names, ", "))
+	return nil
+}
+
+// Backend returns the name of the backend that served the last search or playlist
+// request, "cache" if it came from the response cache, or "" before the first one
+func (api *YouTubeMusicAPI) Backend() string {
+	api.backends.mu.Lock()
+	defer api.backends.mu.Unlock()
+	return api.backends.last
+}
+
+// servedBy records where the last response came from
+func (api *YouTubeMusicAPI) servedBy(name string) {
+	api.backends.mu.Lock()
+	defer api.backends.mu.Unlock()
+	api.backends.last = name
+}
+
+// withBackends runs fn on each available backend in order until one succeeds
+func withBackends[T any](ctx context.Context, api *YouTubeMusicAPI, what string, fn func(MusicBackend) (T, error)) (T, error) {
+	api.backends.mu.Lock()
+	backends := api.backends.backends
+	api.backends.mu.Unlock()
+
+	var zero T
+	var lastErr error
+	for _, backend := range backends {
+		if !backend.IsAvailable() {
+			continue
+		}
+
+		result, err := fn(backend)
+		if err == nil {
+			api.LogDebug("%s served by the %s backend", what, backend.Name())
+			api.servedBy(backend.Name())
+			return result, nil
+		}
+		api.LogDebug("%s failed on the %s backend: %v", what, backend.Name(), err)
+		if ctx.Err() != nil {
+			// Cancelled, not failed: the next backend wouldn't get further
+			return zero, ctx.Err()
+		}
+		lastErr = err
+	}
+
+	// Offer to install ytmusicapi rather than failing with whatever native said
+	if api.bridge.NeedsSetup() && api.usesBackend(BackendBridge) {
+		return zero, ErrBridgeSetup
+	}
+	if lastErr == nil {
+		return zero, withKind(ErrBackendUnavailable, "no backend available for %s", strings.ToLower(what))
+	}
+	return zero, lastErr
+}
+
+// usesBackend reports whether a backend is in the configured order
+func (api *YouTubeMusicAPI) usesBackend(name string) bool {
+	api.backends.mu.Lock()
+	defer api.backends.mu.Unlock()
+	for _, backend := range api.backends.backends {
+		if backend.Name() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// pagesNatively reports whether playlists are fetched page by page through innertube,
+// which is the case when native comes before any other backend that can serve them
+func (api *YouTubeMusicAPI) pagesNatively() bool {
+	api.backends.mu.Lock()
+	backends := api.backends.backends
+	api.backends.mu.Unlock()
+
+	for _, backend := range backends {
+		if !backend.IsAvailable() {
+			continue
+		}
+		return backend.Name() == BackendNative && api.getSAPISID() != ""
+	}
+	return false
+}
@@ -1,21 +1,35 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/url"
 	"os"
 	"os/exec"
 	"path/filepath"
 	"strings"
+	"sync"
+
+	"ytmusic/internal/paths"
 )
 
 // PythonBridge handles communication with the Python ytmusicapi bridge
 type PythonBridge struct {
 	pythonPath string
 	scriptPath string
+	configPath string // Where the script finds the OAuth and headers files
 	logger     func(format string, v ...interface{})
 	api        *YouTubeMusicAPI // Reference to the API for cookie access
+	mu         sync.Mutex       // Serializes requests to the long-lived process
+	proc       *bridgeProcess   // Long-lived bridge, nil until the first command
+	oneShot    bool             // Run a process per command, after the long-lived one kept failing
+	
+	systemPython string     // Python found on PATH, used to create the virtualenv
+	venvDir      string     // Virtualenv created by SetupBridge
+	moduleMu     sync.Mutex // Guards the ytmusicapi check
+	moduleOK     *bool      // Whether ytmusicapi imports, nil until checked
 }
 
 // BridgeResponse represents the response from the Python bridge
@@ -37,13 +51,73 @@ type PlaylistsResponse struct {
 	Playlists []BridgePlaylist `json:"playlists,omitempty"`
 }
 
+// AlbumsResponse represents library albums from the bridge
+type AlbumsResponse struct {
+	BridgeResponse
+	Albums []BridgeAlbum `json:"albums,omitempty"`
+}
+
+// AlbumResponse represents a single album with its tracks from the bridge
+type AlbumResponse struct {
+	BridgeResponse
+	Album BridgeAlbum `json:"album"`
+}
+
+// ArtistResponse represents a single artist page from the bridge
+type ArtistResponse struct {
+	BridgeResponse
+	Artist BridgeArtist `json:"artist"`
+}
+
+// LyricsResponse represents a track's lyrics from the bridge
+type LyricsResponse struct {
+	BridgeResponse
+	Lyrics BridgeLyrics `json:"lyrics"`
+}
+
+// EditResponse represents the result of a library edit from the bridge
+type EditResponse struct {
+	BridgeResponse
+	PlaylistID string `json:"playlist_id,omitempty"`
+}
+
+// ShelvesResponse represents a page of shelves, such as the home feed, from the bridge
+type ShelvesResponse struct {
+	BridgeResponse
+	Shelves []BridgeShelf `json:"shelves,omitempty"`
+}
+
+// PodcastResponse represents a single podcast with its episodes from the bridge
+type PodcastResponse struct {
+	BridgeResponse
+	Podcast BridgePodcast `json:"podcast"`
+}
+
+// PodcastsResponse represents library podcasts from the bridge
+type PodcastsResponse struct {
+	BridgeResponse
+	Podcasts []BridgePodcast `json:"podcasts,omitempty"`
+}
+
+// ArtistsResponse represents library artists or subscriptions from the bridge
+type ArtistsResponse struct {
+	BridgeResponse
+	Artists []BridgeArtist `json:"artists,omitempty"`
+}
+
 // BridgeTrack represents a track from the Python bridge
 type BridgeTrack struct {
-	ID        string `json:"id"`
-	Title     string `json:"title"`
-	Artist    string `json:"artist"`
-	Duration  int    `json:"duration"`
-	Thumbnail string `json:"thumbnail"`
+	ID         string `json:"id"`
+	Title      string `json:"title"`
+	Artist     string `json:"artist"`
+	Duration   int    `json:"duration"`
+	Thumbnail  string `json:"thumbnail"`
+	Album      string `json:"album"`
+	AlbumID    string `json:"album_id"`
+	Year       string `json:"year"`
+	ArtistID   string `json:"artist_id"`
+	Explicit   bool   `json:"explicit"`
+	SetVideoID string `json:"set_video_id"`
 }
 
 // BridgePlaylist represents a playlist from the Python bridge
@@ -55,6 +129,186 @@ type BridgePlaylist struct {
 	Author      string `json:"author"`
 }
 
+// BridgeShelf represents a shelf from the Python bridge
+type BridgeShelf struct {
+	Title string             `json:"title"`
+	Items []BridgeBrowseItem `json:"items"`
+}
+
+// BridgeBrowseItem is a shelf entry; exactly one of its fields is set
+type BridgeBrowseItem struct {
+	Track    *BridgeTrack    `json:"track,omitempty"`
+	Album    *BridgeAlbum    `json:"album,omitempty"`
+	Playlist *BridgePlaylist `json:"playlist,omitempty"`
+	Artist   *BridgeArtist   `json:"artist,omitempty"`
+	Podcast  *BridgePodcast  `json:"podcast,omitempty"`
+	Episode  *BridgeEpisode  `json:"episode,omitempty"`
+}
+
+// BridgeLyrics represents lyrics from the Python bridge
+type BridgeLyrics struct {
+	Text   string            `json:"text"`
+	Source string            `json:"source"`
+	Lines  []BridgeLyricLine `json:"lines,omitempty"`
+}
+
+// BridgeLyricLine represents one timed lyrics line from the Python bridge
+type BridgeLyricLine struct {
+	Text  string `json:"text"`
+	Start int    `json:"start"`
+	End   int    `json:"end"`
+}
+
+// toTrack converts a bridge track to an API track
+func (t BridgeTrack) toTrack() Track {
+	return Track{
+		ID:           t.ID,
+		TrackTitle:   t.Title,
+		Artist:       t.Artist,
+		Duration:     t.Duration,
+		Album:        t.Album,
+		AlbumID:      t.AlbumID,
+		Year:         t.Year,
+		ArtistID:     t.ArtistID,
+		ThumbnailURL: t.Thumbnail,
+		Explicit:     t.Explicit,
+		SetVideoID:   t.SetVideoID,
+	}
+}
+
+// toTracks converts a list of bridge tracks to API tracks
+func toTracks(bridgeTracks []BridgeTrack) []Track {
+	tracks := make([]Track, len(bridgeTracks))
+	for i, bridgeTrack := range bridgeTracks {
+		tracks[i] = bridgeTrack.toTrack()
+	}
+	return tracks
+}
+
+// toAlbum converts a bridge album to an API album
+func (a BridgeAlbum) toAlbum() Album {
+	return Album{
+		ID:         a.ID,
+		AlbumTitle: a.Title,
+		Artist:     a.Artist,
+		Year:       a.Year,
+		Tracks:     toTracks(a.Tracks),
+	}
+}
+
+// toPlaylist converts a bridge playlist to an API playlist
+func (p BridgePlaylist) toPlaylist() Playlist {
+	return Playlist{
+		ID:            p.ID,
+		PlaylistTitle: p.Title,
+		PlaylistDesc:  p.Description,
+		TrackCount:    p.TrackCount,
+		Author:        p.Author,
+	}
+}
+
+// toBrowseItem converts a bridge shelf entry to an API browse item
+func (i BridgeBrowseItem) toBrowseItem() (BrowseItem, bool) {
+	switch {
+	case i.Track != nil:
+		return i.Track.toTrack(), true
+	case i.Album != nil:
+		return i.Album.toAlbum(), true
+	case i.Playlist != nil:
+		return i.Playlist.toPlaylist(), true
+	case i.Artist != nil:
+		return i.Artist.toArtist(), true
+	case i.Podcast != nil:
+		return i.Podcast.toPodcast(), true
+	case i.Episode != nil:
+		return i.Episode.toEpisode(), true
+	}
+	return nil, false
+}
+
+// toArtist converts a bridge artist to an API artist
+func (a BridgeArtist) toArtist() Artist {
+	artist := Artist{
+		ID:       a.ID,
+		Name:     a.Name,
+		Subtitle: a.Subtitle,
+		RadioID:  a.RadioID,
+		TopSongs: toTracks(a.TopSongs),
+	}
+	for _, album := range a.Albums {
+		artist.Albums = append(artist.Albums, album.toAlbum())
+	}
+	for _, single := range a.Singles {
+		artist.Singles = append(artist.Singles, single.toAlbum())
+	}
+	return artist
+}
+
+// BridgeAlbum represents an album from the Python bridge
+type BridgeAlbum struct {
+	ID     string        `json:"id"`
+	Title  string        `json:"title"`
+	Artist string        `json:"artist"`
+	Year   string        `json:"year"`
+	Tracks []BridgeTrack `json:"tracks,omitempty"`
+}
+
+// BridgeArtist represents an artist from the Python bridge
+type BridgeArtist struct {
+	ID       string        `json:"id"`
+	Name     string        `json:"name"`
+	Subtitle string        `json:"subtitle"`
+	RadioID  string        `json:"radio_id,omitempty"`
+	TopSongs []BridgeTrack `json:"top_songs,omitempty"`
+	Albums   []BridgeAlbum `json:"albums,omitempty"`
+	Singles  []BridgeAlbum `json:"singles,omitempty"`
+}
+
+// BridgePodcast represents a podcast show from the Python bridge
+type BridgePodcast struct {
+	ID          string          `json:"id"`
+	Title       string          `json:"title"`
+	Author      string          `json:"author"`
+	Description string          `json:"description,omitempty"`
+	Episodes    []BridgeEpisode `json:"episodes,omitempty"`
+}
+
+// BridgeEpisode represents a podcast episode from the Python bridge
+type BridgeEpisode struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Podcast   string `json:"podcast"`
+	PodcastID string `json:"podcast_id"`
+	Date      string `json:"date"`
+	Duration  int    `json:"duration"`
+}
+
+// toPodcast converts a bridge podcast to an API podcast
+func (p BridgePodcast) toPodcast() Podcast {
+	podcast := Podcast{
+		ID:           p.ID,
+		PodcastTitle: p.Title,
+		Author:       p.Author,
+		Summary:      p.Description,
+	}
+	for _, episode := range p.Episodes {
+		podcast.Episodes = append(podcast.Episodes, episode.toEpisode())
+	}
+	return podcast
+}
+
+// toEpisode converts a bridge episode to an API episode
+func (e BridgeEpisode) toEpisode() Episode {
+	return Episode{
+		ID:           e.ID,
+		EpisodeTitle: e.Title,
+		Podcast:      e.Podcast,
+		PodcastID:    e.PodcastID,
+		Date:         e.Date,
+		Duration:     e.Duration,
+	}
+}
+
 // NewPythonBridge creates a new Python bridge instance
 func NewPythonBridge(configPath string, logger func(format string, v ...interface{})) *PythonBridge {
 	// Try to find Python executable
@@ -92,10 +346,20 @@ func NewPythonBridge(configPath string, logger func(format string, v ...interfac
 		}
 	}
 	
+	// A virtualenv made by SetupBridge takes precedence over the system Python
+	systemPython := pythonPath
+	venvDir := filepath.Join(paths.Data(), "venv")
+	if _, err := os.Stat(venvPython(venvDir)); err == nil {
+		pythonPath = venvPython(venvDir)
+	}
+	
 	return &PythonBridge{
-		pythonPath: pythonPath,
-		scriptPath: scriptPath,
-		logger:     logger,
+		pythonPath:   pythonPath,
+		scriptPath:   scriptPath,
+		configPath:   configPath,
+		logger:       logger,
+		systemPython: systemPython,
+		venvDir:      venvDir,
 	}
 }
 
@@ -118,7 +382,7 @@ func (pb *PythonBridge) IsAvailable() bool {
 		return false
 	}
 	
-	return true
+	return pb.hasYTMusicAPI()
 }
 
 // log helper function
@@ -128,6 +392,11 @@ func (pb *PythonBridge) log(format string, v ...interface{}) {
 	}
 }
 
+// env is the environment the script runs in, telling it where the config directory is
+func (pb *PythonBridge) env() []string {
+	return append(os.Environ(), "YTMUSIC_CONFIG_DIR="+pb.configPath)
+}
+
 // getCookie extracts the __Secure-3PSID cookie value from the API
 func (pb *PythonBridge) getCookie() string {
 	if pb.api == nil || !pb.api.IsLoggedIn {
@@ -147,26 +416,65 @@ func (pb *PythonBridge) getCookie() string {
 	return ""
 }
 
+// redactArgs returns bridge arguments fit for the log, with the cookie hidden
+func redactArgs(args []string) []string {
+	shown := append([]string{}, args...)
+	for i := 0; i+1 < len(shown); i++ {
+		if shown[i] == "--cookie" {
+			shown[i+1] = "[redacted]"
+		}
+	}
+	return shown
+}
+
 // runCommand executes a Python bridge command with cookie authentication
-func (pb *PythonBridge) runCommand(args []string) ([]byte, error) {
+func (pb *PythonBridge) runCommand(ctx context.Context, args []string) ([]byte, error) {
 	if !pb.IsAvailable() {
-		return nil, fmt.Errorf("Python bridge not available")
+		return nil, withKind(ErrBackendUnavailable, "Python bridge not available")
 	}
 	
-	cmdArgs := []string{pb.scriptPath}
-	cmdArgs = append(cmdArgs, args...)
-	
-	// Add cookie if available
+	// Add cookie if available, without touching the caller's slice
+	args = append([]string{}, args...)
 	if cookie := pb.getCookie(); cookie != "" {
-		cmdArgs = append(cmdArgs, "--cookie", cookie)
+		args = append(args, "--cookie", cookie)
+	}
+	if pb.api != nil && pb.api.onBehalfOf != "" {
+		args = append(args, "--user", pb.api.onBehalfOf)
+	}
+	if pb.api != nil {
+		args = append(args, "--language", pb.api.language, "--location", pb.api.region)
 	}
 	
-	pb.log("Running Python bridge command: %s %s", pb.pythonPath, strings.Join(cmdArgs, " "))
+	pb.log("Running Python bridge command: %s", strings.Join(redactArgs(args), " "))
 	
-	cmd := exec.Command(pb.pythonPath, cmdArgs...)
+	// The long-lived bridge skips starting Python and authenticating on every call
+	pb.mu.Lock()
+	oneShot := pb.oneShot
+	pb.mu.Unlock()
+	if !oneShot {
+		output, err := pb.runPersistent(ctx, args)
+		if err == nil {
+			pb.log("Python bridge output length: %d bytes", len(output))
+			return output, nil
+		}
+		if errors.Is(err, errBridgeTimeout) {
+			return nil, fmt.Errorf("Python bridge command failed: %v", err)
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		pb.log("Persistent Python bridge failed (%v), running the command on its own", err)
+	}
+	
+	cmdArgs := append([]string{pb.scriptPath}, args...)
+	cmd := exec.CommandContext(ctx, pb.pythonPath, cmdArgs...)
+	cmd.Env = pb.env()
 	output, err := cmd.Output()
 	
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
 		if exitError, ok := err.(*exec.ExitError); ok {
 			pb.log("Python bridge stderr: %s", string(exitError.Stderr))
 		}
@@ -178,10 +486,10 @@ func (pb *PythonBridge) runCommand(args []string) ([]byte, error) {
 }
 
 // Search searches for tracks using the Python bridge
-func (pb *PythonBridge) Search(query string) ([]Track, error) {
+func (pb *PythonBridge) Search(ctx context.Context, query string) ([]Track, error) {
 	args := []string{"search", "--query", query, "--filter", "songs", "--limit", "20"}
 	
-	output, err := pb.runCommand(args)
+	output, err := pb.runCommand(ctx, args)
 	if err != nil {
 		return nil, err
 	}
@@ -194,18 +502,13 @@ func (pb *PythonBridge) Search(query string) ([]Track, error) {
 	
 	if !response.Success {
 		pb.log("Search failed: %s", response.Error)
-		return nil, fmt.Errorf("search failed: %s", response.Error)
+		return nil, bridgeError("search", response.Error)
 	}
 	
 	// Convert bridge tracks to API tracks
 	tracks := make([]Track, len(response.Tracks))
 	for i, bridgeTrack := range response.Tracks {
-		tracks[i] = Track{
-			ID:         bridgeTrack.ID,
-			TrackTitle: bridgeTrack.Title,
-			Artist:     bridgeTrack.Artist,
-			Duration:   bridgeTrack.Duration,
-		}
+		tracks[i] = bridgeTrack.toTrack()
 	}
 	
 	pb.log("Search returned %d tracks", len(tracks))
@@ -213,10 +516,10 @@ func (pb *PythonBridge) Search(query string) ([]Track, error) {
 }
 
 // GetPlaylists gets user playlists using the Python bridge
-func (pb *PythonBridge) GetPlaylists() ([]Playlist, error) {
+func (pb *PythonBridge) GetPlaylists(ctx context.Context) ([]Playlist, error) {
 	args := []string{"playlists", "--limit", "25"}
 	
-	output, err := pb.runCommand(args)
+	output, err := pb.runCommand(ctx, args)
 	if err != nil {
 		return nil, err
 	}
@@ -229,19 +532,13 @@ func (pb *PythonBridge) GetPlaylists() ([]Playlist, error) {
 	
 	if !response.Success {
 		pb.log("Get playlists failed: %s", response.Error)
-		return nil, fmt.Errorf("get playlists failed: %s", response.Error)
+		return nil, bridgeError("get playlists", response.Error)
 	}
 	
 	// Convert bridge playlists to API playlists
 	playlists := make([]Playlist, len(response.Playlists))
 	for i, bridgePlaylist := range response.Playlists {
-		playlists[i] = Playlist{
-			ID:            bridgePlaylist.ID,
-			PlaylistTitle: bridgePlaylist.Title,
-			PlaylistDesc:  bridgePlaylist.Description,
-			TrackCount:    bridgePlaylist.TrackCount,
-			Author:        bridgePlaylist.Author,
-		}
+		playlists[i] = bridgePlaylist.toPlaylist()
 	}
 	
 	pb.log("Get playlists returned %d playlists", len(playlists))
@@ -249,10 +546,11 @@ func (pb *PythonBridge) GetPlaylists() ([]Playlist, error) {
 }
 
 // GetPlaylistTracks gets tracks from a playlist using the Python bridge
-func (pb *PythonBridge) GetPlaylistTracks(playlistID string) ([]Track, error) {
-	args := []string{"playlist_tracks", "--playlist-id", playlistID, "--limit", "100"}
+func (pb *PythonBridge) GetPlaylistTracks(ctx context.Context, playlistID string) ([]Track, error) {
+	// No limit: the bridge follows continuations itself
+	args := []string{"playlist_tracks", "--playlist-id", playlistID, "--limit", "0"}
 	
-	output, err := pb.runCommand(args)
+	output, err := pb.runCommand(ctx, args)
 	if err != nil {
 		return nil, err
 	}
@@ -265,18 +563,13 @@ func (pb *PythonBridge) GetPlaylistTracks(playlistID string) ([]Track, error) {
 	
 	if !response.Success {
 		pb.log("Get playlist tracks failed: %s", response.Error)
-		return nil, fmt.Errorf("get playlist tracks failed: %s", response.Error)
+		return nil, bridgeError("get playlist tracks", response.Error)
 	}
 	
 	// Convert bridge tracks to API tracks
 	tracks := make([]Track, len(response.Tracks))
 	for i, bridgeTrack := range response.Tracks {
-		tracks[i] = Track{
-			ID:         bridgeTrack.ID,
-			TrackTitle: bridgeTrack.Title,
-			Artist:     bridgeTrack.Artist,
-			Duration:   bridgeTrack.Duration,
-		}
+		tracks[i] = bridgeTrack.toTrack()
 	}
 	
 	pb.log("Get playlist tracks returned %d tracks", len(tracks))
@@ -284,10 +577,10 @@ func (pb *PythonBridge) GetPlaylistTracks(playlistID string) ([]Track, error) {
 }
 
 // GetLikedSongs gets user's liked songs using the Python bridge
-func (pb *PythonBridge) GetLikedSongs() ([]Track, error) {
+func (pb *PythonBridge) GetLikedSongs(ctx context.Context) ([]Track, error) {
 	args := []string{"liked_songs", "--limit", "100"}
 	
-	output, err := pb.runCommand(args)
+	output, err := pb.runCommand(ctx, args)
 	if err != nil {
 		return nil, err
 	}
@@ -300,20 +593,453 @@ func (pb *PythonBridge) GetLikedSongs() ([]Track, error) {
 	
 	if !response.Success {
 		pb.log("Get liked songs failed: %s", response.Error)
-		return nil, fmt.Errorf("get liked songs failed: %s", response.Error)
+		return nil, bridgeError("get liked songs", response.Error)
 	}
 	
 	// Convert bridge tracks to API tracks
 	tracks := make([]Track, len(response.Tracks))
 	for i, bridgeTrack := range response.Tracks {
-		tracks[i] = Track{
-			ID:         bridgeTrack.ID,
-			TrackTitle: bridgeTrack.Title,
-			Artist:     bridgeTrack.Artist,
-			Duration:   bridgeTrack.Duration,
-		}
+		tracks[i] = bridgeTrack.toTrack()
 	}
 	
 	pb.log("Get liked songs returned %d tracks", len(tracks))
 	return tracks, nil
 }
+
+// GetHome gets the home feed's shelves using the Python bridge
+func (pb *PythonBridge) GetHome(ctx context.Context) ([]Shelf, error) {
+	return pb.runShelvesCommand(ctx, []string{"home", "--limit", "6"})
+}
+
+// GetCharts gets a country's chart shelves using the Python bridge
+func (pb *PythonBridge) GetCharts(ctx context.Context, country string) ([]Shelf, error) {
+	return pb.runShelvesCommand(ctx, []string{"charts", "--country", country})
+}
+
+// runShelvesCommand runs a bridge command that returns a page of shelves
+func (pb *PythonBridge) runShelvesCommand(ctx context.Context, args []string) ([]Shelf, error) {
+	output, err := pb.runCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	
+	var response ShelvesResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		pb.log("Error unmarshaling %s response: %v", args[0], err)
+		return nil, fmt.Errorf("failed to parse %s response: %v", args[0], err)
+	}
+	
+	if !response.Success {
+		pb.log("%s failed: %s", args[0], response.Error)
+		return nil, bridgeError(args[0], response.Error)
+	}
+	
+	var shelves []Shelf
+	for _, bridgeShelf := range response.Shelves {
+		shelf := Shelf{Title: bridgeShelf.Title}
+		for _, bridgeItem := range bridgeShelf.Items {
+			if item, ok := bridgeItem.toBrowseItem(); ok {
+				shelf.Items = append(shelf.Items, item)
+			}
+		}
+		if len(shelf.Items) > 0 {
+			shelves = append(shelves, shelf)
+		}
+	}
+	
+	pb.log("%s returned %d shelves", args[0], len(shelves))
+	return shelves, nil
+}
+
+// GetHistory gets the account's recently played tracks using the Python bridge
+func (pb *PythonBridge) GetHistory(ctx context.Context) ([]Track, error) {
+	args := []string{"history"}
+	
+	output, err := pb.runCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	
+	var response SearchResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		pb.log("Error unmarshaling history response: %v", err)
+		return nil, fmt.Errorf("failed to parse history response: %v", err)
+	}
+	
+	if !response.Success {
+		pb.log("Get history failed: %s", response.Error)
+		return nil, bridgeError("get history", response.Error)
+	}
+	
+	tracks := toTracks(response.Tracks)
+	pb.log("Get history returned %d tracks", len(tracks))
+	return tracks, nil
+}
+
+// GetLibraryAlbums gets the user's saved albums using the Python bridge
+func (pb *PythonBridge) GetLibraryAlbums(ctx context.Context) ([]Album, error) {
+	return pb.getAlbums(ctx, []string{"library_albums", "--limit", "100"})
+}
+
+// GetNewReleases gets newly released albums using the Python bridge
+func (pb *PythonBridge) GetNewReleases(ctx context.Context) ([]Album, error) {
+	return pb.getAlbums(ctx, []string{"new_releases"})
+}
+
+// getAlbums runs a bridge command that returns a list of albums
+func (pb *PythonBridge) getAlbums(ctx context.Context, args []string) ([]Album, error) {
+	output, err := pb.runCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	
+	var response AlbumsResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		pb.log("Error unmarshaling %s response: %v", args[0], err)
+		return nil, fmt.Errorf("failed to parse %s response: %v", args[0], err)
+	}
+	
+	if !response.Success {
+		pb.log("%s failed: %s", args[0], response.Error)
+		return nil, bridgeError(args[0], response.Error)
+	}
+	
+	// Convert bridge albums to API albums
+	albums := make([]Album, len(response.Albums))
+	for i, bridgeAlbum := range response.Albums {
+		albums[i] = bridgeAlbum.toAlbum()
+	}
+	
+	pb.log("%s returned %d albums", args[0], len(albums))
+	return albums, nil
+}
+
+// GetAlbum gets an album and its tracks using the Python bridge
+func (pb *PythonBridge) GetAlbum(ctx context.Context, browseID string) (*Album, error) {
+	args := []string{"album", "--browse-id", browseID}
+	
+	output, err := pb.runCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	
+	var response AlbumResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		pb.log("Error unmarshaling album response: %v", err)
+		return nil, fmt.Errorf("failed to parse album response: %v", err)
+	}
+	
+	if !response.Success {
+		pb.log("Get album failed: %s", response.Error)
+		return nil, bridgeError("get album", response.Error)
+	}
+	
+	album := response.Album.toAlbum()
+	
+	pb.log("Get album returned %d tracks", len(album.Tracks))
+	return &album, nil
+}
+
+// SearchPodcasts searches for podcasts and episodes using the Python bridge
+func (pb *PythonBridge) SearchPodcasts(ctx context.Context, query string) ([]Shelf, error) {
+	return pb.runShelvesCommand(ctx, []string{"search_podcasts", "--query", query, "--limit", "20"})
+}
+
+// GetPodcast gets a podcast with its episodes using the Python bridge
+func (pb *PythonBridge) GetPodcast(ctx context.Context, browseID string) (*Podcast, error) {
+	args := []string{"podcast", "--browse-id", browseID, "--limit", "0"}
+	
+	output, err := pb.runCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	
+	var response PodcastResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		pb.log("Error unmarshaling podcast response: %v", err)
+		return nil, fmt.Errorf("failed to parse podcast response: %v", err)
+	}
+	
+	if !response.Success {
+		pb.log("Get podcast failed: %s", response.Error)
+		return nil, bridgeError("get podcast", response.Error)
+	}
+	
+	podcast := response.Podcast.toPodcast()
+	
+	pb.log("Get podcast returned %d episodes", len(podcast.Episodes))
+	return &podcast, nil
+}
+
+// GetLibraryPodcasts gets the podcasts saved to the user's library using the Python bridge
+func (pb *PythonBridge) GetLibraryPodcasts(ctx context.Context) ([]Podcast, error) {
+	args := []string{"library_podcasts", "--limit", "100"}
+	
+	output, err := pb.runCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	
+	var response PodcastsResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		pb.log("Error unmarshaling library podcasts response: %v", err)
+		return nil, fmt.Errorf("failed to parse library podcasts response: %v", err)
+	}
+	
+	if !response.Success {
+		pb.log("Get library podcasts failed: %s", response.Error)
+		return nil, bridgeError("get library podcasts", response.Error)
+	}
+	
+	podcasts := make([]Podcast, len(response.Podcasts))
+	for i, bridgePodcast := range response.Podcasts {
+		podcasts[i] = bridgePodcast.toPodcast()
+	}
+	
+	pb.log("Get library podcasts returned %d podcasts", len(podcasts))
+	return podcasts, nil
+}
+
+// GetUploadedSongs gets the user's uploaded songs using the Python bridge
+func (pb *PythonBridge) GetUploadedSongs(ctx context.Context) ([]Track, error) {
+	args := []string{"upload_songs", "--limit", "0"}
+	
+	output, err := pb.runCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	
+	var response SearchResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		pb.log("Error unmarshaling uploaded songs response: %v", err)
+		return nil, fmt.Errorf("failed to parse uploaded songs response: %v", err)
+	}
+	
+	if !response.Success {
+		pb.log("Get uploaded songs failed: %s", response.Error)
+		return nil, bridgeError("get uploaded songs", response.Error)
+	}
+	
+	tracks := toTracks(response.Tracks)
+	
+	pb.log("Get uploaded songs returned %d tracks", len(tracks))
+	return tracks, nil
+}
+
+// GetUploadedAlbums gets the albums of the user's uploads using the Python bridge
+func (pb *PythonBridge) GetUploadedAlbums(ctx context.Context) ([]Album, error) {
+	return pb.getAlbums(ctx, []string{"upload_albums", "--limit", "0"})
+}
+
+// GetUploadedArtists gets the artists of the user's uploads using the Python bridge
+func (pb *PythonBridge) GetUploadedArtists(ctx context.Context) ([]Artist, error) {
+	return pb.getArtists(ctx, "upload_artists")
+}
+
+// GetLibraryArtists gets the artists in the user's library using the Python bridge
+func (pb *PythonBridge) GetLibraryArtists(ctx context.Context) ([]Artist, error) {
+	return pb.getArtists(ctx, "library_artists")
+}
+
+// GetLibrarySubscriptions gets the user's subscribed artists using the Python bridge
+func (pb *PythonBridge) GetLibrarySubscriptions(ctx context.Context) ([]Artist, error) {
+	return pb.getArtists(ctx, "library_subscriptions")
+}
+
+// getArtists runs a bridge command that returns a list of artists
+func (pb *PythonBridge) getArtists(ctx context.Context, command string) ([]Artist, error) {
+	args := []string{command, "--limit", "100"}
+	
+	output, err := pb.runCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	
+	var response ArtistsResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		pb.log("Error unmarshaling %s response: %v", command, err)
+		return nil, fmt.Errorf("failed to parse %s response: %v", command, err)
+	}
+	
+	if !response.Success {
+		pb.log("%s failed: %s", command, response.Error)
+		return nil, bridgeError(command, response.Error)
+	}
+	
+	// Convert bridge artists to API artists
+	artists := make([]Artist, len(response.Artists))
+	for i, bridgeArtist := range response.Artists {
+		artists[i] = bridgeArtist.toArtist()
+	}
+	
+	pb.log("%s returned %d artists", command, len(artists))
+	return artists, nil
+}
+
+// GetArtist gets an artist page using the Python bridge
+func (pb *PythonBridge) GetArtist(ctx context.Context, channelID string) (*Artist, error) {
+	args := []string{"artist", "--browse-id", channelID}
+	
+	output, err := pb.runCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	
+	var response ArtistResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		pb.log("Error unmarshaling artist response: %v", err)
+		return nil, fmt.Errorf("failed to parse artist response: %v", err)
+	}
+	
+	if !response.Success {
+		pb.log("Get artist failed: %s", response.Error)
+		return nil, bridgeError("get artist", response.Error)
+	}
+	
+	artist := response.Artist.toArtist()
+	
+	pb.log("Get artist returned %d songs, %d albums, %d singles", len(artist.TopSongs), len(artist.Albums), len(artist.Singles))
+	return &artist, nil
+}
+
+// GetWatchPlaylist gets the up-next tracks for a video or radio playlist using the Python bridge
+func (pb *PythonBridge) GetWatchPlaylist(ctx context.Context, videoID, playlistID string) ([]Track, error) {
+	args := []string{"watch_playlist", "--limit", "50"}
+	if videoID != "" {
+		args = append(args, "--video-id", videoID)
+	}
+	if playlistID != "" {
+		args = append(args, "--playlist-id", playlistID)
+	}
+	
+	output, err := pb.runCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	
+	var response SearchResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		pb.log("Error unmarshaling watch playlist response: %v", err)
+		return nil, fmt.Errorf("failed to parse watch playlist response: %v", err)
+	}
+	
+	if !response.Success {
+		pb.log("Get watch playlist failed: %s", response.Error)
+		return nil, bridgeError("get watch playlist", response.Error)
+	}
+	
+	tracks := toTracks(response.Tracks)
+	
+	pb.log("Get watch playlist returned %d tracks", len(tracks))
+	return tracks, nil
+}
+
+// GetLyrics gets a track's lyrics using the Python bridge
+func (pb *PythonBridge) GetLyrics(ctx context.Context, videoID string) (*Lyrics, error) {
+	args := []string{"lyrics", "--video-id", videoID}
+	
+	output, err := pb.runCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	
+	var response LyricsResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		pb.log("Error unmarshaling lyrics response: %v", err)
+		return nil, fmt.Errorf("failed to parse lyrics response: %v", err)
+	}
+	
+	if !response.Success {
+		pb.log("Get lyrics failed: %s", response.Error)
+		return nil, bridgeError("get lyrics", response.Error)
+	}
+	
+	lyrics := &Lyrics{
+		VideoID: videoID,
+		Text:    response.Lyrics.Text,
+		Source:  response.Lyrics.Source,
+	}
+	for _, line := range response.Lyrics.Lines {
+		lyrics.Lines = append(lyrics.Lines, LyricLine{Text: line.Text, Start: line.Start, End: line.End})
+	}
+	
+	return lyrics, nil
+}
+
+// CreatePlaylist creates a private playlist using the Python bridge
+func (pb *PythonBridge) CreatePlaylist(ctx context.Context, title, description string) (string, error) {
+	args := []string{"create_playlist", "--title", title, "--description", description}
+	
+	response, err := pb.runEditCommand(ctx, args)
+	if err != nil {
+		return "", err
+	}
+	
+	return response.PlaylistID, nil
+}
+
+// RenamePlaylist changes a playlist's title using the Python bridge
+func (pb *PythonBridge) RenamePlaylist(ctx context.Context, playlistID, title string) error {
+	_, err := pb.runEditCommand(ctx, []string{"rename_playlist", "--playlist-id", playlistID, "--title", title})
+	return err
+}
+
+// DeletePlaylist deletes a playlist using the Python bridge
+func (pb *PythonBridge) DeletePlaylist(ctx context.Context, playlistID string) error {
+	_, err := pb.runEditCommand(ctx, []string{"delete_playlist", "--playlist-id", playlistID})
+	return err
+}
+
+// AddPlaylistItems adds videos to a playlist using the Python bridge
+func (pb *PythonBridge) AddPlaylistItems(ctx context.Context, playlistID string, videoIDs []string) error {
+	_, err := pb.runEditCommand(ctx, []string{"add_playlist_items", "--playlist-id", playlistID,
+		"--video-ids", strings.Join(videoIDs, ",")})
+	return err
+}
+
+// RemovePlaylistItems removes entries from a playlist using the Python bridge
+func (pb *PythonBridge) RemovePlaylistItems(ctx context.Context, playlistID string, tracks []Track) error {
+	videoIDs := make([]string, len(tracks))
+	setVideoIDs := make([]string, len(tracks))
+	for i, track := range tracks {
+		videoIDs[i] = track.ID
+		setVideoIDs[i] = track.SetVideoID
+	}
+	
+	_, err := pb.runEditCommand(ctx, []string{"remove_playlist_items", "--playlist-id", playlistID,
+		"--video-ids", strings.Join(videoIDs, ","), "--set-video-ids", strings.Join(setVideoIDs, ",")})
+	return err
+}
+
+// AddHistoryItem reports a track as played using the Python bridge
+func (pb *PythonBridge) AddHistoryItem(ctx context.Context, videoID string) error {
+	_, err := pb.runEditCommand(ctx, []string{"add_history_item", "--video-id", videoID})
+	return err
+}
+
+// RateSong likes, dislikes, or clears the rating of a song using the Python bridge
+func (pb *PythonBridge) RateSong(ctx context.Context, videoID, rating string) error {
+	_, err := pb.runEditCommand(ctx, []string{"rate_song", "--video-id", videoID, "--rating", rating})
+	return err
+}
+
+// runEditCommand runs a bridge command that modifies the library
+func (pb *PythonBridge) runEditCommand(ctx context.Context, args []string) (*EditResponse, error) {
+	output, err := pb.runCommand(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	
+	var response EditResponse
+	if err := json.Unmarshal(output, &response); err != nil {
+		pb.log("Error unmarshaling %s response: %v", args[0], err)
+		return nil, fmt.Errorf("failed to parse %s response: %v", args[0], err)
+	}
+	
+	if !response.Success {
+		pb.log("%s failed: %s", args[0], response.Error)
+		return nil, bridgeError(args[0], response.Error)
+	}
+	
+	return &response, nil
+}
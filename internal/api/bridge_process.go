@@ -0,0 +1,217 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"time"
+)
+
+const (
+	// bridgeRequestTimeout is how long a request may take before the bridge is restarted
+	bridgeRequestTimeout = 60 * time.Second
+	// bridgeIdleCheck is how long the bridge may sit idle before it is pinged before use
+	bridgeIdleCheck = 5 * time.Minute
+	// bridgePingTimeout is how long a health check may take
+	bridgePingTimeout = 5 * time.Second
+)
+
+// errBridgeTimeout means a request got no answer in time. It may still have
+// taken effect, so it is not sent again.
+var errBridgeTimeout = errors.New("Python bridge did not answer in time")
+
+// bridgeProcess is a long-lived `ytmusic_bridge.py serve` answering one JSON request per line
+type bridgeProcess struct {
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	lines    chan []byte // Response lines, closed when the process exits
+	nextID   int
+	lastUsed time.Time
+}
+
+// bridgeRequest is a request line sent to the bridge
+type bridgeRequest struct {
+	ID   int      `json:"id"`
+	Args []string `json:"args"`
+}
+
+// bridgeReply is a response line read from the bridge
+type bridgeReply struct {
+	ID       int             `json:"id"`
+	Response json.RawMessage `json:"response"`
+}
+
+// startBridgeProcess launches the bridge in serve mode
+func (pb *PythonBridge) startBridgeProcess() (*bridgeProcess, error) {
+	cmd := exec.Command(pb.pythonPath, pb.scriptPath, "serve")
+	cmd.Env = pb.env()
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("could not start Python bridge: %v", err)
+	}
+	pb.log("Started persistent Python bridge (pid %d)", cmd.Process.Pid)
+
+	proc := &bridgeProcess{
+		cmd:      cmd,
+		stdin:    stdin,
+		lines:    make(chan []byte),
+		lastUsed: time.Now(),
+	}
+
+	go func() {
+		reader := bufio.NewReader(stdout)
+		for {
+			line, err := reader.ReadBytes('\n')
+			if len(line) > 0 {
+				proc.lines <- line
+			}
+			if err != nil {
+				close(proc.lines)
+				return
+			}
+		}
+	}()
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			pb.log("Python bridge stderr: %s", scanner.Text())
+		}
+	}()
+	go cmd.Wait()
+
+	return proc, nil
+}
+
+// request sends one command to the bridge and returns the response it would have printed
+func (proc *bridgeProcess) request(ctx context.Context, args []string, timeout time.Duration) ([]byte, error) {
+	proc.nextID++
+	line, err := json.Marshal(bridgeRequest{ID: proc.nextID, Args: args})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := proc.stdin.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("could not write to Python bridge: %v", err)
+	}
+
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+	for {
+		select {
+		case line, ok := <-proc.lines:
+			if !ok {
+				return nil, fmt.Errorf("Python bridge exited")
+			}
+
+			var reply bridgeReply
+			if err := json.Unmarshal(line, &reply); err != nil {
+				return nil, fmt.Errorf("unreadable Python bridge reply: %v", err)
+			}
+			if reply.Response == nil {
+				// Failures before the request loop, such as a missing ytmusicapi, print a bare response
+				return line, nil
+			}
+			if reply.ID != proc.nextID {
+				// Left over from a request that timed out
+				continue
+			}
+			proc.lastUsed = time.Now()
+			return reply.Response, nil
+
+		case <-timer.C:
+			return nil, errBridgeTimeout
+
+		case <-ctx.Done():
+			// The reply is skipped by ID when it arrives
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// stop ends the bridge process
+func (proc *bridgeProcess) stop() {
+	proc.stdin.Close()
+	if proc.cmd.Process != nil {
+		proc.cmd.Process.Kill()
+	}
+	// Unblock the reader if it holds a reply nobody is waiting for
+	go func() {
+		for range proc.lines {
+		}
+	}()
+}
+
+// runPersistent sends a command to the long-lived bridge, starting it if needed.
+// A bridge idle for a while is pinged first, one that crashed is restarted
+// once before giving up, and one that hung is restarted for the next command.
+func (pb *PythonBridge) runPersistent(ctx context.Context, args []string) ([]byte, error) {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	if pb.proc != nil && time.Since(pb.proc.lastUsed) > bridgeIdleCheck {
+		if _, err := pb.proc.request(context.Background(), []string{"ping"}, bridgePingTimeout); err != nil {
+			pb.log("Python bridge failed its health check: %v", err)
+			pb.proc.stop()
+			pb.proc = nil
+		}
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		if pb.proc == nil {
+			proc, err := pb.startBridgeProcess()
+			if err != nil {
+				return nil, err
+			}
+			pb.proc = proc
+		}
+
+		output, err := pb.proc.request(ctx, args, bridgeRequestTimeout)
+		if err == nil {
+			return output, nil
+		}
+		if ctx.Err() != nil {
+			// The bridge is fine, whoever asked just stopped waiting
+			return nil, err
+		}
+
+		pb.log("Restarting Python bridge: %v", err)
+		pb.proc.stop()
+		pb.proc = nil
+		if errors.Is(err, errBridgeTimeout) {
+			return nil, err
+		}
+		lastErr = err
+	}
+
+	// A bridge that fails right after a restart, such as an old script without
+	// serve mode, won't get better; run one process per command from now on
+	pb.log("Persistent Python bridge keeps failing, running one process per command")
+	pb.oneShot = true
+	return nil, lastErr
+}
+
+// Close stops the long-lived bridge process, if one is running
+func (pb *PythonBridge) Close() {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+
+	if pb.proc != nil {
+		pb.proc.stop()
+		pb.proc = nil
+	}
+}
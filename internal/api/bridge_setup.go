@@ -0,0 +1,125 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// ErrBridgeSetup means the Python bridge could be used once ytmusicapi is installed
+var ErrBridgeSetup = errors.New("ytmusicapi is not installed for the Python bridge")
+
+// venvPython returns the Python executable of a virtualenv
+func venvPython(dir string) string {
+	if runtime.GOOS == "windows" {
+		return filepath.Join(dir, "Scripts", "python.exe")
+	}
+	return filepath.Join(dir, "bin", "python")
+}
+
+// hasYTMusicAPI reports whether the bridge's Python can import ytmusicapi.
+// The answer is cached, as it takes a Python start-up to find out.
+func (pb *PythonBridge) hasYTMusicAPI() bool {
+	pb.moduleMu.Lock()
+	defer pb.moduleMu.Unlock()
+
+	if pb.moduleOK == nil {
+		// The bridge exits with an error before answering when the import fails
+		cmd := exec.Command(pb.pythonPath, pb.scriptPath, "ping")
+		cmd.Env = pb.env()
+		err := cmd.Run()
+		ok := err == nil
+		pb.moduleOK = &ok
+		pb.log("ytmusicapi available to %s: %v", pb.pythonPath, ok)
+	}
+	return *pb.moduleOK
+}
+
+// NeedsSetup reports whether the bridge is only missing ytmusicapi, which SetupBridge can install
+func (pb *PythonBridge) NeedsSetup() bool {
+	if pb.scriptPath == "" {
+		return false
+	}
+	if _, err := os.Stat(pb.scriptPath); err != nil {
+		return false
+	}
+	if _, err := exec.LookPath(pb.systemPython); err != nil {
+		return false
+	}
+	return !pb.hasYTMusicAPI()
+}
+
+// Ready reports whether the bridge can run, with ytmusicapi installed
+func (pb *PythonBridge) Ready() bool {
+	if pb.scriptPath == "" {
+		return false
+	}
+	if _, err := os.Stat(pb.scriptPath); err != nil {
+		return false
+	}
+	if _, err := exec.LookPath(pb.pythonPath); err != nil {
+		return false
+	}
+	return pb.hasYTMusicAPI()
+}
+
+// Setup creates a virtualenv, installs ytmusicapi into it, and switches the bridge to it
+func (pb *PythonBridge) Setup(ctx context.Context) error {
+	pb.log("Creating virtualenv in %s", pb.venvDir)
+	if output, err := exec.CommandContext(ctx, pb.systemPython, "-m", "venv", pb.venvDir).CombinedOutput(); err != nil {
+		return fmt.Errorf("could not create virtualenv: %v: %s", err, lastLine(output))
+	}
+
+	python := venvPython(pb.venvDir)
+	pb.log("Installing ytmusicapi with %s", python)
+	if output, err := exec.CommandContext(ctx, python, "-m", "pip", "install", "--upgrade", "ytmusicapi").CombinedOutput(); err != nil {
+		return fmt.Errorf("could not install ytmusicapi: %v: %s", err, lastLine(output))
+	}
+
+	// Restart the long-lived bridge under the new Python
+	pb.Close()
+	pb.mu.Lock()
+	pb.pythonPath = python
+	pb.oneShot = false
+	pb.mu.Unlock()
+
+	pb.moduleMu.Lock()
+	pb.moduleOK = nil
+	pb.moduleMu.Unlock()
+
+	if !pb.hasYTMusicAPI() {
+		return fmt.Errorf("ytmusicapi was installed but the bridge still can't import it")
+	}
+	return nil
+}
+
+// lastLine returns the last non-empty line of a command's output, which is usually its error
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
+
+// BridgeNeedsSetup reports whether the Python bridge is only missing ytmusicapi
+func (api *YouTubeMusicAPI) BridgeNeedsSetup() bool {
+	return api.bridge.NeedsSetup()
+}
+
+// BridgeReady reports whether the Python bridge can run
+func (api *YouTubeMusicAPI) BridgeReady() bool {
+	return api.bridge.Ready()
+}
+
+// SetupBridge installs ytmusicapi into a virtualenv under the config directory for the Python bridge
+func (api *YouTubeMusicAPI) SetupBridge(ctx context.Context) error {
+	if err := api.bridge.Setup(ctx); err != nil {
+		api.LogDebug("Bridge setup failed: %v", err)
+		return err
+	}
+	api.LogDebug("Python bridge set up with ytmusicapi")
+	return nil
+}
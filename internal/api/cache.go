@@ -0,0 +1,194 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// How long cached responses stay fresh. Playlists change as the user edits them,
+// so they expire soonest; albums hardly ever change.
+const (
+	searchCacheTTL   = 1 * time.Hour
+	playlistCacheTTL = 10 * time.Minute
+	albumCacheTTL    = 24 * time.Hour
+	artistCacheTTL   = 6 * time.Hour
+)
+
+// DefaultCacheSize is the size cap of the response cache in megabytes
+const DefaultCacheSize = 50
+
+// responseCache keeps API responses as JSON files, expiring them by age and
+// evicting the oldest once the directory grows past its size cap
+type responseCache struct {
+	dir      string
+	maxBytes int64 // 0 disables the cache
+	mu       sync.Mutex
+	logger   func(format string, v ...interface{})
+}
+
+// newResponseCache creates a cache in dir holding up to sizeMB megabytes
+func newResponseCache(dir string, sizeMB int, logger func(format string, v ...interface{})) *responseCache {
+	return &responseCache{
+		dir:      dir,
+		maxBytes: int64(sizeMB) * 1024 * 1024,
+		logger:   logger,
+	}
+}
+
+// SetCacheSize sets the size cap of the response cache in megabytes, 0 to disable it
+func (api *YouTubeMusicAPI) SetCacheSize(sizeMB int) {
+	api.cache.mu.Lock()
+	defer api.cache.mu.Unlock()
+	api.cache.maxBytes = int64(sizeMB) * 1024 * 1024
+	api.LogDebug("Response cache size set to %d MB", sizeMB)
+}
+
+// cacheKey names the cached response of a request. Responses depend on the
+// locale and account, so those are part of the key. Keys of the same kind
+// and id share a prefix, so invalidate can drop every page of a playlist.
+func (api *YouTubeMusicAPI) cacheKey(kind, id string, extra ...string) string {
+	key := kind + "-" + shortHash(id, api.language, api.region, api.authUser, api.onBehalfOf)
+	if len(extra) > 0 {
+		key += "-" + shortHash(extra...)
+	}
+	return key
+}
+
+// playlistCacheKey names a cached playlist or one of its pages, with or without the "VL" prefix
+func (api *YouTubeMusicAPI) playlistCacheKey(playlistID string, page ...string) string {
+	return api.cacheKey("playlist", strings.TrimPrefix(playlistID, "VL"), page...)
+}
+
+// tracksPage is a cached page of a playlist or of search results
+type tracksPage struct {
+	Tracks       []Track
+	Continuation string
+}
+
+// shortHash returns a file-name-safe digest of parts
+func shortHash(parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(parts, "\x00")))
+	return hex.EncodeToString(sum[:12])
+}
+
+// get decodes a cached response into out, returning false if there is none younger than ttl
+func (c *responseCache) get(key string, ttl time.Duration, out interface{}) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxBytes <= 0 {
+		return false
+	}
+
+	path := filepath.Join(c.dir, key+".json")
+	info, err := os.Stat(path)
+	if err != nil {
+		return false
+	}
+	if time.Since(info.ModTime()) > ttl {
+		os.Remove(path)
+		return false
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return false
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		c.logger("Dropping unreadable cache entry %s: %v", key, err)
+		os.Remove(path)
+		return false
+	}
+
+	c.logger("Cache hit: %s", key)
+	return true
+}
+
+// put caches a response, then evicts the oldest entries if the cache is over its cap
+func (c *responseCache) put(key string, v interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.maxBytes <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		c.logger("Error encoding cache entry %s: %v", key, err)
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		c.logger("Error creating cache directory: %v", err)
+		return
+	}
+
+	// Write then rename, so a reader never sees half an entry
+	path := filepath.Join(c.dir, key+".json")
+	if err := os.WriteFile(path+".tmp", data, 0644); err != nil {
+		c.logger("Error writing cache entry %s: %v", key, err)
+		return
+	}
+	if err := os.Rename(path+".tmp", path); err != nil {
+		c.logger("Error writing cache entry %s: %v", key, err)
+		return
+	}
+
+	c.prune()
+}
+
+// invalidate drops every cached response whose key starts with prefix
+func (c *responseCache) invalidate(prefix string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, entry := range entries {
+		if strings.HasPrefix(entry.Name(), prefix) {
+			os.Remove(filepath.Join(c.dir, entry.Name()))
+		}
+	}
+}
+
+// prune removes the oldest entries until the cache fits its cap
+func (c *responseCache) prune() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	var files []os.FileInfo
+	var total int64
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, info)
+		total += info.Size()
+	}
+	if total <= c.maxBytes {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+	for _, info := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.dir, info.Name())); err == nil {
+			total -= info.Size()
+		}
+	}
+	c.logger("Pruned response cache to %d bytes", total)
+}
@@ -1,34 +1,55 @@
 package api
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
 	"net/http/cookiejar"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
+
+	"ytmusic/internal/logs"
+	"ytmusic/internal/paths"
 )
 
 // YouTubeMusicAPI handles API requests to YouTube Music via Python bridge
 type YouTubeMusicAPI struct {
-	client     *http.Client
-	configPath string
-	IsLoggedIn bool
-	logger     *log.Logger
-	bridge     *PythonBridge // Use the Python bridge instead of direct HTTP calls
+	client        *http.Client
+	configPath    string
+	IsLoggedIn    bool
+	authUser      string      // X-Goog-AuthUser index for multi-account sessions
+	onBehalfOf    string      // Brand account requests are made as, empty for the Google account
+	accountChosen bool        // Whether the identity was picked by the user rather than defaulted
+	oauth         *oauthToken // Token imported from ytmusicapi oauth credentials
+	streamQuality StreamQuality
+	streamCodec   StreamCodec
+	maxBitrate    int    // kbps streams are kept under, 0 for no cap
+	maxRate       string // Download rate yt-dlp is held to, such as "500K"; empty for none
+	language      string // hl sent with innertube requests
+	region        string // gl sent with innertube requests
+	decipherer    signatureDecipherer // Signature transform for web client stream URLs
+	logger        *log.Logger
+	bridge        *PythonBridge // Use the Python bridge instead of direct HTTP calls
+	cache         *responseCache // Recent search, playlist, album, and artist responses
+	streams       *streamCache   // Resolved streams until they expire, and track durations
+	backends      backendChain   // Backends searches and playlists are fetched from, in order
+
+	record func(endpoint string, data []byte) // Sees every innertube response, for recording test data
 }
 
 // NewYouTubeMusicAPI creates a new YouTubeMusicAPI instance
 func NewYouTubeMusicAPI(debugMode bool) *YouTubeMusicAPI {
 	jar, _ := cookiejar.New(nil)
+	// Each request sets its own timeout, see transport.go
 	client := &http.Client{
-		Timeout: 10 * time.Second,
-		Jar:     jar,
+		Transport: newTransport(),
+		Jar:       jar,
 	}
 
-	configDir, _ := os.UserHomeDir()
-	configPath := filepath.Join(configDir, ".ytmusic")
+	configPath := paths.Config()
 	
 	// Create config directory if it doesn't exist
 	if _, err := os.Stat(configPath); os.IsNotExist(err) {
@@ -36,7 +57,7 @@ func NewYouTubeMusicAPI(debugMode bool) *YouTubeMusicAPI {
 	}
 	
 	// Create logs directory if it doesn't exist
-	logPath := filepath.Join(configPath, "logs")
+	logPath := logs.Dir()
 	if _, err := os.Stat(logPath); os.IsNotExist(err) {
 		os.MkdirAll(logPath, 0755)
 	}
@@ -54,18 +75,31 @@ func NewYouTubeMusicAPI(debugMode bool) *YouTubeMusicAPI {
 	}
 
 	api := &YouTubeMusicAPI{
-		client:     client,
-		configPath: configPath,
-		IsLoggedIn: false,
-		logger:     logger,
+		client:        client,
+		configPath:    configPath,
+		IsLoggedIn:    false,
+		authUser:      "0",
+		streamQuality: QualityHigh,
+		streamCodec:   CodecAny,
+		language:      "en",
+		region:        "US",
+		logger:        logger,
 	}
 
+	api.cache = newResponseCache(filepath.Join(paths.Cache(), "cache"), DefaultCacheSize, api.LogDebug)
+	api.streams = newStreamCache(filepath.Join(paths.Cache(), "streams.json"), api.LogDebug)
+
 	// Initialize Python bridge
 	api.bridge = NewPythonBridge(configPath, api.LogDebug)
 	api.bridge.SetAPI(api)
+	api.SetBackends(DefaultBackends)
 
-	// Try to load cookies
+	// Try to load cookies, then any credentials created by the ytmusicapi CLI
 	api.loadCookies()
+	if !api.IsLoggedIn {
+		api.loadYTMusicAPICredentials()
+	}
+	api.loadAccount()
 	
 	if debugMode && logger != nil {
 		logger.Println("YouTubeMusicAPI initialized")
@@ -83,86 +117,162 @@ func (api *YouTubeMusicAPI) LogDebug(format string, v ...interface{}) {
 	}
 }
 
-// Search searches for tracks using the Python bridge
-func (api *YouTubeMusicAPI) Search(query string) ([]Track, error) {
+// Close stops the background processes the API started
+func (api *YouTubeMusicAPI) Close() {
+	api.bridge.Close()
+}
+
+// Search searches for tracks, trying each backend in order
+func (api *YouTubeMusicAPI) Search(ctx context.Context, query string) ([]Track, error) {
 	if !api.IsLoggedIn {
-		return nil, fmt.Errorf("not logged in")
+		return nil, ErrNotLoggedIn
 	}
 
 	api.LogDebug("Searching for: %s", query)
 
-	// Check if Python bridge is available
-	if !api.bridge.IsAvailable() {
-		api.LogDebug("Python bridge not available, falling back to placeholder results")
-		// Return some placeholder results
-		return []Track{
-			{ID: "dQw4w9WgXcQ", TrackTitle: "Sample: " + query, Artist: "Python bridge not available", Duration: 180},
-			{ID: "xvFZjo5PgG0", TrackTitle: "Install ytmusicapi", Artist: "pip install ytmusicapi", Duration: 240},
-		}, nil
+	cacheKey := api.cacheKey("search", strings.ToLower(strings.TrimSpace(query)))
+	var cached []Track
+	if api.cache.get(cacheKey, searchCacheTTL, &cached) {
+		api.servedBy("cache")
+		return cached, nil
 	}
 
-	// Use Python bridge
-	tracks, err := api.bridge.Search(query)
+	tracks, err := withBackends(ctx, api, "Search", func(backend MusicBackend) ([]Track, error) {
+		tracks, err := backend.Search(ctx, query)
+		return withSource(tracks, backend.Name()), err
+	})
 	if err != nil {
-		api.LogDebug("Python bridge search failed: %v", err)
 		return nil, err
 	}
 
-	api.LogDebug("Found %d tracks via Python bridge", len(tracks))
+	api.LogDebug("Found %d tracks", len(tracks))
+	api.cache.put(cacheKey, tracks)
 	return tracks, nil
 }
 
-// GetUserPlaylists fetches playlists using the Python bridge
-func (api *YouTubeMusicAPI) GetUserPlaylists() ([]Playlist, error) {
+// SearchPage searches for songs one page at a time, so results can be shown
+// while the rest are fetched. An empty continuation fetches the first page;
+// an empty returned token means there are no more. Only innertube pages; other
+// backends return every result with the first page.
+func (api *YouTubeMusicAPI) SearchPage(ctx context.Context, query, continuation string) ([]Track, string, error) {
 	if !api.IsLoggedIn {
-		return nil, fmt.Errorf("not logged in")
+		return nil, "", ErrNotLoggedIn
 	}
 
-	api.LogDebug("Fetching user playlists via Python bridge")
+	// Pages are cached by their continuation, which stays the same while the first page is cached
+	cacheKey := api.cacheKey("search", strings.ToLower(strings.TrimSpace(query)), "page", continuation)
+	var cached tracksPage
+	if api.cache.get(cacheKey, searchCacheTTL, &cached) {
+		api.servedBy("cache")
+		return cached.Tracks, cached.Continuation, nil
+	}
 
-	// Check if Python bridge is available
-	if !api.bridge.IsAvailable() {
-		api.LogDebug("Python bridge not available, returning placeholder playlists")
-		return []Playlist{
-			{ID: "PLACEHOLDER_1", PlaylistTitle: "Python Bridge Not Available", PlaylistDesc: "Install ytmusicapi", TrackCount: 0, Author: "System"},
-			{ID: "PLACEHOLDER_2", PlaylistTitle: "Install Dependencies", PlaylistDesc: "pip install ytmusicapi", TrackCount: 0, Author: "System"},
-		}, nil
+	var page tracksPage
+	var err error
+	if continuation != "" {
+		api.LogDebug("Fetching more results for: %s", query)
+		page.Tracks, page.Continuation, err = api.searchPageNative(ctx, query, continuation)
+		if err == nil {
+			api.servedBy(BackendNative)
+			page.Tracks = withSource(page.Tracks, BackendNative)
+		}
+	} else {
+		api.LogDebug("Searching for: %s", query)
+		page, err = withBackends(ctx, api, "Search", func(backend MusicBackend) (tracksPage, error) {
+			if backend.Name() == BackendNative {
+				tracks, next, err := api.searchPageNative(ctx, query, "")
+				return tracksPage{Tracks: withSource(tracks, BackendNative), Continuation: next}, err
+			}
+			tracks, err := backend.Search(ctx, query)
+			return tracksPage{Tracks: withSource(tracks, backend.Name())}, err
+		})
+	}
+	if err != nil {
+		return nil, "", err
 	}
 
-	// Use Python bridge
-	playlists, err := api.bridge.GetPlaylists()
+	api.LogDebug("Found %d tracks", len(page.Tracks))
+	api.cache.put(cacheKey, page)
+	return page.Tracks, page.Continuation, nil
+}
+
+// GetUserPlaylists fetches the user's library playlists, trying each backend in order
+func (api *YouTubeMusicAPI) GetUserPlaylists(ctx context.Context) ([]Playlist, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching user playlists")
+
+	playlists, err := withBackends(ctx, api, "Playlists", func(backend MusicBackend) ([]Playlist, error) {
+		return backend.GetPlaylists(ctx)
+	})
 	if err != nil {
-		api.LogDebug("Python bridge get playlists failed: %v", err)
 		return nil, err
 	}
 
-	api.LogDebug("Found %d playlists via Python bridge", len(playlists))
+	api.LogDebug("Found %d playlists", len(playlists))
 	return playlists, nil
 }
 
-// GetPlaylistTracks fetches playlist tracks using the Python bridge
-func (api *YouTubeMusicAPI) GetPlaylistTracks(playlistID string) ([]Track, error) {
+// GetPlaylistTracks fetches all of a playlist's tracks, trying each backend in order
+func (api *YouTubeMusicAPI) GetPlaylistTracks(ctx context.Context, playlistID string) ([]Track, error) {
 	if !api.IsLoggedIn {
-		return nil, fmt.Errorf("not logged in")
+		return nil, ErrNotLoggedIn
 	}
 
-	api.LogDebug("Fetching playlist tracks for ID: %s via Python bridge", playlistID)
+	api.LogDebug("Fetching playlist tracks for ID: %s", playlistID)
 
-	// Check if Python bridge is available
-	if !api.bridge.IsAvailable() {
-		api.LogDebug("Python bridge not available, returning placeholder tracks")
-		return []Track{
-			{ID: "dQw4w9WgXcQ", TrackTitle: "Python Bridge Required", Artist: "Install ytmusicapi", Duration: 180},
-		}, nil
+	cacheKey := api.playlistCacheKey(playlistID)
+	var cached []Track
+	if api.cache.get(cacheKey, playlistCacheTTL, &cached) {
+		api.servedBy("cache")
+		return cached, nil
 	}
 
-	// Use Python bridge
-	tracks, err := api.bridge.GetPlaylistTracks(playlistID)
+	tracks, err := withBackends(ctx, api, "Playlist tracks", func(backend MusicBackend) ([]Track, error) {
+		tracks, err := backend.GetPlaylistTracks(ctx, playlistID)
+		return withSource(tracks, backend.Name()), err
+	})
 	if err != nil {
-		api.LogDebug("Python bridge get playlist tracks failed: %v", err)
 		return nil, err
 	}
 
-	api.LogDebug("Found %d tracks in playlist via Python bridge", len(tracks))
+	api.LogDebug("Found %d tracks in playlist", len(tracks))
+	api.cache.put(cacheKey, tracks)
 	return tracks, nil
 }
+
+// GetPlaylistTracksPage fetches one page of a playlist's tracks, returning a token for the next page.
+// An empty continuation fetches the first page; an empty returned token means there are no more.
+func (api *YouTubeMusicAPI) GetPlaylistTracksPage(ctx context.Context, playlistID, continuation string) ([]Track, string, error) {
+	if !api.IsLoggedIn {
+		return nil, "", ErrNotLoggedIn
+	}
+
+	// Only innertube can page; other backends return the whole playlist at once
+	if api.pagesNatively() {
+		// Pages are cached by their continuation, which stays the same while the first page is cached
+		cacheKey := api.playlistCacheKey(playlistID, "page", continuation)
+		var cached tracksPage
+		if api.cache.get(cacheKey, playlistCacheTTL, &cached) {
+			api.servedBy("cache")
+			return cached.Tracks, cached.Continuation, nil
+		}
+		
+		api.LogDebug("Fetching playlist page for ID: %s via innertube", playlistID)
+		tracks, next, err := api.getPlaylistTracksPageNative(ctx, playlistID, continuation)
+		if err == nil {
+			api.servedBy(BackendNative)
+			tracks = withSource(tracks, BackendNative)
+			api.cache.put(cacheKey, tracksPage{Tracks: tracks, Continuation: next})
+		}
+		return tracks, next, err
+	}
+	if continuation != "" {
+		return nil, "", fmt.Errorf("playlist continuations require innertube")
+	}
+
+	tracks, err := api.GetPlaylistTracks(ctx, playlistID)
+	return tracks, "", err
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"strconv"
+)
+
+// TrackDetails are what's known of a track beyond what lists show
+type TrackDetails struct {
+	ViewCount  int64  // 0 if unknown
+	UploadDate string // e.g. "2013-05-17", empty if unknown
+	LikeStatus Rating // Empty if unknown
+}
+
+// detailsResponse is the subset of the player response holding a video's stats
+type detailsResponse struct {
+	VideoDetails struct {
+		ViewCount string `json:"viewCount"`
+	} `json:"videoDetails"`
+	Microformat struct {
+		MicroformatDataRenderer struct {
+			UploadDate string `json:"uploadDate"`
+		} `json:"microformatDataRenderer"`
+	} `json:"microformat"`
+}
+
+// likeStatusResponse is the subset of the next response holding the like button
+type likeStatusResponse struct {
+	PlayerOverlays struct {
+		PlayerOverlayRenderer struct {
+			Actions []struct {
+				LikeButtonRenderer *struct {
+					LikeStatus string `json:"likeStatus"`
+				} `json:"likeButtonRenderer"`
+			} `json:"actions"`
+		} `json:"playerOverlayRenderer"`
+	} `json:"playerOverlays"`
+}
+
+// GetTrackDetails fetches a track's view count, upload date and whether the account likes it
+func (api *YouTubeMusicAPI) GetTrackDetails(ctx context.Context, videoID string) (*TrackDetails, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching details of %s", videoID)
+
+	var player detailsResponse
+	if err := api.sendRequest(ctx, "player", map[string]interface{}{
+		"videoId": videoID,
+	}, &player); err != nil {
+		return nil, err
+	}
+	views, _ := strconv.ParseInt(player.VideoDetails.ViewCount, 10, 64)
+	details := &TrackDetails{
+		ViewCount:  views,
+		UploadDate: player.Microformat.MicroformatDataRenderer.UploadDate,
+	}
+
+	// The like status is only a nicety; the details stand without it
+	var next likeStatusResponse
+	err := api.sendRequest(ctx, "next", map[string]interface{}{
+		"videoId":     videoID,
+		"isAudioOnly": true,
+	}, &next)
+	if err != nil {
+		api.LogDebug("Fetching the like status of %s failed: %v", videoID, err)
+		return details, nil
+	}
+	for _, action := range next.PlayerOverlays.PlayerOverlayRenderer.Actions {
+		if action.LikeButtonRenderer != nil {
+			details.LikeStatus = Rating(action.LikeButtonRenderer.LikeStatus)
+			break
+		}
+	}
+	return details, nil
+}
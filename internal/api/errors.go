@@ -0,0 +1,63 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Errors the UI can give targeted advice for. Errors returned by the API match
+// them with errors.Is while keeping their detailed message.
+var (
+	ErrNotLoggedIn        = fmt.Errorf("not logged in")
+	ErrSessionExpired     = fmt.Errorf("session expired")
+	ErrRateLimited        = fmt.Errorf("rate limited by YouTube Music")
+	ErrGeoBlocked         = fmt.Errorf("not available in your region")
+	ErrBackendUnavailable = fmt.Errorf("no backend available")
+)
+
+// kindError is an error with its own message that matches one of the errors above
+type kindError struct {
+	kind error
+	msg  string
+}
+
+func (e *kindError) Error() string { return e.msg }
+
+// Is lets errors.Is match the error's kind
+func (e *kindError) Is(target error) bool { return target == e.kind }
+
+// withKind formats an error that matches kind
+func withKind(kind error, format string, v ...interface{}) error {
+	return &kindError{kind: kind, msg: fmt.Sprintf(format, v...)}
+}
+
+// statusError describes a failed HTTP response. A signed request rejected as
+// unauthorized means the cookies no longer work.
+func statusError(endpoint string, status int, signed bool) error {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return withKind(ErrRateLimited, "%s request was rate limited (status %d)", endpoint, status)
+	case signed && (status == http.StatusUnauthorized || status == http.StatusForbidden):
+		return withKind(ErrSessionExpired, "%s request was refused (status %d), the session has likely expired", endpoint, status)
+	}
+	return fmt.Errorf("%s request returned status %d", endpoint, status)
+}
+
+// bridgeError describes a failed bridge command, recognizing the HTTP errors
+// ytmusicapi reports in its messages
+func bridgeError(command, message string) error {
+	switch {
+	case strings.Contains(message, "HTTP 429"):
+		return withKind(ErrRateLimited, "%s failed: %s", command, message)
+	case strings.Contains(message, "HTTP 401"), strings.Contains(message, "HTTP 403"):
+		return withKind(ErrSessionExpired, "%s failed: %s", command, message)
+	}
+	return fmt.Errorf("%s failed: %s", command, message)
+}
+
+// geoBlocked reports whether a playability reason says the track is blocked where the user is
+func geoBlocked(reason string) bool {
+	reason = strings.ToLower(reason)
+	return strings.Contains(reason, "country") || strings.Contains(reason, "region")
+}
@@ -0,0 +1,73 @@
+package api
+
+import "context"
+
+// Explore browse IDs
+const (
+	browseCharts      = "FEmusic_charts"
+	browseNewReleases = "FEmusic_new_releases_albums"
+)
+
+// GlobalCharts is the country code of the worldwide charts
+const GlobalCharts = "ZZ"
+
+// GetCharts fetches the chart shelves (top songs, videos, artists) for a country code such as "US".
+// An empty country means the global charts.
+func (api *YouTubeMusicAPI) GetCharts(ctx context.Context, country string) ([]Shelf, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+	if country == "" {
+		country = GlobalCharts
+	}
+
+	api.LogDebug("Fetching charts for %s", country)
+
+	if !api.bridge.IsAvailable() {
+		api.LogDebug("Python bridge not available, fetching charts via innertube")
+
+		var response BrowseResponse
+		err := api.sendRequest(ctx, "browse", map[string]interface{}{
+			"browseId": browseCharts,
+			"formData": map[string]interface{}{
+				"selectedValues": []string{country},
+			},
+		}, &response)
+		if err != nil {
+			return nil, err
+		}
+		return carouselShelves(response.sectionList()), nil
+	}
+
+	shelves, err := api.bridge.GetCharts(ctx, country)
+	if err != nil {
+		api.LogDebug("Python bridge get charts failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d chart shelves via Python bridge", len(shelves))
+	return shelves, nil
+}
+
+// GetNewReleases fetches newly released albums and singles
+func (api *YouTubeMusicAPI) GetNewReleases(ctx context.Context) ([]Album, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching new releases")
+
+	if !api.bridge.IsAvailable() {
+		api.LogDebug("Python bridge not available, fetching new releases via innertube")
+		return api.getAlbumGridNative(ctx, browseNewReleases)
+	}
+
+	albums, err := api.bridge.GetNewReleases(ctx)
+	if err != nil {
+		api.LogDebug("Python bridge get new releases failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d new releases via Python bridge", len(albums))
+	return albums, nil
+}
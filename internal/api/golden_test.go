@@ -0,0 +1,261 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// The innertube responses in testdata are parsed and compared with the
+// .golden.json file next to each, so a change in what the parser extracts
+// shows up as a failing test. The checked-in responses are small examples of
+// each layout. Replace them with live ones after YouTube changes its layout:
+//
+//	go test ./internal/api -run TestGolden -record -playlist <id>
+//
+// This needs a saved login. Check the new golden files, then commit them with
+// the responses. After an intended parser change, rewrite the golden files:
+//
+//	go test ./internal/api -run TestGolden -update
+var (
+	recordResponses = flag.Bool("record", false, "record the responses in testdata from YouTube Music, with the saved login")
+	updateGolden    = flag.Bool("update", false, "rewrite the golden files from what the parser returns")
+	recordQuery     = flag.String("query", "daft punk", "search to record")
+	recordPlaylist  = flag.String("playlist", "", "playlist to record, one long enough to have a second page")
+)
+
+// goldenAlbumID is the browse ID albums are parsed as. It comes from the
+// request rather than the response, so recorded albums all get this one.
+const goldenAlbumID = "MPREb_golden"
+
+// goldenCase is a recorded response and how it is parsed
+type goldenCase struct {
+	name  string
+	parse func(data []byte) (interface{}, error)
+	fetch func(ctx context.Context, api *YouTubeMusicAPI) error // Makes the requests to record; the last response is kept
+}
+
+var goldenCases = []goldenCase{
+	{
+		name: "search",
+		parse: func(data []byte) (interface{}, error) {
+			var response InnertubeSearchResponse
+			if err := json.Unmarshal(data, &response); err != nil {
+				return nil, err
+			}
+			tracks, next := response.tracks()
+			return tracksPage{Tracks: tracks, Continuation: next}, nil
+		},
+		fetch: func(ctx context.Context, api *YouTubeMusicAPI) error {
+			_, _, err := api.searchPageNative(ctx, *recordQuery, "")
+			return err
+		},
+	},
+	{
+		name:  "search_continuation",
+		parse: parseContinuation,
+		fetch: func(ctx context.Context, api *YouTubeMusicAPI) error {
+			_, next, err := api.searchPageNative(ctx, *recordQuery, "")
+			if err != nil {
+				return err
+			}
+			_, _, err = api.searchPageNative(ctx, *recordQuery, next)
+			return err
+		},
+	},
+	{
+		name: "playlist",
+		parse: func(data []byte) (interface{}, error) {
+			var response BrowseResponse
+			if err := json.Unmarshal(data, &response); err != nil {
+				return nil, err
+			}
+			tracks, next := response.playlistTracks()
+			return tracksPage{Tracks: tracks, Continuation: next}, nil
+		},
+		fetch: func(ctx context.Context, api *YouTubeMusicAPI) error {
+			_, _, err := api.getPlaylistTracksPageNative(ctx, *recordPlaylist, "")
+			return err
+		},
+	},
+	{
+		name:  "playlist_continuation",
+		parse: parseContinuation,
+		fetch: func(ctx context.Context, api *YouTubeMusicAPI) error {
+			_, next, err := api.getPlaylistTracksPageNative(ctx, *recordPlaylist, "")
+			if err != nil {
+				return err
+			}
+			_, _, err = api.getPlaylistTracksPageNative(ctx, *recordPlaylist, next)
+			return err
+		},
+	},
+	{
+		name: "album",
+		parse: func(data []byte) (interface{}, error) {
+			var response BrowseResponse
+			if err := json.Unmarshal(data, &response); err != nil {
+				return nil, err
+			}
+			return response.album(goldenAlbumID)
+		},
+		fetch: func(ctx context.Context, api *YouTubeMusicAPI) error {
+			// The album of the first search result that has one
+			tracks, _, err := api.searchPageNative(ctx, *recordQuery, "")
+			if err != nil {
+				return err
+			}
+			for _, track := range tracks {
+				if track.AlbumID != "" {
+					_, err := api.getAlbumNative(ctx, track.AlbumID)
+					return err
+				}
+			}
+			return fmt.Errorf("no result for %q has an album", *recordQuery)
+		},
+	},
+}
+
+// parseContinuation parses the next page of search results or of a playlist
+func parseContinuation(data []byte) (interface{}, error) {
+	var response BrowseResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, err
+	}
+	tracks, next := response.continuationTracks()
+	return tracksPage{Tracks: tracks, Continuation: next}, nil
+}
+
+func TestGolden(t *testing.T) {
+	for _, tc := range goldenCases {
+		t.Run(tc.name, func(t *testing.T) {
+			responsePath := filepath.Join("testdata", tc.name+".json")
+			goldenPath := filepath.Join("testdata", tc.name+".golden.json")
+
+			if *recordResponses {
+				recordResponse(t, tc, responsePath)
+			}
+
+			data, err := os.ReadFile(responsePath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			parsed, err := tc.parse(data)
+			if err != nil {
+				t.Fatalf("parsing %s: %v", responsePath, err)
+			}
+			got, err := json.MarshalIndent(parsed, "", "  ")
+			if err != nil {
+				t.Fatal(err)
+			}
+			got = append(got, '\n')
+
+			if *updateGolden || *recordResponses {
+				if err := os.WriteFile(goldenPath, got, 0644); err != nil {
+					t.Fatal(err)
+				}
+				return
+			}
+			want, err := os.ReadFile(goldenPath)
+			if err != nil {
+				t.Fatalf("%v (run with -update to create it)", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("%s parses differently from %s:\n%s", responsePath, goldenPath, got)
+			}
+		})
+	}
+}
+
+// recordResponse fetches a case's response from YouTube Music and saves it without personal data
+func recordResponse(t *testing.T, tc goldenCase, path string) {
+	if (tc.name == "playlist" || tc.name == "playlist_continuation") && *recordPlaylist == "" {
+		t.Skip("no -playlist to record")
+	}
+	api := NewYouTubeMusicAPI(false)
+	if !api.IsLoggedIn {
+		t.Fatal("recording needs a saved login")
+	}
+
+	var last []byte
+	api.record = func(endpoint string, data []byte) {
+		last = data
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	if err := tc.fetch(ctx, api); err != nil {
+		t.Fatalf("fetching %s: %v", tc.name, err)
+	}
+
+	var response interface{}
+	if err := json.Unmarshal(last, &response); err != nil {
+		t.Fatal(err)
+	}
+	data, err := json.MarshalIndent(redactResponse(response), "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// redactedKeys are dropped from recorded responses: they identify the session
+// or the account, and the parser doesn't read them
+var redactedKeys = map[string]bool{
+	"responseContext":     true,
+	"frameworkUpdates":    true,
+	"trackingParams":      true,
+	"clickTrackingParams": true,
+	"loggingContext":      true,
+	"visitorData":         true,
+	"accountName":         true,
+	"email":               true,
+	"channelHandle":       true,
+}
+
+// redactResponse removes redactedKeys at every depth of a decoded response
+func redactResponse(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if redactedKeys[key] {
+				delete(v, key)
+				continue
+			}
+			v[key] = redactResponse(value)
+		}
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactResponse(value)
+		}
+	}
+	return v
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		in   string
+		want int
+	}{
+		{"3:45", 225},
+		{"0:07", 7},
+		{"1:02:03", 3723},
+		{" 4:00 ", 240},
+		{"2019", 0},
+		{"Album", 0},
+		{"1:2:3:4", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if got := parseDuration(tt.in); got != tt.want {
+			t.Errorf("parseDuration(%q) = %d, want %d", tt.in, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package api
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"ytmusic/internal/paths"
+)
+
+// browseHistory is the browse ID of the account's listening history
+const browseHistory = "FEmusic_history"
+
+// maxLocalHistory bounds how many plays are read back from the local history
+const maxLocalHistory = 200
+
+// Play is one line of the local history file
+type Play struct {
+	PlayedAt time.Time `json:"played_at"`
+	Track    Track     `json:"track"`
+	Seconds  int       `json:"seconds,omitempty"` // How far the play got, missing in plays recorded before it was kept
+}
+
+// NewPlay starts timing a play of a track, which RecordPlay records once it finishes
+func NewPlay(track Track) *Play {
+	return &Play{PlayedAt: time.Now(), Track: track}
+}
+
+// Listened returns how many seconds of the track were heard. Plays recorded
+// before that was kept count as the whole track.
+func (p Play) Listened() int {
+	if p.Seconds == 0 {
+		return p.Track.Duration
+	}
+	return p.Seconds
+}
+
+// Completed reports whether the play counts as a listen: four minutes or half
+// the track, whichever comes first, like scrobblers count them
+func (p Play) Completed() bool {
+	if p.Seconds == 0 {
+		return true
+	}
+	return p.Seconds >= 240 || (p.Track.Duration > 0 && p.Seconds*2 >= p.Track.Duration)
+}
+
+// historyPath returns the location of the local history file
+func (api *YouTubeMusicAPI) historyPath() string {
+	return filepath.Join(paths.Data(), "history.jsonl")
+}
+
+// GetHistory fetches recently played tracks, most recent first.
+// It falls back to the local history when the account history can't be reached.
+func (api *YouTubeMusicAPI) GetHistory(ctx context.Context) ([]Track, error) {
+	if api.IsLoggedIn {
+		tracks, err := api.getRemoteHistory(ctx)
+		if err == nil && len(tracks) > 0 {
+			return tracks, nil
+		}
+		api.LogDebug("Account history unavailable (err=%v, %d tracks), using local history", err, len(tracks))
+	}
+
+	return api.getLocalHistory()
+}
+
+// getRemoteHistory fetches the account's listening history
+func (api *YouTubeMusicAPI) getRemoteHistory(ctx context.Context) ([]Track, error) {
+	if !api.bridge.IsAvailable() {
+		if api.getSAPISID() == "" {
+			return nil, errLibraryUnavailable
+		}
+		api.LogDebug("Python bridge not available, fetching history via innertube")
+
+		var response BrowseResponse
+		err := api.sendRequest(ctx, "browse", map[string]interface{}{
+			"browseId": browseHistory,
+		}, &response)
+		if err != nil {
+			return nil, err
+		}
+
+		// History is grouped into shelves such as "Today" and "Yesterday"
+		return tracksFromShelves(response.sectionList().shelves()), nil
+	}
+
+	tracks, err := api.bridge.GetHistory(ctx)
+	if err != nil {
+		api.LogDebug("Python bridge get history failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d history tracks via Python bridge", len(tracks))
+	return tracks, nil
+}
+
+// RecordPlay appends a finished play to the local history with how many
+// seconds of it were heard. Plays skipped before a second was heard aren't kept.
+func (api *YouTubeMusicAPI) RecordPlay(play *Play, seconds int) error {
+	if play == nil || seconds <= 0 {
+		return nil
+	}
+	play.Seconds = seconds
+	if play.Track.Duration > 0 && play.Seconds > play.Track.Duration {
+		play.Seconds = play.Track.Duration
+	}
+
+	line, err := json.Marshal(play)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(api.historyPath(), os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("could not open history: %v", err)
+	}
+	defer f.Close()
+
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+// GetPlays reads every play in the local history, oldest first
+func (api *YouTubeMusicAPI) GetPlays() ([]Play, error) {
+	f, err := os.Open(api.historyPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []Play{}, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	plays := []Play{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var play Play
+		if err := json.Unmarshal(scanner.Bytes(), &play); err != nil || play.Track.ID == "" {
+			// Skip lines cut short by a crash rather than losing the whole history
+			continue
+		}
+		plays = append(plays, play)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return plays, nil
+}
+
+// getLocalHistory reads the most recent plays from the local history
+func (api *YouTubeMusicAPI) getLocalHistory() ([]Track, error) {
+	entries, err := api.GetPlays()
+	if err != nil {
+		return nil, err
+	}
+
+	tracks := []Track{}
+	for i := len(entries) - 1; i >= 0 && len(tracks) < maxLocalHistory; i-- {
+		tracks = append(tracks, entries[i].Track)
+	}
+
+	api.LogDebug("Read %d tracks from local history", len(tracks))
+	return tracks, nil
+}
@@ -0,0 +1,70 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// browseHome is the browse ID of the home feed
+const browseHome = "FEmusic_home"
+
+// maxHomePages bounds how many pages of shelves the home feed loads
+const maxHomePages = 3
+
+// GetHome fetches the home feed's recommendation shelves
+func (api *YouTubeMusicAPI) GetHome(ctx context.Context) ([]Shelf, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching home feed")
+
+	if !api.bridge.IsAvailable() {
+		// The home feed works unsigned too, it's just less personal
+		api.LogDebug("Python bridge not available, fetching home feed via innertube")
+		return api.getHomeNative(ctx)
+	}
+
+	shelves, err := api.bridge.GetHome(ctx)
+	if err != nil {
+		api.LogDebug("Python bridge get home failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d home shelves via Python bridge", len(shelves))
+	return shelves, nil
+}
+
+// getHomeNative fetches the home feed directly from innertube, following its continuations
+func (api *YouTubeMusicAPI) getHomeNative(ctx context.Context) ([]Shelf, error) {
+	var response BrowseResponse
+	err := api.sendRequest(ctx, "browse", map[string]interface{}{
+		"browseId": browseHome,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := response.sectionList()
+	if sections == nil {
+		return nil, fmt.Errorf("no sections found in home response")
+	}
+	shelves := carouselShelves(sections)
+
+	continuation := sections.Continuations.next()
+	for page := 1; continuation != "" && page < maxHomePages; page++ {
+		var more BrowseResponse
+		err := api.sendRequest(ctx, "browse", map[string]interface{}{
+			"continuation": continuation,
+		}, &more)
+		if err != nil || more.ContinuationContents.SectionListContinuation == nil {
+			// The first page is plenty to show
+			api.LogDebug("Home continuation failed after %d shelves: %v", len(shelves), err)
+			break
+		}
+		shelves = append(shelves, carouselShelves(more.ContinuationContents.SectionListContinuation)...)
+		continuation = more.ContinuationContents.SectionListContinuation.Continuations.next()
+	}
+
+	return shelves, nil
+}
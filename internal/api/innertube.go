@@ -0,0 +1,145 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	innertubeBaseURL       = "https://music.youtube.com/youtubei/v1/"
+	innertubeClientName    = "WEB_REMIX"
+	innertubeClientVersion = "1.20240403.01.00"
+	innertubeUserAgent     = "Mozilla/5.0 (X11; Linux x86_64; rv:124.0) Gecko/20100101 Firefox/124.0"
+)
+
+// maxErrorBody is how much of an error response is read for the debug log
+const maxErrorBody = 64 << 10
+
+// innertubeClient identifies the app a request claims to come from
+type innertubeClient struct {
+	Name      string
+	Version   string
+	UserAgent string
+	BaseURL   string
+	Extra     map[string]interface{} // Additional client context fields (device model, SDK version)
+	Anonymous bool                   // Send without cookies or authorization
+}
+
+// webRemixClient is the YouTube Music web client used for browsing and search
+var webRemixClient = innertubeClient{
+	Name:      innertubeClientName,
+	Version:   innertubeClientVersion,
+	UserAgent: innertubeUserAgent,
+	BaseURL:   innertubeBaseURL,
+}
+
+// innertubeContext builds the client context sent with every innertube request
+func (api *YouTubeMusicAPI) innertubeContext(c innertubeClient) map[string]interface{} {
+	client := map[string]interface{}{
+		"clientName":    c.Name,
+		"clientVersion": c.Version,
+		"hl":            api.language,
+		"gl":            api.region,
+	}
+	for key, value := range c.Extra {
+		client[key] = value
+	}
+
+	user := map[string]interface{}{}
+	if api.onBehalfOf != "" && !c.Anonymous {
+		user["onBehalfOfUser"] = api.onBehalfOf
+	}
+
+	return map[string]interface{}{
+		"client": client,
+		"user":   user,
+	}
+}
+
+// sendRequest posts a signed request to an innertube endpoint and decodes the JSON response into out
+func (api *YouTubeMusicAPI) sendRequest(ctx context.Context, endpoint string, body map[string]interface{}, out interface{}) error {
+	return api.sendClientRequest(ctx, webRemixClient, endpoint, body, out)
+}
+
+// sendClientRequest posts a request as the given innertube client
+func (api *YouTubeMusicAPI) sendClientRequest(ctx context.Context, c innertubeClient, endpoint string, body map[string]interface{}, out interface{}) (err error) {
+	start := time.Now()
+	defer func() { recordRequest(endpoint, start, err) }()
+
+	if body == nil {
+		body = map[string]interface{}{}
+	}
+	body["context"] = api.innertubeContext(c)
+
+	ctx, cancel := context.WithTimeout(ctx, endpointTimeout(endpoint))
+	defer cancel()
+
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode %s request: %v", endpoint, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", c.BaseURL+endpoint+"?alt=json&prettyPrint=false", bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+
+	httpClient := api.client
+	if c.Anonymous {
+		// Mobile clients reject web cookies, so send them without the jar
+		httpClient = &http.Client{Transport: api.client.Transport}
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		api.signRequest(req)
+	}
+	req.Header.Set("User-Agent", c.UserAgent)
+
+	api.LogDebug("Sending innertube request: %s (%s)", endpoint, c.Name)
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%s request failed: %v", endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	if !c.Anonymous && redirectedToLogin(resp) {
+		return withKind(ErrSessionExpired, "%s request was sent to %s, the session has likely expired", endpoint, resp.Request.URL.Host)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		data, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		api.LogDebug("Innertube %s returned status %d: %s", endpoint, resp.StatusCode, string(data))
+		return statusError(endpoint, resp.StatusCode, !c.Anonymous)
+	}
+
+	// The transport asks for gzip and inflates the body as it's read. Decoding
+	// from the stream spares holding a playlist page of several megabytes in
+	// memory next to what it decodes into.
+	var reader io.Reader = resp.Body
+	var recorded bytes.Buffer
+	if api.record != nil {
+		reader = io.TeeReader(reader, &recorded)
+	}
+	if err := json.NewDecoder(reader).Decode(out); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("failed to parse %s response: %v", endpoint, err)
+	}
+
+	// The connection only goes back to the pool once the body is read to the end
+	io.Copy(io.Discard, reader)
+
+	if api.record != nil {
+		api.record(endpoint, recorded.Bytes())
+	}
+	return nil
+}
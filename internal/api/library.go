@@ -0,0 +1,161 @@
+package api
+
+import "context"
+
+// Library browse IDs
+const (
+	likedSongsPlaylistID   = "LM"
+	browseLibraryAlbums    = "FEmusic_liked_albums"
+	browseLibraryArtists   = "FEmusic_library_corpus_track_artists"
+	browseLibrarySubscribe = "FEmusic_library_corpus_artists"
+)
+
+// errLibraryUnavailable is returned when neither the bridge nor a signed session can reach the library
+var errLibraryUnavailable = withKind(ErrBackendUnavailable, "library requires the Python bridge or a SAPISID cookie")
+
+// GetLikedSongs fetches the user's liked songs
+func (api *YouTubeMusicAPI) GetLikedSongs(ctx context.Context) ([]Track, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching liked songs")
+
+	if !api.bridge.IsAvailable() {
+		if api.getSAPISID() == "" {
+			return nil, errLibraryUnavailable
+		}
+		api.LogDebug("Python bridge not available, fetching liked songs via innertube")
+		return api.getPlaylistTracksNative(ctx, likedSongsPlaylistID)
+	}
+
+	tracks, err := api.bridge.GetLikedSongs(ctx)
+	if err != nil {
+		api.LogDebug("Python bridge get liked songs failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d liked songs via Python bridge", len(tracks))
+	return tracks, nil
+}
+
+// GetLibraryAlbums fetches the albums saved to the user's library
+func (api *YouTubeMusicAPI) GetLibraryAlbums(ctx context.Context) ([]Album, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching library albums")
+
+	if !api.bridge.IsAvailable() {
+		if api.getSAPISID() == "" {
+			return nil, errLibraryUnavailable
+		}
+		api.LogDebug("Python bridge not available, fetching library albums via innertube")
+		return api.getLibraryAlbumsNative(ctx)
+	}
+
+	albums, err := api.bridge.GetLibraryAlbums(ctx)
+	if err != nil {
+		api.LogDebug("Python bridge get library albums failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d library albums via Python bridge", len(albums))
+	return albums, nil
+}
+
+// GetLibraryArtists fetches the artists of songs in the user's library
+func (api *YouTubeMusicAPI) GetLibraryArtists(ctx context.Context) ([]Artist, error) {
+	return api.getLibraryArtists(ctx, "artists", browseLibraryArtists, api.bridge.GetLibraryArtists)
+}
+
+// GetLibrarySubscriptions fetches the artists the user is subscribed to
+func (api *YouTubeMusicAPI) GetLibrarySubscriptions(ctx context.Context) ([]Artist, error) {
+	return api.getLibraryArtists(ctx, "subscriptions", browseLibrarySubscribe, api.bridge.GetLibrarySubscriptions)
+}
+
+// getLibraryArtists fetches one of the artist lists of the library
+func (api *YouTubeMusicAPI) getLibraryArtists(ctx context.Context, name, browseID string, viaBridge func(context.Context) ([]Artist, error)) ([]Artist, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching library %s", name)
+
+	if !api.bridge.IsAvailable() {
+		if api.getSAPISID() == "" {
+			return nil, errLibraryUnavailable
+		}
+		api.LogDebug("Python bridge not available, fetching library %s via innertube", name)
+		return api.getLibraryArtistsNative(ctx, browseID)
+	}
+
+	artists, err := viaBridge(ctx)
+	if err != nil {
+		api.LogDebug("Python bridge get library %s failed: %v", name, err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d library %s via Python bridge", len(artists), name)
+	return artists, nil
+}
+
+// getLibraryAlbumsNative fetches the library album grid directly from innertube
+func (api *YouTubeMusicAPI) getLibraryAlbumsNative(ctx context.Context) ([]Album, error) {
+	return api.getAlbumGridNative(ctx, browseLibraryAlbums)
+}
+
+// getAlbumGridNative fetches a browse page made of a grid of albums
+func (api *YouTubeMusicAPI) getAlbumGridNative(ctx context.Context, browseID string) ([]Album, error) {
+	var response BrowseResponse
+	err := api.sendRequest(ctx, "browse", map[string]interface{}{
+		"browseId": browseID,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	albums := []Album{}
+	grid := response.sectionList().grid()
+	if grid == nil {
+		// An empty page has no grid at all
+		return albums, nil
+	}
+
+	for _, item := range grid.Items {
+		if item.MusicTwoRowItemRenderer == nil {
+			continue
+		}
+		if album, ok := item.MusicTwoRowItemRenderer.toAlbum(); ok {
+			albums = append(albums, album)
+		}
+	}
+
+	return albums, nil
+}
+
+// getLibraryArtistsNative fetches a library artist shelf directly from innertube
+func (api *YouTubeMusicAPI) getLibraryArtistsNative(ctx context.Context, browseID string) ([]Artist, error) {
+	var response BrowseResponse
+	err := api.sendRequest(ctx, "browse", map[string]interface{}{
+		"browseId": browseID,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	artists := []Artist{}
+	for _, shelf := range response.sectionList().shelves() {
+		for _, item := range shelf.Contents {
+			if item.MusicResponsiveListItemRenderer == nil {
+				continue
+			}
+			if artist, ok := item.MusicResponsiveListItemRenderer.toArtist(); ok {
+				artists = append(artists, artist)
+			}
+		}
+	}
+
+	return artists, nil
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http/cookiejar"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// ytmusicapiFiles are the credential files the ytmusicapi command writes, in the order they're imported
+var ytmusicapiFiles = []string{"headers_auth.json", "oauth_auth.json", "oauth.json"}
+
+// credentialFiles returns every file a login is saved in, ytmusic's own cookies first
+func (api *YouTubeMusicAPI) credentialFiles() []string {
+	files := []string{filepath.Join(api.configPath, "cookies.json")}
+	for _, name := range ytmusicapiFiles {
+		files = append(files, filepath.Join(api.configPath, name))
+	}
+	return files
+}
+
+// CredentialsModTime returns when the saved login last changed, zero when
+// none is saved, so other processes logging in or out can be noticed
+func (api *YouTubeMusicAPI) CredentialsModTime() time.Time {
+	var latest time.Time
+	for _, path := range api.credentialFiles() {
+		if info, err := os.Stat(path); err == nil && info.ModTime().After(latest) {
+			latest = info.ModTime()
+		}
+	}
+	return latest
+}
+
+// ReloadCredentials forgets the login held in memory and reads the saved one
+// again, such as after `ytmusic login` or `ytmusic logout`, reporting whether
+// there is one
+func (api *YouTubeMusicAPI) ReloadCredentials() bool {
+	api.forgetLogin()
+	api.loadCookies()
+	if !api.IsLoggedIn {
+		api.loadYTMusicAPICredentials()
+	}
+	api.loadAccount()
+	return api.IsLoggedIn
+}
+
+// forgetLogin drops the login held in memory, leaving the saved one alone
+func (api *YouTubeMusicAPI) forgetLogin() {
+	api.client.Jar, _ = cookiejar.New(nil)
+	api.oauth = nil
+	api.IsLoggedIn = false
+	api.authUser = "0"
+	api.onBehalfOf = ""
+	api.accountChosen = false
+}
+
+// Logout forgets the login and removes every file it's saved in, including
+// the ones ytmusicapi wrote, so it isn't imported again at the next start
+func (api *YouTubeMusicAPI) Logout() error {
+	if err := api.ResetCookies(); err != nil {
+		return err
+	}
+	return api.removeCredentials(ytmusicapiFiles...)
+}
+
+// removeCredentials removes saved credential files, by name
+func (api *YouTubeMusicAPI) removeCredentials(names ...string) error {
+	for _, name := range names {
+		path := filepath.Join(api.configPath, name)
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("could not remove %s: %v", path, err)
+		}
+		api.LogDebug("Removed credentials %s", path)
+	}
+	return nil
+}
+
+// LoginWithCookie logs in with a pasted cookie, as ManualLogin does, once
+// YouTube Music accepts it. It replaces any login saved before, which would
+// otherwise be imported ahead of it.
+func (api *YouTubeMusicAPI) LoginWithCookie(ctx context.Context, cookie string) error {
+	api.forgetLogin()
+	if err := api.ManualLogin(cookie); err != nil {
+		return err
+	}
+	if err := api.ValidateSession(ctx); err != nil {
+		// A rejected cookie isn't worth keeping; other errors say nothing about it
+		if errors.Is(err, ErrSessionExpired) {
+			api.ResetCookies()
+			return fmt.Errorf("YouTube Music didn't accept the cookie - copy it again from a signed in music.youtube.com")
+		}
+		api.LogDebug("Could not check the new login: %v", err)
+	}
+	// The account chosen belonged to the login replaced
+	return api.removeCredentials(append(ytmusicapiFiles, "account.json")...)
+}
+
+// BrowserCookie reads the YouTube cookies of a browser profile, such as
+// "firefox" or "chrome:Profile 1", as a Cookie header, using yt-dlp's
+// --cookies-from-browser
+func (api *YouTubeMusicAPI) BrowserCookie(ctx context.Context, browser string) (string, error) {
+	if _, err := exec.LookPath("yt-dlp"); err != nil {
+		return "", fmt.Errorf("yt-dlp not found, which reads the browser's cookies")
+	}
+	dir, err := os.MkdirTemp("", "ytmusic-cookies")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(dir)
+
+	// Without a URL yt-dlp only writes out the cookies it read, then complains
+	// that there's nothing to download
+	path := filepath.Join(dir, "cookies.txt")
+	output, runErr := exec.CommandContext(ctx, "yt-dlp", "--cookies-from-browser", browser, "--cookies", path).CombinedOutput()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("yt-dlp could not read the cookies of %s: %v: %s", browser, runErr, lastLine(output))
+	}
+
+	header := youtubeCookieHeader(data)
+	if !hasLoginCookie(parseCookieHeader(header)) {
+		return "", fmt.Errorf("%s isn't signed in to YouTube Music - log in to music.youtube.com there first", browser)
+	}
+	return header, nil
+}
+
+// youtubeCookieHeader picks the YouTube cookies out of a Netscape cookies
+// file and joins them into a Cookie header
+func youtubeCookieHeader(data []byte) string {
+	var pairs []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		// Lines are domain, subdomains, path, secure, expiry, name and value
+		line := strings.TrimPrefix(scanner.Text(), "#HttpOnly_")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) != 7 || !strings.HasSuffix(fields[0], "youtube.com") {
+			continue
+		}
+		pairs = append(pairs, fields[5]+"="+fields[6])
+	}
+	return strings.Join(pairs, "; ")
+}
+
+// OAuthLoginCommand returns the ytmusicapi command that signs in with a
+// Google device code, writing its token where it's imported from. It prompts
+// on the terminal, so it runs attached to it.
+func (api *YouTubeMusicAPI) OAuthLoginCommand(ctx context.Context) (*exec.Cmd, error) {
+	command, err := api.bridge.ytmusicapiCommand()
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"oauth", "--file", filepath.Join(api.configPath, "oauth_auth.json")}
+	// ytmusicapi asks for the OAuth client unless it's given
+	if clientID, clientSecret, err := api.loadClientSecret(); err == nil && clientID != "" {
+		args = append(args, "--client-id", clientID, "--client-secret", clientSecret)
+	}
+	return exec.CommandContext(ctx, command, args...), nil
+}
+
+// FinishOAuthLogin imports the token OAuthLoginCommand wrote, replacing the
+// cookies and browser headers that would otherwise be used ahead of it
+func (api *YouTubeMusicAPI) FinishOAuthLogin() error {
+	if err := api.removeCredentials("cookies.json", "headers_auth.json", "account.json"); err != nil {
+		return err
+	}
+	if !api.ReloadCredentials() || api.oauth == nil {
+		return fmt.Errorf("ytmusicapi didn't write a token to %s", filepath.Join(api.configPath, "oauth_auth.json"))
+	}
+	return nil
+}
+
+// ytmusicapiCommand finds the ytmusicapi command: the one installed along with
+// the bridge's Python in its virtualenv, or one on PATH
+func (pb *PythonBridge) ytmusicapiCommand() (string, error) {
+	name := "ytmusicapi"
+	if runtime.GOOS == "windows" {
+		name += ".exe"
+	}
+	pb.mu.Lock()
+	python := pb.pythonPath
+	pb.mu.Unlock()
+	if filepath.IsAbs(python) {
+		path := filepath.Join(filepath.Dir(python), name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	path, err := exec.LookPath("ytmusicapi")
+	if err != nil {
+		return "", fmt.Errorf("ytmusicapi not found, install it with pip install ytmusicapi")
+	}
+	return path, nil
+}
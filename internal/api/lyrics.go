@@ -0,0 +1,103 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Lyrics holds a track's lyrics, optionally with per-line timing
+type Lyrics struct {
+	VideoID string
+	Text    string
+	Source  string      // e.g. "Source: LyricFind"
+	Lines   []LyricLine // Set only when timed lyrics are available
+}
+
+// LyricLine is one line of timed lyrics
+type LyricLine struct {
+	Text  string
+	Start int // milliseconds
+	End   int // milliseconds
+}
+
+// Timed reports whether the lyrics have per-line timing
+func (l *Lyrics) Timed() bool {
+	return len(l.Lines) > 0
+}
+
+// errNoLyrics is returned when a track has no lyrics
+var errNoLyrics = fmt.Errorf("no lyrics available for this track")
+
+// GetLyrics fetches the lyrics of a track
+func (api *YouTubeMusicAPI) GetLyrics(ctx context.Context, videoID string) (*Lyrics, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching lyrics for: %s", videoID)
+
+	if !api.bridge.IsAvailable() {
+		api.LogDebug("Python bridge not available, fetching lyrics via innertube")
+		return api.getLyricsNative(ctx, videoID)
+	}
+
+	lyrics, err := api.bridge.GetLyrics(ctx, videoID)
+	if err != nil {
+		api.LogDebug("Python bridge get lyrics failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found lyrics via Python bridge (timed: %v)", lyrics.Timed())
+	return lyrics, nil
+}
+
+// getLyricsNative finds the lyrics tab of the watch page and browses to it
+func (api *YouTubeMusicAPI) getLyricsNative(ctx context.Context, videoID string) (*Lyrics, error) {
+	var next WatchNextResponse
+	err := api.sendRequest(ctx, "next", map[string]interface{}{
+		"videoId":                       videoID,
+		"enablePersistentPlaylistPanel": true,
+		"isAudioOnly":                   true,
+	}, &next)
+	if err != nil {
+		return nil, err
+	}
+
+	// The lyrics tab is disabled (no endpoint) when a track has none
+	browseID := ""
+	for _, tab := range next.Contents.SingleColumnMusicWatchNextResultsRenderer.TabbedRenderer.WatchNextTabbedResultsRenderer.Tabs {
+		endpoint := tab.TabRenderer.Endpoint
+		if endpoint != nil && endpoint.BrowseEndpoint != nil && strings.HasPrefix(endpoint.BrowseEndpoint.BrowseID, "MPLYt") {
+			browseID = endpoint.BrowseEndpoint.BrowseID
+			break
+		}
+	}
+	if browseID == "" {
+		return nil, errNoLyrics
+	}
+
+	var response BrowseResponse
+	err = api.sendRequest(ctx, "browse", map[string]interface{}{
+		"browseId": browseID,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	sections := response.sectionList()
+	if sections == nil {
+		return nil, errNoLyrics
+	}
+	for _, section := range sections.Contents {
+		if shelf := section.MusicDescriptionShelfRenderer; shelf != nil {
+			return &Lyrics{
+				VideoID: videoID,
+				Text:    shelf.Description.Text(),
+				Source:  shelf.Footer.Text(),
+			}, nil
+		}
+	}
+
+	return nil, errNoLyrics
+}
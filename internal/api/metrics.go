@@ -0,0 +1,34 @@
+package api
+
+import (
+	"expvar"
+	"time"
+)
+
+// Innertube requests by endpoint, published at /debug/vars when ytmusic runs with -debug
+var (
+	requestCount   = expvar.NewMap("innertube_requests")
+	requestErrors  = expvar.NewMap("innertube_errors")
+	requestMillis  = expvar.NewMap("innertube_ms")         // Total time spent, to compare with the count
+	slowestRequest = expvar.NewMap("innertube_slowest_ms") // Longest single request
+)
+
+// recordRequest counts a finished request to an endpoint and how long it took
+func recordRequest(endpoint string, start time.Time, err error) {
+	elapsed := time.Since(start).Milliseconds()
+	requestCount.Add(endpoint, 1)
+	requestMillis.Add(endpoint, elapsed)
+	if err != nil {
+		requestErrors.Add(endpoint, 1)
+	}
+	if slowest, ok := slowestRequest.Get(endpoint).(*expvar.Int); !ok || slowest.Value() < elapsed {
+		slowestRequest.Set(endpoint, intVar(elapsed))
+	}
+}
+
+// intVar returns an expvar.Int holding n
+func intVar(n int64) *expvar.Int {
+	v := new(expvar.Int)
+	v.Set(n)
+	return v
+}
@@ -1,24 +1,257 @@
 package api
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/url"
+	"os/exec"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 )
 
+// StreamQuality selects a bitrate tier for audio streams
+type StreamQuality string
+
+const (
+	QualityLow    StreamQuality = "low"    // Smallest available audio stream, ~48kbps
+	QualityMedium StreamQuality = "medium" // Closest to ~128kbps
+	QualityHigh   StreamQuality = "high"   // Best available audio stream, up to ~256kbps
+)
+
+// Next returns the quality tier after q, wrapping from high back to low
+func (q StreamQuality) Next() StreamQuality {
+	switch q {
+	case QualityLow:
+		return QualityMedium
+	case QualityMedium:
+		return QualityHigh
+	}
+	return QualityLow
+}
+
+// StreamCodec restricts audio streams to a codec family
+type StreamCodec string
+
+const (
+	CodecAny  StreamCodec = "any"
+	CodecOpus StreamCodec = "opus"
+	CodecM4A  StreamCodec = "m4a"
+)
+
+// StreamInfo describes a resolved audio stream
+type StreamInfo struct {
+	URL      string
+	Codec    string    // e.g. "opus" or "mp4a.40.2"
+	Bitrate  int       // kbps
+	Duration int       // seconds, 0 if unknown
+	Expires  time.Time // zero if the URL does not expire
+}
+
+// ytdlpFormat is a single entry of yt-dlp's "formats" list
+type ytdlpFormat struct {
+	FormatID string  `json:"format_id"`
+	URL      string  `json:"url"`
+	Ext      string  `json:"ext"`
+	ACodec   string  `json:"acodec"`
+	VCodec   string  `json:"vcodec"`
+	ABR      float64 `json:"abr"`
+}
+
+// ytdlpInfo is the subset of `yt-dlp -j` output we use
+type ytdlpInfo struct {
+	Duration float64       `json:"duration"`
+	Formats  []ytdlpFormat `json:"formats"`
+}
+
+// SetStreamPreferences sets the quality tier and codec used when resolving streams
+func (api *YouTubeMusicAPI) SetStreamPreferences(quality StreamQuality, codec StreamCodec) {
+	api.streamQuality = quality
+	api.streamCodec = codec
+}
+
+// SetMaxBitrate keeps resolved streams at or under a bitrate in kbps, 0 for no cap
+func (api *YouTubeMusicAPI) SetMaxBitrate(kbps int) {
+	api.maxBitrate = kbps
+}
+
+// SetMaxRate holds yt-dlp to a download rate, such as "500K"; empty for no cap
+func (api *YouTubeMusicAPI) SetMaxRate(rate string) {
+	api.maxRate = rate
+}
+
+// StreamPreferences returns the quality tier and codec used when resolving streams
+func (api *YouTubeMusicAPI) StreamPreferences() (StreamQuality, StreamCodec) {
+	return api.streamQuality, api.streamCodec
+}
+
+// SetLocale sets the language and region YouTube Music answers innertube requests in
+func (api *YouTubeMusicAPI) SetLocale(language, region string) {
+	api.language = language
+	api.region = region
+}
+
 // GetStreamURL gets the streaming URL for a track
-func (api *YouTubeMusicAPI) GetStreamURL(trackID string) (string, error) {
+func (api *YouTubeMusicAPI) GetStreamURL(ctx context.Context, trackID string) (string, error) {
+	info, err := api.GetStreamInfo(ctx, trackID)
+	if err != nil {
+		return "", err
+	}
+	return info.URL, nil
+}
+
+// GetStreamInfo resolves an audio-only stream for a track.
+// A stream resolved earlier is reused until shortly before it expires. Otherwise
+// resolvers are tried in order: native innertube, yt-dlp, then the plain watch URL.
+func (api *YouTubeMusicAPI) GetStreamInfo(ctx context.Context, trackID string) (*StreamInfo, error) {
 	if !api.IsLoggedIn {
-		return "", fmt.Errorf("not logged in")
+		return nil, ErrNotLoggedIn
 	}
 
 	api.LogDebug("Getting stream URL for track ID: %s", trackID)
-	
-	// YouTube Music doesn't provide direct stream URLs easily
-	// For our TUI, we'll use the YouTube watch URL which works with mpv
-	url := "https://www.youtube.com/watch?v=" + trackID
-	
-	// For a real implementation, you could use youtube-dl or yt-dlp to extract
-	// the actual stream URL, but that would require additional dependencies.
-	
-	api.LogDebug("Returning stream URL: %s", url)
-	return url, nil
+	watchURL := "https://www.youtube.com/watch?v=" + trackID
+
+	key := api.streamKey(trackID)
+	if cached, ok := api.streams.stream(key); ok {
+		api.LogDebug("Reusing %s stream of %s, which expires at %s", cached.Codec, trackID, cached.Expires.Format("15:04"))
+		return &cached, nil
+	}
+
+	info, err := api.resolveNative(ctx, trackID)
+	if err == nil {
+		api.LogDebug("Resolved %s stream at %dkbps natively", info.Codec, info.Bitrate)
+		api.streams.putStream(key, trackID, *info)
+		return info, nil
+	}
+	api.LogDebug("Native stream resolution failed: %v", err)
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	info, err = api.extractWithYtdlp(ctx, watchURL)
+	if err == nil {
+		api.LogDebug("Resolved %s stream at %dkbps with yt-dlp", info.Codec, info.Bitrate)
+		api.streams.putStream(key, trackID, *info)
+		return info, nil
+	}
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	// mpv can still try the watch URL through its own ytdl hook
+	api.LogDebug("yt-dlp extraction failed (%v), falling back to watch URL", err)
+	return &StreamInfo{URL: watchURL, Duration: api.streams.duration(trackID)}, nil
+}
+
+// extractWithYtdlp asks yt-dlp for the available formats and picks an audio stream
+func (api *YouTubeMusicAPI) extractWithYtdlp(ctx context.Context, watchURL string) (*StreamInfo, error) {
+	if _, err := exec.LookPath("yt-dlp"); err != nil {
+		return nil, fmt.Errorf("yt-dlp not found")
+	}
+
+	args := []string{"-j", "--no-playlist", "--no-warnings"}
+	if api.maxRate != "" {
+		args = append(args, "--limit-rate", api.maxRate)
+	}
+	output, err := exec.CommandContext(ctx, "yt-dlp", append(args, watchURL)...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("yt-dlp failed: %v", err)
+	}
+
+	var info ytdlpInfo
+	if err := json.Unmarshal(output, &info); err != nil {
+		return nil, fmt.Errorf("failed to parse yt-dlp output: %v", err)
+	}
+
+	format, ok := selectAudioFormat(info.Formats, api.streamQuality, api.streamCodec, api.maxBitrate)
+	if !ok {
+		return nil, fmt.Errorf("no audio-only format matches codec %q", api.streamCodec)
+	}
+
+	return &StreamInfo{
+		URL:      format.URL,
+		Codec:    format.ACodec,
+		Bitrate:  int(format.ABR),
+		Duration: int(info.Duration),
+		Expires:  streamExpiry(format.URL),
+	}, nil
+}
+
+// selectAudioFormat picks the audio-only format matching the codec and quality tier,
+// among those at or under maxBitrate kbps when that's set
+func selectAudioFormat(formats []ytdlpFormat, quality StreamQuality, codec StreamCodec, maxBitrate int) (ytdlpFormat, bool) {
+	var candidates []ytdlpFormat
+	for _, f := range formats {
+		if f.URL == "" || f.VCodec != "none" || f.ACodec == "" || f.ACodec == "none" {
+			continue
+		}
+		switch codec {
+		case CodecOpus:
+			if !strings.Contains(f.ACodec, "opus") {
+				continue
+			}
+		case CodecM4A:
+			if f.Ext != "m4a" {
+				continue
+			}
+		}
+		candidates = append(candidates, f)
+	}
+
+	if len(candidates) == 0 {
+		return ytdlpFormat{}, false
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].ABR < candidates[j].ABR
+	})
+
+	// Under a cap, the smallest stream is kept even if it's over
+	if maxBitrate > 0 {
+		capped := candidates[:1]
+		for _, f := range candidates[1:] {
+			if f.ABR <= float64(maxBitrate) {
+				capped = append(capped, f)
+			}
+		}
+		candidates = capped
+	}
+
+	switch quality {
+	case QualityLow:
+		return candidates[0], true
+	case QualityMedium:
+		best := candidates[0]
+		for _, f := range candidates {
+			if abs(f.ABR-128) < abs(best.ABR-128) {
+				best = f
+			}
+		}
+		return best, true
+	default:
+		return candidates[len(candidates)-1], true
+	}
+}
+
+// streamExpiry reads the "expire" unix timestamp from a googlevideo URL
+func streamExpiry(streamURL string) time.Time {
+	parsed, err := url.Parse(streamURL)
+	if err != nil {
+		return time.Time{}
+	}
+	expire, err := strconv.ParseInt(parsed.Query().Get("expire"), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	return time.Unix(expire, 0)
+}
+
+// abs returns the absolute value of f
+func abs(f float64) float64 {
+	if f < 0 {
+		return -f
+	}
+	return f
 }
@@ -1,7 +1,9 @@
 package api
 
 import (
+	"context"
 	"fmt"
+	"strings"
 )
 
 // Playlist represents a YouTube Music playlist
@@ -26,6 +28,322 @@ func (p Playlist) Title() string {
 
 // Description implements list.Item interface for displaying in the list
 func (p Playlist) Description() string {
+	// Mixes and recommended playlists come with a ready-made subtitle instead
+	if p.Author == "" && p.PlaylistDesc != "" {
+		return p.PlaylistDesc
+	}
 	return fmt.Sprintf("by %s (%d tracks)", p.Author, p.TrackCount)
 }
 
+// getLibraryPlaylistsNative fetches the user's library playlists directly from innertube
+func (api *YouTubeMusicAPI) getLibraryPlaylistsNative(ctx context.Context) ([]Playlist, error) {
+	var response BrowseResponse
+	err := api.sendRequest(ctx, "browse", map[string]interface{}{
+		"browseId": "FEmusic_liked_playlists",
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+	
+	grid := response.sectionList().grid()
+	if grid == nil {
+		return nil, fmt.Errorf("no grid found in library response")
+	}
+	
+	playlists := []Playlist{}
+	for _, item := range grid.Items {
+		renderer := item.MusicTwoRowItemRenderer
+		if renderer == nil || renderer.NavigationEndpoint == nil || renderer.NavigationEndpoint.BrowseEndpoint == nil {
+			// The "New playlist" tile has no browse endpoint
+			continue
+		}
+		
+		playlists = append(playlists, Playlist{
+			ID:            strings.TrimPrefix(renderer.NavigationEndpoint.BrowseEndpoint.BrowseID, "VL"),
+			PlaylistTitle: renderer.Title.First(),
+			Author:        renderer.Subtitle.First(),
+		})
+	}
+	
+	return playlists, nil
+}
+
+// maxPlaylistPages bounds continuation fetching so a bad token can't loop forever
+const maxPlaylistPages = 200
+
+// getPlaylistTracksNative fetches all of a playlist's tracks directly from innertube
+func (api *YouTubeMusicAPI) getPlaylistTracksNative(ctx context.Context, playlistID string) ([]Track, error) {
+	tracks, continuation, err := api.getPlaylistTracksPageNative(ctx, playlistID, "")
+	if err != nil {
+		return nil, err
+	}
+	
+	for page := 1; continuation != "" && page < maxPlaylistPages; page++ {
+		var more []Track
+		more, continuation, err = api.getPlaylistTracksPageNative(ctx, playlistID, continuation)
+		if err != nil {
+			// Return what we have rather than nothing
+			api.LogDebug("Playlist continuation failed after %d tracks: %v", len(tracks), err)
+			break
+		}
+		tracks = append(tracks, more...)
+	}
+	
+	return tracks, nil
+}
+
+// getPlaylistTracksPageNative fetches one page of a playlist, returning the token for the next page
+func (api *YouTubeMusicAPI) getPlaylistTracksPageNative(ctx context.Context, playlistID, continuation string) ([]Track, string, error) {
+	if continuation != "" {
+		var response BrowseResponse
+		err := api.sendRequest(ctx, "browse", map[string]interface{}{
+			"continuation": continuation,
+		}, &response)
+		if err != nil {
+			return nil, "", err
+		}
+		
+		tracks, next := response.continuationTracks()
+		return tracks, next, nil
+	}
+	
+	browseID := playlistID
+	if !strings.HasPrefix(browseID, "VL") {
+		browseID = "VL" + browseID
+	}
+	
+	var response BrowseResponse
+	err := api.sendRequest(ctx, "browse", map[string]interface{}{
+		"browseId": browseID,
+	}, &response)
+	if err != nil {
+		return nil, "", err
+	}
+	
+	tracks, next := response.playlistTracks()
+	return tracks, next, nil
+}
+
+// playlistTracks returns the tracks of a playlist's first page and the token for the next page
+func (r *BrowseResponse) playlistTracks() ([]Track, string) {
+	return shelvesPage(r.sectionList().shelves())
+}
+
+// continuationTracks returns the tracks of a continuation response and the token for the next one
+func (r *BrowseResponse) continuationTracks() ([]Track, string) {
+	// Newer responses append items; older ones return a shelf continuation
+	for _, action := range r.OnResponseReceivedActions {
+		items := action.AppendContinuationItemsAction.ContinuationItems
+		if len(items) > 0 {
+			return tracksFromItems(items), continuationFromItems(items)
+		}
+	}
+	if shelf := r.ContinuationContents.MusicPlaylistShelfContinuation; shelf != nil {
+		return tracksFromItems(shelf.Contents), shelf.continuation()
+	}
+	if shelf := r.ContinuationContents.MusicShelfContinuation; shelf != nil {
+		return tracksFromItems(shelf.Contents), shelf.continuation()
+	}
+	return []Track{}, ""
+}
+
+// shelvesPage collects the tracks of a page of shelves and the token of the last shelf that continues
+func shelvesPage(shelves []*MusicShelfRenderer) ([]Track, string) {
+	next := ""
+	for _, shelf := range shelves {
+		if token := shelf.continuation(); token != "" {
+			next = token
+		}
+	}
+	return tracksFromShelves(shelves), next
+}
+
+// tracksFromShelves collects the playable tracks of every shelf in order
+func tracksFromShelves(shelves []*MusicShelfRenderer) []Track {
+	tracks := []Track{}
+	for _, shelf := range shelves {
+		tracks = append(tracks, tracksFromItems(shelf.Contents)...)
+	}
+	return tracks
+}
+
+// tracksFromItems collects the playable tracks of a list of shelf items
+func tracksFromItems(items []ShelfItem) []Track {
+	tracks := []Track{}
+	for _, item := range items {
+		if item.MusicResponsiveListItemRenderer == nil {
+			continue
+		}
+		if track, ok := item.MusicResponsiveListItemRenderer.toTrack(); ok {
+			tracks = append(tracks, track)
+		}
+	}
+	return tracks
+}
+
+// errEditUnavailable is returned when neither the bridge nor a signed session can edit playlists
+var errEditUnavailable = withKind(ErrBackendUnavailable, "editing playlists requires the Python bridge or a SAPISID cookie")
+
+// CreatePlaylist creates a private playlist and returns its ID
+func (api *YouTubeMusicAPI) CreatePlaylist(ctx context.Context, title, description string) (string, error) {
+	if !api.IsLoggedIn {
+		return "", ErrNotLoggedIn
+	}
+	
+	api.LogDebug("Creating playlist: %s", title)
+	
+	if !api.bridge.IsAvailable() {
+		if api.getSAPISID() == "" {
+			return "", errEditUnavailable
+		}
+		
+		var response struct {
+			PlaylistID string `json:"playlistId"`
+		}
+		err := api.sendRequest(ctx, "playlist/create", map[string]interface{}{
+			"title":         title,
+			"description":   description,
+			"privacyStatus": "PRIVATE",
+		}, &response)
+		if err != nil {
+			return "", err
+		}
+		if response.PlaylistID == "" {
+			return "", fmt.Errorf("playlist was not created")
+		}
+		return response.PlaylistID, nil
+	}
+	
+	return api.bridge.CreatePlaylist(ctx, title, description)
+}
+
+// RenamePlaylist changes a playlist's title
+func (api *YouTubeMusicAPI) RenamePlaylist(ctx context.Context, playlistID, title string) error {
+	if !api.IsLoggedIn {
+		return ErrNotLoggedIn
+	}
+	
+	// The cached copy is stale once the playlist changes
+	api.cache.invalidate(api.playlistCacheKey(playlistID))
+	
+	api.LogDebug("Renaming playlist %s to: %s", playlistID, title)
+	
+	if !api.bridge.IsAvailable() {
+		if api.getSAPISID() == "" {
+			return errEditUnavailable
+		}
+		return api.editPlaylistNative(ctx, playlistID, []map[string]interface{}{
+			{"action": "ACTION_SET_PLAYLIST_NAME", "playlistName": title},
+		})
+	}
+	
+	return api.bridge.RenamePlaylist(ctx, playlistID, title)
+}
+
+// DeletePlaylist deletes one of the user's playlists
+func (api *YouTubeMusicAPI) DeletePlaylist(ctx context.Context, playlistID string) error {
+	if !api.IsLoggedIn {
+		return ErrNotLoggedIn
+	}
+	
+	// The cached copy is stale once the playlist changes
+	api.cache.invalidate(api.playlistCacheKey(playlistID))
+	
+	api.LogDebug("Deleting playlist: %s", playlistID)
+	
+	if !api.bridge.IsAvailable() {
+		if api.getSAPISID() == "" {
+			return errEditUnavailable
+		}
+		var response map[string]interface{}
+		return api.sendRequest(ctx, "playlist/delete", map[string]interface{}{
+			"playlistId": strings.TrimPrefix(playlistID, "VL"),
+		}, &response)
+	}
+	
+	return api.bridge.DeletePlaylist(ctx, playlistID)
+}
+
+// AddPlaylistItems adds videos to one of the user's playlists, skipping ones already in it
+func (api *YouTubeMusicAPI) AddPlaylistItems(ctx context.Context, playlistID string, videoIDs []string) error {
+	if !api.IsLoggedIn {
+		return ErrNotLoggedIn
+	}
+	
+	// The cached copy is stale once the playlist changes
+	api.cache.invalidate(api.playlistCacheKey(playlistID))
+	
+	api.LogDebug("Adding %d videos to playlist: %s", len(videoIDs), playlistID)
+	
+	if !api.bridge.IsAvailable() {
+		if api.getSAPISID() == "" {
+			return errEditUnavailable
+		}
+		actions := make([]map[string]interface{}, len(videoIDs))
+		for i, videoID := range videoIDs {
+			actions[i] = map[string]interface{}{
+				"action":       "ACTION_ADD_VIDEO",
+				"addedVideoId": videoID,
+				"dedupeOption": "DEDUPE_OPTION_SKIP",
+			}
+		}
+		return api.editPlaylistNative(ctx, playlistID, actions)
+	}
+	
+	return api.bridge.AddPlaylistItems(ctx, playlistID, videoIDs)
+}
+
+// RemovePlaylistItems removes tracks from one of the user's playlists.
+// The tracks must have been loaded from that playlist so they carry their SetVideoID.
+func (api *YouTubeMusicAPI) RemovePlaylistItems(ctx context.Context, playlistID string, tracks []Track) error {
+	if !api.IsLoggedIn {
+		return ErrNotLoggedIn
+	}
+	
+	// The cached copy is stale once the playlist changes
+	api.cache.invalidate(api.playlistCacheKey(playlistID))
+	
+	for _, track := range tracks {
+		if track.SetVideoID == "" {
+			return fmt.Errorf("%s was not loaded from a playlist", track.TrackTitle)
+		}
+	}
+	
+	api.LogDebug("Removing %d tracks from playlist: %s", len(tracks), playlistID)
+	
+	if !api.bridge.IsAvailable() {
+		if api.getSAPISID() == "" {
+			return errEditUnavailable
+		}
+		actions := make([]map[string]interface{}, len(tracks))
+		for i, track := range tracks {
+			actions[i] = map[string]interface{}{
+				"action":         "ACTION_REMOVE_VIDEO",
+				"removedVideoId": track.ID,
+				"setVideoId":     track.SetVideoID,
+			}
+		}
+		return api.editPlaylistNative(ctx, playlistID, actions)
+	}
+	
+	return api.bridge.RemovePlaylistItems(ctx, playlistID, tracks)
+}
+
+// editPlaylistNative applies a list of edit actions to a playlist through innertube
+func (api *YouTubeMusicAPI) editPlaylistNative(ctx context.Context, playlistID string, actions []map[string]interface{}) error {
+	var response struct {
+		Status string `json:"status"`
+	}
+	err := api.sendRequest(ctx, "browse/edit_playlist", map[string]interface{}{
+		"playlistId": strings.TrimPrefix(playlistID, "VL"),
+		"actions":    actions,
+	}, &response)
+	if err != nil {
+		return err
+	}
+	
+	if response.Status != "STATUS_SUCCEEDED" {
+		return fmt.Errorf("playlist edit failed: %s", response.Status)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// errPodcastsUnavailable is returned when podcasts are requested without the Python bridge
+var errPodcastsUnavailable = withKind(ErrBackendUnavailable, "podcasts require the Python bridge")
+
+// Podcast represents a podcast show
+type Podcast struct {
+	ID           string // Browse ID (MPSPP...)
+	PodcastTitle string
+	Author       string
+	Summary      string    // Show description, only set when the podcast page is loaded
+	Episodes     []Episode // Episodes of the show, loaded on demand
+}
+
+// FilterValue implements list.Item interface for filtering
+func (p Podcast) FilterValue() string {
+	return p.PodcastTitle + " " + p.Author
+}
+
+// Title implements list.Item interface for displaying in the list
+func (p Podcast) Title() string {
+	return p.PodcastTitle
+}
+
+// Description implements list.Item interface for displaying in the list
+func (p Podcast) Description() string {
+	if p.Author == "" {
+		return "Podcast"
+	}
+	return "Podcast • " + p.Author
+}
+
+// Episode represents a podcast episode
+type Episode struct {
+	ID           string // Video ID
+	EpisodeTitle string
+	Podcast      string // Title of the show
+	PodcastID    string // Browse ID of the show, empty if unknown
+	Date         string // Publication date as shown by YouTube Music, such as "3 days ago"
+	Duration     int    // in seconds
+}
+
+// FilterValue implements list.Item interface for filtering
+func (e Episode) FilterValue() string {
+	return e.EpisodeTitle + " " + e.Podcast
+}
+
+// Title implements list.Item interface for displaying in the list
+func (e Episode) Title() string {
+	return e.EpisodeTitle
+}
+
+// Description implements list.Item interface for displaying in the list
+func (e Episode) Description() string {
+	parts := e.Podcast
+	for _, part := range []string{e.Date, FormatLongDuration(e.Duration)} {
+		if part == "" {
+			continue
+		}
+		if parts != "" {
+			parts += " • "
+		}
+		parts += part
+	}
+	return parts
+}
+
+// Track returns the episode as a playable track
+func (e Episode) Track() Track {
+	return Track{
+		ID:         e.ID,
+		TrackTitle: e.EpisodeTitle,
+		Artist:     e.Podcast,
+		Duration:   e.Duration,
+		Episode:    true,
+	}
+}
+
+// FormatLongDuration formats seconds the way episode lengths read, such as "1 hr 5 min"
+func FormatLongDuration(seconds int) string {
+	if seconds <= 0 {
+		return ""
+	}
+	hours, minutes := seconds/3600, (seconds%3600)/60
+	switch {
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%d hr %d min", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%d hr", hours)
+	case minutes > 0:
+		return fmt.Sprintf("%d min", minutes)
+	}
+	return fmt.Sprintf("%d sec", seconds)
+}
+
+// SearchPodcasts searches for podcasts and episodes, returning a shelf of each
+func (api *YouTubeMusicAPI) SearchPodcasts(ctx context.Context, query string) ([]Shelf, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Searching podcasts for: %s", query)
+
+	if !api.bridge.IsAvailable() {
+		api.LogDebug("Python bridge not available, cannot search podcasts")
+		return nil, errPodcastsUnavailable
+	}
+
+	shelves, err := api.bridge.SearchPodcasts(ctx, query)
+	if err != nil {
+		api.LogDebug("Python bridge podcast search failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d podcast shelves via Python bridge", len(shelves))
+	return shelves, nil
+}
+
+// GetPodcast fetches a podcast show with its episodes
+func (api *YouTubeMusicAPI) GetPodcast(ctx context.Context, browseID string) (*Podcast, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching podcast: %s", browseID)
+
+	if !api.bridge.IsAvailable() {
+		api.LogDebug("Python bridge not available, cannot fetch podcast")
+		return nil, errPodcastsUnavailable
+	}
+
+	podcast, err := api.bridge.GetPodcast(ctx, browseID)
+	if err != nil {
+		api.LogDebug("Python bridge get podcast failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d episodes via Python bridge", len(podcast.Episodes))
+	return podcast, nil
+}
+
+// GetLibraryPodcasts fetches the podcasts saved to the user's library
+func (api *YouTubeMusicAPI) GetLibraryPodcasts(ctx context.Context) ([]Podcast, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching library podcasts")
+
+	if !api.bridge.IsAvailable() {
+		api.LogDebug("Python bridge not available, cannot fetch library podcasts")
+		return nil, errPodcastsUnavailable
+	}
+
+	podcasts, err := api.bridge.GetLibraryPodcasts(ctx)
+	if err != nil {
+		api.LogDebug("Python bridge get library podcasts failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d library podcasts via Python bridge", len(podcasts))
+	return podcasts, nil
+}
@@ -0,0 +1,57 @@
+package api
+
+import (
+	"context"
+	"fmt"
+)
+
+// Rating is a like or dislike given to a song
+type Rating string
+
+// Ratings accepted by RateSong
+const (
+	RatingLike        Rating = "LIKE"
+	RatingDislike     Rating = "DISLIKE"
+	RatingIndifferent Rating = "INDIFFERENT" // Removes a like or dislike
+)
+
+// errRateUnavailable is returned when neither the bridge nor a signed session can rate songs
+var errRateUnavailable = withKind(ErrBackendUnavailable, "rating songs requires the Python bridge or a SAPISID cookie")
+
+// rateEndpoints maps ratings to the innertube endpoints that set them
+var rateEndpoints = map[Rating]string{
+	RatingLike:        "like/like",
+	RatingDislike:     "like/dislike",
+	RatingIndifferent: "like/removelike",
+}
+
+// RateSong likes, dislikes, or clears the rating of a song; liked songs appear in Liked Songs
+func (api *YouTubeMusicAPI) RateSong(ctx context.Context, videoID string, rating Rating) error {
+	if !api.IsLoggedIn {
+		return ErrNotLoggedIn
+	}
+
+	endpoint, ok := rateEndpoints[rating]
+	if !ok {
+		return fmt.Errorf("unknown rating: %s", rating)
+	}
+
+	api.LogDebug("Rating %s: %s", videoID, rating)
+
+	if !api.bridge.IsAvailable() {
+		if api.getSAPISID() == "" {
+			return errRateUnavailable
+		}
+		api.LogDebug("Python bridge not available, rating via innertube")
+		var response struct{}
+		return api.sendRequest(ctx, endpoint, map[string]interface{}{
+			"target": map[string]interface{}{"videoId": videoID},
+		}, &response)
+	}
+
+	if err := api.bridge.RateSong(ctx, videoID, string(rating)); err != nil {
+		api.LogDebug("Python bridge rate song failed: %v", err)
+		return err
+	}
+	return nil
+}
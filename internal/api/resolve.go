@@ -0,0 +1,62 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// videoIDPattern matches a bare YouTube video ID
+var videoIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{11}$`)
+
+// ParseVideoID returns the video ID of a bare ID or of a YouTube or YouTube Music link
+func ParseVideoID(arg string) (string, bool) {
+	if videoIDPattern.MatchString(arg) {
+		return arg, true
+	}
+
+	link, err := url.Parse(arg)
+	if err != nil || link.Host == "" {
+		return "", false
+	}
+	host := strings.TrimPrefix(link.Host, "www.")
+	switch {
+	case host == "youtu.be":
+		id := strings.Trim(link.Path, "/")
+		return id, videoIDPattern.MatchString(id)
+	case strings.HasSuffix(host, "youtube.com"):
+		id := link.Query().Get("v")
+		return id, videoIDPattern.MatchString(id)
+	}
+	return "", false
+}
+
+// ResolveTrack finds the track a video ID, a link, or search terms refer to,
+// taking the top search result for search terms
+func (api *YouTubeMusicAPI) ResolveTrack(ctx context.Context, arg string) (Track, error) {
+	if id, ok := ParseVideoID(arg); ok {
+		// The radio starts with the video itself, which gives us its title
+		tracks, err := api.GetWatchPlaylist(ctx, id)
+		if err == nil && len(tracks) > 0 && tracks[0].ID == id {
+			return tracks[0], nil
+		}
+		if ctx.Err() != nil {
+			return Track{}, ctx.Err()
+		}
+		if id != arg {
+			return Track{ID: id, TrackTitle: id}, nil
+		}
+		// A single word that merely looks like an ID is searched for
+	}
+
+	tracks, err := api.Search(ctx, arg)
+	if err != nil {
+		return Track{}, err
+	}
+	if len(tracks) == 0 {
+		return Track{}, fmt.Errorf("no results for %q", arg)
+	}
+	return tracks[0], nil
+}
@@ -0,0 +1,546 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Typed views of the innertube JSON responses. Only the fields we actually
+// read are declared; everything else is ignored by encoding/json.
+
+// Runs is a formatted text container made of one or more runs
+type Runs struct {
+	Runs []Run `json:"runs"`
+}
+
+// Run is a single segment of formatted text, optionally linked to an endpoint
+type Run struct {
+	Text               string              `json:"text"`
+	NavigationEndpoint *NavigationEndpoint `json:"navigationEndpoint,omitempty"`
+}
+
+// Text joins all runs into a single string
+func (r Runs) Text() string {
+	var b strings.Builder
+	for _, run := range r.Runs {
+		b.WriteString(run.Text)
+	}
+	return b.String()
+}
+
+// First returns the text of the first run
+func (r Runs) First() string {
+	if len(r.Runs) == 0 {
+		return ""
+	}
+	return r.Runs[0].Text
+}
+
+// NavigationEndpoint describes where a click on an item leads
+type NavigationEndpoint struct {
+	WatchEndpoint         *WatchEndpoint  `json:"watchEndpoint,omitempty"`
+	WatchPlaylistEndpoint *WatchEndpoint  `json:"watchPlaylistEndpoint,omitempty"`
+	BrowseEndpoint        *BrowseEndpoint `json:"browseEndpoint,omitempty"`
+}
+
+// WatchEndpoint points at a playable video
+type WatchEndpoint struct {
+	VideoID    string `json:"videoId"`
+	PlaylistID string `json:"playlistId"`
+}
+
+// BrowseEndpoint points at a browse page (artist, album, playlist)
+type BrowseEndpoint struct {
+	BrowseID                              string `json:"browseId"`
+	BrowseEndpointContextSupportedConfigs struct {
+		BrowseEndpointContextMusicConfig struct {
+			PageType string `json:"pageType"`
+		} `json:"browseEndpointContextMusicConfig"`
+	} `json:"browseEndpointContextSupportedConfigs"`
+}
+
+// PageType returns the music page type of the browse target
+func (b *BrowseEndpoint) PageType() string {
+	return b.BrowseEndpointContextSupportedConfigs.BrowseEndpointContextMusicConfig.PageType
+}
+
+// Page types used to tell artists, albums, and playlists apart in runs
+const (
+	pageTypeArtist   = "MUSIC_PAGE_TYPE_ARTIST"
+	pageTypeAlbum    = "MUSIC_PAGE_TYPE_ALBUM"
+	pageTypePlaylist = "MUSIC_PAGE_TYPE_PLAYLIST"
+)
+
+// BrowseResponse is the top-level response of the browse endpoint
+type BrowseResponse struct {
+	Contents struct {
+		SectionListRenderer               *SectionListRenderer `json:"sectionListRenderer,omitempty"` // Lyrics pages
+		SingleColumnBrowseResultsRenderer *TabbedRenderer      `json:"singleColumnBrowseResultsRenderer,omitempty"`
+		TwoColumnBrowseResultsRenderer    *struct {
+			Tabs              []Tab `json:"tabs"`
+			SecondaryContents struct {
+				SectionListRenderer *SectionListRenderer `json:"sectionListRenderer,omitempty"`
+			} `json:"secondaryContents"`
+		} `json:"twoColumnBrowseResultsRenderer,omitempty"`
+	} `json:"contents"`
+	// Set on continuation pages instead of Contents
+	ContinuationContents struct {
+		MusicPlaylistShelfContinuation *MusicShelfRenderer  `json:"musicPlaylistShelfContinuation,omitempty"`
+		MusicShelfContinuation         *MusicShelfRenderer  `json:"musicShelfContinuation,omitempty"`
+		SectionListContinuation        *SectionListRenderer `json:"sectionListContinuation,omitempty"`
+	} `json:"continuationContents"`
+	OnResponseReceivedActions []struct {
+		AppendContinuationItemsAction struct {
+			ContinuationItems []ShelfItem `json:"continuationItems"`
+		} `json:"appendContinuationItemsAction"`
+	} `json:"onResponseReceivedActions"`
+	Header struct {
+		MusicDetailHeaderRenderer    *MusicHeaderRenderer `json:"musicDetailHeaderRenderer,omitempty"`
+		MusicImmersiveHeaderRenderer *MusicHeaderRenderer `json:"musicImmersiveHeaderRenderer,omitempty"`
+		MusicVisualHeaderRenderer    *MusicHeaderRenderer `json:"musicVisualHeaderRenderer,omitempty"`
+		MusicHeaderRenderer          *MusicHeaderRenderer `json:"musicHeaderRenderer,omitempty"` // Plain title, as on upload artist pages
+	} `json:"header"`
+}
+
+// InnertubeSearchResponse is the top-level response of the search endpoint
+type InnertubeSearchResponse struct {
+	Contents struct {
+		TabbedSearchResultsRenderer *TabbedRenderer `json:"tabbedSearchResultsRenderer,omitempty"`
+	} `json:"contents"`
+}
+
+// TabbedRenderer holds a list of tabs
+type TabbedRenderer struct {
+	Tabs []Tab `json:"tabs"`
+}
+
+// Tab is a single tab of a tabbed renderer
+type Tab struct {
+	TabRenderer struct {
+		Content struct {
+			SectionListRenderer *SectionListRenderer `json:"sectionListRenderer,omitempty"`
+		} `json:"content"`
+	} `json:"tabRenderer"`
+}
+
+// SectionListRenderer is the vertical list of shelves on a page
+type SectionListRenderer struct {
+	Contents      []Section     `json:"contents"`
+	Continuations Continuations `json:"continuations"` // More sections, on the home feed
+}
+
+// Section is one entry of a section list; exactly one renderer is set
+type Section struct {
+	MusicShelfRenderer            *MusicShelfRenderer         `json:"musicShelfRenderer,omitempty"`
+	MusicPlaylistShelfRenderer    *MusicShelfRenderer         `json:"musicPlaylistShelfRenderer,omitempty"`
+	GridRenderer                  *GridRenderer               `json:"gridRenderer,omitempty"`
+	MusicResponsiveHeaderRenderer *MusicHeaderRenderer        `json:"musicResponsiveHeaderRenderer,omitempty"`
+	MusicCarouselShelfRenderer    *MusicCarouselShelfRenderer `json:"musicCarouselShelfRenderer,omitempty"`
+	MusicDescriptionShelfRenderer *struct {
+		Description Runs `json:"description"`
+		Footer      Runs `json:"footer"`
+	} `json:"musicDescriptionShelfRenderer,omitempty"` // Lyrics text and source
+}
+
+// MusicCarouselShelfRenderer is a horizontal shelf of cards, such as an artist's albums
+type MusicCarouselShelfRenderer struct {
+	Header struct {
+		MusicCarouselShelfBasicHeaderRenderer struct {
+			Title Runs `json:"title"`
+		} `json:"musicCarouselShelfBasicHeaderRenderer"`
+	} `json:"header"`
+	Contents []GridItem `json:"contents"`
+}
+
+// MusicHeaderRenderer is the header of an album or playlist page
+type MusicHeaderRenderer struct {
+	Title            Runs `json:"title"`
+	Subtitle         Runs `json:"subtitle"`         // "Album • 2019" (newer) or "Album • Artist • 2019"
+	StraplineTextOne Runs `json:"straplineTextOne"` // Artist runs on newer layouts
+	StartRadioButton struct {
+		ButtonRenderer struct {
+			NavigationEndpoint NavigationEndpoint `json:"navigationEndpoint"`
+		} `json:"buttonRenderer"`
+	} `json:"startRadioButton"` // Artist pages only
+	SubscriptionButton struct {
+		SubscribeButtonRenderer struct {
+			SubscriberCountText Runs `json:"subscriberCountText"`
+		} `json:"subscribeButtonRenderer"`
+	} `json:"subscriptionButton"` // Artist pages only
+}
+
+// MusicShelfRenderer is a vertical shelf of list items
+type MusicShelfRenderer struct {
+	Title         Runs          `json:"title"`
+	Contents      []ShelfItem   `json:"contents"`
+	Continuations Continuations `json:"continuations"` // Older layouts page through this instead of a trailing item
+}
+
+// Continuations holds the token of the next page of a shelf or section list
+type Continuations []struct {
+	NextContinuationData struct {
+		Continuation string `json:"continuation"`
+	} `json:"nextContinuationData"`
+}
+
+// next returns the next page's token, if any
+func (c Continuations) next() string {
+	for _, continuation := range c {
+		if continuation.NextContinuationData.Continuation != "" {
+			return continuation.NextContinuationData.Continuation
+		}
+	}
+	return ""
+}
+
+// ShelfItem is an item inside a shelf
+type ShelfItem struct {
+	MusicResponsiveListItemRenderer *MusicResponsiveListItemRenderer `json:"musicResponsiveListItemRenderer,omitempty"`
+	ContinuationItemRenderer        *struct {
+		ContinuationEndpoint struct {
+			ContinuationCommand struct {
+				Token string `json:"token"`
+			} `json:"continuationCommand"`
+		} `json:"continuationEndpoint"`
+	} `json:"continuationItemRenderer,omitempty"` // Trailing item pointing at the next page
+}
+
+// GridRenderer is a grid of two-row items (library pages)
+type GridRenderer struct {
+	Items []GridItem `json:"items"`
+}
+
+// GridItem is an item inside a grid
+type GridItem struct {
+	MusicTwoRowItemRenderer         *MusicTwoRowItemRenderer         `json:"musicTwoRowItemRenderer,omitempty"`
+	MusicResponsiveListItemRenderer *MusicResponsiveListItemRenderer `json:"musicResponsiveListItemRenderer,omitempty"` // Song rows in Quick Picks
+}
+
+// MusicTwoRowItemRenderer is a card with a title and subtitle
+type MusicTwoRowItemRenderer struct {
+	Title              Runs                `json:"title"`
+	Subtitle           Runs                `json:"subtitle"`
+	ThumbnailRenderer  Thumbnail           `json:"thumbnailRenderer"`
+	NavigationEndpoint *NavigationEndpoint `json:"navigationEndpoint,omitempty"`
+}
+
+// Thumbnail is an item's artwork in several sizes, smallest first
+type Thumbnail struct {
+	MusicThumbnailRenderer struct {
+		Thumbnail struct {
+			Thumbnails []struct {
+				URL    string `json:"url"`
+				Width  int    `json:"width"`
+				Height int    `json:"height"`
+			} `json:"thumbnails"`
+		} `json:"thumbnail"`
+	} `json:"musicThumbnailRenderer"`
+}
+
+// url returns the URL of the largest size, or "" if there is no artwork
+func (t Thumbnail) url() string {
+	thumbnails := t.MusicThumbnailRenderer.Thumbnail.Thumbnails
+	if len(thumbnails) == 0 {
+		return ""
+	}
+	return thumbnails[len(thumbnails)-1].URL
+}
+
+// explicitBadge is the icon of the "E" badge on explicit tracks
+const explicitBadge = "MUSIC_EXPLICIT_BADGE"
+
+// MusicResponsiveListItemRenderer is a song row in search results and playlists
+type MusicResponsiveListItemRenderer struct {
+	FlexColumns []struct {
+		MusicResponsiveListItemFlexColumnRenderer struct {
+			Text Runs `json:"text"`
+		} `json:"musicResponsiveListItemFlexColumnRenderer"`
+	} `json:"flexColumns"`
+	FixedColumns []struct {
+		MusicResponsiveListItemFixedColumnRenderer struct {
+			Text Runs `json:"text"`
+		} `json:"musicResponsiveListItemFixedColumnRenderer"`
+	} `json:"fixedColumns"`
+	PlaylistItemData *struct {
+		VideoID            string `json:"videoId"`
+		PlaylistSetVideoID string `json:"playlistSetVideoId"`
+	} `json:"playlistItemData,omitempty"`
+	NavigationEndpoint *NavigationEndpoint `json:"navigationEndpoint,omitempty"`
+	Thumbnail          Thumbnail           `json:"thumbnail"`
+	Badges             []struct {
+		MusicInlineBadgeRenderer struct {
+			Icon struct {
+				IconType string `json:"iconType"`
+			} `json:"icon"`
+		} `json:"musicInlineBadgeRenderer"`
+	} `json:"badges"`
+	Overlay struct {
+		MusicItemThumbnailOverlayRenderer struct {
+			Content struct {
+				MusicPlayButtonRenderer struct {
+					PlayNavigationEndpoint NavigationEndpoint `json:"playNavigationEndpoint"`
+				} `json:"musicPlayButtonRenderer"`
+			} `json:"content"`
+		} `json:"musicItemThumbnailOverlayRenderer"`
+	} `json:"overlay"`
+	Menu struct {
+		MenuRenderer struct {
+			Items []struct {
+				MenuServiceItemRenderer *struct {
+					ServiceEndpoint NavigationEndpoint `json:"serviceEndpoint"`
+				} `json:"menuServiceItemRenderer,omitempty"`
+				MenuNavigationItemRenderer *struct {
+					NavigationEndpoint NavigationEndpoint `json:"navigationEndpoint"`
+				} `json:"menuNavigationItemRenderer,omitempty"`
+			} `json:"items"`
+		} `json:"menuRenderer"`
+	} `json:"menu"`
+}
+
+// sectionList returns the primary section list of a browse response
+func (r *BrowseResponse) sectionList() *SectionListRenderer {
+	if two := r.Contents.TwoColumnBrowseResultsRenderer; two != nil {
+		// Newer playlist pages put the tracks in the secondary column
+		if two.SecondaryContents.SectionListRenderer != nil {
+			return two.SecondaryContents.SectionListRenderer
+		}
+		return firstTabSectionList(two.Tabs)
+	}
+	if single := r.Contents.SingleColumnBrowseResultsRenderer; single != nil {
+		return firstTabSectionList(single.Tabs)
+	}
+	return r.Contents.SectionListRenderer
+}
+
+// sectionList returns the section list of the first search tab
+func (r *InnertubeSearchResponse) sectionList() *SectionListRenderer {
+	if tabbed := r.Contents.TabbedSearchResultsRenderer; tabbed != nil {
+		return firstTabSectionList(tabbed.Tabs)
+	}
+	return nil
+}
+
+// header returns the page header from either the legacy or the responsive layout
+func (r *BrowseResponse) header() *MusicHeaderRenderer {
+	for _, header := range []*MusicHeaderRenderer{
+		r.Header.MusicDetailHeaderRenderer,
+		r.Header.MusicImmersiveHeaderRenderer,
+		r.Header.MusicVisualHeaderRenderer,
+		r.Header.MusicHeaderRenderer,
+	} {
+		if header != nil {
+			return header
+		}
+	}
+	if two := r.Contents.TwoColumnBrowseResultsRenderer; two != nil {
+		if list := firstTabSectionList(two.Tabs); list != nil {
+			for _, section := range list.Contents {
+				if section.MusicResponsiveHeaderRenderer != nil {
+					return section.MusicResponsiveHeaderRenderer
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// firstTabSectionList returns the section list of the first tab, if any
+func firstTabSectionList(tabs []Tab) *SectionListRenderer {
+	if len(tabs) == 0 {
+		return nil
+	}
+	return tabs[0].TabRenderer.Content.SectionListRenderer
+}
+
+// shelves returns every music shelf (including playlist shelves) in the list
+func (s *SectionListRenderer) shelves() []*MusicShelfRenderer {
+	if s == nil {
+		return nil
+	}
+	var shelves []*MusicShelfRenderer
+	for _, section := range s.Contents {
+		if section.MusicShelfRenderer != nil {
+			shelves = append(shelves, section.MusicShelfRenderer)
+		}
+		if section.MusicPlaylistShelfRenderer != nil {
+			shelves = append(shelves, section.MusicPlaylistShelfRenderer)
+		}
+	}
+	return shelves
+}
+
+// carousels returns every carousel shelf in the list
+func (s *SectionListRenderer) carousels() []*MusicCarouselShelfRenderer {
+	if s == nil {
+		return nil
+	}
+	var carousels []*MusicCarouselShelfRenderer
+	for _, section := range s.Contents {
+		if section.MusicCarouselShelfRenderer != nil {
+			carousels = append(carousels, section.MusicCarouselShelfRenderer)
+		}
+	}
+	return carousels
+}
+
+// continuation returns the token for the shelf's next page, or "" on the last page
+func (s *MusicShelfRenderer) continuation() string {
+	if token := s.Continuations.next(); token != "" {
+		return token
+	}
+	return continuationFromItems(s.Contents)
+}
+
+// continuationFromItems returns the token of a trailing continuation item, if any
+func continuationFromItems(items []ShelfItem) string {
+	if len(items) == 0 {
+		return ""
+	}
+	if last := items[len(items)-1].ContinuationItemRenderer; last != nil {
+		return last.ContinuationEndpoint.ContinuationCommand.Token
+	}
+	return ""
+}
+
+// grid returns the first grid in the list
+func (s *SectionListRenderer) grid() *GridRenderer {
+	if s == nil {
+		return nil
+	}
+	for _, section := range s.Contents {
+		if section.GridRenderer != nil {
+			return section.GridRenderer
+		}
+	}
+	return nil
+}
+
+// column returns the runs of the flex column at index i
+func (r *MusicResponsiveListItemRenderer) column(i int) Runs {
+	if i < 0 || i >= len(r.FlexColumns) {
+		return Runs{}
+	}
+	return r.FlexColumns[i].MusicResponsiveListItemFlexColumnRenderer.Text
+}
+
+// videoID finds the item's video ID from its play data, play button, or menu
+func (r *MusicResponsiveListItemRenderer) videoID() string {
+	if r.PlaylistItemData != nil && r.PlaylistItemData.VideoID != "" {
+		return r.PlaylistItemData.VideoID
+	}
+
+	if watch := r.Overlay.MusicItemThumbnailOverlayRenderer.Content.MusicPlayButtonRenderer.PlayNavigationEndpoint.WatchEndpoint; watch != nil && watch.VideoID != "" {
+		return watch.VideoID
+	}
+
+	for _, item := range r.Menu.MenuRenderer.Items {
+		if item.MenuServiceItemRenderer != nil {
+			if watch := item.MenuServiceItemRenderer.ServiceEndpoint.WatchEndpoint; watch != nil && watch.VideoID != "" {
+				return watch.VideoID
+			}
+		}
+		if item.MenuNavigationItemRenderer != nil {
+			if watch := item.MenuNavigationItemRenderer.NavigationEndpoint.WatchEndpoint; watch != nil && watch.VideoID != "" {
+				return watch.VideoID
+			}
+		}
+	}
+
+	return ""
+}
+
+// toTrack converts a list item into a Track, returning false for non-playable rows
+func (r *MusicResponsiveListItemRenderer) toTrack() (Track, bool) {
+	id := r.videoID()
+	if id == "" {
+		return Track{}, false
+	}
+
+	track := Track{
+		ID:           id,
+		TrackTitle:   r.column(0).First(),
+		ThumbnailURL: r.Thumbnail.url(),
+	}
+	for _, badge := range r.Badges {
+		if badge.MusicInlineBadgeRenderer.Icon.IconType == explicitBadge {
+			track.Explicit = true
+		}
+	}
+	if r.PlaylistItemData != nil {
+		track.SetVideoID = r.PlaylistItemData.PlaylistSetVideoID
+	}
+
+	// The second column reads like "Artist • Album • 3:45" with links on the artist/album runs
+	var artists []string
+	for _, run := range r.column(1).Runs {
+		if run.NavigationEndpoint != nil && run.NavigationEndpoint.BrowseEndpoint != nil {
+			switch run.NavigationEndpoint.BrowseEndpoint.PageType() {
+			case pageTypeArtist:
+				artists = append(artists, run.Text)
+				if track.ArtistID == "" {
+					track.ArtistID = run.NavigationEndpoint.BrowseEndpoint.BrowseID
+				}
+			case pageTypeAlbum:
+				track.AlbumID = run.NavigationEndpoint.BrowseEndpoint.BrowseID
+				track.Album = run.Text
+			default:
+				// Uploaded songs link to upload pages that are only told apart by their browse ID
+				browseID := run.NavigationEndpoint.BrowseEndpoint.BrowseID
+				if strings.HasPrefix(browseID, uploadedArtistPrefix) {
+					artists = append(artists, run.Text)
+					if track.ArtistID == "" {
+						track.ArtistID = browseID
+					}
+				} else if strings.HasPrefix(browseID, uploadedAlbumPrefix) {
+					track.AlbumID = browseID
+					track.Album = run.Text
+				}
+			}
+			continue
+		}
+		if d := parseDuration(run.Text); d > 0 {
+			track.Duration = d
+		} else if isYear(run.Text) {
+			track.Year = run.Text
+		}
+	}
+	if len(artists) == 0 {
+		// Uploads and some playlist rows have unlinked artist names
+		artists = append(artists, r.column(1).First())
+	}
+	track.Artist = strings.Join(artists, ", ")
+
+	// Playlist rows put the album in its own column
+	for _, run := range r.column(2).Runs {
+		if run.NavigationEndpoint != nil && run.NavigationEndpoint.BrowseEndpoint != nil && track.AlbumID == "" {
+			track.AlbumID = run.NavigationEndpoint.BrowseEndpoint.BrowseID
+			track.Album = run.Text
+		}
+	}
+
+	if len(r.FixedColumns) > 0 {
+		if d := parseDuration(r.FixedColumns[0].MusicResponsiveListItemFixedColumnRenderer.Text.First()); d > 0 {
+			track.Duration = d
+		}
+	}
+
+	return track, true
+}
+
+// parseDuration parses "3:45" or "1:02:03" into seconds, returning 0 if it isn't a duration
+func parseDuration(s string) int {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return 0
+	}
+
+	total := 0
+	for _, part := range parts {
+		n, err := strconv.Atoi(part)
+		if err != nil {
+			return 0
+		}
+		total = total*60 + n
+	}
+	return total
+}
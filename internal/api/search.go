@@ -0,0 +1,43 @@
+package api
+
+import "context"
+
+// searchParamsSongs restricts innertube search results to the songs shelf
+const searchParamsSongs = "EgWKAQIIAWoMEA4QChADEAQQCRAF"
+
+// searchNative searches for songs directly through innertube, returning the first page of results
+func (api *YouTubeMusicAPI) searchNative(ctx context.Context, query string) ([]Track, error) {
+	tracks, _, err := api.searchPageNative(ctx, query, "")
+	return tracks, err
+}
+
+// searchPageNative fetches one page of song results, returning the token for the next page
+func (api *YouTubeMusicAPI) searchPageNative(ctx context.Context, query, continuation string) ([]Track, string, error) {
+	if continuation != "" {
+		var response BrowseResponse
+		err := api.sendRequest(ctx, "search", map[string]interface{}{
+			"continuation": continuation,
+		}, &response)
+		if err != nil {
+			return nil, "", err
+		}
+		tracks, next := response.continuationTracks()
+		return tracks, next, nil
+	}
+
+	var response InnertubeSearchResponse
+	err := api.sendRequest(ctx, "search", map[string]interface{}{
+		"query":  query,
+		"params": searchParamsSongs,
+	}, &response)
+	if err != nil {
+		return nil, "", err
+	}
+	tracks, next := response.tracks()
+	return tracks, next, nil
+}
+
+// tracks returns the songs of the first page of search results and the token for the next page
+func (r *InnertubeSearchResponse) tracks() ([]Track, string) {
+	return shelvesPage(r.sectionList().shelves())
+}
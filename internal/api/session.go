@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// accountMenuResponse is the subset of the account/account_menu response we use
+type accountMenuResponse struct {
+	Actions []struct {
+		OpenPopupAction struct {
+			Popup struct {
+				MultiPageMenuRenderer struct {
+					Header struct {
+						ActiveAccountHeaderRenderer *struct {
+							AccountName Runs `json:"accountName"`
+						} `json:"activeAccountHeaderRenderer"`
+					} `json:"header"`
+				} `json:"multiPageMenuRenderer"`
+			} `json:"popup"`
+		} `json:"openPopupAction"`
+	} `json:"actions"`
+}
+
+// accountName returns the name of the signed-in account, or "" when signed out
+func (r *accountMenuResponse) accountName() string {
+	for _, action := range r.Actions {
+		if header := action.OpenPopupAction.Popup.MultiPageMenuRenderer.Header.ActiveAccountHeaderRenderer; header != nil {
+			return header.AccountName.Text()
+		}
+	}
+	return ""
+}
+
+// redirectedToLogin reports whether a request ended up on Google's sign-in or
+// cookie consent pages, which is where expired cookies are sent
+func redirectedToLogin(resp *http.Response) bool {
+	if resp.Request == nil || resp.Request.URL == nil {
+		return false
+	}
+	host := resp.Request.URL.Host
+	return strings.HasPrefix(host, "consent.") || host == "accounts.google.com"
+}
+
+// ValidateSession checks that the stored credentials still sign in, by asking
+// for the account menu. An expired session logs the API out and returns
+// ErrSessionExpired; other errors, such as a lost connection, leave it logged in.
+func (api *YouTubeMusicAPI) ValidateSession(ctx context.Context) error {
+	if !api.IsLoggedIn {
+		return ErrNotLoggedIn
+	}
+
+	// Only signed requests show whether the session works; a bare
+	// __Secure-3PSID cookie is checked by the bridge when it's used
+	if api.getSAPISID() == "" && api.oauthAccessToken() == "" {
+		api.LogDebug("Skipping session check: no SAPISID cookie or OAuth token")
+		return nil
+	}
+
+	var response accountMenuResponse
+	err := api.sendRequest(ctx, "account/account_menu", nil, &response)
+	if err == nil && response.accountName() == "" {
+		err = withKind(ErrSessionExpired, "YouTube Music no longer recognizes the stored login")
+	}
+	if err != nil {
+		api.LogDebug("Session check failed: %v", err)
+		if errors.Is(err, ErrSessionExpired) {
+			api.IsLoggedIn = false
+		}
+		return err
+	}
+
+	api.LogDebug("Session is valid for %s", response.accountName())
+	return nil
+}
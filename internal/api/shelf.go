@@ -0,0 +1,90 @@
+package api
+
+import "strings"
+
+// BrowseItem is an entry of a shelf: a Track, Album, Playlist or Artist
+type BrowseItem interface {
+	FilterValue() string
+	Title() string
+	Description() string
+}
+
+// Shelf is one carousel of a browse page, such as Quick Picks or a chart
+type Shelf struct {
+	Title string
+	Items []BrowseItem
+}
+
+// carouselShelves converts the carousels of a section list into shelves
+func carouselShelves(sections *SectionListRenderer) []Shelf {
+	var shelves []Shelf
+	for _, carousel := range sections.carousels() {
+		shelf := Shelf{Title: carousel.Header.MusicCarouselShelfBasicHeaderRenderer.Title.Text()}
+		for _, item := range carousel.Contents {
+			if item.MusicResponsiveListItemRenderer != nil {
+				if track, ok := item.MusicResponsiveListItemRenderer.toTrack(); ok {
+					shelf.Items = append(shelf.Items, track)
+				}
+				continue
+			}
+			if item.MusicTwoRowItemRenderer != nil {
+				if browseItem, ok := item.MusicTwoRowItemRenderer.toBrowseItem(); ok {
+					shelf.Items = append(shelf.Items, browseItem)
+				}
+			}
+		}
+		if len(shelf.Items) > 0 {
+			shelves = append(shelves, shelf)
+		}
+	}
+	return shelves
+}
+
+// toBrowseItem converts a carousel card into a Track, Album, Playlist or Artist
+func (r *MusicTwoRowItemRenderer) toBrowseItem() (BrowseItem, bool) {
+	if r.NavigationEndpoint == nil {
+		return nil, false
+	}
+
+	// Songs and videos play directly; their subtitle reads like "Song • Artist"
+	if watch := r.NavigationEndpoint.WatchEndpoint; watch != nil && watch.VideoID != "" {
+		track := Track{ID: watch.VideoID, TrackTitle: r.Title.First(), ThumbnailURL: r.ThumbnailRenderer.url()}
+		var artists []string
+		for _, run := range r.Subtitle.Runs {
+			if run.NavigationEndpoint != nil && run.NavigationEndpoint.BrowseEndpoint != nil &&
+				run.NavigationEndpoint.BrowseEndpoint.PageType() == pageTypeArtist {
+				artists = append(artists, run.Text)
+				if track.ArtistID == "" {
+					track.ArtistID = run.NavigationEndpoint.BrowseEndpoint.BrowseID
+				}
+			}
+		}
+		track.Artist = strings.Join(artists, ", ")
+		if track.Artist == "" {
+			track.Artist = r.Subtitle.Text()
+		}
+		return track, true
+	}
+
+	browse := r.NavigationEndpoint.BrowseEndpoint
+	if browse == nil {
+		return nil, false
+	}
+	switch browse.PageType() {
+	case pageTypeAlbum:
+		return r.toAlbum()
+	case pageTypePlaylist:
+		return Playlist{
+			ID:            strings.TrimPrefix(browse.BrowseID, "VL"),
+			PlaylistTitle: r.Title.First(),
+			PlaylistDesc:  r.Subtitle.Text(),
+		}, true
+	case pageTypeArtist:
+		return Artist{
+			ID:       browse.BrowseID,
+			Name:     r.Title.First(),
+			Subtitle: r.Subtitle.Text(),
+		}, true
+	}
+	return nil, false
+}
@@ -0,0 +1,372 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Clients tried in order by the native resolver. The mobile clients return
+// plain stream URLs; the web client needs signature deciphering.
+var (
+	iosMusicClient = innertubeClient{
+		Name:      "IOS",
+		Version:   "19.45.4",
+		UserAgent: "com.google.ios.youtube/19.45.4 (iPhone16,2; U; CPU iOS 18_1_0 like Mac OS X;)",
+		BaseURL:   "https://youtubei.googleapis.com/youtubei/v1/",
+		Extra: map[string]interface{}{
+			"deviceMake":  "Apple",
+			"deviceModel": "iPhone16,2",
+			"osName":      "iPhone",
+			"osVersion":   "18.1.0.22B83",
+		},
+		Anonymous: true,
+	}
+
+	androidMusicClient = innertubeClient{
+		Name:      "ANDROID_MUSIC",
+		Version:   "7.27.52",
+		UserAgent: "com.google.android.apps.youtube.music/7.27.52 (Linux; U; Android 11) gzip",
+		BaseURL:   "https://youtubei.googleapis.com/youtubei/v1/",
+		Extra: map[string]interface{}{
+			"androidSdkVersion": 30,
+			"osName":            "Android",
+			"osVersion":         "11",
+		},
+		Anonymous: true,
+	}
+)
+
+// playerResponse is the subset of the innertube player response we use
+type playerResponse struct {
+	PlayabilityStatus struct {
+		Status string `json:"status"`
+		Reason string `json:"reason"`
+	} `json:"playabilityStatus"`
+	StreamingData struct {
+		AdaptiveFormats []playerFormat `json:"adaptiveFormats"`
+	} `json:"streamingData"`
+	VideoDetails struct {
+		LengthSeconds string `json:"lengthSeconds"`
+	} `json:"videoDetails"`
+}
+
+// playerFormat is one entry of streamingData.adaptiveFormats
+type playerFormat struct {
+	Itag            int    `json:"itag"`
+	URL             string `json:"url"`
+	SignatureCipher string `json:"signatureCipher"`
+	MimeType        string `json:"mimeType"`
+	Bitrate         int    `json:"bitrate"`
+	AverageBitrate  int    `json:"averageBitrate"`
+}
+
+// mimeCodecPattern extracts the codec from `audio/webm; codecs="opus"`
+var mimeCodecPattern = regexp.MustCompile(`codecs="([^"]+)"`)
+
+// resolveNative resolves a stream through the innertube player endpoint, trying each client in turn
+func (api *YouTubeMusicAPI) resolveNative(ctx context.Context, trackID string) (*StreamInfo, error) {
+	var lastErr error
+	for _, client := range []innertubeClient{iosMusicClient, androidMusicClient, webRemixClient} {
+		info, err := api.resolveWithClient(ctx, client, trackID)
+		if err == nil {
+			return info, nil
+		}
+		api.LogDebug("Native resolution with %s failed: %v", client.Name, err)
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// resolveWithClient calls the player endpoint as one client and picks an audio format
+func (api *YouTubeMusicAPI) resolveWithClient(ctx context.Context, client innertubeClient, trackID string) (*StreamInfo, error) {
+	body := map[string]interface{}{
+		"videoId":        trackID,
+		"contentCheckOk": true,
+		"racyCheckOk":    true,
+	}
+
+	// Web clients must tell the server which player version will decipher the signatures
+	if !client.Anonymous {
+		sts, err := api.decipherer.signatureTimestamp(ctx, api)
+		if err != nil {
+			return nil, err
+		}
+		body["playbackContext"] = map[string]interface{}{
+			"contentPlaybackContext": map[string]interface{}{
+				"signatureTimestamp": sts,
+			},
+		}
+	}
+
+	var response playerResponse
+	if err := api.sendClientRequest(ctx, client, "player", body, &response); err != nil {
+		return nil, err
+	}
+
+	if response.PlayabilityStatus.Status != "OK" {
+		if geoBlocked(response.PlayabilityStatus.Reason) {
+			return nil, withKind(ErrGeoBlocked, "not playable here: %s", response.PlayabilityStatus.Reason)
+		}
+		return nil, fmt.Errorf("not playable: %s %s", response.PlayabilityStatus.Status, response.PlayabilityStatus.Reason)
+	}
+
+	var formats []ytdlpFormat
+	for _, f := range response.StreamingData.AdaptiveFormats {
+		if !strings.HasPrefix(f.MimeType, "audio/") {
+			continue
+		}
+
+		streamURL := f.URL
+		if streamURL == "" && f.SignatureCipher != "" {
+			deciphered, err := api.decipherFormatURL(ctx, f.SignatureCipher)
+			if err != nil {
+				api.LogDebug("Skipping itag %d: %v", f.Itag, err)
+				continue
+			}
+			streamURL = deciphered
+		}
+		if streamURL == "" {
+			continue
+		}
+
+		codec := "unknown"
+		if match := mimeCodecPattern.FindStringSubmatch(f.MimeType); match != nil {
+			codec = match[1]
+		}
+		ext := "webm"
+		if strings.HasPrefix(f.MimeType, "audio/mp4") {
+			ext = "m4a"
+		}
+		bitrate := f.AverageBitrate
+		if bitrate == 0 {
+			bitrate = f.Bitrate
+		}
+
+		formats = append(formats, ytdlpFormat{
+			FormatID: strconv.Itoa(f.Itag),
+			URL:      streamURL,
+			Ext:      ext,
+			ACodec:   codec,
+			VCodec:   "none",
+			ABR:      float64(bitrate) / 1000,
+		})
+	}
+
+	format, ok := selectAudioFormat(formats, api.streamQuality, api.streamCodec, api.maxBitrate)
+	if !ok {
+		return nil, fmt.Errorf("no usable audio format")
+	}
+
+	duration, _ := strconv.Atoi(response.VideoDetails.LengthSeconds)
+	return &StreamInfo{
+		URL:      format.URL,
+		Codec:    format.ACodec,
+		Bitrate:  int(format.ABR),
+		Duration: duration,
+		Expires:  streamExpiry(format.URL),
+	}, nil
+}
+
+// decipherFormatURL turns a signatureCipher ("s=...&sp=sig&url=...") into a playable URL
+func (api *YouTubeMusicAPI) decipherFormatURL(ctx context.Context, cipher string) (string, error) {
+	params, err := url.ParseQuery(cipher)
+	if err != nil {
+		return "", fmt.Errorf("invalid signatureCipher: %v", err)
+	}
+
+	streamURL, err := url.Parse(params.Get("url"))
+	if err != nil || params.Get("s") == "" {
+		return "", fmt.Errorf("incomplete signatureCipher")
+	}
+
+	signature, err := api.decipherer.decipher(ctx, api, params.Get("s"))
+	if err != nil {
+		return "", err
+	}
+
+	sp := params.Get("sp")
+	if sp == "" {
+		sp = "signature"
+	}
+	query := streamURL.Query()
+	query.Set(sp, signature)
+	streamURL.RawQuery = query.Encode()
+	return streamURL.String(), nil
+}
+
+// sigOp is one step of the signature transform: reverse, splice, or swap
+type sigOp struct {
+	kind string
+	arg  int
+}
+
+// signatureDecipherer caches the transform extracted from the current player JS
+type signatureDecipherer struct {
+	mu        sync.Mutex
+	ops       []sigOp
+	timestamp int
+	loadedAt  time.Time
+}
+
+var (
+	playerHashPattern   = regexp.MustCompile(`player\\?/([a-zA-Z0-9_-]+)\\?/`)
+	stsPattern          = regexp.MustCompile(`signatureTimestamp:(\d+)`)
+	decipherFuncPattern = regexp.MustCompile(`[a-zA-Z0-9$]+=function\(a\)\{a=a\.split\(""\);(.+?)return a\.join\(""\)\}`)
+	decipherCallPattern = regexp.MustCompile(`([a-zA-Z0-9$]+)\.([a-zA-Z0-9$]+)\(a,(\d+)\)`)
+)
+
+// playerJSMaxAge is how long an extracted transform is trusted before re-fetching
+const playerJSMaxAge = 6 * time.Hour
+
+// load fetches the player JS and extracts the transform if the cache is stale
+func (d *signatureDecipherer) load(ctx context.Context, api *YouTubeMusicAPI) error {
+	if d.ops != nil && time.Since(d.loadedAt) < playerJSMaxAge {
+		return nil
+	}
+
+	iframe, err := httpGetString(ctx, api.client, "https://www.youtube.com/iframe_api")
+	if err != nil {
+		return fmt.Errorf("failed to fetch iframe API: %v", err)
+	}
+	hash := playerHashPattern.FindStringSubmatch(iframe)
+	if hash == nil {
+		return fmt.Errorf("player version not found")
+	}
+
+	js, err := httpGetString(ctx, api.client, "https://www.youtube.com/s/player/"+hash[1]+"/player_ias.vflset/en_US/base.js")
+	if err != nil {
+		return fmt.Errorf("failed to fetch player JS: %v", err)
+	}
+
+	ops, err := parseSignatureOps(js)
+	if err != nil {
+		return err
+	}
+
+	if match := stsPattern.FindStringSubmatch(js); match != nil {
+		d.timestamp, _ = strconv.Atoi(match[1])
+	}
+	d.ops = ops
+	d.loadedAt = time.Now()
+	api.LogDebug("Loaded %d signature operations from player %s", len(ops), hash[1])
+	return nil
+}
+
+// parseSignatureOps finds the decipher function and maps its helper calls to operations
+func parseSignatureOps(js string) ([]sigOp, error) {
+	body := decipherFuncPattern.FindStringSubmatch(js)
+	if body == nil {
+		return nil, fmt.Errorf("signature function not found in player JS")
+	}
+
+	calls := decipherCallPattern.FindAllStringSubmatch(body[1], -1)
+	if len(calls) == 0 {
+		return nil, fmt.Errorf("signature function has no operations")
+	}
+
+	// The helper object holds three methods whose bodies identify the operation
+	helper := regexp.MustCompile(`var ` + regexp.QuoteMeta(calls[0][1]) + `=\{([\s\S]*?)\};`).FindStringSubmatch(js)
+	if helper == nil {
+		return nil, fmt.Errorf("signature helper object not found")
+	}
+
+	kinds := map[string]string{}
+	for _, method := range regexp.MustCompile(`([a-zA-Z0-9$]+):function\(a(?:,b)?\)\{([^}]*)\}`).FindAllStringSubmatch(helper[1], -1) {
+		switch {
+		case strings.Contains(method[2], "reverse"):
+			kinds[method[1]] = "reverse"
+		case strings.Contains(method[2], "splice"):
+			kinds[method[1]] = "splice"
+		default:
+			kinds[method[1]] = "swap"
+		}
+	}
+
+	ops := make([]sigOp, 0, len(calls))
+	for _, call := range calls {
+		kind, ok := kinds[call[2]]
+		if !ok {
+			return nil, fmt.Errorf("unknown signature operation %s", call[2])
+		}
+		arg, _ := strconv.Atoi(call[3])
+		ops = append(ops, sigOp{kind: kind, arg: arg})
+	}
+	return ops, nil
+}
+
+// decipher applies the player's transform to an encrypted signature
+func (d *signatureDecipherer) decipher(ctx context.Context, api *YouTubeMusicAPI, s string) (string, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.load(ctx, api); err != nil {
+		return "", err
+	}
+	return applySignatureOps([]byte(s), d.ops), nil
+}
+
+// signatureTimestamp returns the player version the web client must report
+func (d *signatureDecipherer) signatureTimestamp(ctx context.Context, api *YouTubeMusicAPI) (int, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if err := d.load(ctx, api); err != nil {
+		return 0, err
+	}
+	return d.timestamp, nil
+}
+
+// applySignatureOps runs reverse/splice/swap steps over the signature
+func applySignatureOps(sig []byte, ops []sigOp) string {
+	for _, op := range ops {
+		switch op.kind {
+		case "reverse":
+			for i, j := 0, len(sig)-1; i < j; i, j = i+1, j-1 {
+				sig[i], sig[j] = sig[j], sig[i]
+			}
+		case "splice":
+			if op.arg < len(sig) {
+				sig = sig[op.arg:]
+			}
+		case "swap":
+			if len(sig) > 0 {
+				k := op.arg % len(sig)
+				sig[0], sig[k] = sig[k], sig[0]
+			}
+		}
+	}
+	return string(sig)
+}
+
+// httpGetString performs a GET and returns the body as a string
+func httpGetString(ctx context.Context, client *http.Client, target string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, playerJSTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("GET %s returned status %d", target, resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
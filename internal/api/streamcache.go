@@ -0,0 +1,153 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// streamMargin is how long before its expiry a stream URL stops being reused,
+// so a track started from the cache can still play to its end
+const streamMargin = 30 * time.Minute
+
+// streamCache remembers track durations for good and resolved stream URLs until
+// they expire, by video ID, in one small JSON file
+type streamCache struct {
+	path   string
+	mu     sync.Mutex
+	data   *streamCacheData // Read from path on first use
+	logger func(format string, v ...interface{})
+}
+
+// streamCacheData is the content of the stream cache file
+type streamCacheData struct {
+	Durations map[string]int        `json:"durations"` // Seconds, by video ID
+	Streams   map[string]StreamInfo `json:"streams"`   // By video ID and the stream preferences they were resolved with
+}
+
+// newStreamCache creates a stream cache kept in the file at path
+func newStreamCache(path string, logger func(format string, v ...interface{})) *streamCache {
+	return &streamCache{path: path, logger: logger}
+}
+
+// load reads the cache file the first time it's needed; the caller holds mu
+func (c *streamCache) load() {
+	if c.data != nil {
+		return
+	}
+	c.data = &streamCacheData{}
+	if data, err := os.ReadFile(c.path); err == nil {
+		if err := json.Unmarshal(data, c.data); err != nil {
+			c.logger("Ignoring unreadable stream cache: %v", err)
+			c.data = &streamCacheData{}
+		}
+	}
+	if c.data.Durations == nil {
+		c.data.Durations = map[string]int{}
+	}
+	if c.data.Streams == nil {
+		c.data.Streams = map[string]StreamInfo{}
+	}
+}
+
+// save writes the cache file, leaving out streams that have expired; the caller holds mu
+func (c *streamCache) save() {
+	for key, info := range c.data.Streams {
+		if !usable(info) {
+			delete(c.data.Streams, key)
+		}
+	}
+
+	data, err := json.Marshal(c.data)
+	if err != nil {
+		c.logger("Error encoding stream cache: %v", err)
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0755); err != nil {
+		c.logger("Error creating cache directory: %v", err)
+		return
+	}
+	// Write then rename, so a crash never leaves half a file
+	if err := os.WriteFile(c.path+".tmp", data, 0644); err != nil {
+		c.logger("Error writing stream cache: %v", err)
+		return
+	}
+	if err := os.Rename(c.path+".tmp", c.path); err != nil {
+		c.logger("Error writing stream cache: %v", err)
+	}
+}
+
+// usable reports whether a cached stream URL has long enough left before it expires
+func usable(info StreamInfo) bool {
+	return !info.Expires.IsZero() && time.Until(info.Expires) > streamMargin
+}
+
+// duration returns a track's remembered duration, 0 if there is none
+func (c *streamCache) duration(videoID string) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	return c.data.Durations[videoID]
+}
+
+// setDuration remembers a track's duration
+func (c *streamCache) setDuration(videoID string, seconds int) {
+	if videoID == "" || seconds <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	if c.data.Durations[videoID] == seconds {
+		return
+	}
+	c.data.Durations[videoID] = seconds
+	c.save()
+}
+
+// stream returns a resolved stream that hasn't expired yet
+func (c *streamCache) stream(key string) (StreamInfo, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	info, ok := c.data.Streams[key]
+	if !ok || !usable(info) {
+		return StreamInfo{}, false
+	}
+	return info, true
+}
+
+// putStream remembers a resolved stream until it expires, and its track's duration for good
+func (c *streamCache) putStream(key, videoID string, info StreamInfo) {
+	if !usable(info) && info.Duration <= 0 {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	if usable(info) {
+		c.data.Streams[key] = info
+	}
+	if info.Duration > 0 {
+		c.data.Durations[videoID] = info.Duration
+	}
+	c.save()
+}
+
+// streamKey names a track's stream resolved for the current account with the current stream preferences
+func (api *YouTubeMusicAPI) streamKey(videoID string) string {
+	return api.cacheKey("stream", videoID, string(api.streamQuality), string(api.streamCodec), fmt.Sprint(api.maxBitrate))
+}
+
+// Duration returns a track's duration as found when its stream was last resolved, 0 if unknown
+func (api *YouTubeMusicAPI) Duration(videoID string) int {
+	return api.streams.duration(videoID)
+}
+
+// SetDuration remembers a track's duration, such as one yt-dlp looked up
+func (api *YouTubeMusicAPI) SetDuration(videoID string, seconds int) {
+	api.streams.setDuration(videoID, seconds)
+}
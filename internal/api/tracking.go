@@ -0,0 +1,160 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// cpnAlphabet is the alphabet of the client playback nonce tying a play's pings together
+const cpnAlphabet = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789-_"
+
+// trackingResponse is the subset of the player response holding the stats URLs
+type trackingResponse struct {
+	PlaybackTracking struct {
+		VideostatsPlaybackURL struct {
+			BaseURL string `json:"baseUrl"`
+		} `json:"videostatsPlaybackUrl"`
+		VideostatsWatchtimeURL struct {
+			BaseURL string `json:"baseUrl"`
+		} `json:"videostatsWatchtimeUrl"`
+	} `json:"playbackTracking"`
+	VideoDetails struct {
+		LengthSeconds string `json:"lengthSeconds"`
+	} `json:"videoDetails"`
+}
+
+// PlaybackSession reports one play of a track to the account's history
+type PlaybackSession struct {
+	api          *YouTubeMusicAPI
+	VideoID      string
+	cpn          string
+	watchtimeURL string // Empty when the play was reported through the bridge
+	length       int
+	reported     int // Position covered by the last watchtime ping
+}
+
+// StartPlayback tells YouTube a track started playing, so it shows up in the
+// account's history and feeds recommendations
+func (api *YouTubeMusicAPI) StartPlayback(ctx context.Context, videoID string) (*PlaybackSession, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	// Only signed cookie sessions can send the pings themselves, and only they can follow up with watchtime
+	if api.getSAPISID() == "" {
+		if !api.bridge.IsAvailable() {
+			return nil, withKind(ErrBackendUnavailable, "playback reporting requires the Python bridge or a SAPISID cookie")
+		}
+		api.LogDebug("Reporting playback of %s via Python bridge", videoID)
+		if err := api.bridge.AddHistoryItem(ctx, videoID); err != nil {
+			return nil, err
+		}
+		return &PlaybackSession{api: api, VideoID: videoID}, nil
+	}
+
+	var response trackingResponse
+	err := api.sendRequest(ctx, "player", map[string]interface{}{
+		"videoId": videoID,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	playbackURL := response.PlaybackTracking.VideostatsPlaybackURL.BaseURL
+	if playbackURL == "" {
+		return nil, fmt.Errorf("no playback tracking URL for %s", videoID)
+	}
+
+	length, _ := strconv.Atoi(response.VideoDetails.LengthSeconds)
+	session := &PlaybackSession{
+		api:          api,
+		VideoID:      videoID,
+		cpn:          newCPN(),
+		watchtimeURL: response.PlaybackTracking.VideostatsWatchtimeURL.BaseURL,
+		length:       length,
+	}
+
+	api.LogDebug("Reporting playback of %s", videoID)
+	if err := session.ping(ctx, playbackURL, nil); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// ReportWatchtime tells YouTube how far playback got since the last report
+func (s *PlaybackSession) ReportWatchtime(ctx context.Context, position int) error {
+	if s.watchtimeURL == "" || position <= s.reported {
+		return nil
+	}
+
+	params := url.Values{}
+	params.Set("st", strconv.Itoa(s.reported))
+	params.Set("et", strconv.Itoa(position))
+	params.Set("cmt", strconv.Itoa(position))
+	params.Set("len", strconv.Itoa(s.length))
+	params.Set("state", "playing")
+
+	s.api.LogDebug("Reporting watchtime of %s: %d-%ds", s.VideoID, s.reported, position)
+	if err := s.ping(ctx, s.watchtimeURL, params); err != nil {
+		return err
+	}
+	s.reported = position
+	return nil
+}
+
+// ping sends a stats request with the session's nonce and the account's cookies
+func (s *PlaybackSession) ping(ctx context.Context, baseURL string, params url.Values) error {
+	target, err := url.Parse(baseURL)
+	if err != nil {
+		return fmt.Errorf("invalid tracking URL: %v", err)
+	}
+
+	query := target.Query()
+	query.Set("ver", "2")
+	query.Set("c", innertubeClientName)
+	query.Set("cpn", s.cpn)
+	for key, values := range params {
+		query[key] = values
+	}
+	target.RawQuery = query.Encode()
+
+	ctx, cancel := context.WithTimeout(ctx, shortTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, "GET", target.String(), nil)
+	if err != nil {
+		return err
+	}
+	s.api.signRequest(req)
+
+	// The stats host is outside music.youtube.com, so the jar won't attach the session cookies itself
+	ytMusicURL, _ := url.Parse(ytMusicOrigin)
+	for _, cookie := range s.api.client.Jar.Cookies(ytMusicURL) {
+		req.AddCookie(cookie)
+	}
+
+	resp, err := s.api.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("tracking request failed: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		return fmt.Errorf("tracking request returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// newCPN generates a client playback nonce
+func newCPN() string {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+	cpn := make([]byte, 16)
+	for i := range cpn {
+		cpn[i] = cpnAlphabet[r.Intn(len(cpnAlphabet))]
+	}
+	return string(cpn)
+}
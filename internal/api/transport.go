@@ -0,0 +1,53 @@
+package api
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// Timeouts of each kind of request. They replace a single timeout on the
+// client, which also cut off large playlist pages still being read.
+const (
+	// innertubeTimeout bounds an innertube request, from connecting to reading its response
+	innertubeTimeout = 15 * time.Second
+
+	// browseTimeout bounds browse requests, whose playlist and library pages run to megabytes
+	browseTimeout = 30 * time.Second
+
+	// playerJSTimeout bounds downloading the player script, over a megabyte
+	playerJSTimeout = 30 * time.Second
+
+	// shortTimeout bounds small requests such as token refreshes and playback reports
+	shortTimeout = 10 * time.Second
+)
+
+// endpointTimeout returns how long a request to an innertube endpoint may take
+func endpointTimeout(endpoint string) time.Duration {
+	if endpoint == "browse" {
+		return browseTimeout
+	}
+	return innertubeTimeout
+}
+
+// newTransport returns the transport every request of the API shares, so
+// moving through albums and playlist pages reuses warm connections instead of
+// repeating the TLS handshake. Browsing fires several requests at once at the
+// same few hosts, more than Go's default of two idle connections per host keeps.
+func newTransport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   10 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	return &http.Transport{
+		Proxy:                 http.ProxyFromEnvironment,
+		DialContext:           dialer.DialContext,
+		ForceAttemptHTTP2:     true,
+		MaxIdleConns:          32,
+		MaxIdleConnsPerHost:   8,
+		IdleConnTimeout:       2 * time.Minute,
+		TLSHandshakeTimeout:   10 * time.Second,
+		ResponseHeaderTimeout: 15 * time.Second,
+		ExpectContinueTimeout: time.Second,
+	}
+}
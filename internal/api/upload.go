@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Uploads browse IDs
+const (
+	browseUploadedSongs   = "FEmusic_library_privately_owned_tracks"
+	browseUploadedAlbums  = "FEmusic_library_privately_owned_releases"
+	browseUploadedArtists = "FEmusic_library_privately_owned_artists"
+	uploadedAlbumPrefix   = "FEmusic_library_privately_owned_release_detail"
+	uploadedArtistPrefix  = "FEmusic_library_privately_owned_artist_detail"
+)
+
+// isUpload reports whether a browse ID points into the user's uploads
+func isUpload(browseID string) bool {
+	return strings.HasPrefix(browseID, "FEmusic_library_privately_owned")
+}
+
+// GetUploadedSongs fetches the songs the user uploaded
+func (api *YouTubeMusicAPI) GetUploadedSongs(ctx context.Context) ([]Track, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching uploaded songs")
+
+	if !api.bridge.IsAvailable() {
+		if api.getSAPISID() == "" {
+			return nil, errLibraryUnavailable
+		}
+		api.LogDebug("Python bridge not available, fetching uploaded songs via innertube")
+		return api.getUploadedSongsNative(ctx)
+	}
+
+	tracks, err := api.bridge.GetUploadedSongs(ctx)
+	if err != nil {
+		api.LogDebug("Python bridge get uploaded songs failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d uploaded songs via Python bridge", len(tracks))
+	return tracks, nil
+}
+
+// GetUploadedAlbums fetches the albums of the user's uploads
+func (api *YouTubeMusicAPI) GetUploadedAlbums(ctx context.Context) ([]Album, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching uploaded albums")
+
+	if !api.bridge.IsAvailable() {
+		if api.getSAPISID() == "" {
+			return nil, errLibraryUnavailable
+		}
+		api.LogDebug("Python bridge not available, fetching uploaded albums via innertube")
+		return api.getAlbumGridNative(ctx, browseUploadedAlbums)
+	}
+
+	albums, err := api.bridge.GetUploadedAlbums(ctx)
+	if err != nil {
+		api.LogDebug("Python bridge get uploaded albums failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d uploaded albums via Python bridge", len(albums))
+	return albums, nil
+}
+
+// GetUploadedArtists fetches the artists of the user's uploads
+func (api *YouTubeMusicAPI) GetUploadedArtists(ctx context.Context) ([]Artist, error) {
+	return api.getLibraryArtists(ctx, "uploaded artists", browseUploadedArtists, api.bridge.GetUploadedArtists)
+}
+
+// getUploadedSongsNative fetches the uploaded songs shelf directly from innertube
+func (api *YouTubeMusicAPI) getUploadedSongsNative(ctx context.Context) ([]Track, error) {
+	var response BrowseResponse
+	err := api.sendRequest(ctx, "browse", map[string]interface{}{
+		"browseId": browseUploadedSongs,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	return tracksFromShelves(response.sectionList().shelves()), nil
+}
+
+// getUploadedArtistNative fetches an uploaded artist's songs directly from innertube.
+// Upload artist pages have no albums, singles, or radio; their songs become the top songs.
+func (api *YouTubeMusicAPI) getUploadedArtistNative(ctx context.Context, browseID string) (*Artist, error) {
+	var response BrowseResponse
+	err := api.sendRequest(ctx, "browse", map[string]interface{}{
+		"browseId": browseID,
+	}, &response)
+	if err != nil {
+		return nil, err
+	}
+
+	artist := &Artist{ID: browseID}
+	if header := response.header(); header != nil {
+		artist.Name = header.Title.Text()
+	}
+	artist.TopSongs = tracksFromShelves(response.sectionList().shelves())
+	if artist.Name == "" && len(artist.TopSongs) > 0 {
+		artist.Name = artist.TopSongs[0].Artist
+	}
+	artist.Subtitle = fmt.Sprintf("%d uploaded songs", len(artist.TopSongs))
+
+	return artist, nil
+}
@@ -0,0 +1,214 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// WatchNextResponse is the top-level response of the next endpoint
+type WatchNextResponse struct {
+	Contents struct {
+		SingleColumnMusicWatchNextResultsRenderer struct {
+			TabbedRenderer struct {
+				WatchNextTabbedResultsRenderer struct {
+					Tabs []struct {
+						TabRenderer struct {
+							Title    string              `json:"title"`
+							Endpoint *NavigationEndpoint `json:"endpoint,omitempty"` // Set on the lyrics and related tabs
+							Content  struct {
+								MusicQueueRenderer struct {
+									Content struct {
+										PlaylistPanelRenderer PlaylistPanelRenderer `json:"playlistPanelRenderer"`
+									} `json:"content"`
+								} `json:"musicQueueRenderer"`
+							} `json:"content"`
+						} `json:"tabRenderer"`
+					} `json:"tabs"`
+				} `json:"watchNextTabbedResultsRenderer"`
+			} `json:"tabbedRenderer"`
+		} `json:"singleColumnMusicWatchNextResultsRenderer"`
+	} `json:"contents"`
+}
+
+// PlaylistPanelRenderer is the up-next list of the player
+type PlaylistPanelRenderer struct {
+	Contents []struct {
+		PlaylistPanelVideoRenderer        *PlaylistPanelVideoRenderer `json:"playlistPanelVideoRenderer,omitempty"`
+		PlaylistPanelVideoWrapperRenderer *struct {
+			PrimaryRenderer struct {
+				PlaylistPanelVideoRenderer *PlaylistPanelVideoRenderer `json:"playlistPanelVideoRenderer,omitempty"`
+			} `json:"primaryRenderer"`
+		} `json:"playlistPanelVideoWrapperRenderer,omitempty"`
+	} `json:"contents"`
+}
+
+// PlaylistPanelVideoRenderer is one track in the up-next list
+type PlaylistPanelVideoRenderer struct {
+	VideoID        string `json:"videoId"`
+	Title          Runs   `json:"title"`
+	LongBylineText Runs   `json:"longBylineText"` // "Artist • Album • 2019"
+	LengthText     Runs   `json:"lengthText"`
+	Thumbnail      struct {
+		Thumbnails []struct {
+			URL string `json:"url"`
+		} `json:"thumbnails"`
+	} `json:"thumbnail"`
+}
+
+// toTrack converts an up-next entry into a Track
+func (r *PlaylistPanelVideoRenderer) toTrack() Track {
+	track := Track{
+		ID:         r.VideoID,
+		TrackTitle: r.Title.Text(),
+		Duration:   parseDuration(r.LengthText.Text()),
+	}
+
+	var artists []string
+	for _, run := range r.LongBylineText.Runs {
+		if run.NavigationEndpoint == nil || run.NavigationEndpoint.BrowseEndpoint == nil {
+			continue
+		}
+		switch run.NavigationEndpoint.BrowseEndpoint.PageType() {
+		case pageTypeArtist:
+			artists = append(artists, run.Text)
+			if track.ArtistID == "" {
+				track.ArtistID = run.NavigationEndpoint.BrowseEndpoint.BrowseID
+			}
+		case pageTypeAlbum:
+			track.Album = run.Text
+			track.AlbumID = run.NavigationEndpoint.BrowseEndpoint.BrowseID
+		}
+	}
+	if thumbnails := r.Thumbnail.Thumbnails; len(thumbnails) > 0 {
+		track.ThumbnailURL = thumbnails[len(thumbnails)-1].URL
+	}
+	if len(artists) == 0 {
+		artists = append(artists, r.LongBylineText.First())
+	}
+	track.Artist = strings.Join(artists, ", ")
+
+	return track
+}
+
+// GetWatchPlaylist fetches the radio of related tracks for a video, starting with the video itself
+func (api *YouTubeMusicAPI) GetWatchPlaylist(ctx context.Context, videoID string) ([]Track, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching radio for track: %s", videoID)
+
+	if !api.bridge.IsAvailable() {
+		api.LogDebug("Python bridge not available, fetching radio via innertube")
+		return api.getWatchPlaylistNative(ctx, videoID, "")
+	}
+
+	tracks, err := api.bridge.GetWatchPlaylist(ctx, videoID, "")
+	if err != nil {
+		api.LogDebug("Python bridge get watch playlist failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d radio tracks via Python bridge", len(tracks))
+	return tracks, nil
+}
+
+// GetArtistRadio fetches the tracks of an artist's radio playlist
+func (api *YouTubeMusicAPI) GetArtistRadio(ctx context.Context, radioID string) ([]Track, error) {
+	if !api.IsLoggedIn {
+		return nil, ErrNotLoggedIn
+	}
+
+	api.LogDebug("Fetching artist radio: %s", radioID)
+
+	if !api.bridge.IsAvailable() {
+		api.LogDebug("Python bridge not available, fetching artist radio via innertube")
+		return api.getWatchPlaylistNative(ctx, "", radioID)
+	}
+
+	tracks, err := api.bridge.GetWatchPlaylist(ctx, "", radioID)
+	if err != nil {
+		api.LogDebug("Python bridge get watch playlist failed: %v", err)
+		return nil, err
+	}
+
+	api.LogDebug("Found %d radio tracks via Python bridge", len(tracks))
+	return tracks, nil
+}
+
+// radioParams asks the next endpoint for a radio rather than a plain up-next list
+const radioParams = "wAEB"
+
+// getWatchPlaylistNative fetches the up-next list for a video and/or playlist from innertube.
+// A video without a playlist gets the video's radio.
+func (api *YouTubeMusicAPI) getWatchPlaylistNative(ctx context.Context, videoID, playlistID string) ([]Track, error) {
+	body := map[string]interface{}{
+		"enablePersistentPlaylistPanel": true,
+		"isAudioOnly":                   true,
+		"tunerSettingValue":             "AUTOMIX_SETTING_NORMAL",
+	}
+	if videoID != "" {
+		body["videoId"] = videoID
+		if playlistID == "" {
+			playlistID = "RDAMVM" + videoID
+			body["params"] = radioParams
+		}
+	}
+	if playlistID != "" {
+		body["playlistId"] = playlistID
+	}
+
+	var response WatchNextResponse
+	if err := api.sendRequest(ctx, "next", body, &response); err != nil {
+		return nil, err
+	}
+	return response.tracks()
+}
+
+// GetTrackMetadata fetches a track's duration, album and artwork, which some playlists leave out
+func (api *YouTubeMusicAPI) GetTrackMetadata(ctx context.Context, videoID string) (Track, error) {
+	if !api.IsLoggedIn {
+		return Track{}, ErrNotLoggedIn
+	}
+
+	var response WatchNextResponse
+	if err := api.sendRequest(ctx, "next", map[string]interface{}{
+		"videoId":     videoID,
+		"isAudioOnly": true,
+	}, &response); err != nil {
+		return Track{}, err
+	}
+	tracks, err := response.tracks()
+	if err != nil {
+		return Track{}, err
+	}
+	for _, track := range tracks {
+		if track.ID == videoID {
+			return track, nil
+		}
+	}
+	return Track{}, fmt.Errorf("%s is missing from its up-next list", videoID)
+}
+
+// tracks returns the up-next list
+func (r *WatchNextResponse) tracks() ([]Track, error) {
+	tabs := r.Contents.SingleColumnMusicWatchNextResultsRenderer.TabbedRenderer.WatchNextTabbedResultsRenderer.Tabs
+	if len(tabs) == 0 {
+		return nil, fmt.Errorf("no up-next list in watch response")
+	}
+
+	tracks := []Track{}
+	for _, item := range tabs[0].TabRenderer.Content.MusicQueueRenderer.Content.PlaylistPanelRenderer.Contents {
+		video := item.PlaylistPanelVideoRenderer
+		if video == nil && item.PlaylistPanelVideoWrapperRenderer != nil {
+			video = item.PlaylistPanelVideoWrapperRenderer.PrimaryRenderer.PlaylistPanelVideoRenderer
+		}
+		if video == nil || video.VideoID == "" {
+			continue
+		}
+		tracks = append(tracks, video.toTrack())
+	}
+
+	return tracks, nil
+}
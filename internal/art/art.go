@@ -0,0 +1,182 @@
+// Package art keeps downloaded album art on disk, so artwork shown again isn't
+// fetched again. Once the images outgrow the cache's cap, the least recently
+// used ones are removed in the background.
+package art
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"sync"
+	"time"
+
+	"ytmusic/internal/paths"
+)
+
+// DefaultSize is the size cap of the art cache in megabytes
+const DefaultSize = 100
+
+// maxImageSize bounds a single download, far above any thumbnail
+const maxImageSize = 10 << 20
+
+// fetchTimeout bounds downloading one image
+const fetchTimeout = 15 * time.Second
+
+// Dir returns the directory album art is cached in
+func Dir() string {
+	return filepath.Join(paths.Cache(), "art")
+}
+
+// Cache is a directory of downloaded artwork with a size cap
+type Cache struct {
+	dir      string
+	maxBytes int64 // 0 disables the cache
+	client   *http.Client
+	logger   func(format string, v ...interface{})
+
+	mu      sync.Mutex
+	pruning bool // Whether a prune is running
+}
+
+// New creates a cache in dir holding up to sizeMB megabytes, 0 to disable it
+func New(dir string, sizeMB int, logger func(format string, v ...interface{})) *Cache {
+	return &Cache{
+		dir:      dir,
+		maxBytes: int64(sizeMB) << 20,
+		client:   &http.Client{Timeout: fetchTimeout},
+		logger:   logger,
+	}
+}
+
+// unsafeKey matches what can't go in a file name
+var unsafeKey = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// Key names a track's artwork. Tracks of an album share its artwork, so it is
+// kept once under the album; tracks without one are kept under their own ID.
+func Key(albumID, videoID string) string {
+	if albumID != "" {
+		return unsafeKey.ReplaceAllString(albumID, "_")
+	}
+	return unsafeKey.ReplaceAllString(videoID, "_")
+}
+
+// path returns the file of a key's artwork
+func (c *Cache) path(key string) string {
+	return filepath.Join(c.dir, key+".jpg")
+}
+
+// Fetch returns the file of a key's artwork, downloading it from url unless
+// it's already cached. It returns "" when the cache is off or there is no url.
+func (c *Cache) Fetch(ctx context.Context, key, url string) (string, error) {
+	if c == nil || c.maxBytes <= 0 || key == "" || url == "" {
+		return "", nil
+	}
+
+	path := c.path(key)
+	if _, err := os.Stat(path); err == nil {
+		// Pruning goes by modification time, so a hit marks the image as used
+		now := time.Now()
+		os.Chtimes(path, now, now)
+		return path, nil
+	}
+
+	if err := c.download(ctx, url, path); err != nil {
+		return "", err
+	}
+	c.logger("Cached artwork %s", key)
+	c.pruneInBackground()
+	return path, nil
+}
+
+// download saves an image to path, writing it under another name first so a
+// reader never sees half of it
+func (c *Cache) download(ctx context.Context, url, path string) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("artwork request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("artwork request returned status %d", resp.StatusCode)
+	}
+
+	if err := os.MkdirAll(c.dir, 0755); err != nil {
+		return fmt.Errorf("could not create %s: %v", c.dir, err)
+	}
+	tmp, err := os.CreateTemp(c.dir, "download-*")
+	if err != nil {
+		return fmt.Errorf("could not save artwork: %v", err)
+	}
+	_, err = io.Copy(tmp, io.LimitReader(resp.Body, maxImageSize))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return fmt.Errorf("could not save artwork: %v", err)
+	}
+	return nil
+}
+
+// pruneInBackground starts a prune unless one is already running
+func (c *Cache) pruneInBackground() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.pruning {
+		return
+	}
+	c.pruning = true
+	go func() {
+		c.prune()
+		c.mu.Lock()
+		c.pruning = false
+		c.mu.Unlock()
+	}()
+}
+
+// prune removes the least recently used images until the cache fits its cap
+func (c *Cache) prune() {
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+
+	var files []os.FileInfo
+	var total int64
+	for _, entry := range entries {
+		info, err := entry.Info()
+		if err != nil || info.IsDir() {
+			continue
+		}
+		files = append(files, info)
+		total += info.Size()
+	}
+	if total <= c.maxBytes {
+		return
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].ModTime().Before(files[j].ModTime())
+	})
+	for _, info := range files {
+		if total <= c.maxBytes {
+			break
+		}
+		if err := os.Remove(filepath.Join(c.dir, info.Name())); err == nil {
+			total -= info.Size()
+		}
+	}
+	c.logger("Pruned artwork cache to %d bytes", total)
+}
@@ -0,0 +1,658 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"ytmusic/internal/paths"
+)
+
+// Config holds the settings read from config.toml in the config directory
+type Config struct {
+	DefaultView string            `toml:"default_view"` // View shown after login
+	Keys        map[string]string `toml:"keys"`         // Action name to key, overriding DefaultKeys
+	Theme       string            `toml:"theme"`        // Name of the preset in Themes
+	Colors      Colors            `toml:"colors"`       // Colors overriding the theme's, empty to keep them
+	MPV         MPV               `toml:"mpv"`
+	Audio       Audio             `toml:"audio"`
+	Locale      Locale            `toml:"locale"`
+	Cache       Cache             `toml:"cache"`
+	HTTP        HTTP              `toml:"http"`
+	Hotkeys     GlobalHotkeys     `toml:"global_hotkeys"`
+
+	// Show the player in the Windows or macOS media controls and follow the media keys
+	MediaControls bool `toml:"media_controls"`
+	// Play only downloaded tracks, without connecting. Offline mode also starts on
+	// its own when YouTube Music can't be reached and there are downloads.
+	Offline bool `toml:"offline"`
+	// How shuffle orders the queue: random, or smart to keep the same artist from
+	// playing twice in a row and tracks from coming back before most of the queue has played
+	Shuffle string `toml:"shuffle"`
+	// Leave out tracks that are already in the queue when adding more, such as
+	// when merging playlists; "play next" moves the queued copy instead
+	SkipDuplicates bool `toml:"skip_duplicates"`
+	// Start with the visualizer of the now playing view on. It stays off on
+	// terminals too slow to draw it, and turns itself off when one falls behind.
+	Visualizer bool `toml:"visualizer"`
+	// Let the mouse seek by clicking or dragging along the progress bar. Terminals
+	// leave selecting text to Shift+drag while the mouse is on.
+	Mouse bool `toml:"mouse"`
+	// Seconds the seek keys jump: ←/→ by seek_step, ,/. by seek_step_long
+	SeekStep     int `toml:"seek_step"`
+	SeekStepLong int `toml:"seek_step_long"`
+	// Order searches and playlists are fetched in, from Backends
+	Backends []string `toml:"backends"`
+}
+
+// Colors are the interface colors, as "#RRGGBB" or an ANSI color number
+type Colors struct {
+	Accent           string `toml:"accent"`            // Borders, titles, and the selected row
+	Text             string `toml:"text"`              // Track titles and other body text
+	Muted            string `toml:"muted"`             // Descriptions and hints
+	Playing          string `toml:"playing"`           // Title of the playing track
+	Error            string `toml:"error"`             // Error messages
+	Warning          string `toml:"warning"`           // Loading and warning messages
+	Active           string `toml:"active"`            // Highlighted tabs and modes
+	Selected         string `toml:"selected"`          // Text of the selected row
+	StatusText       string `toml:"status_text"`       // Text of the status bar
+	StatusBackground string `toml:"status_background"` // Background of the status bar
+}
+
+// Themes are the color presets that theme selects from
+var Themes = map[string]Colors{
+	"default": {
+		Accent:           "#ff0000",
+		Text:             "#FFFFFF",
+		Muted:            "#AAAAAA",
+		Playing:          "#00FF00",
+		Error:            "#FF0000",
+		Warning:          "#FFAA00",
+		Active:           "#00AAFF",
+		Selected:         "#000000",
+		StatusText:       "#000000",
+		StatusBackground: "#EEEEEE",
+	},
+	"gruvbox": {
+		Accent:           "#fe8019",
+		Text:             "#ebdbb2",
+		Muted:            "#928374",
+		Playing:          "#b8bb26",
+		Error:            "#fb4934",
+		Warning:          "#fabd2f",
+		Active:           "#83a598",
+		Selected:         "#282828",
+		StatusText:       "#282828",
+		StatusBackground: "#d5c4a1",
+	},
+	"dracula": {
+		Accent:           "#bd93f9",
+		Text:             "#f8f8f2",
+		Muted:            "#6272a4",
+		Playing:          "#50fa7b",
+		Error:            "#ff5555",
+		Warning:          "#ffb86c",
+		Active:           "#8be9fd",
+		Selected:         "#282a36",
+		StatusText:       "#282a36",
+		StatusBackground: "#f8f8f2",
+	},
+	"high-contrast": {
+		Accent:           "#FFFF00",
+		Text:             "#FFFFFF",
+		Muted:            "#D0D0D0",
+		Playing:          "#00FF00",
+		Error:            "#FF5555",
+		Warning:          "#FFFF00",
+		Active:           "#00FFFF",
+		Selected:         "#000000",
+		StatusText:       "#000000",
+		StatusBackground: "#FFFFFF",
+	},
+}
+
+// MPV configures the player process
+type MPV struct {
+	Path string   `toml:"path"` // Binary to run, looked up on PATH if not absolute
+	Args []string `toml:"args"` // Extra arguments passed before the stream URL
+	// Format mpv asks yt-dlp for when it opens a watch URL itself, which audio
+	// falls back to when no stream could be resolved; empty for mpv's default
+	YtdlFormat string `toml:"ytdl_format"`
+}
+
+// Audio configures stream selection
+type Audio struct {
+	Quality string `toml:"quality"`  // low, medium (also "normal"), or high
+	Codec   string `toml:"codec"`    // any, opus, or m4a
+	MaxRate string `toml:"max_rate"` // Cap on the download rate, such as "500K" or "2M"; empty for none
+}
+
+// MaxBitrate returns the max_rate cap in kbps, 0 if there is none
+func (a Audio) MaxBitrate() int {
+	rate := strings.ToUpper(a.MaxRate)
+	multiplier := 1.0
+	switch {
+	case strings.HasSuffix(rate, "K"):
+		multiplier = 1024
+	case strings.HasSuffix(rate, "M"):
+		multiplier = 1024 * 1024
+	}
+	value, err := strconv.ParseFloat(strings.TrimRight(rate, "KM"), 64)
+	if err != nil {
+		return 0
+	}
+	return int(value * multiplier * 8 / 1000)
+}
+
+// Locale sets the language and region YouTube Music answers in
+type Locale struct {
+	Language string `toml:"language"` // Such as "en" or "de"
+	Region   string `toml:"region"`   // Two-letter country code such as "US"
+}
+
+// Cache sizes the on-disk caches of API responses and album art
+type Cache struct {
+	SizeMB    int `toml:"size_mb"`     // 0 turns the response cache off
+	ArtSizeMB int `toml:"art_size_mb"` // 0 turns the art cache off
+}
+
+// HTTP configures the REST API that daemon mode serves
+type HTTP struct {
+	Listen string `toml:"listen"` // Address such as "127.0.0.1:8787", empty to turn the API off
+}
+
+// GlobalHotkeys are key combinations that control playback from any window on
+// Windows and macOS. They're off unless enabled, as a registered combination no
+// longer reaches the app that's focused while ytmusic runs.
+type GlobalHotkeys struct {
+	Enabled  bool   `toml:"enabled"`
+	Toggle   string `toml:"toggle"`   // Play/pause, such as "ctrl+alt+space"; empty for none
+	Next     string `toml:"next"`     // Next track
+	Previous string `toml:"previous"` // Previous track
+}
+
+// Views that default_view accepts
+var Views = []string{"home", "search", "playlists", "library", "queue", "charts", "uploads"}
+
+// Backends that backends accepts. "mock" serves made-up demo data.
+var Backends = []string{"native", "bridge", "mock"}
+
+// DefaultKeys maps every rebindable action to its default key
+var DefaultKeys = map[string]string{
+	"quit":            "q",
+	"search":          "/",
+	"play_pause":      " ",
+	"next":            "n",
+	"previous":        "b",
+	"seek_back":       "left",
+	"seek_forward":    "right",
+	"seek_back_long":  ",",
+	"seek_fwd_long":   ".",
+	"volume_up":       "+",
+	"volume_down":     "-",
+	"repeat":          "r",
+	"shuffle":         "s",
+	"radio":           "ctrl+r",
+	"autoplay":        "o",
+	"queue_add":       "a",
+	"play_next":       "A",
+	"add_to_playlist": "P",
+	"menu":            "m",
+	"go_to_album":     "g",
+	"go_to_artist":    "G",
+	"playing_album":   "alt+g",
+	"playing_artist":  "alt+G",
+	"jump_playing":    "ctrl+f",
+	"visualizer":      "z",
+	"lyrics":          "y",
+	"stats":           "S",
+	"logs":            "ctrl+l",
+	"compact":         "M",
+	"quality":         "B",
+	"video":           "V",
+	"info":            "i",
+	"like":            "l",
+	"select":          "v",
+	"playlists":       "p",
+	"queue":           "Q",
+	"home":            "H",
+	"charts":          "C",
+	"uploads":         "U",
+	"library":         "L",
+	"reset_cookie":    "R",
+	"help":            "?",
+	"messages":        "N",
+	"accounts":        "I",
+}
+
+// ReservedKeys mean something of their own in some views, such as moving through
+// a list or reordering the queue. Actions can't be bound to them, as they'd take
+// the key over everywhere.
+var ReservedKeys = []string{
+	"enter", "esc", "backspace", "tab", "shift+tab", "up", "down", "k", "j",
+	"pgup", "pgdown", "home", "end", "1", "2", "3", "4", "5", "6",
+	"d", "J", "K", "T", "c", "e", "D", "x", "=", "Y",
+	"ctrl+up", "ctrl+down", "ctrl+a", "ctrl+j", "ctrl+c",
+}
+
+// Default returns the settings used when there is no config file
+func Default() *Config {
+	return &Config{
+		DefaultView: "home",
+		Keys:        map[string]string{},
+		Theme:       "default",
+		MPV: MPV{
+			Path:       "mpv",
+			YtdlFormat: "bestaudio/best",
+		},
+		Audio: Audio{
+			Quality: "high",
+			Codec:   "any",
+		},
+		Locale: Locale{
+			Language: "en",
+			Region:   "US",
+		},
+		Cache: Cache{
+			SizeMB:    50,
+			ArtSizeMB: 100,
+		},
+		Hotkeys: GlobalHotkeys{
+			Toggle:   "ctrl+alt+space",
+			Next:     "ctrl+alt+right",
+			Previous: "ctrl+alt+left",
+		},
+		MediaControls: true,
+		Shuffle:       "random",
+		SeekStep:      5,
+		SeekStepLong:  30,
+		Backends:      []string{"native", "bridge"},
+	}
+}
+
+// Path returns the location of the config file
+func Path() string {
+	return filepath.Join(paths.Config(), "config.toml")
+}
+
+// starterConfig is the config file written on the first run. The backend order
+// is filled in; everything else is left at its default, commented out.
+const starterConfig = `# ytmusic settings. See the README for all of them.
+
+# Where searches, playlists and the home feed come from, tried in order:
+# "native" talks to YouTube Music directly, "bridge" goes through ytmusicapi
+backends = [%s]
+
+# default_view = "home"
+# theme = "default"
+
+# [mpv]
+# path = "mpv"
+# args = ["--ao=pipewire"]
+# ytdl_format = "bestaudio/best"
+
+# [audio]
+# quality = "high"
+`
+
+// Create writes a new config file with the backend order chosen on the first
+// run, failing if one already exists
+func Create(path string, backends []string) error {
+	quoted := make([]string, len(backends))
+	for i, backend := range backends {
+		quoted[i] = strconv.Quote(backend)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("could not create %s: %v", filepath.Dir(path), err)
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %v", filepath.Base(path), err)
+	}
+	if _, err := fmt.Fprintf(f, starterConfig, strings.Join(quoted, ", ")); err != nil {
+		f.Close()
+		return fmt.Errorf("could not write %s: %v", filepath.Base(path), err)
+	}
+	return f.Close()
+}
+
+// ParseError is returned by Load when the file isn't valid TOML, with the
+// defaults in place of everything it sets
+type ParseError struct {
+	File string
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s: %v", e.File, e.Err)
+}
+
+// Load reads the config file over the defaults. A missing file is not an error.
+// Invalid settings fall back to their defaults and are reported together in the
+// returned error, so the caller can still start with the returned config.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return cfg, nil
+	}
+
+	meta, err := toml.DecodeFile(path, cfg)
+	if err != nil {
+		return Default(), &ParseError{File: filepath.Base(path), Err: err}
+	}
+
+	var problems []string
+	for _, key := range meta.Undecoded() {
+		problems = append(problems, fmt.Sprintf("unknown setting %q", key.String()))
+	}
+	problems = append(problems, cfg.validate()...)
+
+	if len(problems) > 0 {
+		return cfg, fmt.Errorf("%s: %s", filepath.Base(path), strings.Join(problems, "; "))
+	}
+	return cfg, nil
+}
+
+// validate resets invalid settings to their defaults and describes each problem
+func (c *Config) validate() []string {
+	defaults := Default()
+	var problems []string
+
+	if !contains(Views, c.DefaultView) {
+		problems = append(problems, fmt.Sprintf("default_view %q is not one of %s", c.DefaultView, strings.Join(Views, ", ")))
+		c.DefaultView = defaults.DefaultView
+	}
+
+	problems = append(problems, c.validateKeys()...)
+
+	if _, ok := Themes[c.Theme]; !ok {
+		problems = append(problems, fmt.Sprintf("theme %q is not one of %s", c.Theme, strings.Join(ThemeNames(), ", ")))
+		c.Theme = defaults.Theme
+	}
+	colors := c.Colors.fields()
+	names := make([]string, 0, len(colors))
+	for name := range colors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if value := colors[name]; *value != "" && !validColor(*value) {
+			problems = append(problems, fmt.Sprintf("colors.%s %q is not a #RRGGBB or 0-255 color", name, *value))
+			*value = ""
+		}
+	}
+
+	if strings.TrimSpace(c.MPV.Path) == "" {
+		problems = append(problems, "mpv.path is empty")
+		c.MPV.Path = defaults.MPV.Path
+	}
+
+	if c.Audio.Quality == "normal" {
+		c.Audio.Quality = "medium"
+	}
+	if !contains([]string{"low", "medium", "high"}, c.Audio.Quality) {
+		problems = append(problems, fmt.Sprintf("audio.quality %q is not low, medium, or high", c.Audio.Quality))
+		c.Audio.Quality = defaults.Audio.Quality
+	}
+	if !contains([]string{"any", "opus", "m4a"}, c.Audio.Codec) {
+		problems = append(problems, fmt.Sprintf("audio.codec %q is not any, opus, or m4a", c.Audio.Codec))
+		c.Audio.Codec = defaults.Audio.Codec
+	}
+	if c.Audio.MaxRate != "" && !ratePattern.MatchString(c.Audio.MaxRate) {
+		problems = append(problems, fmt.Sprintf("audio.max_rate %q is not a rate such as 500K or 2M", c.Audio.MaxRate))
+		c.Audio.MaxRate = ""
+	}
+
+	if c.SeekStep <= 0 {
+		problems = append(problems, fmt.Sprintf("seek_step %d is not a positive number of seconds", c.SeekStep))
+		c.SeekStep = defaults.SeekStep
+	}
+	if c.SeekStepLong <= 0 {
+		problems = append(problems, fmt.Sprintf("seek_step_long %d is not a positive number of seconds", c.SeekStepLong))
+		c.SeekStepLong = defaults.SeekStepLong
+	}
+
+	if !contains([]string{"random", "smart"}, c.Shuffle) {
+		problems = append(problems, fmt.Sprintf("shuffle %q is not random or smart", c.Shuffle))
+		c.Shuffle = defaults.Shuffle
+	}
+
+	if !ValidLanguage(c.Locale.Language) {
+		problems = append(problems, fmt.Sprintf("locale.language %q is not a language code such as \"en\"", c.Locale.Language))
+		c.Locale.Language = defaults.Locale.Language
+	}
+	if !ValidRegion(c.Locale.Region) {
+		problems = append(problems, fmt.Sprintf("locale.region %q is not a two-letter country code", c.Locale.Region))
+		c.Locale.Region = defaults.Locale.Region
+	}
+	c.Locale.Region = strings.ToUpper(c.Locale.Region)
+
+	if c.Cache.SizeMB < 0 {
+		problems = append(problems, fmt.Sprintf("cache.size_mb %d is negative", c.Cache.SizeMB))
+		c.Cache.SizeMB = defaults.Cache.SizeMB
+	}
+	if c.Cache.ArtSizeMB < 0 {
+		problems = append(problems, fmt.Sprintf("cache.art_size_mb %d is negative", c.Cache.ArtSizeMB))
+		c.Cache.ArtSizeMB = defaults.Cache.ArtSizeMB
+	}
+
+	if c.HTTP.Listen != "" && !ValidListenAddress(c.HTTP.Listen) {
+		problems = append(problems, fmt.Sprintf("http.listen %q is not a host:port address", c.HTTP.Listen))
+		c.HTTP.Listen = defaults.HTTP.Listen
+	}
+
+	hotkeys := map[string]*string{
+		"toggle":   &c.Hotkeys.Toggle,
+		"next":     &c.Hotkeys.Next,
+		"previous": &c.Hotkeys.Previous,
+	}
+	usedBy := map[string]string{}
+	for _, name := range []string{"toggle", "next", "previous"} {
+		combo := hotkeys[name]
+		if *combo == "" {
+			continue
+		}
+		if !ValidHotkey(*combo) {
+			problems = append(problems, fmt.Sprintf("global_hotkeys.%s %q is not a combination such as ctrl+alt+space", name, *combo))
+			*combo = ""
+			continue
+		}
+		if other, taken := usedBy[*combo]; taken {
+			problems = append(problems, fmt.Sprintf("global_hotkeys.%s %q is already used by %s", name, *combo, other))
+			*combo = ""
+			continue
+		}
+		usedBy[*combo] = name
+	}
+
+	if problem := validateBackends(c.Backends); problem != "" {
+		problems = append(problems, problem)
+		c.Backends = defaults.Backends
+	}
+
+	return problems
+}
+
+// ValidListenAddress reports whether address is a host:port to listen on
+func ValidListenAddress(address string) bool {
+	_, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return false
+	}
+	n, err := strconv.Atoi(port)
+	return err == nil && n >= 0 && n <= 65535
+}
+
+// validateBackends describes what is wrong with a backend order, or returns ""
+func validateBackends(backends []string) string {
+	if len(backends) == 0 {
+		return "backends is empty"
+	}
+	seen := map[string]bool{}
+	for _, backend := range backends {
+		if !contains(Backends, backend) {
+			return fmt.Sprintf("backends %q is not one of %s", backend, strings.Join(Backends, ", "))
+		}
+		if seen[backend] {
+			return fmt.Sprintf("backends lists %q twice", backend)
+		}
+		seen[backend] = true
+	}
+	return ""
+}
+
+// validateKeys drops bindings for unknown actions and keys bound to two actions
+func (c *Config) validateKeys() []string {
+	var problems []string
+
+	// Sort so the same file always reports the same problems
+	actions := make([]string, 0, len(c.Keys))
+	for action := range c.Keys {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+
+	boundBy := map[string]string{}
+	for action, key := range DefaultKeys {
+		if _, overridden := c.Keys[action]; !overridden {
+			boundBy[key] = action
+		}
+	}
+	for _, action := range actions {
+		key := c.Keys[action]
+		if _, ok := DefaultKeys[action]; !ok {
+			problems = append(problems, fmt.Sprintf("keys.%s is not an action", action))
+			delete(c.Keys, action)
+			continue
+		}
+		if key == "" {
+			problems = append(problems, fmt.Sprintf("keys.%s is empty", action))
+			delete(c.Keys, action)
+			continue
+		}
+		if contains(ReservedKeys, key) {
+			problems = append(problems, fmt.Sprintf("keys.%s %q is reserved for lists and other views", action, key))
+			delete(c.Keys, action)
+			continue
+		}
+		if other, taken := boundBy[key]; taken && other != action {
+			problems = append(problems, fmt.Sprintf("keys.%s %q is already bound to %s", action, key, other))
+			delete(c.Keys, action)
+			continue
+		}
+		boundBy[key] = action
+	}
+
+	return problems
+}
+
+// ThemeColors returns the theme's colors with the overrides from [colors] applied
+func (c *Config) ThemeColors() Colors {
+	colors := Themes[c.Theme]
+	theme := colors.fields()
+	for name, value := range c.Colors.fields() {
+		if *value != "" {
+			*theme[name] = *value
+		}
+	}
+	return colors
+}
+
+// ThemeNames returns the names of the theme presets in order
+func ThemeNames() []string {
+	names := make([]string, 0, len(Themes))
+	for name := range Themes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// fields returns pointers to the colors by their config names
+func (c *Colors) fields() map[string]*string {
+	return map[string]*string{
+		"accent":            &c.Accent,
+		"text":              &c.Text,
+		"muted":             &c.Muted,
+		"playing":           &c.Playing,
+		"error":             &c.Error,
+		"warning":           &c.Warning,
+		"active":            &c.Active,
+		"selected":          &c.Selected,
+		"status_text":       &c.StatusText,
+		"status_background": &c.StatusBackground,
+	}
+}
+
+// Key returns the key bound to an action
+func (c *Config) Key(action string) string {
+	if key, ok := c.Keys[action]; ok {
+		return key
+	}
+	return DefaultKeys[action]
+}
+
+// localePattern matches language codes such as "en" or "pt-BR"
+var localePattern = regexp.MustCompile(`^[a-z]{2,3}(-[A-Za-z]{2,4})?$`)
+
+// ValidLanguage reports whether language is a language code such as "en" or "pt-BR"
+func ValidLanguage(language string) bool {
+	return localePattern.MatchString(language)
+}
+
+// ValidRegion reports whether region is a two-letter country code
+func ValidRegion(region string) bool {
+	return len(region) == 2
+}
+
+// hotkeyPattern matches modifiers and a key joined by "+", such as "ctrl+alt+space"
+var hotkeyPattern = regexp.MustCompile(`^((ctrl|alt|shift|super)\+)+([a-z0-9]|f[1-9]|f1[0-2]|space|left|right|up|down|home|end|pageup|pagedown)$`)
+
+// ValidHotkey reports whether combo is a global hotkey the helpers can register.
+// It needs ctrl, alt or super, so that it doesn't take away plain typing.
+func ValidHotkey(combo string) bool {
+	if !hotkeyPattern.MatchString(combo) {
+		return false
+	}
+	parts := strings.Split(combo, "+")
+	for _, modifier := range parts[:len(parts)-1] {
+		if modifier != "shift" {
+			return true
+		}
+	}
+	return false
+}
+
+// hexColorPattern matches "#RRGGBB" colors
+var hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// ratePattern matches a download rate in bytes per second, with an optional K or M suffix
+var ratePattern = regexp.MustCompile(`^[0-9]+(\.[0-9]+)?[KkMm]?$`)
+
+// validColor reports whether lipgloss understands a color value
+func validColor(value string) bool {
+	if hexColorPattern.MatchString(value) {
+		return true
+	}
+	n, err := strconv.Atoi(value)
+	return err == nil && n >= 0 && n <= 255
+}
+
+// contains reports whether values includes value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
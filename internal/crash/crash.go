@@ -0,0 +1,116 @@
+// Package crash writes a report when ytmusic panics, with the stack trace, the
+// last lines of the debug logs and the config, so a bug report can include them.
+package crash
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"runtime/debug"
+	"time"
+
+	"ytmusic/internal/config"
+	"ytmusic/internal/logs"
+	"ytmusic/internal/paths"
+
+	"github.com/BurntSushi/toml"
+)
+
+// logLines is how many lines of each of today's logs go into a report
+const logLines = 50
+
+// Panic is a recovered panic with the stack of the goroutine that panicked
+type Panic struct {
+	Value interface{}
+	Stack []byte
+}
+
+// Error describes the panic
+func (p Panic) Error() string {
+	return fmt.Sprint(p.Value)
+}
+
+// Recovered wraps the value returned by recover, keeping the stack of a Panic
+// passed on from another goroutine. Call it from the deferred function.
+func Recovered(r interface{}) Panic {
+	if p, ok := r.(Panic); ok {
+		return p
+	}
+	return Panic{Value: r, Stack: debug.Stack()}
+}
+
+// Dir returns the directory crash reports are written to
+func Dir() string {
+	return filepath.Join(paths.State(), "crash")
+}
+
+// secretPattern matches what follows a credential's name, in mpv arguments and log lines
+var secretPattern = regexp.MustCompile(`(?i)(cookie|authorization|token|password|sapisid|secret)([^=:]*[=:]\s*).+`)
+
+// redact hides credentials in a line
+func redact(line string) string {
+	return secretPattern.ReplaceAllString(line, "$1$2[redacted]")
+}
+
+// Write saves a report of the panic, returning its path
+func Write(p Panic, cfg *config.Config) (string, error) {
+	var report bytes.Buffer
+	fmt.Fprintf(&report, "ytmusic crash report\n")
+	fmt.Fprintf(&report, "Time: %s\n", time.Now().Format(time.RFC3339))
+	fmt.Fprintf(&report, "Go: %s %s/%s\n", runtime.Version(), runtime.GOOS, runtime.GOARCH)
+	if info, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range info.Settings {
+			if setting.Key == "vcs.revision" {
+				fmt.Fprintf(&report, "Revision: %s\n", setting.Value)
+			}
+		}
+	}
+
+	fmt.Fprintf(&report, "\npanic: %v\n\n%s\n", p.Value, p.Stack)
+
+	fmt.Fprintf(&report, "\nConfig:\n")
+	if cfg != nil {
+		redacted := *cfg
+		redacted.MPV.Args = make([]string, len(cfg.MPV.Args))
+		for i, arg := range cfg.MPV.Args {
+			redacted.MPV.Args[i] = redact(arg)
+		}
+		if err := toml.NewEncoder(&report).Encode(redacted); err != nil {
+			fmt.Fprintf(&report, "(could not encode: %v)\n", err)
+		}
+	}
+
+	writeLogs(&report)
+
+	dir := Dir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("could not create %s: %v", dir, err)
+	}
+	path := filepath.Join(dir, fmt.Sprintf("crash_%s.txt", time.Now().Format("2006-01-02_15-04-05")))
+	if err := os.WriteFile(path, report.Bytes(), 0600); err != nil {
+		return "", fmt.Errorf("could not write crash report: %v", err)
+	}
+	return path, nil
+}
+
+// writeLogs appends the last lines of today's debug logs, which only exist with -debug
+func writeLogs(report *bytes.Buffer) {
+	found := false
+	for _, path := range logs.Today() {
+		lines, err := logs.Tail(path, logLines)
+		if err != nil || len(lines) == 0 {
+			continue
+		}
+		found = true
+		fmt.Fprintf(report, "\nLast lines of %s:\n", filepath.Base(path))
+		for _, line := range lines {
+			fmt.Fprintln(report, redact(line))
+		}
+	}
+	if !found {
+		fmt.Fprintf(report, "\nNo logs from today; run with -debug to include them in crash reports\n")
+	}
+}
@@ -0,0 +1,58 @@
+package daemon
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+const (
+	dialTimeout = 2 * time.Second
+	// replyTimeout allows for resolving a search and its stream before playing
+	replyTimeout = 60 * time.Second
+)
+
+var (
+	// ErrNotRunning means no daemon answers on the control socket
+	ErrNotRunning = errors.New("the ytmusic daemon isn't running")
+	// ErrAlreadyRunning means another daemon holds the control socket
+	ErrAlreadyRunning = errors.New("a ytmusic daemon is already running")
+	// ErrAlreadyQueued means a track wasn't added because skip_duplicates is on and it's queued
+	ErrAlreadyQueued = errors.New("already in the queue")
+)
+
+// Send sends one request to the running daemon and returns its response.
+// A command the daemon rejected comes back as an error.
+func Send(req Request) (*Status, error) {
+	conn, err := dial(Address())
+	if err != nil {
+		return nil, ErrNotRunning
+	}
+	defer conn.Close()
+	conn.SetDeadline(time.Now().Add(replyTimeout))
+
+	line, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := conn.Write(append(line, '\n')); err != nil {
+		return nil, fmt.Errorf("could not send to the daemon: %v", err)
+	}
+
+	reader := bufio.NewReader(conn)
+	reply, err := reader.ReadBytes('\n')
+	if err != nil {
+		return nil, fmt.Errorf("no answer from the daemon: %v", err)
+	}
+
+	var resp Response
+	if err := json.Unmarshal(reply, &resp); err != nil {
+		return nil, fmt.Errorf("unreadable answer from the daemon: %v", err)
+	}
+	if !resp.OK {
+		return nil, errors.New(resp.Error)
+	}
+	return resp.Status, nil
+}
@@ -0,0 +1,327 @@
+package daemon
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// QueueInfo lists the queue for GET /api/queue
+type QueueInfo struct {
+	Index  int         `json:"index"` // Index of the current track, -1 if none
+	Tracks []TrackInfo `json:"tracks"`
+}
+
+// errorReply is the body of a failed REST request
+type errorReply struct {
+	Error string `json:"error"`
+}
+
+// trackBody is the body of requests that name a track
+type trackBody struct {
+	Track string `json:"track"` // Video ID, link, or search terms
+}
+
+// httpCommands are the transport commands served as POST /api/<command>
+var httpCommands = []string{CommandPlay, CommandPause, CommandToggle, CommandNext, CommandPrevious, CommandStop}
+
+// Handler returns the REST API served on address, along with a small remote
+// control page at /. API requests must carry token, unless it's empty.
+func (s *Server) Handler(address, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", s.httpRemotePage)
+	mux.HandleFunc("/api/status", s.httpStatus)
+	mux.HandleFunc("/api/search", s.httpSearch)
+	mux.HandleFunc("/api/queue", s.httpQueue)
+	for _, command := range httpCommands {
+		mux.HandleFunc("/api/"+command, s.httpCommand(command))
+	}
+	return guard(mux, address, token)
+}
+
+// guard turns away requests the API shouldn't act on: those naming another host,
+// as DNS rebinding does, those from other sites' pages, POSTs that aren't JSON,
+// which browsers send cross-origin without asking first, and API requests
+// without the token when there is one
+func guard(next http.Handler, address, token string) http.Handler {
+	listenHost, _, _ := net.SplitHostPort(address)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !allowedHost(r.Host, listenHost) {
+			writeError(w, http.StatusForbidden, "host %q is not served here", r.Host)
+			return
+		}
+		if origin := r.Header.Get("Origin"); origin != "" {
+			if u, err := url.Parse(origin); err != nil || !strings.EqualFold(u.Host, r.Host) {
+				writeError(w, http.StatusForbidden, "requests from %s are not allowed", origin)
+				return
+			}
+		}
+		if r.Method == http.MethodPost {
+			if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType != "application/json" {
+				writeError(w, http.StatusUnsupportedMediaType, "POST needs Content-Type: application/json")
+				return
+			}
+		}
+		if token != "" && strings.HasPrefix(r.URL.Path, "/api/") {
+			given := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				writeError(w, http.StatusUnauthorized, "missing or wrong token")
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// allowedHost reports whether the Host of a request names the address listened on.
+// Listening on every interface takes any name, as the token guards it then.
+func allowedHost(host, listenHost string) bool {
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	host = strings.Trim(host, "[]")
+	switch {
+	case isUnspecified(listenHost):
+		return true
+	case isLoopback(listenHost):
+		return isLoopback(host)
+	}
+	return strings.EqualFold(host, listenHost)
+}
+
+// ServeREST serves the REST API on address until ctx is cancelled, requiring
+// token on API requests unless it's empty
+func (s *Server) ServeREST(ctx context.Context, address, token string) error {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return err
+	}
+	s.api.LogDebug("REST API listening on %s", listener.Addr())
+
+	server := &http.Server{Handler: s.Handler(address, token), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		server.Close()
+	}()
+
+	if err := server.Serve(listener); !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
+
+// writeJSON sends a JSON reply
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// writeError sends a JSON error reply
+func writeError(w http.ResponseWriter, status int, format string, v ...interface{}) {
+	writeJSON(w, status, errorReply{Error: fmt.Sprintf(format, v...)})
+}
+
+// allow answers 405 unless the request uses one of methods
+func allow(w http.ResponseWriter, r *http.Request, methods ...string) bool {
+	for _, method := range methods {
+		if r.Method == method {
+			return true
+		}
+	}
+	writeError(w, http.StatusMethodNotAllowed, "%s is not allowed here", r.Method)
+	return false
+}
+
+// readTrack reads the track named by a request body, which may be empty
+func readTrack(r *http.Request) (string, error) {
+	var body trackBody
+	if r.ContentLength == 0 {
+		return "", nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+		return "", fmt.Errorf("invalid body: %v", err)
+	}
+	return body.Track, nil
+}
+
+func (s *Server) httpStatus(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+	writeJSON(w, http.StatusOK, s.status())
+}
+
+func (s *Server) httpSearch(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet) {
+		return
+	}
+	query := r.URL.Query().Get("q")
+	if query == "" {
+		writeError(w, http.StatusBadRequest, "search needs a q parameter")
+		return
+	}
+
+	tracks, err := s.api.Search(r.Context(), query)
+	if err != nil {
+		writeError(w, http.StatusBadGateway, "%v", err)
+		return
+	}
+	results := make([]TrackInfo, 0, len(tracks))
+	for _, track := range tracks {
+		results = append(results, *newTrackInfo(track))
+	}
+	writeJSON(w, http.StatusOK, results)
+}
+
+// httpQueue lists the queue (GET), adds a track to it (POST), or removes the track at ?index= (DELETE)
+func (s *Server) httpQueue(w http.ResponseWriter, r *http.Request) {
+	if !allow(w, r, http.MethodGet, http.MethodPost, http.MethodDelete) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodPost:
+		track, err := readTrack(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "%v", err)
+			return
+		}
+		if err := s.queueAdd(r.Context(), track); errors.Is(err, ErrAlreadyQueued) {
+			writeError(w, http.StatusConflict, "%v", err)
+			return
+		} else if err != nil {
+			writeError(w, http.StatusBadGateway, "%v", err)
+			return
+		}
+
+	case http.MethodDelete:
+		index, err := strconv.Atoi(r.URL.Query().Get("index"))
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "removing needs a numeric index parameter")
+			return
+		}
+		if err := s.queueRemove(index); err != nil {
+			writeError(w, http.StatusBadRequest, "%v", err)
+			return
+		}
+	}
+
+	writeJSON(w, http.StatusOK, s.queue())
+}
+
+// httpCommand serves a transport command, with the track to play in the body for play
+func (s *Server) httpCommand(command string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !allow(w, r, http.MethodPost) {
+			return
+		}
+		track, err := readTrack(r)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "%v", err)
+			return
+		}
+
+		resp := s.handle(r.Context(), Request{Command: command, Track: track})
+		if !resp.OK {
+			writeError(w, http.StatusBadGateway, "%s", resp.Error)
+			return
+		}
+		writeJSON(w, http.StatusOK, resp.Status)
+	}
+}
+
+// queueRemove removes a track other than the current one from the queue
+func (s *Server) queueRemove(index int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if index == s.player.Queue.CurrentIndex && !s.stopped {
+		return fmt.Errorf("cannot remove the current track")
+	}
+	if !s.player.Queue.Remove(index) {
+		return fmt.Errorf("no track at index %d", index)
+	}
+	return nil
+}
+
+// queue lists the queue
+func (s *Server) queue() QueueInfo {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	info := QueueInfo{Index: s.player.Queue.CurrentIndex, Tracks: []TrackInfo{}}
+	for _, track := range s.player.Queue.Tracks {
+		info.Tracks = append(info.Tracks, *newTrackInfo(track))
+	}
+	return info
+}
+
+func (s *Server) httpRemotePage(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path != "/" {
+		writeError(w, http.StatusNotFound, "no such endpoint")
+		return
+	}
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	fmt.Fprint(w, remotePage)
+}
+
+// remotePage is a remote control for phone browsers, built on the REST API
+const remotePage = `<!DOCTYPE html>
+<html>
+<head>
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>ytmusic</title>
+<style>
+body { font-family: sans-serif; background: #111; color: #eee; text-align: center; }
+button { font-size: 1.5em; margin: 0.3em; padding: 0.4em 0.8em; }
+input { font-size: 1.1em; width: 70%; }
+#track { font-size: 1.2em; margin: 1em; }
+</style>
+</head>
+<body>
+<div id="track">Loading...</div>
+<div id="time"></div>
+<button onclick="send('prev')">&#9198;</button>
+<button onclick="send('toggle')">&#9199;</button>
+<button onclick="send('next')">&#9197;</button>
+<form onsubmit="send('play', this.q.value); this.q.value=''; return false">
+<p><input name="q" placeholder="Play a song..."> <button>Play</button></p>
+</form>
+<script>
+// The token comes in the address as #token=..., and is kept for later visits
+if (location.hash.indexOf("#token=") === 0) {
+  localStorage.setItem("token", location.hash.slice(7));
+  history.replaceState(null, "", location.pathname);
+}
+var token = localStorage.getItem("token");
+function headers() { return token ? {"Authorization": "Bearer " + token} : {}; }
+function clock(s) { return Math.floor(s / 60) + ":" + String(s % 60).padStart(2, "0"); }
+function show(status) {
+  var track = status.track;
+  document.getElementById("track").textContent = track ? track.title + " - " + track.artist : "Nothing queued";
+  document.getElementById("time").textContent = status.state === "stopped" ? "Stopped" :
+    (status.state === "paused" ? "Paused " : "") + clock(status.position) + " / " + clock(status.duration);
+}
+function send(command, track) {
+  var h = headers();
+  h["Content-Type"] = "application/json";
+  fetch("/api/" + command, {method: "POST", headers: h, body: track ? JSON.stringify({track: track}) : ""})
+    .then(function (r) { return r.json(); })
+    .then(function (body) { body.error ? alert(body.error) : show(body); });
+}
+function refresh() { fetch("/api/status", {headers: headers()}).then(function (r) { return r.json(); }).then(show); }
+refresh();
+setInterval(refresh, 2000);
+</script>
+</body>
+</html>
+`
@@ -0,0 +1,66 @@
+package daemon
+
+import "ytmusic/internal/api"
+
+// Commands a Request can carry
+const (
+	CommandStatus   = "status"    // Report what's playing
+	CommandPlay     = "play"      // Play Track now, or resume when Track is empty
+	CommandPause    = "pause"     // Pause playback
+	CommandToggle   = "toggle"    // Pause or resume playback
+	CommandNext     = "next"      // Skip to the next track in the queue
+	CommandPrevious = "prev"      // Go back to the previous track
+	CommandStop     = "stop"      // Stop playback, keeping the queue
+	CommandQueueAdd = "queue-add" // Add Track to the end of the queue
+)
+
+// Request is a command sent to the daemon. Requests and responses are JSON
+// objects, one per line, and a connection may send any number of requests.
+type Request struct {
+	Command string `json:"command"`
+	Track   string `json:"track,omitempty"` // Video ID, link, or search terms, for play and queue-add
+}
+
+// Response answers a Request
+type Response struct {
+	OK     bool    `json:"ok"`
+	Error  string  `json:"error,omitempty"`
+	Status *Status `json:"status,omitempty"` // Player state after the command, when it succeeded
+}
+
+// Status describes the player
+type Status struct {
+	State       string     `json:"state"` // "playing", "paused" or "stopped"
+	Track       *TrackInfo `json:"track,omitempty"`
+	Position    int        `json:"position"` // Seconds into the track
+	Duration    int        `json:"duration"`
+	QueueIndex  int        `json:"queue_index"` // Index of the current track in the queue, -1 if none
+	QueueLength int        `json:"queue_length"`
+}
+
+// States reported in Status
+const (
+	StatePlaying = "playing"
+	StatePaused  = "paused"
+	StateStopped = "stopped"
+)
+
+// TrackInfo is a track as reported in Status
+type TrackInfo struct {
+	ID       string `json:"id"`
+	Title    string `json:"title"`
+	Artist   string `json:"artist"`
+	Album    string `json:"album,omitempty"`
+	Duration int    `json:"duration"`
+}
+
+// newTrackInfo converts a queue track for Status
+func newTrackInfo(track api.Track) *TrackInfo {
+	return &TrackInfo{
+		ID:       track.ID,
+		Title:    track.TrackTitle,
+		Artist:   track.Artist,
+		Album:    track.Album,
+		Duration: track.Duration,
+	}
+}
@@ -0,0 +1,497 @@
+package daemon
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/player"
+)
+
+// trackingTimeout bounds reporting a play to the account's history
+const trackingTimeout = 30 * time.Second
+
+// errQueueChanged is returned when the queue moved on while a track was loading
+var errQueueChanged = errors.New("the queue changed while the track was loading")
+
+// Server runs the player without a UI and carries out commands from the control socket
+type Server struct {
+	api    *api.YouTubeMusicAPI
+	player *player.Player
+
+	mu       sync.Mutex // Held while using the player, which connections and playback share
+	stopped  bool       // Whether nothing is loaded in mpv, as opposed to paused
+	tracking *api.PlaybackSession
+	playing  *api.Play // Play of the current track, recorded to the local history when it finishes
+
+	preloading string // Video ID of the next track whose stream is being resolved
+}
+
+// NewServer creates a server for a configured API client and player.
+// Playback starts stopped, with whatever queue the player holds.
+func NewServer(ytApi *api.YouTubeMusicAPI, musicPlayer *player.Player) *Server {
+	return &Server{api: ytApi, player: musicPlayer, stopped: true}
+}
+
+// Listen opens the control socket, failing with ErrAlreadyRunning if another daemon holds it
+func Listen() (net.Listener, error) {
+	return listen(Address())
+}
+
+// Serve accepts commands on the control socket until ctx is cancelled, then closes it
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	defer listener.Close()
+	s.api.LogDebug("Daemon listening on %s", Address())
+
+	go s.followPlayback(ctx)
+	go func() {
+		<-ctx.Done()
+		listener.Close()
+	}()
+
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+			return err
+		}
+		go s.serveConn(ctx, conn)
+	}
+}
+
+// Close stops playback and saves the queue for the next session
+func (s *Server) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.recordPlay(s.player.CurrentPos)
+	if s.tracking != nil {
+		if err := s.tracking.ReportWatchtime(context.Background(), s.player.CurrentPos); err != nil {
+			s.api.LogDebug("Error reporting watchtime: %v", err)
+		}
+		s.tracking = nil
+	}
+	s.player.Stop()
+	if err := s.player.SaveState(); err != nil {
+		s.api.LogDebug("Error saving queue: %v", err)
+	}
+}
+
+// serveConn answers the requests of one connection in order
+func (s *Server) serveConn(ctx context.Context, conn net.Conn) {
+	defer conn.Close()
+
+	scanner := bufio.NewScanner(conn)
+	encoder := json.NewEncoder(conn)
+	for scanner.Scan() {
+		var req Request
+		resp := Response{}
+		if err := json.Unmarshal(scanner.Bytes(), &req); err != nil {
+			resp.Error = fmt.Sprintf("invalid request: %v", err)
+		} else {
+			resp = s.handle(ctx, req)
+		}
+		if err := encoder.Encode(resp); err != nil {
+			return
+		}
+	}
+}
+
+// handle carries out one request
+func (s *Server) handle(ctx context.Context, req Request) Response {
+	s.api.LogDebug("Daemon command: %s %s", req.Command, req.Track)
+
+	var err error
+	switch req.Command {
+	case CommandStatus:
+	case CommandPlay:
+		err = s.play(ctx, req.Track)
+	case CommandPause:
+		s.mu.Lock()
+		if !s.stopped && s.player.IsPlaying {
+			s.player.TogglePause()
+		}
+		s.mu.Unlock()
+	case CommandToggle:
+		err = s.toggle(ctx)
+	case CommandNext:
+		queue := s.player.Queue
+		err = s.startTrack(ctx, queue.PeekNext, func() { queue.NextTrack() }, "no next track in the queue")
+	case CommandPrevious:
+		queue := s.player.Queue
+		err = s.startTrack(ctx, queue.PeekPrevious, func() { queue.PreviousTrack() }, "no previous track in the queue")
+	case CommandStop:
+		s.mu.Lock()
+		s.finishTracking(s.player.CurrentPos)
+		s.player.Stop()
+		s.stopped = true
+		s.mu.Unlock()
+	case CommandQueueAdd:
+		err = s.queueAdd(ctx, req.Track)
+	default:
+		err = fmt.Errorf("unknown command %q", req.Command)
+	}
+
+	if err != nil {
+		s.api.LogDebug("Daemon command %s failed: %v", req.Command, err)
+		return Response{Error: err.Error()}
+	}
+	return Response{OK: true, Status: s.status()}
+}
+
+// play plays a track right away, ahead of the rest of the queue, or resumes playback without one
+func (s *Server) play(ctx context.Context, arg string) error {
+	if arg == "" {
+		s.mu.Lock()
+		if s.stopped {
+			s.mu.Unlock()
+			return s.startCurrent(ctx)
+		}
+		if !s.player.IsPlaying {
+			s.player.TogglePause()
+		}
+		s.mu.Unlock()
+		return nil
+	}
+
+	// Resolving can take a while, so don't hold up other connections meanwhile
+	track, err := s.api.ResolveTrack(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	queue := s.player.Queue
+	return s.startTrack(ctx, func() *api.Track { return &track }, func() {
+		queue.PlayTrack(queue.InsertNext(track))
+	}, "")
+}
+
+// toggle pauses or resumes playback, starting the current track when stopped
+func (s *Server) toggle(ctx context.Context) error {
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		return s.startCurrent(ctx)
+	}
+	s.player.TogglePause()
+	s.mu.Unlock()
+	return nil
+}
+
+// queueAdd adds a track to the end of the queue
+func (s *Server) queueAdd(ctx context.Context, arg string) error {
+	if arg == "" {
+		return fmt.Errorf("queue-add needs a track")
+	}
+	track, err := s.api.ResolveTrack(ctx, arg)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.player.Queue.Add(track) {
+		return fmt.Errorf("%s: %w", track.TrackTitle, ErrAlreadyQueued)
+	}
+	return nil
+}
+
+// startCurrent starts the queue's current track
+func (s *Server) startCurrent(ctx context.Context) error {
+	return s.startTrack(ctx, s.player.Queue.GetCurrentTrack, func() {}, "the queue is empty")
+}
+
+// startTrack plays the track pick leads to, then has move bring the queue onto it.
+// The stream is resolved without s.mu, so a slow lookup doesn't hold up other
+// connections, and a failed one leaves the queue and playback as they were.
+// The caller doesn't hold s.mu.
+func (s *Server) startTrack(ctx context.Context, pick func() *api.Track, move func(), empty string) error {
+	s.mu.Lock()
+	picked := pick()
+	s.mu.Unlock()
+	if picked == nil {
+		return errors.New(empty)
+	}
+	track := *picked
+
+	source, duration, err := s.source(ctx, track)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if now := pick(); now == nil || now.ID != track.ID {
+		return errQueueChanged
+	}
+	s.finishTracking(s.player.CurrentPos)
+	if err := s.player.Play(source, duration); err != nil {
+		s.stopped = true
+		return fmt.Errorf("could not start mpv: %v", err)
+	}
+	s.stopped = false
+	move()
+
+	s.playing = api.NewPlay(track)
+	go s.startTracking(track.ID)
+	return nil
+}
+
+// playOn carries playback on after mpv moved past the queue by itself, stopping
+// when the track pick leads to can't be played. The caller doesn't hold s.mu.
+func (s *Server) playOn(ctx context.Context, pick func() *api.Track, move func()) {
+	err := s.startTrack(ctx, pick, move, "nothing left to play")
+	if err == nil || errors.Is(err, errQueueChanged) {
+		return
+	}
+	s.api.LogDebug("Error playing next track: %v", err)
+	s.mu.Lock()
+	s.player.Stop()
+	s.stopped = true
+	s.mu.Unlock()
+}
+
+// source finds what mpv plays for a track: its download if there is one,
+// otherwise its stream, with the duration the stream reports
+func (s *Server) source(ctx context.Context, track api.Track) (string, int, error) {
+	if path := s.player.Downloads.Path(track.ID); path != "" {
+		return path, track.Duration, nil
+	}
+	info, err := s.api.GetStreamInfo(ctx, track.ID)
+	if err != nil {
+		return "", 0, err
+	}
+	if info.Duration > 0 {
+		return info.URL, info.Duration, nil
+	}
+	return info.URL, track.Duration, nil
+}
+
+// startTracking reports a play to the account's history, unless it's been skipped meanwhile.
+// It outlives the request that started the play, so it has a context of its own.
+func (s *Server) startTracking(videoID string) {
+	ctx, cancel := context.WithTimeout(context.Background(), trackingTimeout)
+	defer cancel()
+
+	session, err := s.api.StartPlayback(ctx, videoID)
+	if err != nil {
+		s.api.LogDebug("Could not report playback of %s: %v", videoID, err)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if current := s.player.Queue.GetCurrentTrack(); current != nil && current.ID == videoID && !s.stopped {
+		s.tracking = session
+	}
+}
+
+// finishTracking records how far the play got, reports it to the account and stops tracking it. The caller holds s.mu.
+func (s *Server) finishTracking(position int) {
+	s.recordPlay(position)
+
+	session := s.tracking
+	s.tracking = nil
+	if session == nil {
+		return
+	}
+	go func() {
+		if err := session.ReportWatchtime(context.Background(), position); err != nil {
+			s.api.LogDebug("Error reporting watchtime: %v", err)
+		}
+	}()
+}
+
+// recordPlay adds the finished play to the local history. The caller holds s.mu.
+func (s *Server) recordPlay(position int) {
+	play := s.playing
+	s.playing = nil
+	if err := s.api.RecordPlay(play, position); err != nil {
+		s.api.LogDebug("Error recording play: %v", err)
+	}
+}
+
+// followPlayback advances through the queue as tracks end and keeps the position current
+func (s *Server) followPlayback(ctx context.Context) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+
+		case event := <-s.player.Events():
+			s.handleEvent(ctx, event)
+
+		case <-ticker.C:
+			s.mu.Lock()
+			s.player.SyncPosition()
+			if next := s.preloadCandidate(); next != nil {
+				go s.preload(ctx, *next)
+			}
+			s.mu.Unlock()
+		}
+	}
+}
+
+// handleEvent applies a player event and acts on it under s.mu, so the
+// player's state only changes while commands are locked out
+func (s *Server) handleEvent(ctx context.Context, event player.PlayerEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.player.HandleEvent(event) {
+		return
+	}
+	switch event.Type {
+	case player.TrackEnded:
+		s.trackEnded(ctx)
+	case player.TrackAdvanced:
+		s.trackAdvanced(ctx, event.Track)
+	}
+}
+
+// trackEnded plays the next track in the queue, or stops when there is none. The caller holds s.mu.
+func (s *Server) trackEnded(ctx context.Context) {
+	s.finishTracking(s.player.Duration)
+	s.player.CurrentPos = 0
+	queue := s.player.Queue
+	if queue.PeekNext() == nil {
+		s.stopped = true
+		// The queue ran dry; keep going with tracks related to the last one
+		if current := queue.GetCurrentTrack(); queue.Autoplay && current != nil {
+			go s.autoplay(ctx, current.ID)
+		}
+		return
+	}
+	go s.playOn(ctx, queue.PeekNext, func() { queue.NextTrack() })
+}
+
+// autoplay continues an ended queue with tracks related to its last one
+func (s *Server) autoplay(ctx context.Context, videoID string) {
+	tracks, err := s.api.GetWatchPlaylist(ctx, videoID)
+	if err != nil {
+		s.api.LogDebug("Autoplay failed: %v", err)
+		return
+	}
+
+	s.mu.Lock()
+	// A command may have started something else meanwhile
+	if !s.stopped {
+		s.mu.Unlock()
+		return
+	}
+
+	// The radio starts with the seed track, so skip anything already queued
+	queue := s.player.Queue
+	for _, track := range tracks {
+		if !queue.Contains(track.ID) {
+			queue.Add(track)
+		}
+	}
+	s.mu.Unlock()
+
+	s.playOn(ctx, queue.PeekNext, func() { queue.NextTrack() })
+}
+
+// preloadCandidate returns the next track when it's time to preload it for
+// gapless playback, dropping a preload the queue no longer leads to. The caller holds s.mu.
+func (s *Server) preloadCandidate() *api.Track {
+	if !s.player.NearEnd() {
+		return nil
+	}
+
+	next := s.player.Queue.PeekNext()
+	if preloaded := s.player.Preloaded(); preloaded != nil {
+		if next != nil && preloaded.ID == next.ID {
+			return nil
+		}
+		s.player.DropPreload()
+	}
+	if next == nil || s.preloading == next.ID {
+		return nil
+	}
+	s.preloading = next.ID
+	return next
+}
+
+// preload resolves the stream of the track that plays next and appends it to mpv's playlist
+func (s *Server) preload(ctx context.Context, track api.Track) {
+	source, duration, err := s.source(ctx, track)
+	if err != nil {
+		s.api.LogDebug("Could not preload %s: %v", track.ID, err)
+		s.mu.Lock()
+		s.preloading = ""
+		s.mu.Unlock()
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.preloading = ""
+	if next := s.player.Queue.PeekNext(); next == nil || next.ID != track.ID || !s.player.NearEnd() {
+		return
+	}
+	if err := s.player.Preload(track, source, duration); err != nil {
+		s.api.LogDebug("Could not preload %s: %v", track.ID, err)
+	}
+}
+
+// trackAdvanced follows mpv onto the preloaded track, or plays the track the
+// queue leads to if it changed after the preload. The caller holds s.mu.
+func (s *Server) trackAdvanced(ctx context.Context, track api.Track) {
+	// The player already holds the new track's duration
+	finished := 0
+	if current := s.player.Queue.GetCurrentTrack(); current != nil {
+		finished = current.Duration
+	}
+	s.finishTracking(finished)
+	next, ok := s.player.Queue.NextTrack()
+	if !ok || next == nil {
+		s.player.Stop()
+		s.stopped = true
+		return
+	}
+	if next.ID != track.ID {
+		go s.playOn(ctx, s.player.Queue.GetCurrentTrack, func() {})
+		return
+	}
+
+	s.playing = api.NewPlay(*next)
+	go s.startTracking(next.ID)
+}
+
+// status describes the player
+func (s *Server) status() *Status {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := &Status{
+		State:       StateStopped,
+		Position:    s.player.CurrentPos,
+		Duration:    s.player.Duration,
+		QueueIndex:  s.player.Queue.CurrentIndex,
+		QueueLength: len(s.player.Queue.Tracks),
+	}
+	if track := s.player.Queue.GetCurrentTrack(); track != nil {
+		status.Track = newTrackInfo(*track)
+	}
+	switch {
+	case s.stopped:
+		status.Position, status.Duration = 0, 0
+	case s.player.IsPlaying:
+		status.State = StatePlaying
+	default:
+		status.State = StatePaused
+	}
+	return status
+}
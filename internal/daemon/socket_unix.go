@@ -0,0 +1,41 @@
+//go:build !windows
+
+package daemon
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+
+	"ytmusic/internal/paths"
+)
+
+// Address returns the path of the daemon's control socket
+func Address() string {
+	return filepath.Join(paths.State(), "daemon.sock")
+}
+
+// listen creates the control socket, replacing one left behind by a daemon
+// that didn't shut down cleanly. Only the user can connect to it.
+func listen(address string) (net.Listener, error) {
+	if conn, err := dial(address); err == nil {
+		conn.Close()
+		return nil, ErrAlreadyRunning
+	}
+	os.Remove(address)
+
+	listener, err := net.Listen("unix", address)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(address, 0600); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// dial connects to the control socket
+func dial(address string) (net.Conn, error) {
+	return net.DialTimeout("unix", address, dialTimeout)
+}
@@ -0,0 +1,25 @@
+//go:build windows
+
+package daemon
+
+import "net"
+
+// Address returns the loopback address the daemon listens on, as Windows
+// has no unix sockets that every Go version supports
+func Address() string {
+	return "127.0.0.1:47311"
+}
+
+// listen accepts connections from this machine only
+func listen(address string) (net.Listener, error) {
+	if conn, err := dial(address); err == nil {
+		conn.Close()
+		return nil, ErrAlreadyRunning
+	}
+	return net.Listen("tcp", address)
+}
+
+// dial connects to the daemon
+func dial(address string) (net.Conn, error) {
+	return net.DialTimeout("tcp", address, dialTimeout)
+}
@@ -0,0 +1,66 @@
+package daemon
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"ytmusic/internal/paths"
+)
+
+// tokenPath is where the REST API's bearer token is kept between runs, so
+// bookmarked remote control pages keep working
+func tokenPath() string {
+	return filepath.Join(paths.Config(), "http_token")
+}
+
+// NeedsToken reports whether the REST API on address is reachable from other
+// machines, so that requests must carry the bearer token
+func NeedsToken(address string) bool {
+	host, _, err := net.SplitHostPort(address)
+	return err != nil || !isLoopback(host)
+}
+
+// Token returns the REST API's bearer token, generating it on first use
+func Token() (string, error) {
+	if data, err := os.ReadFile(tokenPath()); err == nil {
+		if token := strings.TrimSpace(string(data)); token != "" {
+			return token, nil
+		}
+	}
+
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("could not generate a token: %v", err)
+	}
+	token := hex.EncodeToString(buf)
+	if err := os.MkdirAll(paths.Config(), 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(tokenPath(), []byte(token+"\n"), 0600); err != nil {
+		return "", fmt.Errorf("could not save the token: %v", err)
+	}
+	return token, nil
+}
+
+// isLoopback reports whether host names this machine only
+func isLoopback(host string) bool {
+	if strings.EqualFold(host, "localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// isUnspecified reports whether host stands for every interface
+func isUnspecified(host string) bool {
+	if host == "" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsUnspecified()
+}
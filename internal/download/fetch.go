@@ -0,0 +1,109 @@
+package download
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"ytmusic/internal/api"
+)
+
+// Formats are the audio formats tracks can be saved in
+var Formats = []string{"opus", "m4a", "mp3"}
+
+// ValidFormat reports whether format is one of Formats
+func ValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckTools reports what's missing to download: yt-dlp fetches the audio
+// and ffmpeg converts and tags it
+func CheckTools() error {
+	for _, tool := range []string{"yt-dlp", "ffmpeg"} {
+		if _, err := exec.LookPath(tool); err != nil {
+			return fmt.Errorf("%s not found, which downloads need", tool)
+		}
+	}
+	return nil
+}
+
+// Fetch downloads a track's audio into dir with yt-dlp, converted to format
+// and tagged with its title, artist and album, and returns the file written.
+// rate caps the download rate, such as "500K"; empty for none.
+func Fetch(ctx context.Context, track api.Track, format, dir, rate string) (string, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("could not create %s: %v", dir, err)
+	}
+
+	base := filepath.Join(dir, fileName(track))
+	// yt-dlp reads the name as a template, in which % starts a field
+	template := strings.ReplaceAll(base, "%", "%%") + ".%(ext)s"
+	args := []string{
+		"--extract-audio", "--audio-format", format, "--format", "bestaudio/best",
+		"--embed-metadata", "--no-playlist", "--no-progress", "--no-warnings",
+		"--output", template,
+	}
+	if rate != "" {
+		args = append(args, "--limit-rate", rate)
+	}
+	output, err := exec.CommandContext(ctx, "yt-dlp", append(args, track.URL())...).CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", ctx.Err()
+		}
+		return "", fmt.Errorf("yt-dlp failed: %v: %s", err, lastLine(output))
+	}
+
+	path := base + "." + format
+	if _, err := os.Stat(path); err != nil {
+		return "", fmt.Errorf("yt-dlp didn't write %s", path)
+	}
+	return path, nil
+}
+
+// maxNameBytes is how long the artist and title part of a file name may get
+const maxNameBytes = 200
+
+// fileName names a track's file "Artist - Title [video ID]", without the
+// characters file systems reject
+func fileName(track api.Track) string {
+	name := track.TrackTitle
+	if track.Artist != "" {
+		name = track.Artist + " - " + name
+	}
+	name = strings.Map(func(r rune) rune {
+		switch r {
+		case '/', '\\', ':', '*', '?', '"', '<', '>', '|':
+			return '_'
+		}
+		if r < ' ' {
+			return -1
+		}
+		return r
+	}, name)
+	// Most file systems cap names at 255 bytes, which also has to fit the video ID,
+	// the extension and yt-dlp's temporary suffixes
+	if len(name) > maxNameBytes {
+		cut := maxNameBytes
+		for cut > 0 && !utf8.RuneStart(name[cut]) {
+			cut--
+		}
+		name = name[:cut]
+	}
+	return strings.TrimSpace(name) + " [" + track.ID + "]"
+}
+
+// lastLine returns the last non-empty line of a command's output, which is usually its error
+func lastLine(output []byte) string {
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,161 @@
+// Package download keeps the index of tracks saved to disk for offline playback
+package download
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/paths"
+)
+
+// Entry is a downloaded track
+type Entry struct {
+	Track  api.Track `json:"track"`
+	Path   string    `json:"path"`   // Audio file, absolute or relative to Dir
+	Format string    `json:"format"` // Container of the file, such as "opus" or "m4a"
+	Added  time.Time `json:"added"`
+}
+
+// Index is the set of downloaded tracks, by video ID
+type Index struct {
+	entries map[string]Entry
+}
+
+// Dir returns the directory downloads are saved to
+func Dir() string {
+	return filepath.Join(paths.Data(), "downloads")
+}
+
+// indexPath returns the location of the download index
+func indexPath() string {
+	return filepath.Join(Dir(), "index.json")
+}
+
+// LoadIndex reads the download index. A missing index is an empty one.
+func LoadIndex() (*Index, error) {
+	ix := &Index{entries: map[string]Entry{}}
+
+	data, err := os.ReadFile(indexPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ix, nil
+		}
+		return ix, fmt.Errorf("could not read download index: %v", err)
+	}
+
+	var entries []Entry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return ix, fmt.Errorf("could not parse download index: %v", err)
+	}
+
+	for _, entry := range entries {
+		if entry.Track.ID == "" || entry.Path == "" {
+			continue
+		}
+		if !filepath.IsAbs(entry.Path) {
+			entry.Path = filepath.Join(Dir(), entry.Path)
+		}
+		// Files deleted by hand are no longer downloaded
+		if _, err := os.Stat(entry.Path); err != nil {
+			continue
+		}
+		ix.entries[entry.Track.ID] = entry
+	}
+	return ix, nil
+}
+
+// Add records a downloaded track, replacing an earlier download of it
+func (ix *Index) Add(entry Entry) {
+	ix.entries[entry.Track.ID] = entry
+}
+
+// Save writes the download index, with the paths of files in Dir relative to it
+func (ix *Index) Save() error {
+	entries := make([]Entry, 0, len(ix.entries))
+	for _, entry := range ix.entries {
+		if rel, err := filepath.Rel(Dir(), entry.Path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			entry.Path = rel
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Added.Before(entries[j].Added)
+	})
+
+	data, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return fmt.Errorf("could not encode download index: %v", err)
+	}
+	if err := os.MkdirAll(Dir(), 0755); err != nil {
+		return fmt.Errorf("could not create %s: %v", Dir(), err)
+	}
+	// Written whole and then moved into place, so an interrupted save keeps the old index
+	tmp := indexPath() + ".tmp"
+	if err := os.WriteFile(tmp, data, 0644); err != nil {
+		return fmt.Errorf("could not write download index: %v", err)
+	}
+	if err := os.Rename(tmp, indexPath()); err != nil {
+		return fmt.Errorf("could not write download index: %v", err)
+	}
+	return nil
+}
+
+// Len returns the number of downloaded tracks
+func (ix *Index) Len() int {
+	if ix == nil {
+		return 0
+	}
+	return len(ix.entries)
+}
+
+// Path returns the file a track was downloaded to, or "" if it wasn't
+func (ix *Index) Path(videoID string) string {
+	if ix == nil {
+		return ""
+	}
+	return ix.entries[videoID].Path
+}
+
+// Tracks returns every downloaded track, most recently downloaded first
+func (ix *Index) Tracks() []api.Track {
+	return ix.Search("")
+}
+
+// Search returns the downloaded tracks whose title, artist, or album contain
+// every word of the query, most recently downloaded first
+func (ix *Index) Search(query string) []api.Track {
+	if ix == nil {
+		return nil
+	}
+
+	words := strings.Fields(strings.ToLower(query))
+	var matches []Entry
+	for _, entry := range ix.entries {
+		text := strings.ToLower(entry.Track.TrackTitle + " " + entry.Track.Artist + " " + entry.Track.Album)
+		matched := true
+		for _, word := range words {
+			if !strings.Contains(text, word) {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			matches = append(matches, entry)
+		}
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		return matches[i].Added.After(matches[j].Added)
+	})
+	tracks := make([]api.Track, len(matches))
+	for i, entry := range matches {
+		tracks[i] = entry.Track
+	}
+	return tracks
+}
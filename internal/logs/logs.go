@@ -0,0 +1,154 @@
+// Package logs reads the debug logs that -debug writes to the logs directory
+// of the state directory, for the in-app log viewer and crash reports.
+package logs
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"ytmusic/internal/paths"
+)
+
+// Level is how serious a log line is. The logs carry no levels of their own,
+// so it is guessed from the wording of the line.
+type Level int
+
+const (
+	Info Level = iota
+	Warning
+	Error
+)
+
+// String returns the level's name, as shown by the log viewer's filter
+func (l Level) String() string {
+	switch l {
+	case Warning:
+		return "Warnings"
+	case Error:
+		return "Errors"
+	}
+	return "All"
+}
+
+// Next returns the following level, or the previous one when back is set
+func (l Level) Next(back bool) Level {
+	if back {
+		return (l + 2) % 3
+	}
+	return (l + 1) % 3
+}
+
+// errorWords and warningWords mark the lines of each level
+var (
+	errorWords   = []string{"error", "failed", "could not", "couldn't", "panic"}
+	warningWords = []string{"warning", "unavailable", "timed out", "retry", "retrying", "falling back", "dropping", "ignoring"}
+)
+
+// LevelOf guesses the level of a log line
+func LevelOf(line string) Level {
+	lower := strings.ToLower(line)
+	for _, word := range errorWords {
+		if strings.Contains(lower, word) {
+			return Error
+		}
+	}
+	for _, word := range warningWords {
+		if strings.Contains(lower, word) {
+			return Warning
+		}
+	}
+	return Info
+}
+
+// Dir returns the directory the debug logs are written to
+func Dir() string {
+	return filepath.Join(paths.State(), "logs")
+}
+
+// Today returns the paths of today's logs from the app and from the player
+func Today() []string {
+	date := time.Now().Format("2006-01-02")
+	return []string{
+		filepath.Join(Dir(), "ytmusic_"+date+".log"),
+		filepath.Join(Dir(), "player_"+date+".log"),
+	}
+}
+
+// Tail returns the last n lines of a file
+func Tail(path string, n int) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimRight(scanner.Text(), "\r"))
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// TailToday returns the last n lines of each of today's logs, interleaved by
+// time. Logs that don't exist yet are skipped.
+func TailToday(n int) ([]string, error) {
+	type entry struct {
+		time string
+		line string
+	}
+	var entries []entry
+	for _, path := range Today() {
+		lines, err := Tail(path, n)
+		if err != nil && !os.IsNotExist(err) {
+			return nil, err
+		}
+		// A line without a time, such as part of a multi-line message, goes with the one before
+		last := ""
+		for _, line := range lines {
+			if t := timestamp(line); t != "" {
+				last = t
+			}
+			entries = append(entries, entry{time: last, line: line})
+		}
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].time < entries[j].time
+	})
+	all := make([]string, len(entries))
+	for i, e := range entries {
+		all[i] = e.line
+	}
+	return all, nil
+}
+
+// timestampLayout is how the log package writes the date and time
+const timestampLayout = "2006/01/02 15:04:05"
+
+// timestamp returns the date and time a log line starts with, after a prefix
+// such as "Player: " if it has one, or "" if it has none
+func timestamp(line string) string {
+	starts := []int{0}
+	if i := strings.Index(line, ": "); i >= 0 {
+		starts = append(starts, i+2)
+	}
+	for _, start := range starts {
+		end := start + len(timestampLayout)
+		if end > len(line) {
+			continue
+		}
+		if _, err := time.Parse(timestampLayout, line[start:end]); err == nil {
+			return line[start:end]
+		}
+	}
+	return ""
+}
@@ -0,0 +1,24 @@
+package media
+
+import "errors"
+
+// StartHotkeys launches the helper that registers global hotkeys, reporting
+// each command when its key combination is pressed, in any window. keys maps
+// commands to combinations such as "ctrl+alt+space"; empty ones are left out.
+func StartHotkeys(keys map[Command]string, logger func(format string, v ...interface{})) (*Controls, error) {
+	var bindings []string
+	for _, command := range []Command{Play, Pause, Toggle, Next, Previous, Stop} {
+		if combo := keys[command]; combo != "" {
+			bindings = append(bindings, string(command)+"="+combo)
+		}
+	}
+	if len(bindings) == 0 {
+		return nil, errors.New("no global hotkeys set")
+	}
+
+	cmd, err := hotkeysCommand(bindings)
+	if err != nil {
+		return nil, err
+	}
+	return start(cmd, "Global hotkeys helper", logger)
+}
@@ -0,0 +1,151 @@
+// Package media connects playback to the operating system's media controls, so
+// hardware media keys and the system's now playing flyout control the player.
+//
+// The controls are owned by a small helper script per platform that reads the
+// now playing state as JSON lines on stdin and writes the buttons pressed as
+// lines on stdout. Global hotkeys work the same way, through a helper that
+// registers key combinations with the system and reports them as commands.
+package media
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"ytmusic/internal/paths"
+)
+
+// Command is a button pressed in the system's media controls
+type Command string
+
+// Commands the helpers report
+const (
+	Play     Command = "play"
+	Pause    Command = "pause"
+	Toggle   Command = "toggle"
+	Next     Command = "next"
+	Previous Command = "previous"
+	Stop     Command = "stop"
+)
+
+// NowPlaying is what the system's media controls show. An empty title clears them.
+type NowPlaying struct {
+	Title    string `json:"title"`
+	Artist   string `json:"artist"`
+	Album    string `json:"album"`
+	ArtURL   string `json:"art_url"`
+	ArtPath  string `json:"art_path"` // Cached copy of the artwork, empty until downloaded
+	Duration int    `json:"duration"` // in seconds
+	Position int    `json:"position"` // in seconds
+	Playing  bool   `json:"playing"`
+}
+
+// Controls is a running media controls helper
+type Controls struct {
+	cmd      *exec.Cmd
+	stdin    io.WriteCloser
+	encoder  *json.Encoder
+	commands chan Command
+	last     NowPlaying
+	logger   func(format string, v ...interface{})
+}
+
+// Start launches the helper for this platform
+func Start(logger func(format string, v ...interface{})) (*Controls, error) {
+	cmd, err := helperCommand()
+	if err != nil {
+		return nil, err
+	}
+	return start(cmd, "Media controls helper", logger)
+}
+
+// start runs a helper, reading what it reports until it exits
+func start(cmd *exec.Cmd, name string, logger func(format string, v ...interface{})) (*Controls, error) {
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s input: %v", strings.ToLower(name), err)
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("error opening %s output: %v", strings.ToLower(name), err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("error starting %s: %v", strings.ToLower(name), err)
+	}
+	logger("Started %s: %s", strings.ToLower(name), strings.Join(cmd.Args, " "))
+
+	c := &Controls{
+		cmd:      cmd,
+		stdin:    stdin,
+		encoder:  json.NewEncoder(stdin),
+		commands: make(chan Command, 8),
+		logger:   logger,
+	}
+	go c.readCommands(stdout, name)
+	return c, nil
+}
+
+// readCommands forwards the helper's button presses until it exits, logging
+// anything else it writes
+func (c *Controls) readCommands(r io.Reader, name string) {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		switch command := Command(strings.TrimSpace(scanner.Text())); command {
+		case Play, Pause, Toggle, Next, Previous, Stop:
+			c.commands <- command
+		default:
+			c.logger("%s: %s", name, scanner.Text())
+		}
+	}
+	c.logger("%s exited", name)
+	close(c.commands)
+}
+
+// Commands returns the channel of button presses, closed when the helper exits
+func (c *Controls) Commands() <-chan Command {
+	return c.commands
+}
+
+// Update shows the now playing state, doing nothing if it hasn't changed
+func (c *Controls) Update(np NowPlaying) {
+	if c == nil || np == c.last {
+		return
+	}
+	c.last = np
+	if err := c.encoder.Encode(np); err != nil {
+		c.logger("Error updating media controls: %v", err)
+	}
+}
+
+// Close stops the helper, removing the player from the system's media controls
+func (c *Controls) Close() {
+	if c == nil {
+		return
+	}
+	c.stdin.Close()
+	if c.cmd.Process != nil {
+		c.cmd.Process.Kill()
+	}
+	c.cmd.Wait()
+}
+
+// findScript looks for a helper script the way the Python bridge is found
+func findScript(name string) (string, error) {
+	possiblePaths := []string{
+		filepath.Join("scripts", name),
+		filepath.Join("..", "scripts", name),
+		filepath.Join("..", "..", "scripts", name),
+		filepath.Join(paths.Config(), name),
+	}
+	for _, path := range possiblePaths {
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		}
+	}
+	return "", fmt.Errorf("%s script not found", name)
+}
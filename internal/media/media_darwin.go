@@ -0,0 +1,32 @@
+//go:build darwin
+
+package media
+
+import (
+	"fmt"
+	"os/exec"
+)
+
+// helperCommand runs the Swift helper that drives Now Playing and the remote commands
+func helperCommand() (*exec.Cmd, error) {
+	script, err := findScript("media_controls.swift")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := exec.LookPath("swift"); err != nil {
+		return nil, fmt.Errorf("swift not found, install the Xcode command line tools: %v", err)
+	}
+	return exec.Command("swift", script), nil
+}
+
+// hotkeysCommand runs the Swift helper that registers the global hotkeys with Carbon
+func hotkeysCommand(bindings []string) (*exec.Cmd, error) {
+	script, err := findScript("global_hotkeys.swift")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := exec.LookPath("swift"); err != nil {
+		return nil, fmt.Errorf("swift not found, install the Xcode command line tools: %v", err)
+	}
+	return exec.Command("swift", append([]string{script}, bindings...)...), nil
+}
@@ -0,0 +1,18 @@
+//go:build !windows && !darwin
+
+package media
+
+import (
+	"errors"
+	"os/exec"
+)
+
+// helperCommand reports that this platform has no supported media controls
+func helperCommand() (*exec.Cmd, error) {
+	return nil, errors.New("system media controls are only supported on Windows and macOS")
+}
+
+// hotkeysCommand reports that this platform has no supported global hotkeys
+func hotkeysCommand(bindings []string) (*exec.Cmd, error) {
+	return nil, errors.New("global hotkeys are only supported on Windows and macOS")
+}
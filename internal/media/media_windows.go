@@ -0,0 +1,24 @@
+//go:build windows
+
+package media
+
+import "os/exec"
+
+// helperCommand runs the PowerShell helper that drives the System Media Transport Controls
+func helperCommand() (*exec.Cmd, error) {
+	script, err := findScript("media_controls.ps1")
+	if err != nil {
+		return nil, err
+	}
+	return exec.Command("powershell", "-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-File", script), nil
+}
+
+// hotkeysCommand runs the PowerShell helper that registers the global hotkeys
+func hotkeysCommand(bindings []string) (*exec.Cmd, error) {
+	script, err := findScript("global_hotkeys.ps1")
+	if err != nil {
+		return nil, err
+	}
+	args := []string{"-NoProfile", "-NonInteractive", "-ExecutionPolicy", "Bypass", "-File", script}
+	return exec.Command("powershell", append(args, bindings...)...), nil
+}
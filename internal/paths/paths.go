@@ -0,0 +1,213 @@
+// Package paths locates ytmusic's files. They follow the XDG base directory
+// spec: settings and credentials in $XDG_CONFIG_HOME/ytmusic, the listening
+// history and downloads in $XDG_DATA_HOME/ytmusic, the queue and logs in
+// $XDG_STATE_HOME/ytmusic and the response cache in $XDG_CACHE_HOME/ytmusic,
+// each defaulting to its usual place under the home directory. The -config-dir
+// flag keeps everything in one directory instead, the way ~/.ytmusic used to.
+package paths
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// appName is the directory ytmusic uses inside each base directory
+const appName = "ytmusic"
+
+var (
+	mu        sync.Mutex
+	configDir string // Set by -config-dir, empty to follow XDG
+)
+
+// SetConfigDir keeps all of ytmusic's files in dir. Call it before anything reads them.
+func SetConfigDir(dir string) {
+	mu.Lock()
+	defer mu.Unlock()
+	configDir = dir
+}
+
+// override returns the directory set with SetConfigDir, empty if there is none
+func override() string {
+	mu.Lock()
+	defer mu.Unlock()
+	return configDir
+}
+
+// base returns $env/ytmusic, falling back to fallback under the home directory
+// when the variable is unset or not absolute, as the spec asks
+func base(env string, fallback ...string) string {
+	if dir := override(); dir != "" {
+		return dir
+	}
+	if dir := os.Getenv(env); filepath.IsAbs(dir) {
+		return filepath.Join(dir, appName)
+	}
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(append(append([]string{homeDir}, fallback...), appName)...)
+}
+
+// Config returns the directory of the config file and the login credentials
+func Config() string {
+	return base("XDG_CONFIG_HOME", ".config")
+}
+
+// Data returns the directory of the listening history, downloads, exports and the bridge's virtualenv
+func Data() string {
+	return base("XDG_DATA_HOME", ".local", "share")
+}
+
+// State returns the directory of the saved queue, podcast positions, logs,
+// crash reports and the daemon's socket
+func State() string {
+	return base("XDG_STATE_HOME", ".local", "state")
+}
+
+// Cache returns the directory of cached API responses
+func Cache() string {
+	return base("XDG_CACHE_HOME", ".cache")
+}
+
+// Legacy returns ~/.ytmusic, where everything was kept before the XDG directories
+func Legacy() string {
+	homeDir, _ := os.UserHomeDir()
+	return filepath.Join(homeDir, ".ytmusic")
+}
+
+// Tilde shortens a path in the home directory to start with ~, for display
+func Tilde(path string) string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil || homeDir == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(homeDir, path); err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return filepath.Join("~", rel)
+	}
+	return path
+}
+
+// legacyEntries maps what ~/.ytmusic held to the directory it moves to
+var legacyEntries = map[string]func() string{
+	"config.toml":          Config,
+	"cookies.json":         Config,
+	"headers_auth.json":    Config,
+	"oauth_auth.json":      Config,
+	"oauth.json":           Config,
+	"client_secret.json":   Config,
+	"account.json":         Config,
+	"ytmusic_bridge.py":    Config,
+	"media_controls.ps1":   Config,
+	"media_controls.swift": Config,
+	"history.jsonl":        Data,
+	"downloads":            Data,
+	"exports":              Data,
+	"venv":                 Data,
+	"queue.json":           State,
+	"episodes.json":        State,
+	"logs":                 State,
+	"crash":                State,
+	"cache":                Cache,
+}
+
+// Migrate moves the files of an existing ~/.ytmusic into the XDG directories,
+// returning how many it moved. Files that are already in their new place, or
+// that ytmusic doesn't know, stay behind, and so does ~/.ytmusic while it isn't empty.
+// It does nothing with -config-dir.
+func Migrate() (int, error) {
+	if override() != "" {
+		return 0, nil
+	}
+	legacy := Legacy()
+	entries, err := os.ReadDir(legacy)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("could not read %s: %v", legacy, err)
+	}
+
+	moved := 0
+	var failed error
+	for _, entry := range entries {
+		dirFor, ok := legacyEntries[entry.Name()]
+		if !ok {
+			continue
+		}
+		from := filepath.Join(legacy, entry.Name())
+		to := filepath.Join(dirFor(), entry.Name())
+		if _, err := os.Stat(to); err == nil {
+			continue
+		}
+		if err := move(from, to); err != nil {
+			failed = fmt.Errorf("could not move %s to %s: %v", from, to, err)
+			continue
+		}
+		moved++
+	}
+
+	// Only removes the directory once nothing is left in it
+	os.Remove(legacy)
+	return moved, failed
+}
+
+// move renames a file or directory, copying it when it goes to another file system
+func move(from, to string) error {
+	if err := os.MkdirAll(filepath.Dir(to), 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(from, to); err == nil {
+		return nil
+	}
+
+	if err := copyTree(from, to); err != nil {
+		os.RemoveAll(to)
+		return err
+	}
+	return os.RemoveAll(from)
+}
+
+// copyTree copies a file, or a directory and everything in it
+func copyTree(from, to string) error {
+	return filepath.Walk(from, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(from, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(to, rel)
+
+		switch {
+		case info.IsDir():
+			return os.MkdirAll(target, info.Mode().Perm())
+		case info.Mode()&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(link, target)
+		case !info.Mode().IsRegular():
+			// Sockets and the like belong to a running process and can't be copied
+			return nil
+		}
+
+		src, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer src.Close()
+		dst, err := os.OpenFile(target, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, info.Mode().Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(dst, src); err != nil {
+			dst.Close()
+			return err
+		}
+		return dst.Close()
+	})
+}
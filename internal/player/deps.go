@@ -0,0 +1,62 @@
+package player
+
+import (
+	"os/exec"
+	"runtime"
+)
+
+// Dependency is an external program ytmusic plays through
+type Dependency struct {
+	Name     string // Program to look for
+	Path     string // Where it was found, empty if it wasn't
+	Purpose  string // What it is needed for
+	Required bool   // Whether nothing plays without it
+	Install  string // Where to get it
+	Command  string // Command that installs it on this system, empty if there's no usual one
+}
+
+// Found reports whether the program is installed
+func (d Dependency) Found() bool {
+	return d.Path != ""
+}
+
+// Dependencies looks for mpv, at mpvPath, and for the yt-dlp it streams through
+func Dependencies(mpvPath string) []Dependency {
+	deps := []Dependency{
+		{Name: mpvPath, Purpose: "plays the audio", Required: true, Install: "https://mpv.io/installation", Command: installCommand("mpv")},
+		{Name: "yt-dlp", Purpose: "resolves streams mpv can't, and music videos", Install: "https://github.com/yt-dlp/yt-dlp#installation", Command: installCommand("yt-dlp")},
+	}
+	for i := range deps {
+		deps[i].Path, _ = exec.LookPath(deps[i].Name)
+	}
+	return deps
+}
+
+// packageManagers are the Linux package managers looked for, with how each installs a package
+var packageManagers = []struct {
+	name    string
+	install string
+}{
+	{"apt", "sudo apt install "},
+	{"dnf", "sudo dnf install "},
+	{"pacman", "sudo pacman -S "},
+	{"zypper", "sudo zypper install "},
+	{"apk", "sudo apk add "},
+	{"brew", "brew install "},
+}
+
+// installCommand returns the usual command that installs a package here, or "" if there isn't one
+func installCommand(pkg string) string {
+	switch runtime.GOOS {
+	case "darwin":
+		return "brew install " + pkg
+	case "windows":
+		return "scoop install " + pkg
+	}
+	for _, pm := range packageManagers {
+		if _, err := exec.LookPath(pm.name); err == nil {
+			return pm.install + pkg
+		}
+	}
+	return ""
+}
@@ -0,0 +1,55 @@
+//go:build !windows
+
+package player
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// ipcConcurrentIO is true because unix sockets allow reading and writing from separate goroutines
+const ipcConcurrentIO = true
+
+// ipcSocketPath returns a per-process path for mpv's IPC socket
+func ipcSocketPath() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("ytmusic-mpv-%d.sock", os.Getpid()))
+}
+
+// dialIPC connects to mpv's unix domain socket
+func dialIPC(path string) (io.ReadWriteCloser, error) {
+	return net.Dial("unix", path)
+}
+
+// cleanupIPC removes a stale socket file left by a previous mpv
+func cleanupIPC(path string) {
+	os.Remove(path)
+}
+
+// quitOrphans asks mpv instances left behind by ytmusic processes that no
+// longer run, such as after a crash, to quit. Their sockets carry the pid of
+// the ytmusic that started them, so a running instance's mpv is left alone.
+func quitOrphans(logger func(format string, v ...interface{})) {
+	paths, _ := filepath.Glob(filepath.Join(os.TempDir(), "ytmusic-mpv-*.sock"))
+	for _, path := range paths {
+		var pid int
+		if _, err := fmt.Sscanf(filepath.Base(path), "ytmusic-mpv-%d.sock", &pid); err != nil || pid == os.Getpid() {
+			continue
+		}
+		if err := syscall.Kill(pid, 0); err == nil || err == syscall.EPERM {
+			continue
+		}
+
+		if conn, err := net.DialTimeout("unix", path, time.Second); err == nil {
+			logger("Stopping mpv left behind by ytmusic process %d", pid)
+			conn.SetDeadline(time.Now().Add(time.Second))
+			io.WriteString(conn, `{"command":["quit"]}`+"\n")
+			conn.Close()
+		}
+		os.Remove(path)
+	}
+}
@@ -0,0 +1,28 @@
+//go:build windows
+
+package player
+
+import (
+	"fmt"
+	"io"
+	"os"
+)
+
+// ipcConcurrentIO is false because a synchronous pipe handle blocks writes while a read is pending
+const ipcConcurrentIO = false
+
+// ipcSocketPath returns a per-process name for mpv's IPC named pipe
+func ipcSocketPath() string {
+	return fmt.Sprintf(`\\.\pipe\ytmusic-mpv-%d`, os.Getpid())
+}
+
+// dialIPC opens the client end of mpv's named pipe
+func dialIPC(path string) (io.ReadWriteCloser, error) {
+	return os.OpenFile(path, os.O_RDWR, 0)
+}
+
+// cleanupIPC is a no-op; named pipes disappear with the mpv process
+func cleanupIPC(path string) {}
+
+// quitOrphans is a no-op; named pipes can't be listed like socket files
+func quitOrphans(logger func(format string, v ...interface{})) {}
@@ -0,0 +1,249 @@
+package player
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// mpvIPC talks to a running mpv instance over its JSON IPC socket
+type mpvIPC struct {
+	conn      io.ReadWriteCloser
+	mu        sync.Mutex
+	requestID int
+	pending   map[int]chan mpvResponse
+	reader    *bufio.Reader // Set when commands read their own replies (no concurrent I/O)
+	onEvent   func(mpvEvent)
+	logger    func(format string, v ...interface{})
+}
+
+// mpvResponse is a reply to a command sent with a request_id
+type mpvResponse struct {
+	Error     string          `json:"error"`
+	Data      json.RawMessage `json:"data"`
+	RequestID int             `json:"request_id"`
+}
+
+// mpvEvent is an asynchronous event such as end-file or pause
+type mpvEvent struct {
+	Event  string `json:"event"`
+	Reason string `json:"reason"`
+	Name   string `json:"name"`
+}
+
+// mpvCommandTimeout bounds how long we wait for mpv to answer a command
+const mpvCommandTimeout = 2 * time.Second
+
+// connectMPV dials the IPC socket, retrying while mpv starts up
+func connectMPV(path string, logger func(format string, v ...interface{}), onEvent func(mpvEvent)) (*mpvIPC, error) {
+	var conn io.ReadWriteCloser
+	var err error
+
+	// mpv creates the socket shortly after the process starts
+	for attempt := 0; attempt < 50; attempt++ {
+		conn, err = dialIPC(path)
+		if err == nil {
+			break
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not connect to mpv IPC at %s: %v", path, err)
+	}
+
+	ipc := &mpvIPC{
+		conn:    conn,
+		pending: make(map[int]chan mpvResponse),
+		onEvent: onEvent,
+		logger:  logger,
+	}
+	if ipcConcurrentIO {
+		go ipc.readLoop()
+	} else {
+		// Reads would block writes on this transport, so each command reads its own reply
+		// and any events queued ahead of it
+		ipc.reader = bufio.NewReader(conn)
+	}
+
+	return ipc, nil
+}
+
+// log helper function
+func (c *mpvIPC) log(format string, v ...interface{}) {
+	if c.logger != nil {
+		c.logger(format, v...)
+	}
+}
+
+// readLoop dispatches replies to waiting commands and events to the handler
+func (c *mpvIPC) readLoop() {
+	scanner := bufio.NewScanner(c.conn)
+	for scanner.Scan() {
+		response := c.dispatch(scanner.Bytes())
+		if response == nil {
+			continue
+		}
+
+		c.mu.Lock()
+		ch, ok := c.pending[response.RequestID]
+		delete(c.pending, response.RequestID)
+		c.mu.Unlock()
+
+		if ok {
+			ch <- *response
+		}
+	}
+
+	// Connection closed: fail anything still waiting
+	c.mu.Lock()
+	for id, ch := range c.pending {
+		close(ch)
+		delete(c.pending, id)
+	}
+	c.mu.Unlock()
+}
+
+// dispatch handles one line from mpv: events go to the handler, replies are returned
+func (c *mpvIPC) dispatch(line []byte) *mpvResponse {
+	var probe struct {
+		Event     string `json:"event"`
+		RequestID *int   `json:"request_id"`
+	}
+	if err := json.Unmarshal(line, &probe); err != nil {
+		c.log("Ignoring malformed mpv IPC line: %s", string(line))
+		return nil
+	}
+
+	if probe.Event != "" {
+		var event mpvEvent
+		json.Unmarshal(line, &event)
+		if c.onEvent != nil {
+			c.onEvent(event)
+		}
+		return nil
+	}
+
+	if probe.RequestID == nil {
+		return nil
+	}
+	var response mpvResponse
+	json.Unmarshal(line, &response)
+	return &response
+}
+
+// command sends a command and waits for its reply
+func (c *mpvIPC) command(args ...interface{}) (json.RawMessage, error) {
+	if c.reader != nil {
+		return c.commandSync(args...)
+	}
+
+	c.mu.Lock()
+	c.requestID++
+	id := c.requestID
+	ch := make(chan mpvResponse, 1)
+	c.pending[id] = ch
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"command":    args,
+		"request_id": id,
+	})
+	if err == nil {
+		_, err = c.conn.Write(append(payload, '\n'))
+	}
+	if err != nil {
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return nil, fmt.Errorf("mpv IPC write failed: %v", err)
+	}
+	c.mu.Unlock()
+
+	select {
+	case response, ok := <-ch:
+		if !ok {
+			return nil, fmt.Errorf("mpv IPC connection closed")
+		}
+		if response.Error != "success" {
+			return nil, fmt.Errorf("mpv: %s", response.Error)
+		}
+		return response.Data, nil
+	case <-time.After(mpvCommandTimeout):
+		c.mu.Lock()
+		delete(c.pending, id)
+		c.mu.Unlock()
+		return nil, fmt.Errorf("mpv IPC command timed out")
+	}
+}
+
+// commandSync writes a command and reads lines until its reply arrives
+func (c *mpvIPC) commandSync(args ...interface{}) (json.RawMessage, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.requestID++
+	id := c.requestID
+	payload, err := json.Marshal(map[string]interface{}{
+		"command":    args,
+		"request_id": id,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := c.conn.Write(append(payload, '\n')); err != nil {
+		return nil, fmt.Errorf("mpv IPC write failed: %v", err)
+	}
+
+	for {
+		line, err := c.reader.ReadBytes('\n')
+		if err != nil {
+			return nil, fmt.Errorf("mpv IPC connection closed")
+		}
+		response := c.dispatch(line)
+		if response == nil || response.RequestID != id {
+			continue
+		}
+		if response.Error != "success" {
+			return nil, fmt.Errorf("mpv: %s", response.Error)
+		}
+		return response.Data, nil
+	}
+}
+
+// setProperty sets an mpv property
+func (c *mpvIPC) setProperty(name string, value interface{}) error {
+	_, err := c.command("set_property", name, value)
+	return err
+}
+
+// getFloat reads a numeric mpv property
+func (c *mpvIPC) getFloat(name string) (float64, error) {
+	data, err := c.command("get_property", name)
+	if err != nil {
+		return 0, err
+	}
+	var value float64
+	if err := json.Unmarshal(data, &value); err != nil {
+		return 0, fmt.Errorf("unexpected %s value: %s", name, string(data))
+	}
+	return value, nil
+}
+
+// getBool reads a flag mpv property such as pause
+func (c *mpvIPC) getBool(name string) (bool, error) {
+	data, err := c.command("get_property", name)
+	if err != nil {
+		return false, err
+	}
+	var value bool
+	if err := json.Unmarshal(data, &value); err != nil {
+		return false, fmt.Errorf("unexpected %s value: %s", name, string(data))
+	}
+	return value, nil
+}
+
+// close closes the IPC connection
+func (c *mpvIPC) close() {
+	c.conn.Close()
+}
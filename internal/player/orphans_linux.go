@@ -0,0 +1,35 @@
+//go:build linux
+
+package player
+
+import (
+	"os/exec"
+	"runtime"
+	"sync"
+	"syscall"
+)
+
+// The kernel sends Pdeathsig when the thread that started a process exits, not
+// the process. Go ends a thread when a goroutine locked to it returns, so mpv is
+// started from one goroutine that stays locked to its thread for good.
+var (
+	starts    = make(chan func())
+	startOnce sync.Once
+)
+
+// startWithParent starts mpv so the kernel stops it if ytmusic dies without stopping it
+func startWithParent(cmd *exec.Cmd) error {
+	startOnce.Do(func() {
+		go func() {
+			runtime.LockOSThread()
+			for start := range starts {
+				start()
+			}
+		}()
+	})
+
+	cmd.SysProcAttr = &syscall.SysProcAttr{Pdeathsig: syscall.SIGTERM}
+	done := make(chan error, 1)
+	starts <- func() { done <- cmd.Start() }
+	return <-done
+}
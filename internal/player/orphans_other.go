@@ -0,0 +1,11 @@
+//go:build !linux
+
+package player
+
+import "os/exec"
+
+// startWithParent starts mpv; elsewhere quitOrphans stops it on the next start
+// if ytmusic dies without stopping it
+func startWithParent(cmd *exec.Cmd) error {
+	return cmd.Start()
+}
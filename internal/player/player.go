@@ -9,27 +9,104 @@ import (
 	"runtime"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/download"
+	"ytmusic/internal/logs"
 )
 
-// Player handles music playback
+// Player handles music playback. Its fields belong to the goroutine that drives it;
+// the goroutines watching mpv only report through Events, and HandleEvent applies
+// what they report on the driving goroutine.
 type Player struct {
-	cmd          *exec.Cmd
-	Queue        *Queue
-	IsPlaying    bool
-	CurrentPos   int
-	Duration     int
-	logger       *log.Logger
-	nextCallback func() // Callback for when a track ends
+	cmd           *exec.Cmd
+	Queue         *Queue
+	IsPlaying     bool
+	Buffering     bool // mpv is paused waiting for the stream to fill its cache
+	CurrentPos    int
+	Duration      int
+	clockStart    time.Time // When playback would have started at 0, to estimate the position without IPC
+	Volume        int // 0-100, applied through IPC
+	ResumePos     int // Position to seek to when resumeTrackID next starts
+	resumeTrackID string
+	logger        *log.Logger
+	ipc           *mpvIPC // JSON IPC connection to the running mpv, nil if unavailable
+	ipcPath       string
+	playback      *playback        // State of the mpv instance currently playing
+	vis           *visualizer      // Reads the levels mpv measures for the visualizer, nil while it's off
+	events        chan PlayerEvent // Delivered to the UI as Bubble Tea messages
+	episodes      map[string]int   // Saved podcast positions by video ID, loaded on first use
+	episodesMu    sync.Mutex
+	MPVPath       string          // mpv binary to run
+	MPVArgs       []string        // Extra mpv arguments from the config file
+	YtdlFormat    string          // yt-dlp format for audio from a watch URL, empty for mpv's default
+	MaxRate       string          // Cap on the download rate, such as "500K"; empty for none
+	Video         bool            // Whether mpv shows video in a window, streaming watch URLs instead of audio streams
+	Downloads     *download.Index // Tracks played from disk instead of streamed
+	Durations     DurationStore   // Remembers durations yt-dlp looked up, nil to look them up every time
+}
+
+// DurationStore remembers track durations by video ID
+type DurationStore interface {
+	Duration(videoID string) int // 0 if unknown
+	SetDuration(videoID string, seconds int)
+}
+
+// EventType identifies a player event
+type EventType int
+
+const (
+	// TrackEnded is sent when a track plays through to its end
+	TrackEnded EventType = iota
+	// TrackAdvanced is sent when mpv moves on to the preloaded track by itself
+	TrackAdvanced
+	// DurationFound is sent when yt-dlp has found the duration of a track played from its watch URL
+	DurationFound
+)
+
+// PreloadLead is how many seconds before the end of a track the next one should be preloaded
+const PreloadLead = 30
+
+// PlayerEvent is an asynchronous notification from the player
+type PlayerEvent struct {
+	Type     EventType
+	Track    api.Track // Track now playing, for TrackAdvanced and DurationFound
+	Duration int       // Duration of Track, for TrackAdvanced and DurationFound
+	playback *playback // mpv instance the event came from
+}
+
+// playback holds per-mpv state so events from an old mpv can't affect the next one.
+// mu guards everything the mpv goroutines share with the driving goroutine.
+type playback struct {
+	mu      sync.Mutex
+	track   api.Track  // Track mpv is playing
+	next    *preloaded // Track appended to mpv's playlist to follow without a gap
+	stopped bool
+	ended   bool
+	endOnce sync.Once
+	done    chan struct{} // Closed once mpv has exited
+}
+
+// finished reports whether the playback was stopped or played through
+func (pb *playback) finished() bool {
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	return pb.stopped || pb.ended
+}
+
+// preloaded is a track waiting in mpv's playlist
+type preloaded struct {
+	track    api.Track
+	duration int
 }
 
 // NewPlayer creates a new Player instance
 func NewPlayer(debugMode bool) *Player {
 	var logger *log.Logger
 	if debugMode {
-		configDir, _ := os.UserHomeDir()
-		logPath := filepath.Join(configDir, ".ytmusic", "logs")
-		logFile := filepath.Join(logPath, fmt.Sprintf("player_%s.log", time.Now().Format("2006-01-02")))
+		logFile := filepath.Join(logs.Dir(), fmt.Sprintf("player_%s.log", time.Now().Format("2006-01-02")))
 		f, err := os.OpenFile(logFile, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0666)
 		if err != nil {
 			fmt.Printf("Error opening player log file: %v\n", err)
@@ -42,12 +119,19 @@ func NewPlayer(debugMode bool) *Player {
 		IsPlaying:  false,
 		CurrentPos: 0,
 		Duration:   0,
+		Volume:     100,
 		logger:     logger,
+		ipcPath:    ipcSocketPath(),
+		events:     make(chan PlayerEvent, 8),
+		MPVPath:    "mpv",
 	}
 	
 	// Create queue with logging function
 	p.Queue = NewQueue(p.LogDebug)
 	
+	// A crashed or killed ytmusic may have left its mpv playing
+	quitOrphans(p.LogDebug)
+	
 	return p
 }
 
@@ -58,172 +142,500 @@ func (p *Player) LogDebug(format string, v ...interface{}) {
 	}
 }
 
-// SetNextCallback sets a callback to be called when a track ends
-func (p *Player) SetNextCallback(callback func()) {
-	p.nextCallback = callback
+// Events returns the channel of asynchronous player events
+func (p *Player) Events() <-chan PlayerEvent {
+	return p.events
 }
 
 // Play starts playback of a URL
 func (p *Player) Play(url string, duration int) error {
-	if p.IsPlaying {
+	// Stop even when paused, otherwise the old mpv keeps running
+	if p.cmd != nil {
 		p.Stop()
 	}
 	
 	p.LogDebug("Playing URL: %s, initial duration: %d", url, duration)
 	
-	// Use yt-dlp to get the actual duration
-	p.LogDebug("Trying to get accurate duration with yt-dlp")
-	cmdGetDuration := exec.Command("yt-dlp", "--get-duration", url)
-	output, err := cmdGetDuration.Output()
-	if err == nil {
-		durationStr := strings.TrimSpace(string(output))
-		p.LogDebug("Got duration string from yt-dlp: %s", durationStr)
-		
-		// Parse duration like "3:45" or "1:23:45"
-		parts := strings.Split(durationStr, ":")
-		newDuration := 0
-		
-		if len(parts) == 2 {
-			// MM:SS format
-			minutes, _ := strconv.Atoi(parts[0])
-			seconds, _ := strconv.Atoi(parts[1])
-			newDuration = minutes*60 + seconds
-		} else if len(parts) == 3 {
-			// HH:MM:SS format
-			hours, _ := strconv.Atoi(parts[0])
-			minutes, _ := strconv.Atoi(parts[1])
-			seconds, _ := strconv.Atoi(parts[2])
-			newDuration = hours*3600 + minutes*60 + seconds
-		}
-		
-		if newDuration > 0 {
-			p.LogDebug("Setting new duration: %d seconds (was %d seconds)", newDuration, duration)
-			duration = newDuration
+	// Resolved stream URLs come with a known duration; only watch URLs need a
+	// yt-dlp lookup. It takes seconds, so mpv starts without waiting for it.
+	var err error
+	lookup := false
+	if strings.Contains(url, "youtube.com/watch") {
+		if known := p.rememberedDuration(); known > 0 {
+			duration = known
+		} else {
+			lookup = true
 		}
-	} else {
-		p.LogDebug("Failed to get duration with yt-dlp: %v", err)
 	}
 	
-	// Now play with mpv
-	p.cmd = exec.Command("mpv", "--no-video", "--no-terminal", url)
-	err = p.cmd.Start()
+	// Now play with mpv, exposing a JSON IPC socket so we can control it
+	cleanupIPC(p.ipcPath)
+	args := []string{
+		"--no-terminal",
+		"--input-ipc-server=" + p.ipcPath,
+		// Buffer a preloaded track before the current one ends
+		"--prefetch-playlist=yes",
+		fmt.Sprintf("--volume=%d", p.Volume),
+	}
+	args = append(args, videoArgs(p.Video, p.YtdlFormat)...)
+	args = append(args, p.visualizerArgs()...)
+	resumePos := 0
+	var track api.Track
+	if current := p.Queue.GetCurrentTrack(); current != nil {
+		track = *current
+		if p.ResumePos > 0 && current.ID == p.resumeTrackID {
+			resumePos = p.ResumePos
+		} else if current.Episode {
+			// Podcasts pick up where they were left off
+			resumePos = p.EpisodePosition(current.ID)
+		}
+	}
+	if resumePos > 0 {
+		p.LogDebug("Resuming at %d seconds", resumePos)
+		args = append(args, fmt.Sprintf("--start=%d", resumePos))
+	}
+	p.ResumePos = 0
+	args = append(args, rateLimitArgs(p.MaxRate)...)
+	args = append(args, p.MPVArgs...)
+	p.cmd = exec.Command(p.MPVPath, append(args, url)...)
+	err = startWithParent(p.cmd)
 	if err != nil {
 		p.LogDebug("Error starting mpv: %v", err)
 		return err
 	}
 	
 	p.IsPlaying = true
-	p.CurrentPos = 0
+	p.Buffering = false
+	p.CurrentPos = resumePos
 	p.Duration = duration
+	p.clockStart = time.Now().Add(-time.Duration(resumePos) * time.Second)
+	
+	pb := &playback{track: track, done: make(chan struct{})}
+	p.playback = pb
+	
+	// Without IPC we fall back to signals for pause and a tick counter for progress
+	p.ipc, err = connectMPV(p.ipcPath, p.LogDebug, func(event mpvEvent) {
+		p.handleEvent(pb, event)
+	})
+	if err != nil {
+		p.LogDebug("mpv IPC unavailable: %v", err)
+		p.ipc = nil
+	}
 	
 	// Start a goroutine to monitor playback end
-	go p.monitorPlayback()
+	go p.monitorPlayback(pb, p.cmd)
+	if lookup {
+		go p.lookupWatchDuration(pb, track, url)
+	}
 	
 	return nil
 }
 
-// monitorPlayback waits for the current track to end
-func (p *Player) monitorPlayback() {
-	if p.cmd == nil || p.cmd.Process == nil {
+// rememberedDuration returns the duration an earlier lookup found for the current track, 0 if none did
+func (p *Player) rememberedDuration() int {
+	track := p.Queue.GetCurrentTrack()
+	if p.Durations == nil || track == nil {
+		return 0
+	}
+	known := p.Durations.Duration(track.ID)
+	if known > 0 {
+		p.LogDebug("Using the remembered duration of %s: %d seconds", track.ID, known)
+	}
+	return known
+}
+
+// lookupWatchDuration asks yt-dlp for the duration of a track playing from its
+// watch URL, remembers it, and reports it as a DurationFound event
+func (p *Player) lookupWatchDuration(pb *playback, track api.Track, url string) {
+	p.LogDebug("Trying to get accurate duration with yt-dlp")
+	duration := p.lookupDuration(url, 0)
+	if duration <= 0 {
 		return
 	}
+	if p.Durations != nil && track.ID != "" {
+		p.Durations.SetDuration(track.ID, duration)
+	}
+
+	select {
+	case p.events <- PlayerEvent{Type: DurationFound, Track: track, Duration: duration, playback: pb}:
+	default:
+		p.LogDebug("Player event channel full, dropping duration of %s", track.ID)
+	}
+}
+
+// lookupDuration asks yt-dlp for a watch URL's duration, returning fallback on failure
+func (p *Player) lookupDuration(url string, fallback int) int {
+	output, err := exec.Command("yt-dlp", "--get-duration", url).Output()
+	if err != nil {
+		p.LogDebug("Failed to get duration with yt-dlp: %v", err)
+		return fallback
+	}
 	
-	// Wait for the process to finish
-	p.cmd.Wait()
+	durationStr := strings.TrimSpace(string(output))
+	p.LogDebug("Got duration string from yt-dlp: %s", durationStr)
 	
-	// Only proceed if the track actually finished (not stopped manually)
-	if p.IsPlaying && p.CurrentPos >= p.Duration-1 {
-		p.LogDebug("Track finished naturally, advancing to next")
-		p.IsPlaying = false
+	// Parse duration like "3:45" or "1:23:45"
+	parts := strings.Split(durationStr, ":")
+	newDuration := 0
+	
+	if len(parts) == 2 {
+		// MM:SS format
+		minutes, _ := strconv.Atoi(parts[0])
+		seconds, _ := strconv.Atoi(parts[1])
+		newDuration = minutes*60 + seconds
+	} else if len(parts) == 3 {
+		// HH:MM:SS format
+		hours, _ := strconv.Atoi(parts[0])
+		minutes, _ := strconv.Atoi(parts[1])
+		seconds, _ := strconv.Atoi(parts[2])
+		newDuration = hours*3600 + minutes*60 + seconds
+	}
+	
+	if newDuration <= 0 {
+		return fallback
+	}
+	
+	p.LogDebug("Setting new duration: %d seconds (was %d seconds)", newDuration, fallback)
+	return newDuration
+}
+
+// monitorPlayback waits for mpv to exit; a clean exit without IPC means the track finished
+func (p *Player) monitorPlayback(pb *playback, cmd *exec.Cmd) {
+	err := cmd.Wait()
+	close(pb.done)
+	
+	pb.mu.Lock()
+	stopped := pb.stopped
+	pb.mu.Unlock()
+	if err == nil && !stopped {
+		p.LogDebug("mpv exited cleanly, treating as end of track")
+		p.finishTrack(pb)
+	} else {
+		p.LogDebug("mpv exited: stopped=%v err=%v", stopped, err)
+	}
+}
+
+// finishTrack reports the end of a playback exactly once
+func (p *Player) finishTrack(pb *playback) {
+	pb.endOnce.Do(func() {
+		pb.mu.Lock()
+		if pb.stopped {
+			pb.mu.Unlock()
+			return
+		}
+		pb.ended = true
+		track := pb.track
+		pb.mu.Unlock()
 		
-		// Call the next callback if set
-		if p.nextCallback != nil {
-			p.nextCallback()
+		p.LogDebug("Track finished naturally")
+		if err := p.SaveEpisodePosition(track, 0); err != nil {
+			p.LogDebug("Error forgetting episode position: %v", err)
+		}
+		
+		select {
+		case p.events <- PlayerEvent{Type: TrackEnded, playback: pb}:
+		default:
+			p.LogDebug("Player event channel full, dropping track end")
+		}
+	})
+}
+
+// advance follows mpv onto the preloaded track, reporting false if there is none
+func (p *Player) advance(pb *playback) bool {
+	pb.mu.Lock()
+	if pb.stopped {
+		pb.mu.Unlock()
+		return false
+	}
+	next, finished := pb.next, pb.track
+	if next != nil {
+		pb.track = next.track
+		pb.next = nil
+	}
+	pb.mu.Unlock()
+	
+	if next == nil {
+		return false
+	}
+	
+	p.LogDebug("mpv moved on to preloaded track %s", next.track.ID)
+	if err := p.SaveEpisodePosition(finished, 0); err != nil {
+		p.LogDebug("Error forgetting episode position: %v", err)
+	}
+	
+	select {
+	case p.events <- PlayerEvent{Type: TrackAdvanced, Track: next.track, Duration: next.duration, playback: pb}:
+	default:
+		p.LogDebug("Player event channel full, dropping track advance")
+	}
+	return true
+}
+
+// HandleEvent applies an event to the player's state, reporting false for a
+// stale event from an mpv that has since been stopped or replaced, which the
+// caller should ignore
+func (p *Player) HandleEvent(event PlayerEvent) bool {
+	pb := event.playback
+	if pb == nil || pb != p.playback {
+		p.LogDebug("Ignoring event from an old mpv")
+		return false
+	}
+	pb.mu.Lock()
+	stopped := pb.stopped
+	pb.mu.Unlock()
+	if stopped {
+		p.LogDebug("Ignoring event from a stopped mpv")
+		return false
+	}
+	
+	switch event.Type {
+	case TrackEnded:
+		p.IsPlaying = false
+		p.Buffering = false
+	case TrackAdvanced:
+		p.CurrentPos = 0
+		p.Duration = event.Duration
+	case DurationFound:
+		// mpv may have moved on to a preloaded track in the meantime
+		pb.mu.Lock()
+		current := pb.track.ID
+		pb.mu.Unlock()
+		if current != event.Track.ID {
+			return false
+		}
+		p.Duration = event.Duration
+	}
+	return true
+}
+
+// NearEnd reports whether the playing track is close enough to its end to preload the next one
+func (p *Player) NearEnd() bool {
+	return p.ipc != nil && p.IsPlaying && p.Duration > 0 && p.Duration-p.CurrentPos <= PreloadLead
+}
+
+// Preload appends the track that plays next to mpv's playlist, so mpv starts
+// it the moment the current one ends instead of being restarted for it
+func (p *Player) Preload(track api.Track, url string, duration int) error {
+	pb := p.playback
+	if p.ipc == nil || pb == nil || pb.finished() {
+		return fmt.Errorf("preloading requires a running mpv with IPC")
+	}
+	
+	p.DropPreload()
+	
+	// A resume position given on the command line would apply to every file
+	if err := p.ipc.setProperty("start", "none"); err != nil {
+		p.LogDebug("Error clearing start position: %v", err)
+	}
+	if _, err := p.ipc.command("loadfile", url, "append"); err != nil {
+		return err
+	}
+	
+	p.LogDebug("Preloaded %s: %s", track.ID, url)
+	pb.mu.Lock()
+	pb.next = &preloaded{track: track, duration: duration}
+	pb.mu.Unlock()
+	return nil
+}
+
+// Preloaded returns the track waiting to follow the current one, or nil
+func (p *Player) Preloaded() *api.Track {
+	pb := p.playback
+	if pb == nil {
+		return nil
+	}
+	pb.mu.Lock()
+	defer pb.mu.Unlock()
+	if pb.next == nil {
+		return nil
+	}
+	track := pb.next.track
+	return &track
+}
+
+// DropPreload removes the preloaded track, such as when the queue changed after it was loaded
+func (p *Player) DropPreload() {
+	pb := p.playback
+	if pb == nil {
+		return
+	}
+	pb.mu.Lock()
+	next := pb.next
+	pb.next = nil
+	pb.mu.Unlock()
+	
+	if next != nil && p.ipc != nil {
+		p.LogDebug("Dropping preloaded track %s", next.track.ID)
+		if _, err := p.ipc.command("playlist-clear"); err != nil {
+			p.LogDebug("Error clearing mpv playlist: %v", err)
 		}
-	} else {
-		p.LogDebug("Track was stopped manually or still playing")
 	}
 }
 
 // Stop stops the current playback
 func (p *Player) Stop() {
 	p.LogDebug("Stopping playback")
-	if p.IsPlaying && p.cmd != nil && p.cmd.Process != nil {
+	if p.playback != nil {
+		// Remember how far into a podcast we got before it's cut off
+		p.playback.mu.Lock()
+		track, finished := p.playback.track, p.playback.stopped || p.playback.ended
+		p.playback.stopped = true
+		p.playback.mu.Unlock()
+		if !finished {
+			if err := p.SaveEpisodePosition(track, p.CurrentPos); err != nil {
+				p.LogDebug("Error saving episode position: %v", err)
+			}
+		}
+	}
+	if p.ipc != nil {
+		p.ipc.close()
+		p.ipc = nil
+	}
+	if p.cmd != nil && p.cmd.Process != nil && p.playback != nil {
+		// monitorPlayback owns Wait; wait for it so the old mpv releases the IPC socket
 		p.cmd.Process.Kill()
-		p.cmd.Wait()
+		select {
+		case <-p.playback.done:
+		case <-time.After(2 * time.Second):
+			p.LogDebug("Timed out waiting for mpv to exit")
+		}
 	}
 	p.IsPlaying = false
+	p.Buffering = false
 }
 
-// TogglePause toggles the pause state of the player
-func (p *Player) TogglePause() {
-	p.LogDebug("Toggling pause state, current state: %v", p.IsPlaying)
-	if p.cmd != nil && p.cmd.Process != nil {
-		// Send SIGTSTP to pause/unpause mpv
-		// Note: This is a simplified approach, ideally you'd use an mpv IPC socket
-		if runtime.GOOS != "windows" {
-			if p.IsPlaying {
-				exec.Command("kill", "-SIGTSTP", fmt.Sprintf("%d", p.cmd.Process.Pid)).Run()
-			} else {
-				exec.Command("kill", "-SIGCONT", fmt.Sprintf("%d", p.cmd.Process.Pid)).Run()
+// Active reports whether mpv has a track loaded, playing or paused
+func (p *Player) Active() bool {
+	return p.playback != nil && !p.playback.finished()
+}
+
+// HasStarted reports whether an mpv process has been launched this session
+func (p *Player) HasStarted() bool {
+	return p.cmd != nil
+}
+
+// handleEvent receives asynchronous events from mpv
+func (p *Player) handleEvent(pb *playback, event mpvEvent) {
+	p.LogDebug("mpv event: %s %s", event.Event, event.Reason)
+	
+	// end-file with reason "eof" is the only reliable signal that the track played through.
+	// mpv goes on to a preloaded track by itself, also when the current one fails.
+	if event.Event == "end-file" && (event.Reason == "eof" || event.Reason == "error") && p.advance(pb) {
+		return
+	}
+	if event.Event == "end-file" && event.Reason == "eof" {
+		p.finishTrack(pb)
+	}
+}
+
+// SyncPosition polls mpv for the playback position, duration and pause state.
+// Without IPC the position is estimated from the time since playback started.
+func (p *Player) SyncPosition() {
+	if !p.Active() {
+		return
+	}
+	
+	if p.ipc == nil {
+		if p.IsPlaying {
+			pos := int(time.Since(p.clockStart).Seconds())
+			if p.Duration > 0 && pos > p.Duration {
+				pos = p.Duration
 			}
+			p.CurrentPos = pos
 		}
+		return
 	}
 	
-	p.IsPlaying = !p.IsPlaying
+	// Pausing from mpv's window or another IPC client shows up here too
+	if paused, err := p.ipc.getBool("pause"); err == nil {
+		p.IsPlaying = !paused
+	}
+	if buffering, err := p.ipc.getBool("paused-for-cache"); err == nil {
+		p.Buffering = buffering
+	}
+	
+	// time-pos is unavailable while mpv is still opening the stream
+	if pos, err := p.ipc.getFloat("time-pos"); err == nil {
+		p.CurrentPos = int(pos)
+	}
+	if duration, err := p.ipc.getFloat("duration"); err == nil && duration > 0 {
+		p.Duration = int(duration)
+	}
 }
 
-// PlayTrack plays a specific track from the queue
-func (p *Player) PlayTrack(index int) error {
-	if !p.Queue.PlayTrack(index) {
-		return fmt.Errorf("invalid track index: %d", index)
+// Seek moves playback by offset seconds (negative to rewind)
+func (p *Player) Seek(offset int) error {
+	if p.ipc == nil {
+		return fmt.Errorf("seeking requires mpv IPC")
 	}
 	
-	track := p.Queue.GetCurrentTrack()
-	if track == nil {
-		return fmt.Errorf("no track to play")
+	p.LogDebug("Seeking by %d seconds", offset)
+	if _, err := p.ipc.command("seek", offset, "relative"); err != nil {
+		return err
 	}
 	
-	// Get stream URL and play
-	return p.PlayCurrentTrack()
+	p.SyncPosition()
+	return nil
 }
 
-// PlayCurrentTrack plays the current track in the queue
-func (p *Player) PlayCurrentTrack() error {
-	track := p.Queue.GetCurrentTrack()
-	if track == nil {
-		return fmt.Errorf("no track to play")
+// SeekTo moves playback to position seconds into the track
+func (p *Player) SeekTo(position int) error {
+	if p.ipc == nil {
+		return fmt.Errorf("seeking requires mpv IPC")
 	}
 	
-	// Here you would get the stream URL from the API
-	// For now, we'll use a simplified approach
-	url := "https://www.youtube.com/watch?v=" + track.ID
+	p.LogDebug("Seeking to %d seconds", position)
+	if _, err := p.ipc.command("seek", position, "absolute"); err != nil {
+		return err
+	}
 	
-	return p.Play(url, track.Duration)
+	p.SyncPosition()
+	return nil
 }
 
-// PlayNext plays the next track in the queue
-func (p *Player) PlayNext() error {
-	track, ok := p.Queue.NextTrack()
-	if !ok || track == nil {
-		return fmt.Errorf("no next track available")
+// SetVolume sets the playback volume (0-100)
+func (p *Player) SetVolume(volume int) error {
+	if volume < 0 {
+		volume = 0
 	}
+	if volume > 100 {
+		volume = 100
+	}
+	p.Volume = volume
 	
-	// Get stream URL and play
-	url := "https://www.youtube.com/watch?v=" + track.ID
-	return p.Play(url, track.Duration)
+	if p.ipc == nil {
+		// Applied on the next Play
+		return nil
+	}
+	return p.ipc.setProperty("volume", volume)
 }
 
-// PlayPrevious plays the previous track in the queue
-func (p *Player) PlayPrevious() error {
-	track, ok := p.Queue.PreviousTrack()
-	if !ok || track == nil {
-		return fmt.Errorf("no previous track available")
+// TogglePause toggles the pause state of the player
+func (p *Player) TogglePause() {
+	p.LogDebug("Toggling pause state, current state: %v", p.IsPlaying)
+	if p.ipc != nil {
+		if err := p.ipc.setProperty("pause", p.IsPlaying); err != nil {
+			p.LogDebug("Error toggling pause over IPC: %v", err)
+			return
+		}
+	} else if p.cmd != nil && p.cmd.Process != nil {
+		// Without IPC there is no way to pause mpv on Windows, so don't pretend we did
+		if runtime.GOOS == "windows" {
+			p.LogDebug("Cannot pause without mpv IPC on Windows")
+			return
+		}
+		
+		// Send SIGTSTP to pause/unpause mpv when IPC is unavailable
+		if p.IsPlaying {
+			exec.Command("kill", "-SIGTSTP", fmt.Sprintf("%d", p.cmd.Process.Pid)).Run()
+		} else {
+			exec.Command("kill", "-SIGCONT", fmt.Sprintf("%d", p.cmd.Process.Pid)).Run()
+			// The position estimate picks up from where playback paused
+			p.clockStart = time.Now().Add(-time.Duration(p.CurrentPos) * time.Second)
+		}
 	}
 	
-	// Get stream URL and play
-	url := "https://www.youtube.com/watch?v=" + track.ID
-	return p.Play(url, track.Duration)
+	p.IsPlaying = !p.IsPlaying
 }
 
 // ToggleShuffle toggles shuffle mode
@@ -235,3 +647,21 @@ func (p *Player) ToggleShuffle() {
 func (p *Player) CycleRepeatMode() PlaybackMode {
 	return p.Queue.CycleRepeatMode()
 }
+
+// readaheadSecs is how far ahead mpv buffers when the download rate is capped
+const readaheadSecs = 20
+
+// rateLimitArgs caps the download rate of what mpv plays. yt-dlp, which mpv hands
+// watch URLs to, is held to the rate; mpv has no cap of its own for direct streams,
+// so it's only allowed to buffer a little ahead, which spreads the download over
+// the whole track.
+func rateLimitArgs(rate string) []string {
+	if rate == "" {
+		return nil
+	}
+	return []string{
+		"--ytdl-raw-options=limit-rate=" + rate,
+		fmt.Sprintf("--cache-secs=%d", readaheadSecs),
+		fmt.Sprintf("--demuxer-readahead-secs=%d", readaheadSecs),
+	}
+}
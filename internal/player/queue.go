@@ -15,17 +15,20 @@ const (
 	RepeatAll
 )
 
-// Queue manages tracks for playback
+// Queue manages tracks for playback. Like Player, it belongs to the goroutine that drives playback.
 type Queue struct {
-	Tracks       []api.Track
-	CurrentIndex int
-	ShuffleMode  bool
-	RepeatMode   PlaybackMode
-	History      []int // Keeps track of play history for navigation
-	ShuffleOrder []int // Stores the shuffle order
-	logger       func(format string, v ...interface{})
+	Tracks         []api.Track
+	CurrentIndex   int
+	ShuffleMode    bool
+	RepeatMode     PlaybackMode
+	Autoplay       bool  // Refill with related tracks when the queue runs dry
+	History        []int // Keeps track of play history for navigation
+	ShuffleOrder   []int // Stores the shuffle order
+	SmartShuffle   bool  // Spread out artists and keep tracks from coming back soon, instead of a plain random order
+	SkipDuplicates bool  // Leave out tracks that are already queued when adding
+	nextCycle      []int // Smart shuffle order after repeat all wraps around, made ahead of time
+	logger         func(format string, v ...interface{})
 }
-
 // NewQueue creates a new queue
 func NewQueue(logFn func(format string, v ...interface{})) *Queue {
 	return &Queue{
@@ -61,12 +64,18 @@ func (q *Queue) Clear() {
 	q.CurrentIndex = -1
 	q.History = []int{}
 	q.ShuffleOrder = []int{}
+	q.nextCycle = nil
 }
 
-// Add adds a track to the queue
-func (q *Queue) Add(track api.Track) {
+// Add adds a track to the queue. It returns false when the track was left out as a duplicate.
+func (q *Queue) Add(track api.Track) bool {
+	if q.SkipDuplicates && q.Contains(track.ID) {
+		q.log("Skipping duplicate track: %s - %s", track.TrackTitle, track.Artist)
+		return false
+	}
 	q.log("Adding track to queue: %s - %s", track.TrackTitle, track.Artist)
 	q.Tracks = append(q.Tracks, track)
+	q.nextCycle = nil
 	
 	// Update shuffle order if shuffle is enabled
 	if q.ShuffleMode {
@@ -78,18 +87,24 @@ func (q *Queue) Add(track api.Track) {
 		// If this is the first track, set it as current
 		q.CurrentIndex = 0
 	}
+	return true
 }
 
-// AddTracks adds multiple tracks to the queue
-func (q *Queue) AddTracks(tracks []api.Track) {
+// AddTracks adds multiple tracks to the queue and returns how many were added,
+// which is fewer than given when duplicates are left out
+func (q *Queue) AddTracks(tracks []api.Track) int {
+	if q.SkipDuplicates {
+		tracks = q.withoutDuplicates(tracks)
+	}
 	q.log("Adding %d tracks to queue", len(tracks))
 	
 	if len(tracks) == 0 {
-		return
+		return 0
 	}
 	
 	originalLength := len(q.Tracks)
 	q.Tracks = append(q.Tracks, tracks...)
+	q.nextCycle = nil
 	
 	// Update shuffle order if shuffle is enabled
 	if q.ShuffleMode {
@@ -105,6 +120,25 @@ func (q *Queue) AddTracks(tracks []api.Track) {
 	if q.CurrentIndex == -1 {
 		q.CurrentIndex = 0
 	}
+	return len(tracks)
+}
+
+// withoutDuplicates returns the tracks that are neither queued nor repeated earlier in tracks
+func (q *Queue) withoutDuplicates(tracks []api.Track) []api.Track {
+	seen := make(map[string]bool, len(q.Tracks)+len(tracks))
+	for _, track := range q.Tracks {
+		seen[track.ID] = true
+	}
+	kept := make([]api.Track, 0, len(tracks))
+	for _, track := range tracks {
+		if seen[track.ID] {
+			q.log("Skipping duplicate track: %s - %s", track.TrackTitle, track.Artist)
+			continue
+		}
+		seen[track.ID] = true
+		kept = append(kept, track)
+	}
+	return kept
 }
 
 // SetTracks replaces the queue with the provided tracks
@@ -139,18 +173,55 @@ func (q *Queue) NextTrack() (track *api.Track, ok bool) {
 		return nil, false
 	}
 	
+	// Smart shuffle starts each round of repeat all in a new order
+	newCycle := q.smartCycleEnds()
+	nextIndex, ok := q.nextIndex()
 	if q.CurrentIndex != -1 {
 		q.History = append(q.History, q.CurrentIndex)
 	}
+	if !ok {
+		q.log("End of queue reached with no repeat")
+		return nil, false
+	}
+	if newCycle {
+		q.log("Starting a new smart shuffle round")
+		q.ShuffleOrder = q.nextCycleOrder()
+		q.nextCycle = nil
+	}
 	
-	// Handle different repeat modes
-	if q.RepeatMode == RepeatOne && q.CurrentIndex != -1 {
-		// With repeat one, we just replay the current track
-		q.log("Repeat One mode: replaying current track")
-		return &q.Tracks[q.CurrentIndex], true
+	q.log("Playing next track: %d", nextIndex)
+	q.CurrentIndex = nextIndex
+	return &q.Tracks[q.CurrentIndex], true
+}
+
+// smartCycleEnds reports whether smart shuffle is at the end of a round that repeat all starts over
+func (q *Queue) smartCycleEnds() bool {
+	if !q.ShuffleMode || !q.SmartShuffle || q.RepeatMode != RepeatAll || len(q.ShuffleOrder) == 0 {
+		return false
+	}
+	return q.ShuffleOrder[len(q.ShuffleOrder)-1] == q.CurrentIndex
+}
+
+// PeekNext returns the track NextTrack would advance to, without advancing
+func (q *Queue) PeekNext() *api.Track {
+	nextIndex, ok := q.nextIndex()
+	if !ok {
+		return nil
+	}
+	return &q.Tracks[nextIndex]
+}
+
+// nextIndex returns the index of the track that plays after the current one,
+// following the repeat and shuffle modes
+func (q *Queue) nextIndex() (int, bool) {
+	if len(q.Tracks) == 0 {
+		return -1, false
 	}
 	
-	var nextIndex int
+	// With repeat one, we just replay the current track
+	if q.RepeatMode == RepeatOne && q.CurrentIndex != -1 {
+		return q.CurrentIndex, true
+	}
 	
 	if q.ShuffleMode {
 		// In shuffle mode, use the shuffle order
@@ -165,43 +236,47 @@ func (q *Queue) NextTrack() (track *api.Track, ok bool) {
 		}
 		
 		if currentShufflePos == -1 || currentShufflePos == len(q.ShuffleOrder)-1 {
-			// We're at the end of the shuffle order
-			if q.RepeatMode == RepeatAll {
-				// Reset to beginning of shuffle order
-				nextIndex = q.ShuffleOrder[0]
-				q.log("Repeat All mode (shuffle): returning to first track in shuffle order")
-			} else {
-				// No more tracks
-				q.log("End of shuffle order reached with no repeat")
-				return nil, false
+			// At the end of the shuffle order, start over only with repeat all
+			if q.smartCycleEnds() {
+				return q.nextCycleOrder()[0], true
 			}
-		} else {
-			// Move to the next track in shuffle order
-			nextIndex = q.ShuffleOrder[currentShufflePos+1]
-			q.log("Playing next track in shuffle order: %d", nextIndex)
-		}
-	} else {
-		// Normal playback
-		if q.CurrentIndex == -1 || q.CurrentIndex == len(q.Tracks)-1 {
-			// We're at the end of the queue
-			if q.RepeatMode == RepeatAll {
-				// Loop back to the beginning
-				nextIndex = 0
-				q.log("Repeat All mode: returning to first track")
-			} else {
-				// No more tracks
-				q.log("End of queue reached with no repeat")
-				return nil, false
+			if q.RepeatMode == RepeatAll && len(q.ShuffleOrder) > 0 {
+				return q.ShuffleOrder[0], true
 			}
-		} else {
-			// Move to the next track
-			nextIndex = q.CurrentIndex + 1
-			q.log("Playing next track: %d", nextIndex)
+			return -1, false
 		}
+		return q.ShuffleOrder[currentShufflePos+1], true
 	}
 	
-	q.CurrentIndex = nextIndex
-	return &q.Tracks[q.CurrentIndex], true
+	if q.CurrentIndex == -1 || q.CurrentIndex == len(q.Tracks)-1 {
+		// At the end of the queue, loop back only with repeat all
+		if q.RepeatMode == RepeatAll {
+			return 0, true
+		}
+		return -1, false
+	}
+	return q.CurrentIndex + 1, true
+}
+
+// PeekPrevious returns the track PreviousTrack would go back to, without going back
+func (q *Queue) PeekPrevious() *api.Track {
+	index := q.CurrentIndex
+	switch {
+	case len(q.Tracks) == 0:
+		return nil
+	case len(q.History) > 0:
+		index = q.History[len(q.History)-1]
+	case q.ShuffleMode:
+		// Without history, shuffle replays the current track
+	case q.CurrentIndex > 0:
+		index--
+	case q.RepeatMode == RepeatAll:
+		index = len(q.Tracks) - 1
+	}
+	if index < 0 {
+		return nil
+	}
+	return &q.Tracks[index]
 }
 
 // PreviousTrack goes back to the previous track
@@ -262,23 +337,28 @@ func (q *Queue) ToggleShuffleMode() {
 		for i := range q.Tracks {
 			q.ShuffleOrder[i] = i
 		}
+		q.nextCycle = nil
 		
-		// Shuffle the order
-		rand.Seed(time.Now().UnixNano())
-		rand.Shuffle(len(q.ShuffleOrder), func(i, j int) {
-			q.ShuffleOrder[i], q.ShuffleOrder[j] = q.ShuffleOrder[j], q.ShuffleOrder[i]
-		})
-		
-		// If there's a current track, make sure it stays as the current one
-		if originalTrack != nil {
-			// Find the current track in the shuffle order and swap it to the current position
-			for i, idx := range q.ShuffleOrder {
-				if idx == q.CurrentIndex {
-					q.ShuffleOrder[i], q.ShuffleOrder[0] = q.ShuffleOrder[0], q.ShuffleOrder[i]
-					break
+		if q.SmartShuffle {
+			q.smartShuffle(originalTrack != nil)
+		} else {
+			// Shuffle the order
+			rand.Seed(time.Now().UnixNano())
+			rand.Shuffle(len(q.ShuffleOrder), func(i, j int) {
+				q.ShuffleOrder[i], q.ShuffleOrder[j] = q.ShuffleOrder[j], q.ShuffleOrder[i]
+			})
+			
+			// If there's a current track, make sure it stays as the current one
+			if originalTrack != nil {
+				// Find the current track in the shuffle order and swap it to the current position
+				for i, idx := range q.ShuffleOrder {
+					if idx == q.CurrentIndex {
+						q.ShuffleOrder[i], q.ShuffleOrder[0] = q.ShuffleOrder[0], q.ShuffleOrder[i]
+						break
+					}
 				}
+				q.CurrentIndex = q.ShuffleOrder[0]
 			}
-			q.CurrentIndex = q.ShuffleOrder[0]
 		}
 	} else {
 		// Disable shuffle - revert to sequential playback
@@ -309,6 +389,16 @@ func (q *Queue) shuffleSegment(start, end int) {
 		return
 	}
 	
+	if q.SmartShuffle {
+		// Keep the artist before the segment from playing twice in a row
+		after := ""
+		if start > 0 {
+			after = q.Tracks[q.ShuffleOrder[start-1]].Artist
+		}
+		copy(q.ShuffleOrder[start:end+1], q.smartOrder(q.ShuffleOrder[start:end+1], nil, after))
+		return
+	}
+	
 	segment := q.ShuffleOrder[start : end+1]
 	
 	r := rand.New(rand.NewSource(time.Now().UnixNano()))
@@ -337,3 +427,245 @@ func (q *Queue) CycleRepeatMode() PlaybackMode {
 	return q.RepeatMode
 }
 
+// ToggleAutoplay toggles refilling the queue with related tracks when it runs dry
+func (q *Queue) ToggleAutoplay() bool {
+	q.Autoplay = !q.Autoplay
+	q.log("Autoplay toggled to: %v", q.Autoplay)
+	return q.Autoplay
+}
+
+// Contains reports whether a track with the given ID is already queued
+func (q *Queue) Contains(id string) bool {
+	return q.IndexOf(id) != -1
+}
+
+// IndexOf returns the position of the first queued track with the given ID, or -1
+func (q *Queue) IndexOf(id string) int {
+	for i, track := range q.Tracks {
+		if track.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// Duplicates returns the positions of every queued track whose ID is queued more than once, by ID
+func (q *Queue) Duplicates() map[string][]int {
+	positions := map[string][]int{}
+	for i, track := range q.Tracks {
+		positions[track.ID] = append(positions[track.ID], i)
+	}
+	for id, indices := range positions {
+		if len(indices) < 2 {
+			delete(positions, id)
+		}
+	}
+	return positions
+}
+
+// Remove deletes the track at index, keeping the current track, history, and shuffle order consistent
+func (q *Queue) Remove(index int) bool {
+	if index < 0 || index >= len(q.Tracks) {
+		q.log("Cannot remove track with index %d, out of bounds", index)
+		return false
+	}
+	
+	q.log("Removing track at index %d: %s", index, q.Tracks[index].TrackTitle)
+	q.Tracks = append(q.Tracks[:index], q.Tracks[index+1:]...)
+	
+	if q.CurrentIndex > index {
+		q.CurrentIndex--
+	} else if q.CurrentIndex >= len(q.Tracks) {
+		q.CurrentIndex = len(q.Tracks) - 1
+	}
+	
+	q.History = removeIndex(q.History, index)
+	q.ShuffleOrder = removeIndex(q.ShuffleOrder, index)
+	q.nextCycle = nil
+	return true
+}
+
+// Move moves the track at from to index to, keeping the current track, history, and shuffle order
+// pointing at the same tracks
+func (q *Queue) Move(from, to int) bool {
+	if from < 0 || from >= len(q.Tracks) || to < 0 || to >= len(q.Tracks) {
+		q.log("Cannot move track from %d to %d, out of bounds", from, to)
+		return false
+	}
+	if from == to {
+		return true
+	}
+	
+	q.log("Moving track from index %d to %d: %s", from, to, q.Tracks[from].TrackTitle)
+	track := q.Tracks[from]
+	if from < to {
+		copy(q.Tracks[from:to], q.Tracks[from+1:to+1])
+	} else {
+		copy(q.Tracks[to+1:from+1], q.Tracks[to:from])
+	}
+	q.Tracks[to] = track
+	
+	if q.CurrentIndex != -1 {
+		q.CurrentIndex = movedIndex(q.CurrentIndex, from, to)
+	}
+	for i, idx := range q.History {
+		q.History[i] = movedIndex(idx, from, to)
+	}
+	for i, idx := range q.ShuffleOrder {
+		q.ShuffleOrder[i] = movedIndex(idx, from, to)
+	}
+	// The next round was made from the old indices
+	q.nextCycle = nil
+	return true
+}
+
+// MoveAfterCurrent moves the track at index to play right after the current one,
+// in the shuffle order too, and returns its new index
+func (q *Queue) MoveAfterCurrent(index int) (int, bool) {
+	if index == q.CurrentIndex || q.CurrentIndex == -1 {
+		return index, false
+	}
+	
+	// The current track shifts up when a track before it moves past it
+	to := q.CurrentIndex + 1
+	if index < q.CurrentIndex {
+		to = q.CurrentIndex
+	}
+	if !q.Move(index, to) {
+		return index, false
+	}
+	
+	if q.ShuffleMode {
+		order := make([]int, 0, len(q.ShuffleOrder))
+		for _, idx := range q.ShuffleOrder {
+			if idx != to {
+				order = append(order, idx)
+			}
+		}
+		for i, idx := range order {
+			if idx == q.CurrentIndex {
+				q.ShuffleOrder = append(order[:i+1], append([]int{to}, order[i+1:]...)...)
+				break
+			}
+		}
+		q.nextCycle = nil
+	}
+	return to, true
+}
+
+// movedIndex returns where the track at idx ends up when the track at from moves to to
+func movedIndex(idx, from, to int) int {
+	switch {
+	case idx == from:
+		return to
+	case from < to && idx > from && idx <= to:
+		return idx - 1
+	case to < from && idx >= to && idx < from:
+		return idx + 1
+	}
+	return idx
+}
+
+// TotalDuration returns the combined duration of all queued tracks in seconds
+func (q *Queue) TotalDuration() int {
+	total := 0
+	for _, track := range q.Tracks {
+		total += track.Duration
+	}
+	return total
+}
+
+// removeIndex drops references to a removed track index and shifts later ones down
+func removeIndex(indices []int, removed int) []int {
+	result := indices[:0]
+	for _, idx := range indices {
+		if idx == removed {
+			continue
+		}
+		if idx > removed {
+			idx--
+		}
+		result = append(result, idx)
+	}
+	return result
+}
+
+// InsertNext inserts a track right after the current one so it plays next,
+// and returns the index it ends up at. When duplicates are skipped, a track
+// that's already queued is moved there instead, unless it's the current one.
+func (q *Queue) InsertNext(track api.Track) int {
+	if q.CurrentIndex == -1 || len(q.Tracks) == 0 {
+		if q.Add(track) {
+			return len(q.Tracks) - 1
+		}
+		return q.IndexOf(track.ID)
+	}
+	if index := q.IndexOf(track.ID); q.SkipDuplicates && index != -1 {
+		// Moving it can shift the current track, so the index comes from the move
+		moved, _ := q.MoveAfterCurrent(index)
+		return moved
+	}
+	
+	pos := q.CurrentIndex + 1
+	q.log("Inserting track at index %d: %s - %s", pos, track.TrackTitle, track.Artist)
+	
+	q.Tracks = append(q.Tracks, api.Track{})
+	copy(q.Tracks[pos+1:], q.Tracks[pos:])
+	q.Tracks[pos] = track
+	q.nextCycle = nil
+	
+	// Shift references to tracks that moved down by one
+	for i, idx := range q.History {
+		if idx >= pos {
+			q.History[i] = idx + 1
+		}
+	}
+	
+	if q.ShuffleMode {
+		currentShufflePos := -1
+		for i, idx := range q.ShuffleOrder {
+			if idx >= pos {
+				q.ShuffleOrder[i] = idx + 1
+			}
+			if idx == q.CurrentIndex {
+				currentShufflePos = i
+			}
+		}
+		
+		// The new track plays next regardless of shuffle
+		insertAt := currentShufflePos + 1
+		q.ShuffleOrder = append(q.ShuffleOrder, 0)
+		copy(q.ShuffleOrder[insertAt+1:], q.ShuffleOrder[insertAt:])
+		q.ShuffleOrder[insertAt] = pos
+	}
+	return pos
+}
+
+// Upcoming returns up to n tracks that will play after the current one, in play order
+func (q *Queue) Upcoming(n int) []api.Track {
+	order := make([]int, len(q.Tracks))
+	for i := range order {
+		order[i] = i
+	}
+	if q.ShuffleMode && len(q.ShuffleOrder) == len(q.Tracks) {
+		order = q.ShuffleOrder
+	}
+	
+	// Start after the current track's place in the play order
+	start := 0
+	for i, idx := range order {
+		if idx == q.CurrentIndex {
+			start = i + 1
+			break
+		}
+	}
+	
+	var upcoming []api.Track
+	for _, idx := range order[start:] {
+		if len(upcoming) == n {
+			break
+		}
+		upcoming = append(upcoming, q.Tracks[idx])
+	}
+	return upcoming
+}
@@ -0,0 +1,45 @@
+package player
+
+import (
+	"testing"
+
+	"ytmusic/internal/api"
+)
+
+// TestInsertNextDuplicate plays a track that's already queued, as the
+// daemon's play does with skip_duplicates on, and checks the track played
+// is the one asked for wherever it was queued
+func TestInsertNextDuplicate(t *testing.T) {
+	cases := []struct {
+		name    string
+		current int
+		play    string
+	}{
+		{name: "before the current track", current: 1, play: "a"},
+		{name: "before the last track", current: 2, play: "a"},
+		{name: "after the current track", current: 0, play: "c"},
+		{name: "the current track", current: 1, play: "b"},
+	}
+	for _, shuffle := range []bool{false, true} {
+		for _, c := range cases {
+			q := NewQueue(nil)
+			q.SkipDuplicates = true
+			q.SetTracks([]api.Track{{ID: "a"}, {ID: "b"}, {ID: "c"}})
+			if shuffle {
+				q.ToggleShuffleMode()
+			}
+			q.PlayTrack(c.current)
+
+			if !q.PlayTrack(q.InsertNext(api.Track{ID: c.play})) {
+				t.Errorf("%s (shuffle %v): the index returned can't be played", c.name, shuffle)
+				continue
+			}
+			if got := q.GetCurrentTrack(); got == nil || got.ID != c.play {
+				t.Errorf("%s (shuffle %v): playing %v, want %s", c.name, shuffle, got, c.play)
+			}
+			if len(q.Tracks) != 3 {
+				t.Errorf("%s (shuffle %v): %d tracks queued, want 3", c.name, shuffle, len(q.Tracks))
+			}
+		}
+	}
+}
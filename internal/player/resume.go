@@ -0,0 +1,69 @@
+package player
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/paths"
+)
+
+// episodeFinishedMargin is how close to the end an episode counts as finished, in seconds
+const episodeFinishedMargin = 30
+
+// episodePositionsPath returns the location of the saved episode positions
+func episodePositionsPath() string {
+	return filepath.Join(paths.State(), "episodes.json")
+}
+
+// loadEpisodePositions reads the saved positions, keyed by video ID
+func loadEpisodePositions() map[string]int {
+	positions := map[string]int{}
+	data, err := os.ReadFile(episodePositionsPath())
+	if err == nil {
+		json.Unmarshal(data, &positions)
+	}
+	return positions
+}
+
+// EpisodePosition returns where a podcast episode was left off, or 0 to start from the beginning
+func (p *Player) EpisodePosition(videoID string) int {
+	p.episodesMu.Lock()
+	defer p.episodesMu.Unlock()
+
+	if p.episodes == nil {
+		p.episodes = loadEpisodePositions()
+	}
+	return p.episodes[videoID]
+}
+
+// SaveEpisodePosition remembers how far an episode was played; finished episodes are forgotten
+func (p *Player) SaveEpisodePosition(track api.Track, position int) error {
+	if !track.Episode {
+		return nil
+	}
+
+	p.episodesMu.Lock()
+	defer p.episodesMu.Unlock()
+
+	if p.episodes == nil {
+		p.episodes = loadEpisodePositions()
+	}
+	if position <= 0 || (track.Duration > 0 && position >= track.Duration-episodeFinishedMargin) {
+		if _, ok := p.episodes[track.ID]; !ok {
+			return nil
+		}
+		delete(p.episodes, track.ID)
+	} else {
+		p.episodes[track.ID] = position
+	}
+
+	data, err := json.MarshalIndent(p.episodes, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	p.LogDebug("Saving episode %s at position %d", track.ID, position)
+	return os.WriteFile(episodePositionsPath(), data, 0644)
+}
@@ -0,0 +1,121 @@
+package player
+
+import (
+	"math/rand"
+	"strings"
+	"time"
+)
+
+// noRepeatShare is the share of the queue smart shuffle plays before a track comes back
+const noRepeatShare = 0.75
+
+// smartOrder orders indices for smart shuffle: no two tracks by the same artist
+// in a row where the queue allows it, and no track again until most of the
+// queue has played since. recent lists the indices played last, most recent
+// last, and after is the artist of the track playing before the order starts.
+func (q *Queue) smartOrder(indices, recent []int, after string) []int {
+	r := rand.New(rand.NewSource(time.Now().UnixNano()))
+
+	// How many plays ago each track last played, 0 for the latest
+	playsAgo := map[int]int{}
+	for i := len(recent) - 1; i >= 0; i-- {
+		if _, seen := playsAgo[recent[i]]; !seen {
+			playsAgo[recent[i]] = len(recent) - 1 - i
+		}
+	}
+
+	// A track may come back once window tracks have played since it did
+	window := int(float64(len(q.Tracks)) * noRepeatShare)
+	earliest := func(idx int) int {
+		ago, played := playsAgo[idx]
+		if !played {
+			return 0
+		}
+		return window - 1 - ago
+	}
+
+	remaining := append([]int(nil), indices...)
+	r.Shuffle(len(remaining), func(i, j int) {
+		remaining[i], remaining[j] = remaining[j], remaining[i]
+	})
+
+	order := make([]int, 0, len(indices))
+	previous := after
+	for len(remaining) > 0 {
+		pos := len(order)
+
+		// Prefer a track that may come back by now and is by another artist,
+		// then one that may come back, then whichever has waited longest
+		pick := -1
+		for i, idx := range remaining {
+			if earliest(idx) > pos {
+				continue
+			}
+			if pick == -1 {
+				pick = i
+			}
+			if !sameArtist(q.Tracks[idx].Artist, previous) {
+				pick = i
+				break
+			}
+		}
+		if pick == -1 {
+			pick = 0
+			for i, idx := range remaining {
+				if earliest(idx) < earliest(remaining[pick]) {
+					pick = i
+				}
+			}
+		}
+
+		idx := remaining[pick]
+		remaining = append(remaining[:pick], remaining[pick+1:]...)
+		order = append(order, idx)
+		previous = q.Tracks[idx].Artist
+	}
+	return order
+}
+
+// smartShuffle fills the shuffle order when shuffle is turned on. The current
+// track, if any, stays first, and the tracks just played wait their turn.
+func (q *Queue) smartShuffle(keepCurrent bool) {
+	if !keepCurrent {
+		q.ShuffleOrder = q.smartOrder(q.ShuffleOrder, q.History, "")
+		q.nextCycle = nil
+		return
+	}
+
+	others := make([]int, 0, len(q.Tracks))
+	for i := range q.Tracks {
+		if i != q.CurrentIndex {
+			others = append(others, i)
+		}
+	}
+	recent := append(append([]int(nil), q.History...), q.CurrentIndex)
+	q.ShuffleOrder = append([]int{q.CurrentIndex}, q.smartOrder(others, recent, q.Tracks[q.CurrentIndex].Artist)...)
+	q.nextCycle = nil
+}
+
+// nextCycleOrder returns the shuffle order smart shuffle continues with once
+// repeat all wraps around, keeping the tracks that played last for the end.
+// It's made ahead of time so the first track of the next round can be preloaded,
+// and dropped whenever the queue changes.
+func (q *Queue) nextCycleOrder() []int {
+	if q.nextCycle == nil {
+		q.nextCycle = q.smartOrder(q.ShuffleOrder, q.ShuffleOrder, q.currentArtist())
+	}
+	return q.nextCycle
+}
+
+// currentArtist returns the artist of the current track, or ""
+func (q *Queue) currentArtist() string {
+	if track := q.GetCurrentTrack(); track != nil {
+		return track.Artist
+	}
+	return ""
+}
+
+// sameArtist reports whether two artist names are the same artist, ignoring case
+func sameArtist(a, b string) bool {
+	return a != "" && strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
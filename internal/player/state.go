@@ -0,0 +1,97 @@
+package player
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/paths"
+)
+
+// queueState is the on-disk form of the queue saved between sessions
+type queueState struct {
+	Tracks       []api.Track  `json:"tracks"`
+	CurrentIndex int          `json:"current_index"`
+	Position     int          `json:"position"`
+	ShuffleMode  bool         `json:"shuffle"`
+	RepeatMode   PlaybackMode `json:"repeat"`
+	Autoplay     bool         `json:"autoplay,omitempty"`
+	ShuffleOrder []int        `json:"shuffle_order,omitempty"`
+}
+
+// queueStatePath returns the location of the saved queue
+func queueStatePath() string {
+	return filepath.Join(paths.State(), "queue.json")
+}
+
+// SaveState writes the queue, current track, and position to disk
+func (p *Player) SaveState() error {
+	q := p.Queue
+	state := queueState{
+		Tracks:       q.Tracks,
+		CurrentIndex: q.CurrentIndex,
+		Position:     p.CurrentPos,
+		ShuffleMode:  q.ShuffleMode,
+		RepeatMode:   q.RepeatMode,
+		Autoplay:     q.Autoplay,
+		ShuffleOrder: q.ShuffleOrder,
+	}
+
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	p.LogDebug("Saving queue with %d tracks at index %d, position %d", len(state.Tracks), state.CurrentIndex, state.Position)
+	return os.WriteFile(queueStatePath(), data, 0644)
+}
+
+// RestoreState loads the queue saved by SaveState, if any
+func (p *Player) RestoreState() error {
+	data, err := os.ReadFile(queueStatePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	var state queueState
+	if err := json.Unmarshal(data, &state); err != nil {
+		return err
+	}
+
+	if len(state.Tracks) == 0 || state.CurrentIndex < 0 || state.CurrentIndex >= len(state.Tracks) {
+		p.LogDebug("Saved queue is empty or invalid, ignoring")
+		return nil
+	}
+
+	// A stale shuffle order would point at the wrong tracks
+	if state.ShuffleMode && len(state.ShuffleOrder) != len(state.Tracks) {
+		state.ShuffleMode = false
+		state.ShuffleOrder = nil
+	}
+
+	q := p.Queue
+	q.Tracks = state.Tracks
+	q.CurrentIndex = state.CurrentIndex
+	q.ShuffleMode = state.ShuffleMode
+	q.RepeatMode = state.RepeatMode
+	q.Autoplay = state.Autoplay
+	q.ShuffleOrder = state.ShuffleOrder
+	if q.ShuffleOrder == nil {
+		q.ShuffleOrder = []int{}
+	}
+	q.History = []int{}
+	q.nextCycle = nil
+
+	// The next Play of the restored track seeks back to where we stopped
+	p.ResumePos = state.Position
+	p.resumeTrackID = state.Tracks[state.CurrentIndex].ID
+	p.CurrentPos = state.Position
+	p.Duration = state.Tracks[state.CurrentIndex].Duration
+
+	p.LogDebug("Restored queue with %d tracks at index %d, position %d", len(q.Tracks), q.CurrentIndex, state.Position)
+	return nil
+}
@@ -0,0 +1,50 @@
+package player
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// videoFormat is the yt-dlp format mpv picks for music videos, kept to 720p so
+// a window doesn't cost much more than the audio
+const videoFormat = "bestvideo[height<=?720]+bestaudio/best"
+
+// videoArgs are the mpv arguments for showing video in a window, or for audio
+// only in audioFormat when mpv has to pick the stream itself
+func videoArgs(video bool, audioFormat string) []string {
+	if !video {
+		if audioFormat == "" {
+			return []string{"--no-video"}
+		}
+		return []string{"--no-video", "--ytdl-format=" + audioFormat}
+	}
+	return []string{"--force-window=immediate", "--ytdl-format=" + videoFormat}
+}
+
+// VideoAvailable reports why mpv can't show video here, or nil if it can.
+// Video is streamed through mpv's yt-dlp hook and needs a display to open a window on.
+func VideoAvailable() error {
+	if _, err := exec.LookPath("yt-dlp"); err != nil {
+		return fmt.Errorf("video needs yt-dlp")
+	}
+	if runtime.GOOS != "windows" && runtime.GOOS != "darwin" &&
+		os.Getenv("DISPLAY") == "" && os.Getenv("WAYLAND_DISPLAY") == "" {
+		return fmt.Errorf("no display to open a video window on")
+	}
+	return nil
+}
+
+// WatchURL returns the page mpv streams a track's video from
+func WatchURL(trackID string) string {
+	return "https://www.youtube.com/watch?v=" + trackID
+}
+
+// Restart plays a track again from a position, such as after switching video on or off
+func (p *Player) Restart(url string, position int) error {
+	if current := p.Queue.GetCurrentTrack(); current != nil {
+		p.ResumePos, p.resumeTrackID = position, current.ID
+	}
+	return p.Play(url, p.Duration)
+}
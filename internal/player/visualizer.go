@@ -0,0 +1,158 @@
+package player
+
+import (
+	"bufio"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// VisualizerBands is how many frequency bands the visualizer measures
+const VisualizerBands = 24
+
+// visualizerLabel names the audio filter measuring the bands, so it can be removed again
+const visualizerLabel = "@ytmusic-vis"
+
+// Frequencies the bands are spread across, evenly on a log scale, in Hz
+const (
+	visualizerLow  = 50.0
+	visualizerHigh = 12000.0
+)
+
+// visualizerFloor is the level, in dB, that shows as an empty band
+const visualizerFloor = -60.0
+
+// VisualizerFrame is the level of each band of the audio playing, from 0 to 1
+type VisualizerFrame struct {
+	Levels []float64
+	At     time.Time // When it was read from mpv
+}
+
+// visualizer reads what mpv measures of the audio from a fifo
+type visualizer struct {
+	path   string
+	file   *os.File
+	frames chan VisualizerFrame
+}
+
+// visualizerGraph is the lavfi graph that passes the audio through unchanged
+// while a copy of it is cut into bands, whose levels are written to path
+// about 30 times a second
+func visualizerGraph(path string) string {
+	var bands, merged strings.Builder
+	octaves := math.Log2(visualizerHigh/visualizerLow) / VisualizerBands
+	for i := 0; i < VisualizerBands; i++ {
+		center := visualizerLow * math.Pow(2, octaves*(float64(i)+0.5))
+		fmt.Fprintf(&bands, ";[s%d]bandpass=f=%.0f:width_type=o:w=%.3f[b%d]", i, center, octaves, i)
+		fmt.Fprintf(&merged, "[b%d]", i)
+	}
+	var split strings.Builder
+	for i := 0; i < VisualizerBands; i++ {
+		fmt.Fprintf(&split, "[s%d]", i)
+	}
+	return fmt.Sprintf("asplit[out][vis];"+
+		"[vis]aformat=channel_layouts=mono,asetnsamples=n=1470:p=0,asplit=%d%s%s;"+
+		"%samerge=inputs=%d,astats=metadata=1:reset=1:measure_overall=none:measure_perchannel=RMS_level,"+
+		"ametadata=mode=print:file=%s,anullsink",
+		VisualizerBands, split.String(), bands.String(), merged.String(), VisualizerBands, path)
+}
+
+// filter is the mpv audio filter that feeds the visualizer
+func (v *visualizer) filter() string {
+	return visualizerLabel + ":lavfi=[" + visualizerGraph(v.path) + "]"
+}
+
+// read parses the levels mpv writes, keeping only the latest frame when the UI falls behind
+func (v *visualizer) read() {
+	defer close(v.frames)
+	scanner := bufio.NewScanner(v.file)
+	var levels []float64 // Of the frame being read, nil before the first
+	for scanner.Scan() {
+		line := scanner.Text()
+		// Each frame starts with a line of its own, such as frame:12 pts:17640 pts_time:0.4
+		if strings.HasPrefix(line, "frame:") {
+			if levels != nil {
+				v.send(levels)
+			}
+			levels = make([]float64, VisualizerBands)
+			continue
+		}
+		// Such as lavfi.astats.3.RMS_level=-23.5, for the third band
+		key, value, ok := strings.Cut(line, "=")
+		if !ok || levels == nil || !strings.HasPrefix(key, "lavfi.astats.") {
+			continue
+		}
+		band, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(key, "lavfi.astats."), ".RMS_level"))
+		if err != nil || band < 1 || band > VisualizerBands {
+			continue
+		}
+		if db, err := strconv.ParseFloat(value, 64); err == nil {
+			levels[band-1] = math.Max(0, math.Min(1, 1-db/visualizerFloor))
+		}
+	}
+}
+
+// send hands a frame to the UI, replacing one it hasn't taken yet
+func (v *visualizer) send(levels []float64) {
+	frame := VisualizerFrame{Levels: levels, At: time.Now()}
+	for {
+		select {
+		case v.frames <- frame:
+			return
+		default:
+		}
+		select {
+		case <-v.frames:
+		default:
+		}
+	}
+}
+
+// StartVisualizer has mpv measure the audio it plays, now and for every track
+// after, delivering the levels of its frequency bands on the returned channel
+func (p *Player) StartVisualizer() (<-chan VisualizerFrame, error) {
+	if p.vis != nil {
+		return p.vis.frames, nil
+	}
+	path := visualizerPath()
+	file, err := openFifo(path)
+	if err != nil {
+		return nil, err
+	}
+	p.vis = &visualizer{path: path, file: file, frames: make(chan VisualizerFrame, 1)}
+	go p.vis.read()
+
+	if p.ipc != nil {
+		if _, err := p.ipc.command("af", "add", p.vis.filter()); err != nil {
+			p.LogDebug("Error adding the visualizer filter: %v", err)
+		}
+	}
+	return p.vis.frames, nil
+}
+
+// StopVisualizer stops measuring the audio
+func (p *Player) StopVisualizer() {
+	if p.vis == nil {
+		return
+	}
+	// mpv stops writing before the fifo goes, or the audio would stall
+	if p.ipc != nil {
+		if _, err := p.ipc.command("af", "remove", visualizerLabel); err != nil {
+			p.LogDebug("Error removing the visualizer filter: %v", err)
+		}
+	}
+	p.vis.file.Close()
+	os.Remove(p.vis.path)
+	p.vis = nil
+}
+
+// visualizerArgs returns the mpv arguments that feed the visualizer, if it's on
+func (p *Player) visualizerArgs() []string {
+	if p.vis == nil {
+		return nil
+	}
+	return []string{"--af-append=" + p.vis.filter()}
+}
@@ -0,0 +1,30 @@
+//go:build !windows
+
+package player
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// visualizerPath returns a per-process path for the fifo mpv writes the visualizer's levels to
+func visualizerPath() string {
+	return filepath.Join(os.TempDir(), fmt.Sprintf("ytmusic-vis-%d.fifo", os.Getpid()))
+}
+
+// openFifo creates a fifo and opens it for reading. It's opened for writing
+// too, so reads wait across mpv instances instead of ending when one exits.
+func openFifo(path string) (*os.File, error) {
+	os.Remove(path)
+	if err := syscall.Mkfifo(path, 0600); err != nil {
+		return nil, fmt.Errorf("creating the visualizer's fifo: %v", err)
+	}
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		os.Remove(path)
+		return nil, fmt.Errorf("opening the visualizer's fifo: %v", err)
+	}
+	return file, nil
+}
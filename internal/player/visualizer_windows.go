@@ -0,0 +1,18 @@
+//go:build windows
+
+package player
+
+import (
+	"errors"
+	"os"
+)
+
+// visualizerPath is unused; Windows has no fifo for mpv to write to
+func visualizerPath() string {
+	return ""
+}
+
+// openFifo fails, as the visualizer needs a fifo
+func openFifo(path string) (*os.File, error) {
+	return nil, errors.New("the visualizer isn't available on Windows")
+}
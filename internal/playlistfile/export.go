@@ -0,0 +1,196 @@
+// Package playlistfile reads and writes playlists as M3U, CSV and JSON files
+package playlistfile
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/paths"
+)
+
+// Formats of playlist files
+const (
+	FormatM3U  = "m3u"
+	FormatCSV  = "csv"
+	FormatJSON = "json"
+)
+
+// Dir returns the directory the TUI suggests for exports
+func Dir() string {
+	return filepath.Join(paths.Data(), "exports")
+}
+
+// ExpandHome replaces a leading ~ in a path typed by the user with the home directory
+func ExpandHome(path string) string {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return path
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~"))
+}
+
+// Formats lists the supported formats
+var Formats = []string{FormatM3U, FormatCSV, FormatJSON}
+
+// csvHeader names the columns of a CSV export
+var csvHeader = []string{"video_id", "title", "artist", "album", "duration", "url"}
+
+// playlistJSON is a playlist as written by a JSON export
+type playlistJSON struct {
+	ID     string      `json:"id"`
+	Title  string      `json:"title"`
+	Tracks []trackJSON `json:"tracks"`
+}
+
+// trackJSON is a track as written by a JSON export
+type trackJSON struct {
+	ID       string `json:"video_id"`
+	Title    string `json:"title"`
+	Artist   string `json:"artist"`
+	Album    string `json:"album,omitempty"`
+	Duration int    `json:"duration"`
+	URL      string `json:"url"`
+}
+
+// ValidFormat reports whether format is one of Formats
+func ValidFormat(format string) bool {
+	for _, f := range Formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatOf returns the format a file name's extension stands for, or "" for other extensions
+func FormatOf(path string) string {
+	format := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	if format == "m3u8" {
+		return FormatM3U
+	}
+	if ValidFormat(format) {
+		return format
+	}
+	return ""
+}
+
+// FileName turns a playlist title into a file name for format, without the
+// characters file systems reject
+func FileName(title, format string) string {
+	name := strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`/\:*?"<>|`, r) || r < ' ' {
+			return '_'
+		}
+		return r
+	}, strings.TrimSpace(title))
+	if name == "" || name == "." || name == ".." {
+		name = "playlist"
+	}
+	return name + "." + format
+}
+
+// Write writes a playlist's tracks to w in format
+func Write(w io.Writer, format string, playlist api.Playlist, tracks []api.Track) error {
+	switch format {
+	case FormatM3U:
+		return writeM3U(w, playlist, tracks)
+	case FormatCSV:
+		return writeCSV(w, tracks)
+	case FormatJSON:
+		return writeJSON(w, playlist, tracks)
+	}
+	return fmt.Errorf("unknown export format %q, use one of: %s", format, strings.Join(Formats, ", "))
+}
+
+// WriteFile writes a playlist's tracks to a file, creating its directory if needed
+func WriteFile(path, format string, playlist api.Playlist, tracks []api.Track) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("could not create export directory: %v", err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %v", path, err)
+	}
+	if err := Write(file, format, playlist, tracks); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("could not write %s: %v", path, err)
+	}
+	return nil
+}
+
+// writeM3U writes an extended M3U playlist, whose entries are the tracks' YouTube URLs
+func writeM3U(w io.Writer, playlist api.Playlist, tracks []api.Track) error {
+	var b strings.Builder
+	b.WriteString("#EXTM3U\n")
+	if playlist.PlaylistTitle != "" {
+		fmt.Fprintf(&b, "#PLAYLIST:%s\n", oneLine(playlist.PlaylistTitle))
+	}
+	for _, t := range tracks {
+		// M3U uses -1 for unknown durations
+		duration := t.Duration
+		if duration <= 0 {
+			duration = -1
+		}
+		name := oneLine(t.TrackTitle)
+		if t.Artist != "" {
+			name = oneLine(t.Artist) + " - " + name
+		}
+		fmt.Fprintf(&b, "#EXTINF:%d,%s\n%s\n", duration, name, t.URL())
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeCSV writes one row per track under a header row
+func writeCSV(w io.Writer, tracks []api.Track) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, t := range tracks {
+		row := []string{t.ID, t.TrackTitle, t.Artist, t.Album, strconv.Itoa(t.Duration), t.URL()}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// writeJSON writes the playlist as an object with its tracks
+func writeJSON(w io.Writer, playlist api.Playlist, tracks []api.Track) error {
+	out := playlistJSON{ID: playlist.ID, Title: playlist.PlaylistTitle, Tracks: make([]trackJSON, 0, len(tracks))}
+	for _, t := range tracks {
+		out.Tracks = append(out.Tracks, trackJSON{
+			ID:       t.ID,
+			Title:    t.TrackTitle,
+			Artist:   t.Artist,
+			Album:    t.Album,
+			Duration: t.Duration,
+			URL:      t.URL(),
+		})
+	}
+
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(out)
+}
+
+// oneLine replaces line breaks, which would split an M3U entry
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,315 @@
+package playlistfile
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"ytmusic/internal/api"
+)
+
+// Entry is a track read from a playlist file. Files from other services have
+// no video ID, so the track is found by searching for its title and artist.
+type Entry struct {
+	VideoID  string
+	Title    string
+	Artist   string
+	Album    string
+	Duration int // Seconds, 0 when unknown
+}
+
+// Query returns the search terms that find the entry on YouTube Music
+func (e Entry) Query() string {
+	return strings.TrimSpace(e.Artist + " " + e.Title)
+}
+
+// String names the entry in messages
+func (e Entry) String() string {
+	switch {
+	case e.Title == "":
+		return e.VideoID
+	case e.Artist == "":
+		return e.Title
+	}
+	return e.Artist + " - " + e.Title
+}
+
+// File is a playlist read from a file
+type File struct {
+	Title   string // From the file when it names the playlist, otherwise its file name
+	Entries []Entry
+}
+
+// ReadFile reads a playlist file, in the format its extension names
+func ReadFile(path string) (*File, error) {
+	format := FormatOf(path)
+	if format == "" {
+		return nil, fmt.Errorf("can't tell the format of %s, use a .m3u, .csv or .json file", path)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	playlist, err := Read(file, format)
+	if err != nil {
+		return nil, fmt.Errorf("could not read %s: %v", path, err)
+	}
+	if playlist.Title == "" {
+		playlist.Title = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return playlist, nil
+}
+
+// Read reads a playlist in format: an M3U playlist, a CSV file with a header
+// row such as ours or Exportify's Spotify exports, or one of our JSON exports
+func Read(r io.Reader, format string) (*File, error) {
+	r = skipBOM(r)
+	switch format {
+	case FormatM3U:
+		return readM3U(r)
+	case FormatCSV:
+		return readCSV(r)
+	case FormatJSON:
+		return readJSON(r)
+	}
+	return nil, fmt.Errorf("unknown import format %q, use one of: %s", format, strings.Join(Formats, ", "))
+}
+
+// skipBOM drops the byte order mark that Windows tools, Excel among them,
+// start UTF-8 files with
+func skipBOM(r io.Reader) io.Reader {
+	buffered := bufio.NewReader(r)
+	if bom, err := buffered.Peek(3); err == nil && string(bom) == "\xef\xbb\xbf" {
+		buffered.Discard(3)
+	}
+	return buffered
+}
+
+// readM3U reads an M3U playlist. YouTube links give the video ID; other
+// entries, such as local files, are searched for by their #EXTINF name or
+// their file name, as "Artist - Title".
+func readM3U(r io.Reader) (*File, error) {
+	playlist := &File{}
+	var info Entry
+
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		switch {
+		case line == "":
+			continue
+
+		case strings.HasPrefix(line, "#PLAYLIST:"):
+			playlist.Title = strings.TrimSpace(strings.TrimPrefix(line, "#PLAYLIST:"))
+
+		case strings.HasPrefix(line, "#EXTINF:"):
+			// #EXTINF:<seconds>[ attributes],<name>
+			fields := strings.SplitN(strings.TrimPrefix(line, "#EXTINF:"), ",", 2)
+			info = Entry{}
+			if seconds := strings.Fields(fields[0]); len(seconds) > 0 {
+				if n, err := strconv.Atoi(seconds[0]); err == nil && n > 0 {
+					info.Duration = n
+				}
+			}
+			if len(fields) == 2 {
+				info.Artist, info.Title = splitName(fields[1])
+			}
+
+		case strings.HasPrefix(line, "#"):
+			continue
+
+		default:
+			entry := info
+			info = Entry{}
+			if id, ok := api.ParseVideoID(line); ok && line != id {
+				entry.VideoID = id
+			} else if entry.Title == "" {
+				name := filepath.Base(filepath.FromSlash(line))
+				entry.Artist, entry.Title = splitName(strings.TrimSuffix(name, filepath.Ext(name)))
+			}
+			playlist.Entries = append(playlist.Entries, entry)
+		}
+	}
+	return playlist, scanner.Err()
+}
+
+// splitName splits "Artist - Title" into its parts, or returns it all as the title
+func splitName(name string) (artist, title string) {
+	name = strings.TrimSpace(name)
+	if artist, title, ok := cut(name, " - "); ok {
+		return strings.TrimSpace(artist), strings.TrimSpace(title)
+	}
+	return "", name
+}
+
+// cut is strings.Cut, which needs a newer Go than go.mod asks for
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// csvColumns are the header names recognized for each field, lower-cased.
+// Besides our own exports they cover Exportify's Spotify exports.
+var csvColumns = map[string][]string{
+	"video_id":    {"video_id", "video id", "videoid"},
+	"url":         {"url", "link"},
+	"title":       {"title", "track name", "track", "name", "song"},
+	"artist":      {"artist", "artist name(s)", "artist name", "artists"},
+	"album":       {"album", "album name"},
+	"duration":    {"duration", "duration (s)", "length"},
+	"duration_ms": {"duration (ms)", "duration_ms"},
+}
+
+// readCSV reads a CSV file whose header row names its columns
+func readCSV(r io.Reader) (*File, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return &File{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for field, names := range csvColumns {
+			for _, n := range names {
+				if _, seen := columns[field]; n == name && !seen {
+					columns[field] = i
+				}
+			}
+		}
+	}
+	_, hasTitle := columns["title"]
+	_, hasID := columns["video_id"]
+	_, hasURL := columns["url"]
+	if !hasTitle && !hasID && !hasURL {
+		return nil, fmt.Errorf("the header row has no title, video_id or url column")
+	}
+
+	playlist := &File{}
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			return playlist, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		get := func(field string) string {
+			if i, ok := columns[field]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		entry := Entry{Title: get("title"), Artist: get("artist"), Album: get("album")}
+		if id, ok := api.ParseVideoID(get("video_id")); ok {
+			entry.VideoID = id
+		} else if id, ok := api.ParseVideoID(get("url")); ok {
+			entry.VideoID = id
+		}
+		if seconds, err := strconv.Atoi(get("duration")); err == nil {
+			entry.Duration = seconds
+		} else if ms, err := strconv.Atoi(get("duration_ms")); err == nil {
+			entry.Duration = ms / 1000
+		}
+		if entry.VideoID != "" || entry.Title != "" {
+			playlist.Entries = append(playlist.Entries, entry)
+		}
+	}
+}
+
+// readJSON reads one of our JSON exports
+func readJSON(r io.Reader) (*File, error) {
+	var in playlistJSON
+	if err := json.NewDecoder(r).Decode(&in); err != nil {
+		return nil, err
+	}
+
+	playlist := &File{Title: in.Title}
+	for _, t := range in.Tracks {
+		entry := Entry{VideoID: t.ID, Title: t.Title, Artist: t.Artist, Album: t.Album, Duration: t.Duration}
+		if id, ok := api.ParseVideoID(t.URL); entry.VideoID == "" && ok {
+			entry.VideoID = id
+		}
+		if entry.VideoID != "" || entry.Title != "" {
+			playlist.Entries = append(playlist.Entries, entry)
+		}
+	}
+	return playlist, nil
+}
+
+// matchTolerance is how far a result's duration can be from the entry's and still match, in seconds
+const matchTolerance = 5
+
+// Match picks the search result that best matches an entry. It reports
+// whether the match is sure enough to take without asking: the title and
+// artist agree, and so does the duration when both are known.
+func Match(entry Entry, results []api.Track) (best int, sure bool) {
+	bestScore := -1
+	for i, t := range results {
+		score := 0
+		if sameText(t.TrackTitle, entry.Title) {
+			score += 4
+		} else if containsText(t.TrackTitle, entry.Title) || containsText(entry.Title, t.TrackTitle) {
+			score += 2
+		}
+		if entry.Artist == "" || containsText(t.Artist, entry.Artist) || containsText(entry.Artist, t.Artist) {
+			score += 2
+		}
+		if entry.Duration > 0 && t.Duration > 0 {
+			if diff := entry.Duration - t.Duration; diff <= matchTolerance && diff >= -matchTolerance {
+				score++
+			} else {
+				score--
+			}
+		}
+		if score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+
+	if bestScore < 0 {
+		return -1, false
+	}
+	// The same title by the artist, without a duration that disagrees
+	return best, bestScore >= 6
+}
+
+// normalize lower-cases text and keeps only its letters and digits, so
+// punctuation and spacing differences don't stop a match
+func normalize(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsNumber(r) {
+			return unicode.ToLower(r)
+		}
+		return -1
+	}, s)
+}
+
+// sameText reports whether two titles are the same once normalized
+func sameText(a, b string) bool {
+	return normalize(a) != "" && normalize(a) == normalize(b)
+}
+
+// containsText reports whether normalized a contains normalized b
+func containsText(a, b string) bool {
+	return normalize(b) != "" && strings.Contains(normalize(a), normalize(b))
+}
@@ -0,0 +1,146 @@
+// Package stats summarizes the listening history kept in history.jsonl
+package stats
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"ytmusic/internal/api"
+)
+
+// Period is the stretch of time a summary covers
+type Period int
+
+const (
+	Week Period = iota
+	Month
+	Year
+	AllTime
+	periodCount
+)
+
+// String returns the period's name as shown in the stats view
+func (p Period) String() string {
+	switch p {
+	case Week:
+		return "This Week"
+	case Month:
+		return "This Month"
+	case Year:
+		return "This Year"
+	default:
+		return "All Time"
+	}
+}
+
+// Next returns the period after p, or before it when backwards, wrapping around
+func (p Period) Next(backwards bool) Period {
+	if backwards {
+		return (p + periodCount - 1) % periodCount
+	}
+	return (p + 1) % periodCount
+}
+
+// Start returns when the period began as of now, in now's time zone.
+// Weeks start on Monday; all time starts at the zero time.
+func (p Period) Start(now time.Time) time.Time {
+	year, month, day := now.Date()
+	switch p {
+	case Week:
+		daysSinceMonday := (int(now.Weekday()) + 6) % 7
+		return time.Date(year, month, day-daysSinceMonday, 0, 0, 0, 0, now.Location())
+	case Month:
+		return time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+	case Year:
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, now.Location())
+	default:
+		return time.Time{}
+	}
+}
+
+// Count is how often a track or artist was played
+type Count struct {
+	Name    string // Track title or artist name
+	Detail  string // Artist of a track, empty for artists
+	Plays   int
+	Seconds int
+}
+
+// Summary is the listening statistics of a period
+type Summary struct {
+	Period  Period
+	Plays   int     // Completed plays
+	Skipped int     // Plays stopped before they counted
+	Seconds int     // Time listened, skipped plays included
+	Tracks  []Count // Most played tracks, most played first
+	Artists []Count // Most played artists, most played first
+	Hours   [24]int // Completed plays by the hour of day they started, in local time
+}
+
+// Summarize computes the statistics of the plays in a period, keeping the top
+// tracks and artists
+func Summarize(plays []api.Play, period Period, now time.Time, top int) Summary {
+	summary := Summary{Period: period}
+	start := period.Start(now)
+
+	tracks := map[string]*Count{}
+	artists := map[string]*Count{}
+	for _, play := range plays {
+		if play.PlayedAt.Before(start) {
+			continue
+		}
+		summary.Seconds += play.Listened()
+		if !play.Completed() {
+			summary.Skipped++
+			continue
+		}
+		summary.Plays++
+		summary.Hours[play.PlayedAt.In(now.Location()).Hour()]++
+
+		track := tracks[play.Track.ID]
+		if track == nil {
+			track = &Count{Name: play.Track.TrackTitle, Detail: play.Track.Artist}
+			tracks[play.Track.ID] = track
+		}
+		track.Plays++
+		track.Seconds += play.Listened()
+
+		if play.Track.Artist == "" {
+			continue
+		}
+		key := strings.ToLower(strings.TrimSpace(play.Track.Artist))
+		artist := artists[key]
+		if artist == nil {
+			artist = &Count{Name: play.Track.Artist}
+			artists[key] = artist
+		}
+		artist.Plays++
+		artist.Seconds += play.Listened()
+	}
+
+	summary.Tracks = topCounts(tracks, top)
+	summary.Artists = topCounts(artists, top)
+	return summary
+}
+
+// topCounts returns the n most played counts, breaking ties by listening time, then name
+func topCounts(counts map[string]*Count, n int) []Count {
+	list := make([]Count, 0, len(counts))
+	for _, count := range counts {
+		list = append(list, *count)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Plays != list[j].Plays {
+			return list[i].Plays > list[j].Plays
+		}
+		if list[i].Seconds != list[j].Seconds {
+			return list[i].Seconds > list[j].Seconds
+		}
+		return list[i].Name < list[j].Name
+	})
+	if len(list) > n {
+		list = list[:n]
+	}
+	return list
+}
@@ -0,0 +1,74 @@
+package ui
+
+import (
+	"context"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+)
+
+// accountsMsg lists the accounts the user can switch between
+type accountsMsg struct {
+	accounts []api.Account
+	err      error
+	auto     bool // Fetched after login rather than asked for
+}
+
+// GetAccountsCmd lists the accounts the user can switch between
+func GetAccountsCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, auto bool) tea.Cmd {
+	return func() tea.Msg {
+		accounts, err := ytApi.GetAccounts(ctx)
+		return accountsMsg{accounts: accounts, err: err, auto: auto}
+	}
+}
+
+// offerAccountsCmd looks for brand accounts after login, unless an account was already picked
+func (m *Model) offerAccountsCmd() tea.Cmd {
+	if m.Api.AccountChosen() {
+		return nil
+	}
+	return GetAccountsCmd(m.ctx, m.Api, true)
+}
+
+// updateAccounts opens the account picker, after login only when there is a choice to make
+func (m *Model) updateAccounts(msg accountsMsg) tea.Cmd {
+	if msg.err != nil {
+		if msg.auto {
+			m.Api.LogDebug("Could not list accounts: %v", msg.err)
+		} else {
+			m.showAPIError("Error listing accounts", msg.err)
+		}
+		return nil
+	}
+
+	if len(msg.accounts) < 2 {
+		if !msg.auto {
+			m.showWarning("This login has no other accounts to switch to")
+		}
+		return nil
+	}
+	if msg.auto && (m.Api.AccountChosen() || m.Prompt != promptNone) {
+		return nil
+	}
+
+	items := make([]list.Item, len(msg.accounts))
+	for i, account := range msg.accounts {
+		items[i] = account
+	}
+	cmd := m.openPrompt(promptAccount, "")
+	if msg.auto {
+		m.showInfo("This login has brand accounts - pick the one to use")
+	}
+	return tea.Batch(cmd, m.PickerList.SetItems(items))
+}
+
+// switchAccount makes requests as the picked account and reloads everything
+func (m *Model) switchAccount(account api.Account) tea.Cmd {
+	if err := m.Api.SetAccount(account); err != nil {
+		m.showWarning("Switched to " + account.Name + ", but " + err.Error())
+	} else {
+		m.showInfo("Switched to " + account.Name)
+	}
+	return CheckLoginCmd(m.Api)
+}
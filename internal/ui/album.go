@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+)
+
+// openAlbum starts loading an album
+func (m *Model) openAlbum(browseID string) tea.Cmd {
+	m.IsLoading = true
+	m.clearStatus()
+	return tea.Batch(
+		m.Spinner.Tick,
+		GetAlbumCmd(m.ctx, m.Api, browseID),
+	)
+}
+
+// showAlbum switches to the album view with a loaded album
+func (m *Model) showAlbum(album *api.Album) {
+	// Another album opened from an album goes back to the first
+	if m.ViewMode != ViewAlbum || m.CurrentAlbum == nil || m.CurrentAlbum.ID != album.ID {
+		m.pushView(ViewAlbum)
+	}
+	m.CurrentAlbum = album
+
+	items := make([]list.Item, len(album.Tracks))
+	for i, track := range album.Tracks {
+		items[i] = track
+	}
+
+	m.AlbumList.Title = album.AlbumTitle
+	m.AlbumList.SetItems(items)
+	m.AlbumList.Select(0)
+}
+
+// albumSummary renders the album's artist, year, and length
+func albumSummary(album *api.Album) string {
+	total := 0
+	for _, track := range album.Tracks {
+		total += track.Duration
+	}
+
+	summary := album.Artist
+	if album.Year != "" {
+		summary += " • " + album.Year
+	}
+	return fmt.Sprintf("%s • %d tracks • %s", summary, len(album.Tracks), formatDuration(total))
+}
@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// ArtistSection is one of the sections of the artist view
+type ArtistSection int
+
+const (
+	ArtistTopSongs ArtistSection = iota
+	ArtistAlbums
+	ArtistSingles
+	artistSectionCount
+)
+
+// String returns the section's display name
+func (s ArtistSection) String() string {
+	switch s {
+	case ArtistTopSongs:
+		return "Top Songs"
+	case ArtistAlbums:
+		return "Albums"
+	case ArtistSingles:
+		return "Singles"
+	}
+	return "Unknown"
+}
+
+// openArtist starts loading an artist page
+func (m *Model) openArtist(channelID string) tea.Cmd {
+	m.IsLoading = true
+	m.clearStatus()
+	return tea.Batch(
+		m.Spinner.Tick,
+		GetArtistCmd(m.ctx, m.Api, channelID),
+	)
+}
+
+// showArtistSection fills the artist list with one section of the current artist
+func (m *Model) showArtistSection(section ArtistSection) {
+	m.ArtistSection = section
+	if m.CurrentArtist == nil {
+		return
+	}
+
+	var items []list.Item
+	switch section {
+	case ArtistTopSongs:
+		for _, track := range m.CurrentArtist.TopSongs {
+			items = append(items, track)
+		}
+	case ArtistAlbums:
+		for _, album := range m.CurrentArtist.Albums {
+			items = append(items, album)
+		}
+	case ArtistSingles:
+		for _, single := range m.CurrentArtist.Singles {
+			items = append(items, single)
+		}
+	}
+
+	m.ArtistList.Title = m.CurrentArtist.Name
+	m.ArtistList.SetItems(items)
+	m.ArtistList.Select(0)
+}
+
+// startArtistRadio replaces the queue with the current artist's radio
+func (m *Model) startArtistRadio() tea.Cmd {
+	if m.CurrentArtist == nil || m.CurrentArtist.RadioID == "" {
+		m.showWarning("No radio available for this artist")
+		return nil
+	}
+
+	m.IsLoading = true
+	m.clearStatus()
+	return tea.Batch(
+		m.Spinner.Tick,
+		GetArtistRadioCmd(m.ctx, m.Api, m.CurrentArtist.RadioID),
+	)
+}
+
+// renderArtistTabs renders the artist section tabs
+func renderArtistTabs(active ArtistSection) string {
+	names := make([]string, artistSectionCount)
+	for s := ArtistSection(0); s < artistSectionCount; s++ {
+		names[s] = s.String()
+	}
+	return renderTabs(names, int(active))
+}
@@ -0,0 +1,61 @@
+package ui
+
+import (
+	"context"
+	"errors"
+)
+
+// canceled reports whether a request failed only because it was cancelled,
+// which needs no message: the user moved on or the app is quitting
+func canceled(err error) bool {
+	return errors.Is(err, context.Canceled)
+}
+
+// startSearch cancels the search in flight, if any, and returns the context for a new one
+func (m *Model) startSearch() context.Context {
+	m.stopSearch()
+	m.searchCtx, m.cancelSearch = context.WithCancel(m.ctx)
+	return m.searchCtx
+}
+
+// stopSearch cancels the search in flight, if any, keeping the results that already arrived
+func (m *Model) stopSearch() {
+	if m.cancelSearch != nil {
+		m.cancelSearch()
+		m.cancelSearch = nil
+	}
+	m.searchCtx = nil
+	m.loadingMore = false
+}
+
+// startPlaylistLoad cancels loading the previous playlist, if any, and returns the context for a new one
+func (m *Model) startPlaylistLoad() context.Context {
+	m.stopPlaylistLoad()
+	m.playlistCtx, m.cancelPlaylist = context.WithCancel(m.ctx)
+	return m.playlistCtx
+}
+
+// stopPlaylistLoad cancels loading the playlist, keeping the tracks that already arrived
+func (m *Model) stopPlaylistLoad() {
+	if m.cancelPlaylist != nil {
+		m.cancelPlaylist()
+		m.cancelPlaylist = nil
+	}
+	m.playlistCtx = nil
+	m.LoadingPlaylist = nil
+	m.loadingMore = false
+}
+
+// abortLoading cancels the search or playlist load the user is waiting on.
+// It returns false when nothing cancellable is in flight.
+func (m *Model) abortLoading() bool {
+	if m.cancelSearch == nil && m.LoadingPlaylist == nil {
+		return false
+	}
+	m.stopSearch()
+	m.stopPlaylistLoad()
+	m.listMore = "" // Scrolling on doesn't resume what was cancelled
+	m.IsLoading = false
+	m.showInfo("Cancelled")
+	return true
+}
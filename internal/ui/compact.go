@@ -0,0 +1,91 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// compactRows is the window height below which the compact layout is used on its own
+const compactRows = 12
+
+// compactBarWidth bounds the progress bar of the compact player bar
+const (
+	compactBarMin = 10
+	compactBarMax = 40
+)
+
+// compactHints are the keys shown on the compact player bar's second line
+const compactHints = "[Space] Pause/Play  [n/b] Next/Previous  [←/→] Seek  [M] Full layout  [q] Quit"
+
+// compact reports whether the view collapses to the compact player bar
+func (m *Model) compact() bool {
+	return m.Compact || m.Height < compactRows
+}
+
+// toggleCompact switches between the full layout and the compact player bar
+func (m *Model) toggleCompact() {
+	if !m.Compact && m.Height < compactRows {
+		m.showInfo("The window is too small for the full layout")
+		return
+	}
+	m.Compact = !m.Compact
+}
+
+// renderCompact renders the playing track and its progress on one line, with the
+// latest notification or the main keys on a second when the window has room
+func renderCompact(m *Model) string {
+	width := m.Width
+	if width <= 0 {
+		width = 80
+	}
+
+	line := "No song playing"
+	if track := m.Player.Queue.GetCurrentTrack(); track != nil {
+		status := "⏸"
+		if m.Player.Buffering {
+			status = "⏳"
+		} else if m.Player.IsPlaying {
+			status = "▶"
+		}
+		timeInfo := formatDuration(m.shownPosition()) + "/" + formatDuration(m.Player.Duration)
+
+		// The title gives way to the time, and the progress bar takes what's left
+		name, artist := fitPair(track.TrackTitle, track.Artist, width-len(timeInfo)-1-len(" -  ")-lipgloss.Width(status))
+		title := truncateTo(status+" "+playingStyle.Render(name)+" - "+infoStyle.Render(artist),
+			width-len(timeInfo)-1)
+		line = title + " " + timeInfo
+		if barWidth := width - lipgloss.Width(line) - 1; barWidth >= compactBarMin {
+			if barWidth > compactBarMax {
+				barWidth = compactBarMax
+			}
+			bar := m.Progress
+			bar.Width = barWidth
+			progress := 0.0
+			if m.Player.Duration > 0 {
+				progress = float64(m.shownPosition()) / float64(m.Player.Duration)
+			}
+			line = title + " " + bar.ViewAs(progress) + " " + timeInfo
+			m.progressArea = progressArea{row: 0, col: lipgloss.Width(title) + 1, width: progressCells(bar)}
+		}
+	}
+	if m.Height < 2 {
+		return truncateTo(line, width)
+	}
+
+	second := resultInfoStyle.Render(compactHints)
+	if m.IsLoading {
+		second = m.Spinner.View() + " Loading..."
+	} else if m.Status != nil {
+		second = m.Status.level.render(m.Status.text)
+	}
+	return strings.Join([]string{truncateTo(line, width), truncateTo(second, width)}, "\n")
+}
+
+// truncateTo cuts a rendered line down to a number of terminal columns
+func truncateTo(s string, width int) string {
+	if width < 1 {
+		return ""
+	}
+	return lipgloss.NewStyle().MaxWidth(width).Render(s)
+}
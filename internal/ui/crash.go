@@ -0,0 +1,51 @@
+package ui
+
+import (
+	"ytmusic/internal/crash"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// crashMsg carries a panic from a command's goroutine to the event loop
+type crashMsg struct {
+	crash crash.Panic
+}
+
+// catchPanics runs cmd and the commands it batches so that a panic in them
+// reaches the event loop as a crashMsg, instead of ending the program from a
+// goroutine with the terminal still in raw mode and mpv still playing
+func catchPanics(cmd tea.Cmd) tea.Cmd {
+	if cmd == nil {
+		return nil
+	}
+	return func() (msg tea.Msg) {
+		defer func() {
+			if r := recover(); r != nil {
+				msg = crashMsg{crash: crash.Recovered(r)}
+			}
+		}()
+
+		msg = cmd()
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			for i := range batch {
+				batch[i] = catchPanics(batch[i])
+			}
+		}
+		return msg
+	}
+}
+
+// recoverCrash writes a crash report for a panic in Update or View, then
+// panics again so Bubble Tea restores the terminal and Run returns.
+// Deferred directly, since recover only works there.
+func (m *Model) recoverCrash() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	report := crash.Recovered(r)
+	if m.CrashReport == "" && m.CrashErr == nil {
+		m.CrashReport, m.CrashErr = crash.Write(report, m.Config)
+	}
+	panic(report)
+}
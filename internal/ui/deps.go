@@ -0,0 +1,83 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/player"
+)
+
+// playerDepsMsg reports which of the programs playback needs are installed
+type playerDepsMsg struct {
+	deps []player.Dependency
+}
+
+// CheckPlayerDepsCmd looks for mpv and yt-dlp
+func CheckPlayerDepsCmd(mpvPath string) tea.Cmd {
+	return func() tea.Msg {
+		return playerDepsMsg{deps: player.Dependencies(mpvPath)}
+	}
+}
+
+// setDependencies keeps the programs that weren't found, for the banner and the checks before playing
+func (m *Model) setDependencies(deps []player.Dependency) {
+	m.Missing = nil
+	for _, dep := range deps {
+		if !dep.Found() {
+			m.Missing = append(m.Missing, dep)
+		}
+	}
+}
+
+// handlePlayerDeps shows what's missing: mpv in a banner, since nothing plays
+// without it, and yt-dlp once, since most tracks play without it
+func (m *Model) handlePlayerDeps(msg playerDepsMsg) {
+	m.setDependencies(msg.deps)
+	if dep := m.missing(false); dep != nil && m.Onboarding == nil {
+		m.showWarning(fmt.Sprintf("%s isn't installed, so music videos and some tracks won't play. %s", dep.Name, installHint(*dep)))
+	}
+}
+
+// missing returns the first missing program that is required, or optional, nil if none is
+func (m *Model) missing(required bool) *player.Dependency {
+	for i := range m.Missing {
+		if m.Missing[i].Required == required {
+			return &m.Missing[i]
+		}
+	}
+	return nil
+}
+
+// checkPlayable looks for the programs again, as they may have been installed
+// since, and explains why a stream can't play if one it needs is missing
+func (m *Model) checkPlayable(url string) error {
+	m.setDependencies(player.Dependencies(m.Player.MPVPath))
+	if dep := m.missing(true); dep != nil {
+		return fmt.Errorf("%s isn't installed, so nothing can play", dep.Name)
+	}
+	// mpv opens watch URLs through its yt-dlp hook
+	if dep := m.missing(false); dep != nil && strings.Contains(url, "youtube.com/watch") {
+		return fmt.Errorf("no stream was found, and mpv needs %s to look for one itself", dep.Name)
+	}
+	return nil
+}
+
+// installHint says how to install a program
+func installHint(dep player.Dependency) string {
+	if dep.Command != "" {
+		return "Install it with `" + dep.Command + "` or from " + dep.Install
+	}
+	return "Install it from " + dep.Install
+}
+
+// renderMissingPlayer is the banner shown while mpv can't be found
+func renderMissingPlayer(m *Model) string {
+	dep := m.missing(true)
+	if dep == nil {
+		return ""
+	}
+	return errorStyle.Render(fmt.Sprintf("✗ %s isn't installed, so nothing can play.", dep.Name)) + "\n" +
+		warningStyle.Render("  "+installHint(*dep)+", or set path under [mpv] in the config file") + "\n\n"
+}
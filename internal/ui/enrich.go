@@ -0,0 +1,166 @@
+package ui
+
+import (
+	"context"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+)
+
+// enrichWorkers is how many tracks of a playlist are looked up at once
+const enrichWorkers = 4
+
+// enrichment looks up what the pages of the open playlist left out of their tracks
+type enrichment struct {
+	playlistID string
+	ctx        context.Context
+	cancel     context.CancelFunc
+	slots      chan struct{} // Bounds the lookups in flight across all pages
+}
+
+// enrichedMsg carries a track looked up for the open playlist
+type enrichedMsg struct {
+	playlistID string
+	track      api.Track
+	results    <-chan api.Track // The rest of the page's lookups
+}
+
+// needsEnriching reports whether a track was listed without its length or artwork
+func needsEnriching(track api.Track) bool {
+	return !track.Episode && (track.Duration == 0 || track.ThumbnailURL == "")
+}
+
+// enrichTracks starts looking up the tracks of a playlist page that are missing details
+func (m *Model) enrichTracks(playlistID string, tracks []api.Track) tea.Cmd {
+	var missing []api.Track
+	for _, track := range tracks {
+		if needsEnriching(track) {
+			missing = append(missing, track)
+		}
+	}
+	if len(missing) == 0 || m.Offline {
+		return nil
+	}
+
+	if m.enrich == nil || m.enrich.playlistID != playlistID {
+		m.stopEnrich()
+		ctx, cancel := context.WithCancel(m.ctx)
+		m.enrich = &enrichment{
+			playlistID: playlistID,
+			ctx:        ctx,
+			cancel:     cancel,
+			slots:      make(chan struct{}, enrichWorkers),
+		}
+	}
+	return EnrichTracksCmd(m.enrich, m.Api, missing)
+}
+
+// stopEnrich cancels the lookups of the previous playlist, if any
+func (m *Model) stopEnrich() {
+	if m.enrich != nil {
+		m.enrich.cancel()
+		m.enrich = nil
+	}
+}
+
+// EnrichTracksCmd looks up tracks a few at a time, delivering each as it arrives
+func EnrichTracksCmd(e *enrichment, ytApi *api.YouTubeMusicAPI, tracks []api.Track) tea.Cmd {
+	return func() tea.Msg {
+		results := make(chan api.Track)
+		go e.run(ytApi, tracks, results)
+		return waitForEnriched(e.playlistID, results)
+	}
+}
+
+// WaitForEnrichedCmd waits for the next track looked up for a playlist page
+func WaitForEnrichedCmd(playlistID string, results <-chan api.Track) tea.Cmd {
+	return func() tea.Msg {
+		return waitForEnriched(playlistID, results)
+	}
+}
+
+func waitForEnriched(playlistID string, results <-chan api.Track) tea.Msg {
+	track, ok := <-results
+	if !ok {
+		return nil
+	}
+	return enrichedMsg{playlistID: playlistID, track: track, results: results}
+}
+
+// run looks up each track once a slot is free, closing results when all are done
+func (e *enrichment) run(ytApi *api.YouTubeMusicAPI, tracks []api.Track, results chan<- api.Track) {
+	var wg sync.WaitGroup
+	defer func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	for _, track := range tracks {
+		select {
+		case e.slots <- struct{}{}:
+		case <-e.ctx.Done():
+			return
+		}
+		wg.Add(1)
+		go func(track api.Track) {
+			defer func() {
+				<-e.slots
+				wg.Done()
+			}()
+			found, err := ytApi.GetTrackMetadata(e.ctx, track.ID)
+			if err != nil {
+				if !canceled(err) {
+					ytApi.LogDebug("Looking up %s failed: %v", track.ID, err)
+				}
+				return
+			}
+			select {
+			case results <- fillMissing(track, found):
+			case <-e.ctx.Done():
+			}
+		}(track)
+	}
+}
+
+// fillMissing fills in the details a track was listed without from a lookup of it
+func fillMissing(track, found api.Track) api.Track {
+	if track.Duration == 0 {
+		track.Duration = found.Duration
+	}
+	if track.Album == "" && track.AlbumID == "" {
+		track.Album, track.AlbumID = found.Album, found.AlbumID
+	}
+	if track.ArtistID == "" {
+		track.ArtistID = found.ArtistID
+	}
+	if track.ThumbnailURL == "" {
+		track.ThumbnailURL = found.ThumbnailURL
+	}
+	return track
+}
+
+// handleEnriched shows a looked up track in the list and the queue
+func (m *Model) handleEnriched(msg enrichedMsg) tea.Cmd {
+	if m.enrich == nil || m.enrich.playlistID != msg.playlistID {
+		return nil // Left over from a playlist opened before
+	}
+	if m.OpenPlaylist == nil || m.OpenPlaylist.ID != msg.playlistID {
+		m.stopEnrich() // Other results replaced the playlist
+		return nil
+	}
+
+	cmds := []tea.Cmd{WaitForEnrichedCmd(msg.playlistID, msg.results)}
+	for i, item := range m.TrackList.Items() {
+		if track, ok := item.(api.Track); ok && track.ID == msg.track.ID {
+			cmds = append(cmds, m.TrackList.SetItem(i, fillMissing(track, msg.track)))
+		}
+	}
+	for i, track := range m.Player.Queue.Tracks {
+		if track.ID == msg.track.ID {
+			m.Player.Queue.Tracks[i] = fillMissing(track, msg.track)
+		}
+	}
+	return tea.Batch(cmds...)
+}
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"context"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+)
+
+// GetExploreCmd fetches new releases and a country's charts
+func GetExploreCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, country string) tea.Cmd {
+	return func() tea.Msg {
+		var shelves []api.Shelf
+
+		// Either half is worth showing if the other fails
+		albums, releasesErr := ytApi.GetNewReleases(ctx)
+		if releasesErr == nil && len(albums) > 0 {
+			shelf := api.Shelf{Title: "New Releases"}
+			for _, album := range albums {
+				shelf.Items = append(shelf.Items, album)
+			}
+			shelves = append(shelves, shelf)
+		}
+
+		charts, chartsErr := ytApi.GetCharts(ctx, country)
+		shelves = append(shelves, charts...)
+
+		if len(shelves) == 0 {
+			err := chartsErr
+			if err == nil {
+				err = releasesErr
+			}
+			return exploreResultMsg{country: country, err: err}
+		}
+		return exploreResultMsg{country: country, shelves: shelves}
+	}
+}
+
+// loadExplore switches to the explore view, fetching it for the given country
+func (m *Model) loadExplore(country string) tea.Cmd {
+	m.ChartsCountry = strings.ToUpper(country)
+	m.setView(ViewExplore)
+	m.IsLoading = true
+	return tea.Batch(
+		m.Spinner.Tick,
+		GetExploreCmd(m.ctx, m.Api, m.ChartsCountry),
+	)
+}
+
+// showExploreShelf fills the explore list with one shelf
+func (m *Model) showExploreShelf(index int) {
+	m.ExploreShelf = showShelf(&m.ExploreList, m.ExploreShelves, index)
+}
+
+// chartsCountryName describes the charts' country for display
+func chartsCountryName(country string) string {
+	if country == "" || country == api.GlobalCharts {
+		return "Global"
+	}
+	return country
+}
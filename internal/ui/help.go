@@ -0,0 +1,190 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+
+	"ytmusic/internal/config"
+)
+
+// helpBinding is one line of the help overlay
+type helpBinding struct {
+	action string // Action in config.DefaultKeys, shown with its configured key
+	key    string // Fixed key, for bindings that can't be rebound
+	desc   string
+}
+
+// helpGroup is a titled block of the help overlay
+type helpGroup struct {
+	title    string
+	bindings []helpBinding
+}
+
+// helpGroups lists every binding, grouped the way the help overlay shows them
+var helpGroups = []helpGroup{
+	{"Navigation", []helpBinding{
+		{key: "↑/↓", desc: "Move"},
+		{key: "Enter", desc: "Play or open"},
+		{key: "Esc/Backspace", desc: "Back, or cancel loading"},
+		{key: "1-6", desc: "Switch tab"},
+		{key: "Tab/Shift+Tab", desc: "Switch shelf, section or tab"},
+		{action: "search", desc: "Search (Tab for podcasts)"},
+		{action: "home", desc: "Home tab"},
+		{action: "library", desc: "Library tab"},
+		{action: "playlists", desc: "Playlists tab"},
+		{action: "queue", desc: "Queue tab"},
+		{action: "charts", desc: "Charts and new releases"},
+		{action: "uploads", desc: "Uploads"},
+		{action: "go_to_album", desc: "Go to album"},
+		{action: "go_to_artist", desc: "Go to artist"},
+		{action: "playing_album", desc: "Go to playing album"},
+		{action: "playing_artist", desc: "Go to playing artist"},
+		{action: "jump_playing", desc: "Find playing track in list"},
+		{action: "lyrics", desc: "Lyrics"},
+		{action: "stats", desc: "Listening stats"},
+		{action: "logs", desc: "Debug logs (with -debug)"},
+		{action: "compact", desc: "Compact player bar"},
+	}},
+	{"Playback", []helpBinding{
+		{action: "play_pause", desc: "Pause/resume"},
+		{action: "next", desc: "Next track"},
+		{action: "previous", desc: "Previous track"},
+		{action: "seek_back", desc: "Seek back"},
+		{action: "seek_forward", desc: "Seek forward"},
+		{action: "seek_back_long", desc: "Seek back further"},
+		{action: "seek_fwd_long", desc: "Seek forward further"},
+		{action: "volume_up", desc: "Volume up"},
+		{action: "volume_down", desc: "Volume down"},
+		{action: "repeat", desc: "Cycle repeat mode"},
+		{action: "shuffle", desc: "Toggle shuffle"},
+		{action: "radio", desc: "Start a radio"},
+		{action: "autoplay", desc: "Toggle autoplay"},
+		{action: "quality", desc: "Cycle stream quality"},
+		{action: "video", desc: "Toggle video window"},
+		{action: "visualizer", desc: "Toggle visualizer"},
+	}},
+	{"Library", []helpBinding{
+		{action: "queue_add", desc: "Add to queue"},
+		{action: "play_next", desc: "Play next"},
+		{key: "Ctrl+Enter", desc: "Play next, where the terminal tells it from Enter"},
+		{action: "add_to_playlist", desc: "Add to playlist"},
+		{action: "select", desc: "Mark tracks for a batch action"},
+		{action: "menu", desc: "Track actions menu"},
+		{action: "info", desc: "Track info"},
+		{action: "like", desc: "Like or unlike track"},
+		{key: "d", desc: "Remove from queue or playlist"},
+		{key: "J/K", desc: "Move queue track down/up (also Ctrl+↓/↑)"},
+		{key: "T", desc: "Move queue track to the top"},
+		{key: "c", desc: "Copy track link / new playlist / charts country"},
+		{key: "e", desc: "Rename playlist"},
+		{key: "D", desc: "Delete playlist"},
+		{key: "x", desc: "Export playlist to M3U, CSV or JSON"},
+	}},
+	{"Other", []helpBinding{
+		{action: "help", desc: "Toggle this help"},
+		{action: "messages", desc: "Recent messages"},
+		{action: "accounts", desc: "Switch account"},
+		{action: "reset_cookie", desc: "Log out"},
+		{action: "quit", desc: "Quit"},
+	}},
+}
+
+// keyName turns a Bubble Tea key string into the label shown for it
+func keyName(key string) string {
+	switch key {
+	case " ":
+		return "Space"
+	case "left":
+		return "←"
+	case "right":
+		return "→"
+	case "up":
+		return "↑"
+	case "down":
+		return "↓"
+	case "enter":
+		return "Enter"
+	case "esc":
+		return "Esc"
+	case "tab":
+		return "Tab"
+	}
+	if strings.HasPrefix(key, "ctrl+") {
+		return "Ctrl+" + strings.ToUpper(strings.TrimPrefix(key, "ctrl+"))
+	}
+	if strings.HasPrefix(key, "alt+") {
+		return "Alt+" + strings.TrimPrefix(key, "alt+")
+	}
+	return key
+}
+
+// label returns the key shown for a binding under a config
+func (b helpBinding) label(cfg *config.Config) string {
+	if b.action == "" {
+		return b.key
+	}
+	return keyName(cfg.Key(b.action))
+}
+
+// HelpText lists the key bindings as plain text, for the -help flag
+func HelpText(cfg *config.Config) string {
+	var s strings.Builder
+	for _, group := range helpGroups {
+		s.WriteString(group.title + ":\n")
+		for _, binding := range group.bindings {
+			s.WriteString("  " + padRight(binding.label(cfg), 16) + binding.desc + "\n")
+		}
+		s.WriteString("\n")
+	}
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+// renderHelp renders the help overlay, fitting as many groups side by side as the window allows
+func renderHelp(m *Model) string {
+	var blocks []string
+	for _, group := range helpGroups {
+		width := 0
+		for _, binding := range group.bindings {
+			if w := lipgloss.Width(binding.label(m.Config)); w > width {
+				width = w
+			}
+		}
+
+		lines := []string{titleStyle.Render(group.title), ""}
+		for _, binding := range group.bindings {
+			lines = append(lines, modeStyle.Render(padRight(binding.label(m.Config), width))+"  "+infoStyle.Render(binding.desc))
+		}
+		blocks = append(blocks, lipgloss.NewStyle().PaddingRight(4).Render(strings.Join(lines, "\n")))
+	}
+
+	// Inside the app border and padding
+	available := m.Width - 8
+	var rows []string
+	var row []string
+	rowWidth := 0
+	for _, block := range blocks {
+		w := lipgloss.Width(block)
+		if len(row) > 0 && rowWidth+w > available {
+			rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+			row, rowWidth = nil, 0
+		}
+		row = append(row, block)
+		rowWidth += w
+	}
+	if len(row) > 0 {
+		rows = append(rows, lipgloss.JoinHorizontal(lipgloss.Top, row...))
+	}
+
+	return titleStyle.Render("YouTube Music - Help") + "\n\n" +
+		strings.Join(rows, "\n\n") + "\n\n" +
+		resultInfoStyle.Render("Press any key to close. Keys can be changed in "+config.Path())
+}
+
+// padRight pads s with spaces to width cells
+func padRight(s string, width int) string {
+	if w := lipgloss.Width(s); w < width {
+		return s + strings.Repeat(" ", width-w)
+	}
+	return s
+}
@@ -0,0 +1,59 @@
+package ui
+
+import (
+	"context"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+)
+
+// GetHomeCmd fetches the home feed
+func GetHomeCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI) tea.Cmd {
+	return func() tea.Msg {
+		shelves, err := ytApi.GetHome(ctx)
+		return homeResultMsg{shelves: shelves, err: err}
+	}
+}
+
+// showHomeShelf fills the home list with one shelf of the feed
+func (m *Model) showHomeShelf(index int) {
+	m.HomeShelf = showShelf(&m.HomeList, m.HomeShelves, index)
+}
+
+// showShelf fills a list with one of a page's shelves and returns the index shown
+func showShelf(l *list.Model, shelves []api.Shelf, index int) int {
+	if index < 0 || index >= len(shelves) {
+		l.SetItems([]list.Item{})
+		return 0
+	}
+
+	shelf := shelves[index]
+	items := make([]list.Item, len(shelf.Items))
+	for i, item := range shelf.Items {
+		items[i] = item
+	}
+
+	l.Title = "YouTube Music - " + shelf.Title
+	l.SetItems(items)
+	l.Select(0)
+	return index
+}
+
+// cycleShelf returns the shelf after (or before) current, wrapping around
+func cycleShelf(shelves []api.Shelf, current int, back bool) int {
+	count := len(shelves)
+	if back {
+		return (current + count - 1) % count
+	}
+	return (current + 1) % count
+}
+
+// renderShelfTabs renders a tab per shelf
+func renderShelfTabs(shelves []api.Shelf, active int) string {
+	names := make([]string, len(shelves))
+	for i, shelf := range shelves {
+		names[i] = shelf.Title
+	}
+	return renderTabs(names, active)
+}
@@ -0,0 +1,154 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"ytmusic/internal/api"
+)
+
+// trackInfo is the popup with everything known about a track
+type trackInfo struct {
+	track      api.Track
+	details    *api.TrackDetails // nil until fetched
+	detailsErr error
+	stream     *api.StreamInfo // nil until resolved
+	streamErr  error
+	loading    bool
+}
+
+// trackInfoMsg carries what was fetched for the info popup
+type trackInfoMsg struct {
+	videoID    string
+	details    *api.TrackDetails
+	detailsErr error
+	stream     *api.StreamInfo
+	streamErr  error
+}
+
+// showTrackInfo opens the info popup for the selected (or playing) track and fetches the rest of its details
+func (m *Model) showTrackInfo() tea.Cmd {
+	track, ok := m.targetTrack()
+	if !ok {
+		current := m.Player.Queue.GetCurrentTrack()
+		if current == nil {
+			m.showWarning("No track selected")
+			return nil
+		}
+		track = *current
+	}
+	m.Info = &trackInfo{track: track, loading: true}
+	m.clearStatus()
+	return TrackInfoCmd(m.ctx, m.Api, track.ID)
+}
+
+// updateTrackInfo fills the open popup with fetched details, if it still shows that track
+func (m *Model) updateTrackInfo(msg trackInfoMsg) {
+	if m.Info == nil || m.Info.track.ID != msg.videoID {
+		return
+	}
+	m.Info.details, m.Info.detailsErr = msg.details, msg.detailsErr
+	m.Info.stream, m.Info.streamErr = msg.stream, msg.streamErr
+	m.Info.loading = false
+}
+
+// TrackInfoCmd fetches a track's details and resolves its stream for the info popup
+func TrackInfoCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, videoID string) tea.Cmd {
+	return func() tea.Msg {
+		msg := trackInfoMsg{videoID: videoID}
+		msg.details, msg.detailsErr = ytApi.GetTrackDetails(ctx, videoID)
+		msg.stream, msg.streamErr = ytApi.GetStreamInfo(ctx, videoID)
+		return msg
+	}
+}
+
+// view renders the popup as a bordered box of labelled rows no wider than width
+func (info *trackInfo) view(width int) string {
+	track := info.track
+	rows := [][2]string{
+		{"Title", track.TrackTitle},
+		{"Artist", track.Artist},
+		{"Album", track.Album},
+		{"Year", track.Year},
+		{"Length", formatDuration(track.Duration)},
+		{"Video ID", track.ID},
+		{"Link", track.URL()},
+		{"Listed by", track.Source},
+	}
+	if track.Explicit {
+		rows = append(rows, [2]string{"Explicit", "yes"})
+	}
+
+	switch {
+	case info.loading:
+		rows = append(rows, [2]string{"Details", "Loading..."})
+	case info.detailsErr != nil:
+		rows = append(rows, [2]string{"Details", "unavailable: " + info.detailsErr.Error()})
+	case info.details != nil:
+		rows = append(rows,
+			[2]string{"Views", formatCount(info.details.ViewCount)},
+			[2]string{"Uploaded", info.details.UploadDate},
+			[2]string{"Liked", likeStatusName(info.details.LikeStatus)},
+		)
+	}
+
+	switch {
+	case info.loading:
+	case info.streamErr != nil:
+		rows = append(rows, [2]string{"Stream", "unavailable: " + info.streamErr.Error()})
+	case info.stream != nil && info.stream.Codec != "":
+		rows = append(rows, [2]string{"Stream", fmt.Sprintf("%s at %d kbps", info.stream.Codec, info.stream.Bitrate)})
+	case info.stream != nil:
+		rows = append(rows, [2]string{"Stream", "left to mpv's own extractor"})
+	}
+
+	lines := []string{titleStyle.Render("Track Info"), ""}
+	for _, row := range rows {
+		value := row[1]
+		if value == "" {
+			value = "unknown"
+		}
+		// Inside the box's border and padding, after the label
+		lines = append(lines, resultInfoStyle.Render(padRight(row[0], 11))+infoStyle.Render(fit(value, width-4-11)))
+	}
+	lines = append(lines, "", resultInfoStyle.Render("Press any key to close"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(appStyle.GetBorderRightForeground()).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+// likeStatusName describes a like status for the info popup
+func likeStatusName(status api.Rating) string {
+	switch status {
+	case api.RatingLike:
+		return "yes"
+	case api.RatingDislike:
+		return "disliked"
+	case api.RatingIndifferent:
+		return "no"
+	}
+	return ""
+}
+
+// formatCount formats a count with thousands separators, or nothing if it's unknown
+func formatCount(n int64) string {
+	if n <= 0 {
+		return ""
+	}
+	digits := fmt.Sprintf("%d", n)
+	var s strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			s.WriteByte(',')
+		}
+		s.WriteRune(digit)
+	}
+	return s.String()
+}
@@ -0,0 +1,37 @@
+package ui
+
+import "ytmusic/internal/config"
+
+// playNextKey is how Ctrl+Enter arrives from terminals that tell it apart from
+// Enter, which send a line feed for it. Most send a plain Enter, so A is the key
+// that always works.
+const playNextKey = "ctrl+j"
+
+// keyBindings translates pressed keys into the default keys the update loop handles
+type keyBindings map[string]string
+
+// newKeyBindings builds the translation for the configured keys
+func newKeyBindings(cfg *config.Config) keyBindings {
+	bindings := keyBindings{}
+	// A rebound action's old key stops doing anything...
+	for action, defaultKey := range config.DefaultKeys {
+		if cfg.Key(action) != defaultKey {
+			bindings[defaultKey] = ""
+		}
+	}
+	// ...unless another action was bound to it
+	for action, defaultKey := range config.DefaultKeys {
+		if key := cfg.Key(action); key != defaultKey {
+			bindings[key] = defaultKey
+		}
+	}
+	return bindings
+}
+
+// resolve returns the default key for a pressed key, or "" if its action was rebound elsewhere
+func (b keyBindings) resolve(key string) string {
+	if resolved, ok := b[key]; ok {
+		return resolved
+	}
+	return key
+}
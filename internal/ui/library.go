@@ -0,0 +1,161 @@
+package ui
+
+import (
+	"context"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/download"
+)
+
+// LibrarySection is one of the sub-sections of the library view
+type LibrarySection int
+
+const (
+	LibraryLikedSongs LibrarySection = iota
+	LibraryAlbums
+	LibraryArtists
+	LibrarySubscriptions
+	LibraryPodcasts
+	LibraryRecentlyPlayed
+	LibraryDownloads
+	librarySectionCount
+)
+
+// String returns the section's display name
+func (s LibrarySection) String() string {
+	switch s {
+	case LibraryLikedSongs:
+		return "Liked Songs"
+	case LibraryAlbums:
+		return "Albums"
+	case LibraryArtists:
+		return "Artists"
+	case LibrarySubscriptions:
+		return "Subscriptions"
+	case LibraryPodcasts:
+		return "Podcasts"
+	case LibraryRecentlyPlayed:
+		return "Recently Played"
+	case LibraryDownloads:
+		return "Downloads"
+	}
+	return "Unknown"
+}
+
+// GetLibrarySectionCmd fetches the contents of a library section
+func GetLibrarySectionCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, downloads *download.Index, section LibrarySection) tea.Cmd {
+	return func() tea.Msg {
+		var items []list.Item
+		var err error
+
+		switch section {
+		case LibraryLikedSongs, LibraryRecentlyPlayed:
+			var tracks []api.Track
+			if section == LibraryLikedSongs {
+				tracks, err = ytApi.GetLikedSongs(ctx)
+			} else {
+				tracks, err = ytApi.GetHistory(ctx)
+			}
+			if err == nil {
+				for _, track := range tracks {
+					items = append(items, track)
+				}
+			}
+		case LibraryAlbums:
+			var albums []api.Album
+			if albums, err = ytApi.GetLibraryAlbums(ctx); err == nil {
+				for _, album := range albums {
+					items = append(items, album)
+				}
+			}
+		case LibraryArtists, LibrarySubscriptions:
+			var artists []api.Artist
+			if section == LibraryArtists {
+				artists, err = ytApi.GetLibraryArtists(ctx)
+			} else {
+				artists, err = ytApi.GetLibrarySubscriptions(ctx)
+			}
+			if err == nil {
+				for _, artist := range artists {
+					items = append(items, artist)
+				}
+			}
+		case LibraryPodcasts:
+			var podcasts []api.Podcast
+			if podcasts, err = ytApi.GetLibraryPodcasts(ctx); err == nil {
+				for _, podcast := range podcasts {
+					items = append(items, podcast)
+				}
+			}
+		case LibraryDownloads:
+			for _, track := range downloads.Tracks() {
+				items = append(items, track)
+			}
+		}
+
+		return librarySectionMsg{section: section, items: items, err: err}
+	}
+}
+
+// showLibrarySection switches the library view to a section, loading it on first visit
+func (m *Model) showLibrarySection(section LibrarySection) tea.Cmd {
+	m.LibrarySection = section
+	m.LibraryList.Title = "YouTube Music - Library: " + section.String()
+
+	if items, ok := m.LibraryItems[section]; ok {
+		m.LibraryList.SetItems(items)
+		return nil
+	}
+
+	if m.Offline && section != LibraryDownloads {
+		m.LibraryList.SetItems([]list.Item{})
+		m.showWarning(section.String() + " isn't available offline")
+		return nil
+	}
+
+	m.LibraryList.SetItems([]list.Item{})
+	m.IsLoading = true
+	return tea.Batch(
+		m.Spinner.Tick,
+		GetLibrarySectionCmd(m.ctx, m.Api, m.Downloads, section),
+	)
+}
+
+// renderTabs renders section names with the active one highlighted
+func renderTabs(names []string, active int) string {
+	tabs := make([]string, len(names))
+	for i, name := range names {
+		if i == active {
+			tabs[i] = modeStyle.Render("[" + name + "]")
+		} else {
+			tabs[i] = resultInfoStyle.Render(name)
+		}
+	}
+	return strings.Join(tabs, "  ")
+}
+
+// renderLibraryTabs renders the library section tabs
+func renderLibraryTabs(active LibrarySection) string {
+	names := make([]string, librarySectionCount)
+	for s := LibrarySection(0); s < librarySectionCount; s++ {
+		names[s] = s.String()
+	}
+	return renderTabs(names, int(active))
+}
+
+// RecordPlayCmd adds a finished play to the local history
+func RecordPlayCmd(ytApi *api.YouTubeMusicAPI, play *api.Play, position int) tea.Cmd {
+	if play == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		if err := ytApi.RecordPlay(play, position); err != nil {
+			ytApi.LogDebug("Error recording play: %v", err)
+		}
+		return nil
+	}
+}
@@ -0,0 +1,136 @@
+package ui
+
+import (
+	"context"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+)
+
+// likes is the user's liked songs, fetched the first time a track list is
+// shown. The list delegates share it to draw the ♥ before liked tracks.
+type likes struct {
+	requested bool
+	ids       map[string]bool // Liked tracks, by video ID
+}
+
+// newLikes returns likes not fetched yet
+func newLikes() *likes {
+	return &likes{ids: map[string]bool{}}
+}
+
+// set replaces the liked tracks with those of the liked songs playlist
+func (l *likes) set(tracks []api.Track) {
+	l.ids = map[string]bool{}
+	for _, track := range tracks {
+		l.ids[track.ID] = true
+	}
+}
+
+// setItems replaces the liked tracks with those of the loaded liked songs section
+func (l *likes) setItems(items []list.Item) {
+	l.requested = true
+	l.ids = map[string]bool{}
+	for _, item := range items {
+		if track, ok := itemTrack(item); ok {
+			l.ids[track.ID] = true
+		}
+	}
+}
+
+// mark records a track as liked or not
+func (l *likes) mark(videoID string, liked bool) {
+	if liked {
+		l.ids[videoID] = true
+	} else {
+		delete(l.ids, videoID)
+	}
+}
+
+// likesMsg carries the liked songs fetched to mark them in the lists
+type likesMsg struct {
+	tracks []api.Track
+	err    error
+}
+
+// likeMsg reports how liking or unliking a track went
+type likeMsg struct {
+	track api.Track
+	liked bool
+	err   error
+}
+
+// GetLikesCmd fetches the liked songs
+func GetLikesCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI) tea.Cmd {
+	return func() tea.Msg {
+		tracks, err := ytApi.GetLikedSongs(ctx)
+		return likesMsg{tracks: tracks, err: err}
+	}
+}
+
+// loadLikes fetches the liked songs once a list of tracks is shown to a signed in user
+func (m *Model) loadLikes() tea.Cmd {
+	if m.Likes.requested || m.LoginMode || m.Offline || !isBrowseView(m.ViewMode) {
+		return nil
+	}
+	if _, ok := selectedTrack(m.ActiveList); !ok {
+		return nil
+	}
+	m.Likes.requested = true
+	return GetLikesCmd(m.ctx, m.Api)
+}
+
+// handleLikes marks the fetched liked songs, keeping any liked meanwhile
+func (m *Model) handleLikes(msg likesMsg) {
+	if msg.err != nil {
+		if !canceled(msg.err) {
+			m.Api.LogDebug("Error fetching liked songs: %v", msg.err)
+		}
+		return
+	}
+	changed := m.Likes.ids
+	m.Likes.set(msg.tracks)
+	for id := range changed {
+		m.Likes.ids[id] = true
+	}
+}
+
+// toggleLike likes a track, or unlikes it if it's liked, showing the change right away
+func (m *Model) toggleLike(track api.Track) tea.Cmd {
+	if m.Offline {
+		m.showWarning("Can't rate tracks while offline")
+		return nil
+	}
+	liked := !m.Likes.ids[track.ID]
+	m.Likes.mark(track.ID, liked)
+	// The library's liked songs are out of date now
+	delete(m.LibraryItems, LibraryLikedSongs)
+	return LikeSongCmd(m.ctx, m.Api, track, liked)
+}
+
+// LikeSongCmd likes or unlikes a track in the background
+func LikeSongCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, track api.Track, liked bool) tea.Cmd {
+	return func() tea.Msg {
+		rating := api.RatingIndifferent
+		if liked {
+			rating = api.RatingLike
+		}
+		return likeMsg{track: track, liked: liked, err: ytApi.RateSong(ctx, track.ID, rating)}
+	}
+}
+
+// handleLike reports a like or unlike, putting the mark back if it failed
+func (m *Model) handleLike(msg likeMsg) {
+	if msg.err != nil {
+		m.Likes.mark(msg.track.ID, !msg.liked)
+		m.showError("Error rating " + msg.track.TrackTitle + ": " + msg.err.Error())
+		return
+	}
+	if msg.liked {
+		m.showInfo("Liked " + msg.track.TrackTitle)
+	} else {
+		m.showInfo("Removed " + msg.track.TrackTitle + " from your liked songs")
+	}
+}
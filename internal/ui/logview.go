@@ -0,0 +1,119 @@
+package ui
+
+import (
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/logs"
+)
+
+// logViewLines is how many lines of each log the log viewer reads
+const logViewLines = 500
+
+// logRefresh is how often the log viewer reads the logs again while it's open
+const logRefresh = time.Second
+
+// logsMsg carries the lines read for the log viewer
+type logsMsg struct {
+	seq   int // Opening of the viewer the read belongs to
+	lines []string
+	err   error
+}
+
+// ReadLogsCmd reads the tails of today's logs after a delay
+func ReadLogsCmd(seq int, delay time.Duration) tea.Cmd {
+	read := func() tea.Msg {
+		lines, err := logs.TailToday(logViewLines)
+		return logsMsg{seq: seq, lines: lines, err: err}
+	}
+	if delay == 0 {
+		return read
+	}
+	return tea.Tick(delay, func(time.Time) tea.Msg {
+		return read()
+	})
+}
+
+// toggleLogs opens the debug log viewer, or closes it. Logs are only written with -debug.
+func (m *Model) toggleLogs() tea.Cmd {
+	if m.ViewMode == ViewLogs {
+		m.popView()
+		return nil
+	}
+	if !m.DebugMode {
+		m.showWarning("Logs are only written with -debug; restart ytmusic with it to see them here")
+		return nil
+	}
+
+	m.pushView(ViewLogs)
+	m.logsSeq++
+	m.LogLines = nil
+	m.LogsView.SetContent(resultInfoStyle.Render("Reading the logs..."))
+	return ReadLogsCmd(m.logsSeq, 0)
+}
+
+// updateLogs shows freshly read log lines and schedules the next read while the viewer is open
+func (m *Model) updateLogs(msg logsMsg) tea.Cmd {
+	if msg.seq != m.logsSeq || m.ViewMode != ViewLogs {
+		return nil
+	}
+	if msg.err != nil {
+		m.LogsView.SetContent(errorStyle.Render("Could not read the logs: " + msg.err.Error()))
+	} else {
+		first := m.LogLines == nil
+		m.LogLines = msg.lines
+		m.refreshLogs(first)
+	}
+	return ReadLogsCmd(m.logsSeq, logRefresh)
+}
+
+// showLogLevel switches the log viewer to another level filter
+func (m *Model) showLogLevel(level logs.Level) {
+	m.LogsLevel = level
+	m.refreshLogs(true)
+}
+
+// refreshLogs renders the lines at or above the selected level. The view
+// follows new lines while it's scrolled to the bottom, or always when jump is set.
+func (m *Model) refreshLogs(jump bool) {
+	follow := jump || m.LogsView.AtBottom()
+
+	var b strings.Builder
+	shown := 0
+	for _, line := range m.LogLines {
+		level := logs.LevelOf(line)
+		if level < m.LogsLevel {
+			continue
+		}
+		switch level {
+		case logs.Error:
+			line = errorStyle.Render(line)
+		case logs.Warning:
+			line = warningStyle.Render(line)
+		}
+		if shown > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(line)
+		shown++
+	}
+	if shown == 0 {
+		b.WriteString(resultInfoStyle.Render("Nothing logged at this level today"))
+	}
+
+	m.LogsView.SetContent(b.String())
+	if follow {
+		m.LogsView.GotoBottom()
+	}
+}
+
+// renderLogLevels renders the level filter tabs
+func renderLogLevels(active logs.Level) string {
+	var names []string
+	for level := logs.Info; level <= logs.Error; level++ {
+		names = append(names, level.String())
+	}
+	return renderTabs(names, int(active))
+}
@@ -0,0 +1,99 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+)
+
+// toggleLyrics opens the lyrics pane for the current track, or closes it
+func (m *Model) toggleLyrics() tea.Cmd {
+	if m.ViewMode == ViewLyrics {
+		m.popView()
+		return nil
+	}
+
+	track := m.Player.Queue.GetCurrentTrack()
+	if track == nil {
+		m.showWarning("No song playing")
+		return nil
+	}
+
+	m.pushView(ViewLyrics)
+	return m.loadLyrics(*track)
+}
+
+// loadLyrics fetches lyrics for a track unless they're already shown
+func (m *Model) loadLyrics(track api.Track) tea.Cmd {
+	if m.Lyrics != nil && m.Lyrics.VideoID == track.ID {
+		return nil
+	}
+
+	m.Lyrics = nil
+	m.LyricsView.SetContent(resultInfoStyle.Render("Loading lyrics for " + track.TrackTitle + "..."))
+	m.LyricsView.GotoTop()
+	return GetLyricsCmd(m.ctx, m.Api, track.ID)
+}
+
+// refreshLyrics re-renders the lyrics, following the current line of timed lyrics
+func (m *Model) refreshLyrics() {
+	if m.Lyrics == nil {
+		return
+	}
+
+	if !m.Lyrics.Timed() {
+		m.LyricsView.SetContent(m.Lyrics.Text + "\n\n" + resultInfoStyle.Render(m.Lyrics.Source))
+		return
+	}
+
+	position := m.Player.CurrentPos * 1000
+	active := -1
+	lines := make([]string, len(m.Lyrics.Lines))
+	for i, line := range m.Lyrics.Lines {
+		if position >= line.Start && (position < line.End || line.End == 0) {
+			active = i
+			lines[i] = playingStyle.Render(line.Text)
+		} else {
+			lines[i] = line.Text
+		}
+	}
+	m.LyricsView.SetContent(strings.Join(lines, "\n") + "\n\n" + resultInfoStyle.Render(m.Lyrics.Source))
+
+	// Keep the current line in the middle of the pane
+	if active >= 0 {
+		offset := active - m.LyricsView.Height/2
+		if offset < 0 {
+			offset = 0
+		}
+		m.LyricsView.SetYOffset(offset)
+	}
+}
+
+// scrollLyrics handles scrolling keys in the lyrics pane, reporting whether the key was used
+func (m *Model) scrollLyrics(key string) bool {
+	return scrollViewport(&m.LyricsView, key)
+}
+
+// scrollViewport handles scrolling keys in a pane of text, reporting whether the key was used
+func scrollViewport(v *viewport.Model, key string) bool {
+	switch key {
+	case "up", "k":
+		v.LineUp(1)
+	case "down", "j":
+		v.LineDown(1)
+	case "pgup":
+		v.ViewUp()
+	case "pgdown":
+		v.ViewDown()
+	case "home":
+		v.GotoTop()
+	case "end":
+		v.GotoBottom()
+	default:
+		return false
+	}
+	return true
+}
@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/art"
+	"ytmusic/internal/media"
+)
+
+// mediaCommandMsg is a button pressed in the system's media controls, or a global hotkey
+type mediaCommandMsg struct {
+	command  media.Command
+	controls *media.Controls // Where it was pressed, to wait for the next
+}
+
+// WaitForMediaCommandCmd waits for the next button pressed in the system's
+// media controls, or the next global hotkey
+func WaitForMediaCommandCmd(controls *media.Controls) tea.Cmd {
+	if controls == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		command, ok := <-controls.Commands()
+		if !ok {
+			return nil
+		}
+		return mediaCommandMsg{command: command, controls: controls}
+	}
+}
+
+// handleMediaCommand acts on a media control button the way the matching key does
+func (m *Model) handleMediaCommand(command media.Command) tea.Cmd {
+	switch command {
+	case media.Play:
+		if !m.Player.IsPlaying {
+			return m.togglePlayback()
+		}
+
+	case media.Pause, media.Stop:
+		if m.Player.IsPlaying {
+			m.Player.TogglePause()
+		}
+
+	case media.Toggle:
+		return m.togglePlayback()
+
+	case media.Next:
+		return m.skipTrack(true)
+
+	case media.Previous:
+		return m.skipTrack(false)
+	}
+	return nil
+}
+
+// skipTrack moves the queue to the next or previous track and fetches its stream,
+// which plays it the same way as any other track
+func (m *Model) skipTrack(next bool) tea.Cmd {
+	m.clearStatus()
+	var track *api.Track
+	var ok bool
+	if next {
+		track, ok = m.Player.Queue.NextTrack()
+	} else {
+		track, ok = m.Player.Queue.PreviousTrack()
+	}
+	if !ok || track == nil {
+		if next {
+			m.showError("Error playing next track: no next track available")
+		} else {
+			m.showError("Error playing previous track: no previous track available")
+		}
+		return nil
+	}
+
+	m.IsLoading = true
+	return tea.Batch(
+		m.Spinner.Tick,
+		m.getStreamCmd(track.ID),
+	)
+}
+
+// togglePlayback pauses or resumes, starting the current track of a restored queue
+func (m *Model) togglePlayback() tea.Cmd {
+	// A restored queue has a current track but nothing running yet
+	if !m.Player.HasStarted() {
+		if track := m.Player.Queue.GetCurrentTrack(); track != nil {
+			m.IsLoading = true
+			return tea.Batch(
+				m.Spinner.Tick,
+				m.getStreamCmd(track.ID),
+			)
+		}
+		return nil
+	}
+
+	if m.Player.IsPlaying || m.Player.Queue.GetCurrentTrack() != nil {
+		m.Player.TogglePause()
+		return m.pollProgress()
+	}
+	return nil
+}
+
+// nowPlaying describes the current track for the system's media controls
+func (m *Model) nowPlaying() media.NowPlaying {
+	track := m.Player.Queue.GetCurrentTrack()
+	if track == nil {
+		return media.NowPlaying{}
+	}
+
+	duration := track.Duration
+	if m.Player.HasStarted() && m.Player.Duration > 0 {
+		duration = m.Player.Duration
+	}
+	return media.NowPlaying{
+		Title:    track.TrackTitle,
+		Artist:   track.Artist,
+		Album:    track.Album,
+		ArtURL:   track.ThumbnailURL,
+		ArtPath:  m.playingArt(*track),
+		Duration: duration,
+		Position: m.Player.CurrentPos,
+		Playing:  m.Player.IsPlaying,
+	}
+}
+
+// artMsg reports the artwork downloaded for the media controls
+type artMsg struct {
+	key  string
+	path string
+	err  error
+}
+
+// FetchArtCmd downloads a track's artwork into the art cache, unless it's there already
+func FetchArtCmd(cache *art.Cache, key, url string) tea.Cmd {
+	return func() tea.Msg {
+		path, err := cache.Fetch(context.Background(), key, url)
+		return artMsg{key: key, path: path, err: err}
+	}
+}
+
+// fetchArt fetches the playing track's artwork for the media controls once it changes
+func (m *Model) fetchArt() tea.Cmd {
+	track := m.Player.Queue.GetCurrentTrack()
+	if m.Media == nil || track == nil {
+		return nil
+	}
+	key := art.Key(track.AlbumID, track.ID)
+	if key == m.artKey {
+		return nil
+	}
+	m.artKey, m.artPath = key, ""
+	return FetchArtCmd(m.Art, key, track.ThumbnailURL)
+}
+
+// handleArt keeps the downloaded artwork if its track is still playing
+func (m *Model) handleArt(msg artMsg) {
+	if msg.err != nil {
+		m.Player.LogDebug("Error caching artwork %s: %v", msg.key, msg.err)
+		return
+	}
+	if msg.key == m.artKey {
+		m.artPath = msg.path
+	}
+}
+
+// playingArt returns the downloaded artwork of the playing track, or "" until it arrives
+func (m *Model) playingArt(track api.Track) string {
+	if art.Key(track.AlbumID, track.ID) != m.artKey {
+		return ""
+	}
+	return m.artPath
+}
@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/utils"
+)
+
+// menuItem is one action of a popup menu
+type menuItem struct {
+	label  string
+	key    string               // Shortcut that picks the item directly
+	action func(*Model) tea.Cmd // Run when the item is picked, after the menu closes
+}
+
+// menu is a small popup list of actions, navigated with ↑/↓ and picked with Enter or a shortcut
+type menu struct {
+	title  string
+	items  []menuItem
+	cursor int
+}
+
+// openMenu shows a popup menu over the current view
+func (m *Model) openMenu(title string, items []menuItem) {
+	m.Menu = &menu{title: title, items: items}
+	m.clearStatus()
+}
+
+// updateMenu handles keys while a menu is open
+func (m *Model) updateMenu(msg tea.KeyMsg) tea.Cmd {
+	mn := m.Menu
+	switch msg.String() {
+	case "esc", "q", "m":
+		m.Menu = nil
+		return nil
+
+	case "up", "k":
+		mn.cursor = (mn.cursor + len(mn.items) - 1) % len(mn.items)
+		return nil
+
+	case "down", "j":
+		mn.cursor = (mn.cursor + 1) % len(mn.items)
+		return nil
+
+	case "enter":
+		m.Menu = nil
+		return mn.items[mn.cursor].action(m)
+	}
+
+	for _, item := range mn.items {
+		if item.key == msg.String() {
+			m.Menu = nil
+			return item.action(m)
+		}
+	}
+	return nil
+}
+
+// view renders the menu as a bordered box no wider than width
+func (mn *menu) view(width int) string {
+	// Inside the box's border and padding
+	lines := []string{titleStyle.Render(fit(mn.title, width-4)), ""}
+	for i, item := range mn.items {
+		line := "[" + item.key + "] " + item.label
+		if i == mn.cursor {
+			lines = append(lines, modeStyle.Render("> "+line))
+		} else {
+			lines = append(lines, infoStyle.Render("  "+line))
+		}
+	}
+	lines = append(lines, "", resultInfoStyle.Render("[↑/↓] Move  [Enter] Pick  [Esc] Close"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(appStyle.GetBorderRightForeground()).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
+
+// trackMenu lists the actions available for a track, liked or not
+func trackMenu(track api.Track, liked bool) []menuItem {
+	like := "Like"
+	if liked {
+		like = "Unlike"
+	}
+	items := []menuItem{
+		{"Play next", "A", func(m *Model) tea.Cmd { return m.queueTrack(track, true) }},
+		{"Add to queue", "a", func(m *Model) tea.Cmd { return m.queueTrack(track, false) }},
+		{"Add to playlist", "P", func(m *Model) tea.Cmd { return m.pickPlaylist(track) }},
+		{like, "l", func(m *Model) tea.Cmd { return m.toggleLike(track) }},
+		{"Start radio", "r", func(m *Model) tea.Cmd { return m.startRadio(track) }},
+	}
+	if track.AlbumID != "" {
+		items = append(items, menuItem{"Go to album", "g", func(m *Model) tea.Cmd { return m.openAlbum(track.AlbumID) }})
+	}
+	if track.ArtistID != "" {
+		items = append(items, menuItem{"Go to artist", "G", func(m *Model) tea.Cmd { return m.openArtist(track.ArtistID) }})
+	}
+	return append(items, menuItem{"Copy link", "c", func(m *Model) tea.Cmd { return CopyLinkCmd(track) }})
+}
+
+// CopyLinkCmd copies a track's link to the clipboard
+func CopyLinkCmd(track api.Track) tea.Cmd {
+	return func() tea.Msg {
+		if err := utils.CopyToClipboard(track.URL()); err != nil {
+			return statusMsg{level: levelError, text: "Error copying link: " + err.Error()}
+		}
+		return statusMsg{text: "Copied " + track.URL()}
+	}
+}
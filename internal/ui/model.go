@@ -1,17 +1,25 @@
 package ui
 
 import (
+	"context"
 	"time"
 
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/progress"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
-	"github.com/charmbracelet/lipgloss"
 	
 	"ytmusic/internal/api"
+	"ytmusic/internal/art"
+	"ytmusic/internal/config"
+	"ytmusic/internal/logs"
+	"ytmusic/internal/download"
+	"ytmusic/internal/media"
 	"ytmusic/internal/player"
+	"ytmusic/internal/stats"
+	"ytmusic/internal/utils"
 )
 
 // ViewMode defines the different view modes for the application
@@ -21,103 +29,182 @@ const (
 	ViewSearch ViewMode = iota
 	ViewTracks
 	ViewPlaylists
+	ViewQueue
+	ViewLibrary
+	ViewAlbum
+	ViewArtist
+	ViewLyrics
+	ViewHome
+	ViewExplore
+	ViewPodcast
+	ViewUploads
+	ViewNowPlaying
+	ViewStats
+	ViewLogs
 )
 
-// Styling
-var (
-	appStyle = lipgloss.NewStyle().
-		Border(lipgloss.RoundedBorder()).
-		BorderForeground(lipgloss.Color("#ff0000")).
-		Padding(1, 2).
-		AlignHorizontal(lipgloss.Left).
-		AlignVertical(lipgloss.Top)
-
-	titleStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Background(lipgloss.Color("#ff0000")).
-		Bold(true).
-		Padding(0, 1)
-
-	statusBarStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#000000")).
-		Background(lipgloss.Color("#EEEEEE")).
-		Padding(0, 1)
-
-	playingStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00FF00")).
-		Bold(true)
-
-	infoStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFFFFF"))
-
-	errorStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FF0000")).
-		Bold(true)
-
-	warningStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#FFAA00")).
-		Bold(true)
-		
-	resultInfoStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#AAAAAA")).
-		Italic(true)
-		
-	modeStyle = lipgloss.NewStyle().
-		Foreground(lipgloss.Color("#00AAFF")).
-		Bold(true)
-)
+// startViews maps the config file's default_view names to views
+var startViews = map[string]ViewMode{
+	"home":      ViewHome,
+	"search":    ViewTracks,
+	"playlists": ViewPlaylists,
+	"library":   ViewLibrary,
+	"queue":     ViewQueue,
+	"charts":    ViewExplore,
+	"uploads":   ViewUploads,
+}
 
 // Model is the main application model
 type Model struct {
-	Api           *api.YouTubeMusicAPI
-	Player        *player.Player
-	TrackList     list.Model
-	PlaylistList  list.Model
-	SearchInput   textinput.Model
-	Progress      progress.Model
-	Spinner       spinner.Model
-	CurrentTrack  api.Track
-	Width         int
-	Height        int
-	SearchMode    bool
-	LoginMode     bool
-	ResetMode     bool
-	IsLoading     bool
-	ErrorMsg      string
-	DebugMode     bool
-	SearchResults int           // Number of search results
-	Playlists     []api.Playlist // User playlists
-	ViewMode      ViewMode       // Current view mode
-	ActiveList    *list.Model    // Pointer to the currently active list
+	Api             *api.YouTubeMusicAPI
+	Player          *player.Player
+	TrackList       list.Model
+	PlaylistList    list.Model
+	QueueList       list.Model
+	LibraryList     list.Model
+	AlbumList       list.Model
+	ArtistList      list.Model
+	PickerList      list.Model // Playlists offered by the add-to-playlist picker
+	HomeList        list.Model
+	ExploreList     list.Model
+	EpisodeList     list.Model // Episodes of the podcast shown in the podcast view
+	UploadsList     list.Model
+	LyricsView      viewport.Model
+	SearchInput     textinput.Model
+	LoginInput      textinput.Model // Cookie paste field for the login view
+	PromptInput     textinput.Model // Text field of the open prompt
+	Progress        progress.Model
+	Spinner         spinner.Model
+	CurrentTrack    api.Track
+	Width           int
+	Height          int
+	SearchMode      bool
+	SearchPodcasts  bool // Search for podcasts and episodes instead of songs
+	LoginMode       bool
+	ResetMode       bool
+	IsLoading       bool
+	progressPolling bool                           // Whether a progress poll is scheduled, so only one runs at a time
+	Status          *notification                  // Notification in the status line, nil once dismissed
+	Notifications   []notification                 // Recent notifications, oldest first
+	notifySeq       int                            // ID of the latest notification
+	DebugMode       bool
+	SearchResults   int                            // Number of search results
+	Playlists       []api.Playlist                 // User playlists
+	ViewMode        ViewMode                       // Current view mode
+	ActiveList      *list.Model                    // Pointer to the currently active list
+	HomeShelves     []api.Shelf                    // Recommendation shelves of the home feed
+	HomeShelf       int                            // Index of the shelf shown in the home view
+	ExploreShelves  []api.Shelf                    // New releases and chart shelves
+	ExploreShelf    int                            // Index of the shelf shown in the explore view
+	ChartsCountry   string                         // Country code of the charts, "ZZ" for global
+	UploadShelves   []api.Shelf                    // Uploaded songs, albums and artists
+	UploadShelf     int                            // Index of the tab shown in the uploads view
+	LibrarySection  LibrarySection                 // Section shown in the library view
+	LibraryItems    map[LibrarySection][]list.Item // Loaded library sections
+	CurrentAlbum    *api.Album                     // Album shown in the album view
+	CurrentArtist   *api.Artist                    // Artist shown in the artist view
+	CurrentPodcast  *api.Podcast                   // Podcast shown in the podcast view
+	ArtistSection   ArtistSection                  // Section shown in the artist view
+	ViewHistory     []viewState                    // Views to return to with Esc, oldest first
+	Tab             int                            // Tab of the tab bar the current view belongs to
+	Lyrics          *api.Lyrics                    // Lyrics shown in the lyrics pane
+	Plays           []api.Play                     // Local listening history shown in the stats view, nil until read
+	StatsPeriod     stats.Period                   // Period shown in the stats view
+	StatsView       viewport.Model
+	LogLines        []string                       // Lines read for the log viewer, nil until read
+	LogsLevel       logs.Level                     // Lowest level the log viewer shows
+	LogsView        viewport.Model
+	logsSeq         int                            // Opening of the log viewer, so reads for an earlier one are dropped
+	Prompt          promptKind                     // Prompt currently open, if any
+	PromptTarget    api.Playlist                   // Playlist the open prompt acts on
+	PromptTrack     api.Track                      // Track the open prompt acts on
+	PromptTracks    []api.Track                    // Tracks the playlist picker adds
+	Selection       *selection                     // Tracks marked in select mode
+	Likes           *likes                         // Liked songs, marked in the lists
+	PlayingRow      *playingRow                    // Playing track, marked in the lists
+	VisualizerOn    bool                           // Whether the now playing view shows the visualizer
+	visFrames       <-chan player.VisualizerFrame  // Levels mpv measures, nil while it isn't measuring
+	visWaiting      bool                           // Whether a command is waiting for the next levels
+	visLevels       []float64                      // Latest levels, drawn in the now playing view
+	visLate         int                            // Frames in a row drawn too late
+	progressArea    progressArea                   // Where the progress bar was last drawn
+	scrubbing       bool                           // Whether the progress bar is being dragged along
+	scrubPos        int                            // Point of the track the drag would seek to, in seconds
+	OpenPlaylist    *api.Playlist                  // Playlist shown in the track list, nil for other results
+	Tracking        *api.PlaybackSession           // Play being reported to the account's history
+	Playing         *api.Play                      // Play being timed for the local history
+	LoadingPlaylist *api.Playlist                  // Playlist whose remaining pages are still streaming in
+	Preloading      string                         // Video ID of the next track whose stream is being resolved for gapless playback
+	AutoplayAhead   string                         // Video ID of the last queued track whose related tracks were fetched before it ended
+	SeekStep        int                            // Seconds to seek with ←/→
+	SeekStepLong    int                            // Seconds to seek with ,/.
+	Keys            keyBindings                    // Keys rebound in the config file
+	Config          *config.Config                 // Settings loaded at startup
+	CrashReport     string                         // Where the report of a crash was saved
+	CrashErr        error                          // Why the report of a crash couldn't be saved
+	ShowHelp        bool                           // Whether the help overlay covers the view
+	Compact         bool                           // Whether the compact player bar was switched on with its key
+	Menu            *menu                          // Popup menu currently open, nil if none
+	Info            *trackInfo                     // Track info popup currently open, nil if none
+	Onboarding      *onboarding                    // First-run setup, nil once done or when not needed
+	ShowMessages    bool                           // Whether the message log covers the view
+	Media           *media.Controls                // System media controls, nil if unavailable or disabled
+	Hotkeys         *media.Controls                // Global hotkeys, nil unless enabled and available
+	Art             *art.Cache                     // Album art downloaded for the media controls
+	artKey          string                         // Artwork of the playing track, as named by art.Key
+	artPath         string                         // Downloaded file of artKey, empty until it arrives
+	Downloads       *download.Index                // Tracks saved for offline playback
+	Offline         bool                           // Whether only downloads are searched and played
+	SetupDeclined   bool                           // Whether the offer to install ytmusicapi was turned down
+	SessionChecking bool                           // Whether the login is being checked periodically
+	SessionExpired  bool                           // Whether the login screen is shown because the session expired
+	ctx             context.Context                // Cancelled at shutdown, stopping every request in flight
+	cancel          context.CancelFunc
+	searchCtx       context.Context                // Context the search in flight and its further pages are fetched with
+	cancelSearch    context.CancelFunc             // Cancels the search in flight, if any
+	searchQuery     string                         // Query of the search the track list shows
+	listMore        string                         // Continuation of the track list's next page, empty once it's all loaded
+	loadingMore     bool                           // Whether the track list's next page is being fetched
+	playlistCtx     context.Context                // Context the pages of LoadingPlaylist are fetched with
+	cancelPlaylist  context.CancelFunc             // Cancels loading LoadingPlaylist, if any
+	enrich          *enrichment                    // Looks up details missing from OpenPlaylist's tracks, nil if none are
+	Missing         []player.Dependency            // mpv or yt-dlp, when they couldn't be found
+	configModTime   time.Time                      // When the config file applied was written, to reload it on change
+	loginModTime    time.Time                      // When the login in use was saved, to pick up ytmusic login and logout
+}
+
+// qualityBitrates describes the bitrate of each stream quality tier
+var qualityBitrates = map[api.StreamQuality]string{
+	api.QualityLow:    "~48 kbps",
+	api.QualityMedium: "~128 kbps",
+	api.QualityHigh:   "up to ~256 kbps",
 }
 
 // InitialModel creates the initial application model
-func InitialModel(debugMode bool) *Model {
+func InitialModel(debugMode bool, cfg *config.Config, cfgErr error) *Model {
 	// Initialize API
 	ytApi := api.NewYouTubeMusicAPI(debugMode)
+	ytApi.SetStreamPreferences(api.StreamQuality(cfg.Audio.Quality), api.StreamCodec(cfg.Audio.Codec))
+	ytApi.SetMaxBitrate(cfg.Audio.MaxBitrate())
+	ytApi.SetMaxRate(cfg.Audio.MaxRate)
+	ytApi.SetLocale(cfg.Locale.Language, cfg.Locale.Region)
+	ytApi.SetCacheSize(cfg.Cache.SizeMB)
+	ytApi.SetBackends(cfg.Backends)
 	
-	// Initialize list with custom delegate for better track display
-	trackDelegate := list.NewDefaultDelegate()
+	theme := newTheme(cfg.ThemeColors())
+	applyTheme(theme)
 	
-	// Customize the delegate styles for better visual appearance
-	trackDelegate.Styles.NormalTitle = trackDelegate.Styles.NormalTitle.
-		Foreground(lipgloss.Color("#FFFFFF")).
-		Bold(true)
-		
-	trackDelegate.Styles.NormalDesc = trackDelegate.Styles.NormalDesc.
-		Foreground(lipgloss.Color("#AAAAAA"))
+	// Tracks marked in select mode, liked or playing, which the delegates of the browse lists draw
+	marks := newSelection()
+	liked := newLikes()
+	playing := &playingRow{}
 	
-	trackDelegate.Styles.SelectedTitle = trackDelegate.Styles.SelectedTitle.
-		Foreground(lipgloss.Color("#000000")).
-		Background(lipgloss.Color("#ff0000")).
-		Bold(true)
-	
-	trackDelegate.Styles.SelectedDesc = trackDelegate.Styles.SelectedDesc.
-		Foreground(lipgloss.Color("#000000")).
-		Background(lipgloss.Color("#ff0000"))
+	// Initialize list with custom delegate for better track display
+	trackDelegate := list.NewDefaultDelegate()
+	trackDelegate.Styles = theme.delegateStyles()
 	
 	// Initialize track list with default dimensions (will be updated on window size)
-	trackList := list.New([]list.Item{}, trackDelegate, 80, 20)
+	trackList := list.New([]list.Item{}, markDelegate{trackDelegate, marks, liked, playing}, 80, 20)
 	trackList.Title = "YouTube Music - Tracks"
 	trackList.SetShowTitle(true)
 	trackList.SetShowHelp(false)
@@ -137,29 +224,187 @@ func InitialModel(debugMode bool) *Model {
 	playlistList.SetFilteringEnabled(false)
 	playlistList.Styles.Title = titleStyle
 	
+	// Initialize queue list, reusing the track styling
+	queueDelegate := list.NewDefaultDelegate()
+	queueDelegate.Styles = trackDelegate.Styles
+	
+	queueList := list.New([]list.Item{}, queueDelegate, 80, 20)
+	queueList.Title = "YouTube Music - Queue"
+	queueList.SetShowTitle(true)
+	queueList.SetShowHelp(false)
+	queueList.SetShowStatusBar(false)
+	queueList.SetFilteringEnabled(false)
+	queueList.Styles.Title = titleStyle
+	
+	// Initialize library list, reusing the track styling
+	libraryDelegate := list.NewDefaultDelegate()
+	libraryDelegate.Styles = trackDelegate.Styles
+	
+	libraryList := list.New([]list.Item{}, markDelegate{libraryDelegate, marks, liked, playing}, 80, 20)
+	libraryList.Title = "YouTube Music - Library"
+	libraryList.SetShowTitle(true)
+	libraryList.SetShowHelp(false)
+	libraryList.SetShowStatusBar(false)
+	libraryList.SetFilteringEnabled(false)
+	libraryList.Styles.Title = titleStyle
+	
+	// Initialize album list, reusing the track styling
+	albumDelegate := list.NewDefaultDelegate()
+	albumDelegate.Styles = trackDelegate.Styles
+	
+	albumList := list.New([]list.Item{}, markDelegate{albumDelegate, marks, liked, playing}, 80, 20)
+	albumList.Title = "YouTube Music - Album"
+	albumList.SetShowTitle(true)
+	albumList.SetShowHelp(false)
+	albumList.SetShowStatusBar(false)
+	albumList.SetFilteringEnabled(false)
+	albumList.Styles.Title = titleStyle
+	
+	// Initialize artist list, reusing the track styling
+	artistDelegate := list.NewDefaultDelegate()
+	artistDelegate.Styles = trackDelegate.Styles
+	
+	artistList := list.New([]list.Item{}, markDelegate{artistDelegate, marks, liked, playing}, 80, 20)
+	artistList.Title = "YouTube Music - Artist"
+	artistList.SetShowTitle(true)
+	artistList.SetShowHelp(false)
+	artistList.SetShowStatusBar(false)
+	artistList.SetFilteringEnabled(false)
+	artistList.Styles.Title = titleStyle
+	
+	// Initialize home list, reusing the track styling
+	homeDelegate := list.NewDefaultDelegate()
+	homeDelegate.Styles = trackDelegate.Styles
+	
+	homeList := list.New([]list.Item{}, markDelegate{homeDelegate, marks, liked, playing}, 80, 20)
+	homeList.Title = "YouTube Music - Home"
+	homeList.SetShowTitle(true)
+	homeList.SetShowHelp(false)
+	homeList.SetShowStatusBar(false)
+	homeList.SetFilteringEnabled(false)
+	homeList.Styles.Title = titleStyle
+	
+	// Initialize explore list, reusing the track styling
+	exploreDelegate := list.NewDefaultDelegate()
+	exploreDelegate.Styles = trackDelegate.Styles
+	
+	exploreList := list.New([]list.Item{}, markDelegate{exploreDelegate, marks, liked, playing}, 80, 20)
+	exploreList.Title = "YouTube Music - Charts"
+	exploreList.SetShowTitle(true)
+	exploreList.SetShowHelp(false)
+	exploreList.SetShowStatusBar(false)
+	exploreList.SetFilteringEnabled(false)
+	exploreList.Styles.Title = titleStyle
+	
+	// Initialize episode list, reusing the track styling
+	episodeDelegate := list.NewDefaultDelegate()
+	episodeDelegate.Styles = trackDelegate.Styles
+	
+	episodeList := list.New([]list.Item{}, markDelegate{episodeDelegate, marks, liked, playing}, 80, 20)
+	episodeList.Title = "YouTube Music - Podcast"
+	episodeList.SetShowTitle(true)
+	episodeList.SetShowHelp(false)
+	episodeList.SetShowStatusBar(false)
+	episodeList.SetFilteringEnabled(false)
+	episodeList.Styles.Title = titleStyle
+	
+	// Initialize uploads list, reusing the track styling
+	uploadsDelegate := list.NewDefaultDelegate()
+	uploadsDelegate.Styles = trackDelegate.Styles
+	
+	uploadsList := list.New([]list.Item{}, markDelegate{uploadsDelegate, marks, liked, playing}, 80, 20)
+	uploadsList.Title = "YouTube Music - Uploads"
+	uploadsList.SetShowTitle(true)
+	uploadsList.SetShowHelp(false)
+	uploadsList.SetShowStatusBar(false)
+	uploadsList.SetFilteringEnabled(false)
+	uploadsList.Styles.Title = titleStyle
+	
+	// Initialize the add-to-playlist picker, reusing the track styling
+	pickerDelegate := list.NewDefaultDelegate()
+	pickerDelegate.Styles = trackDelegate.Styles
+	
+	pickerList := list.New([]list.Item{}, pickerDelegate, 80, 20)
+	pickerList.SetShowTitle(false)
+	pickerList.SetShowHelp(false)
+	pickerList.SetShowStatusBar(false)
+	pickerList.SetFilteringEnabled(false)
+	
 	// Search input
 	ti := textinput.New()
 	ti.Placeholder = "Search for music..."
 	ti.CharLimit = 50
 	ti.Width = 30
 	
+	// Login cookie input
+	li := textinput.New()
+	li.Placeholder = "Paste your Cookie header or __Secure-3PSID value..."
+	li.EchoMode = textinput.EchoPassword
+	li.EchoCharacter = '•'
+	li.CharLimit = 16384 // Whole Cookie headers run to several kilobytes
+	li.Width = 50
+	
+	// Prompt input for playlist names
+	pi := textinput.New()
+	pi.Placeholder = "Playlist name..."
+	pi.CharLimit = 150
+	pi.Width = 40
+	
 	// Progress bar
-	p := progress.New(progress.WithDefaultGradient())
+	p := progress.New(theme.progressOption())
 	p.Width = 70 // Default width, will be updated
 	
 	// Spinner
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	
-	// Player with debug mode
+	// Player with debug mode, restoring the queue from the last session
 	musicPlayer := player.NewPlayer(debugMode)
+	musicPlayer.MPVPath = cfg.MPV.Path
+	musicPlayer.MPVArgs = cfg.MPV.Args
+	musicPlayer.YtdlFormat = cfg.MPV.YtdlFormat
+	musicPlayer.MaxRate = cfg.Audio.MaxRate
+	musicPlayer.Durations = ytApi
+	musicPlayer.Queue.SmartShuffle = cfg.Shuffle == "smart"
+	musicPlayer.Queue.SkipDuplicates = cfg.SkipDuplicates
 	
+	downloads, err := download.LoadIndex()
+	if err != nil {
+		musicPlayer.LogDebug("Error loading downloads: %v", err)
+	}
+	musicPlayer.Downloads = downloads
+	if err := musicPlayer.RestoreState(); err != nil {
+		musicPlayer.LogDebug("Error restoring queue: %v", err)
+	}
+	
+	ctx, cancel := context.WithCancel(context.Background())
 	m := &Model{
+		ctx:           ctx,
+		cancel:        cancel,
 		Api:           ytApi,
 		Player:        musicPlayer,
 		TrackList:     trackList,
 		PlaylistList:  playlistList,
+		QueueList:     queueList,
+		LibraryList:   libraryList,
+		AlbumList:     albumList,
+		ArtistList:    artistList,
+		PickerList:    pickerList,
+		HomeList:      homeList,
+		ExploreList:   exploreList,
+		EpisodeList:   episodeList,
+		UploadsList:   uploadsList,
+		Selection:     marks,
+		Likes:         liked,
+		PlayingRow:    playing,
+		ChartsCountry: api.GlobalCharts,
+		LyricsView:    viewport.New(80, 20),
+		StatsView:     viewport.New(80, 20),
+		LogsView:      viewport.New(80, 20),
+		LibraryItems:  make(map[LibrarySection][]list.Item),
 		SearchInput:   ti,
+		LoginInput:    li,
+		PromptInput:   pi,
 		Progress:      p,
 		Spinner:       s,
 		SearchMode:    false,
@@ -168,33 +413,129 @@ func InitialModel(debugMode bool) *Model {
 		IsLoading:     false,
 		DebugMode:     debugMode,
 		SearchResults: 0,
-		ViewMode:      ViewTracks,
-		Width:         80,  // Default dimensions
+		ViewMode:      ViewHome,
+		SeekStep:      cfg.SeekStep,
+		SeekStepLong:  cfg.SeekStepLong,
+		Keys:          newKeyBindings(cfg),
+		Config:        cfg,
+		configModTime: configModTime(),
+		loginModTime:  ytApi.CredentialsModTime(),
+		Downloads:     downloads,
+		Width:         80, // Default dimensions
 		Height:        24,
 	}
 	
-	// Set the active list to tracks by default
-	m.ActiveList = &m.TrackList
+	// Start on the configured view, the home feed by default
+	m.setView(startViews[cfg.DefaultView])
+	if tab := tabForView(m.ViewMode); tab >= 0 {
+		m.Tab = tab
+	} else {
+		// Charts and uploads open over the home tab
+		m.ViewHistory = []viewState{{mode: ViewHome}}
+	}
+	switch cfg.DefaultView {
+	case "search":
+		m.SearchMode = true
+		m.SearchInput.Focus()
+	case "queue":
+		m.refreshQueueList()
+		if musicPlayer.Queue.CurrentIndex >= 0 {
+			m.QueueList.Select(musicPlayer.Queue.CurrentIndex)
+		}
+	}
 	
-	// Set up the next track callback
-	m.Player.SetNextCallback(func() {
-		// We need to send a message to the Bubble Tea program
-		// This is done via a channel or using the Send method on the Program
-		// For simplicity, we'll just automatically try to play the next track
-		if err := m.Player.PlayNext(); err != nil {
-			m.ErrorMsg = "Error playing next track: " + err.Error()
+	if track := musicPlayer.Queue.GetCurrentTrack(); track != nil {
+		m.CurrentTrack = *track
+		
+		// Offer to continue the track that was cut off at the last exit
+		if musicPlayer.ResumePos > 0 {
+			m.openPrompt(promptResume, "")
 		}
-	})
+	}
+	
+	if cfg.MediaControls {
+		controls, err := media.Start(musicPlayer.LogDebug)
+		if err != nil {
+			musicPlayer.LogDebug("Media controls unavailable: %v", err)
+		}
+		m.Media = controls
+		m.Art = art.New(art.Dir(), cfg.Cache.ArtSizeMB, musicPlayer.LogDebug)
+	}
+	
+	if cfg.Hotkeys.Enabled {
+		hotkeys, err := media.StartHotkeys(map[media.Command]string{
+			media.Toggle:   cfg.Hotkeys.Toggle,
+			media.Next:     cfg.Hotkeys.Next,
+			media.Previous: cfg.Hotkeys.Previous,
+		}, musicPlayer.LogDebug)
+		if err != nil {
+			// Unlike the media controls they were asked for, so they're missed
+			m.showWarning("Global hotkeys unavailable: " + err.Error())
+		}
+		m.Hotkeys = hotkeys
+	}
+	
+	if cfg.Visualizer && slowTerminal() {
+		musicPlayer.LogDebug("Leaving the visualizer off, as the terminal redraws too slowly")
+	} else {
+		m.VisualizerOn = cfg.Visualizer
+	}
+	
+	if cfg.Offline {
+		m.Offline = true
+		m.LibrarySection = LibraryDownloads
+	}
+	
+	if cfgErr != nil {
+		m.showWarning("Config: " + cfgErr.Error())
+	}
+	
+	if needsOnboarding(cfg, ytApi) {
+		m.Onboarding = &onboarding{}
+	}
 	
 	return m
 }
 
+// Shutdown stops playback and saves the queue for the next session
+func (m *Model) Shutdown() {
+	m.cancel()
+	if err := m.Api.RecordPlay(m.Playing, m.Player.CurrentPos); err != nil {
+		m.Player.LogDebug("Error recording play: %v", err)
+	}
+	m.Player.Stop()
+	m.Player.StopVisualizer()
+	m.Media.Close()
+	m.Hotkeys.Close()
+	m.Api.Close()
+	if err := m.Player.SaveState(); err != nil {
+		m.Player.LogDebug("Error saving queue: %v", err)
+	}
+}
+
 // Init initializes the model
 func (m *Model) Init() tea.Cmd {
-	return tea.Batch(
+	// The setup looks for the player along with the Python bridge
+	depsCmd := CheckPlayerDepsCmd(m.Config.MPV.Path)
+	if m.Onboarding != nil {
+		depsCmd = CheckDependenciesCmd(m.Api, m.Config.MPV.Path)
+	}
+	var mouse tea.Cmd
+	if m.Config.Mouse {
+		mouse = mouseCmd(true)
+	}
+	return catchPanics(tea.Batch(
 		m.Spinner.Tick,
-		CheckLoginCmd(m.Api),
-	)
+		mouse,
+		m.checkStartupCmd(),
+		WaitForPlayerEventCmd(m.Player),
+		WaitForMediaCommandCmd(m.Media),
+		WaitForMediaCommandCmd(m.Hotkeys),
+		m.dismissCmd(),
+		depsCmd,
+		WatchConfigCmd(),
+		WatchCredentialsCmd(m.Api),
+	))
 }
 
 // Messages
@@ -202,9 +543,16 @@ type loginStatusMsg struct {
 	isLoggedIn bool
 }
 
+type loginCompletedMsg struct {
+	err error
+}
+
 type searchResultMsg struct {
-	tracks []api.Track
-	err    error
+	query        string
+	tracks       []api.Track
+	continuation string // Token for the next page, empty on the last page
+	more         bool   // A further page of results already shown, not a new search
+	err          error
 }
 
 type playlistsResultMsg struct {
@@ -213,17 +561,129 @@ type playlistsResultMsg struct {
 }
 
 type playlistTracksResultMsg struct {
+	playlistID   string
+	tracks       []api.Track
+	continuation string // Token for the next page, empty on the last page
+	more         bool   // Whether this is a continuation page rather than the first
+	err          error
+}
+
+type librarySectionMsg struct {
+	section LibrarySection
+	items   []list.Item
+	err     error
+}
+
+type albumResultMsg struct {
+	album *api.Album
+	err   error
+}
+
+type artistResultMsg struct {
+	artist *api.Artist
+	err    error
+}
+
+type radioResultMsg struct {
+	tracks []api.Track
+	err    error
+}
+
+type autoplayResultMsg struct {
 	tracks []api.Track
+	ahead  bool // Fetched before the last track ended, so only the queue grows
 	err    error
 }
 
+type playsMsg struct {
+	plays []api.Play
+	err   error
+}
+
+type lyricsResultMsg struct {
+	videoID string
+	lyrics  *api.Lyrics
+	err     error
+}
+
+type playlistEditedMsg struct {
+	status string // Shown on success
+	err    error
+}
+
+type playlistExportedMsg struct {
+	playlist api.Playlist
+	path     string
+	count    int // Tracks written
+	err      error
+}
+
+type homeResultMsg struct {
+	shelves []api.Shelf
+	err     error
+}
+
+type podcastSearchMsg struct {
+	items []list.Item
+	err   error
+}
+
+type podcastResultMsg struct {
+	podcast *api.Podcast
+	err     error
+}
+
+type uploadsResultMsg struct {
+	shelves []api.Shelf
+	err     error
+}
+
+type exploreResultMsg struct {
+	country string
+	shelves []api.Shelf
+	err     error
+}
+
+type playlistItemRemovedMsg struct {
+	playlistID string
+	track      api.Track
+	err        error
+}
+
+type playbackStartedMsg struct {
+	videoID string
+	session *api.PlaybackSession
+	err     error
+}
+
+type preloadMsg struct {
+	track    api.Track
+	url      string
+	duration int // Duration reported by the extractor, 0 if unknown
+	err      error
+}
+
 type streamURLMsg struct {
-	url string
-	err error
+	url      string
+	duration int // Duration reported by the extractor, 0 if unknown
+	err      error
 }
 
 type progressMsg struct{}
 
+// progressInterval is how often the player is polled for its position
+const progressInterval = 500 * time.Millisecond
+
+type playerEventMsg struct {
+	event player.PlayerEvent
+}
+
+// statusMsg reports the outcome of a background action that needs no other handling
+type statusMsg struct {
+	level notifyLevel
+	text  string
+}
+
 type cookieResetMsg struct {
 	success bool
 	err     error
@@ -236,42 +696,138 @@ func CheckLoginCmd(api *api.YouTubeMusicAPI) tea.Cmd {
 	}
 }
 
-// SearchCmd performs a search
-func SearchCmd(api *api.YouTubeMusicAPI, query string) tea.Cmd {
+// LoginCmd logs in with a pasted cookie value
+func LoginCmd(api *api.YouTubeMusicAPI, cookie string) tea.Cmd {
+	return func() tea.Msg {
+		return loginCompletedMsg{err: api.ManualLogin(cookie)}
+	}
+}
+
+// OpenBrowserCmd opens a URL in the system browser without blocking the UI
+func OpenBrowserCmd(url string) tea.Cmd {
 	return func() tea.Msg {
-		tracks, err := api.Search(query)
-		return searchResultMsg{tracks: tracks, err: err}
+		utils.OpenBrowser(url)
+		return nil
+	}
+}
+
+// SearchCmd performs a search, fetching the first page of results
+func SearchCmd(ctx context.Context, api *api.YouTubeMusicAPI, query string) tea.Cmd {
+	return func() tea.Msg {
+		tracks, continuation, err := api.SearchPage(ctx, query, "")
+		if ctx.Err() != nil {
+			// A newer search replaced this one
+			return searchResultMsg{err: ctx.Err()}
+		}
+		return searchResultMsg{query: query, tracks: tracks, continuation: continuation, err: err}
+	}
+}
+
+// SearchMoreCmd fetches the next page of search results
+func SearchMoreCmd(ctx context.Context, api *api.YouTubeMusicAPI, query, continuation string) tea.Cmd {
+	return func() tea.Msg {
+		tracks, next, err := api.SearchPage(ctx, query, continuation)
+		if ctx.Err() != nil {
+			return searchResultMsg{more: true, err: ctx.Err()}
+		}
+		return searchResultMsg{query: query, tracks: tracks, continuation: next, more: true, err: err}
 	}
 }
 
 // GetPlaylistsCmd fetches the user's playlists
-func GetPlaylistsCmd(api *api.YouTubeMusicAPI) tea.Cmd {
+func GetPlaylistsCmd(ctx context.Context, api *api.YouTubeMusicAPI) tea.Cmd {
 	return func() tea.Msg {
-		playlists, err := api.GetUserPlaylists()
+		playlists, err := api.GetUserPlaylists(ctx)
 		return playlistsResultMsg{playlists: playlists, err: err}
 	}
 }
 
-// GetPlaylistTracksCmd fetches tracks from a playlist
-func GetPlaylistTracksCmd(api *api.YouTubeMusicAPI, playlistID string) tea.Cmd {
+// GetPlaylistTracksCmd fetches the first page of tracks from a playlist
+func GetPlaylistTracksCmd(ctx context.Context, api *api.YouTubeMusicAPI, playlistID string) tea.Cmd {
 	return func() tea.Msg {
-		tracks, err := api.GetPlaylistTracks(playlistID)
-		return playlistTracksResultMsg{tracks: tracks, err: err}
+		tracks, continuation, err := api.GetPlaylistTracksPage(ctx, playlistID, "")
+		if ctx.Err() != nil {
+			return playlistTracksResultMsg{playlistID: playlistID, err: ctx.Err()}
+		}
+		return playlistTracksResultMsg{playlistID: playlistID, tracks: tracks, continuation: continuation, err: err}
+	}
+}
+
+// GetPlaylistContinuationCmd fetches the next page of tracks from a playlist
+func GetPlaylistContinuationCmd(ctx context.Context, api *api.YouTubeMusicAPI, playlistID, continuation string) tea.Cmd {
+	return func() tea.Msg {
+		tracks, next, err := api.GetPlaylistTracksPage(ctx, playlistID, continuation)
+		if ctx.Err() != nil {
+			return playlistTracksResultMsg{playlistID: playlistID, more: true, err: ctx.Err()}
+		}
+		return playlistTracksResultMsg{playlistID: playlistID, tracks: tracks, continuation: next, more: true, err: err}
+	}
+}
+
+// GetAlbumCmd fetches an album and its tracks
+func GetAlbumCmd(ctx context.Context, api *api.YouTubeMusicAPI, browseID string) tea.Cmd {
+	return func() tea.Msg {
+		album, err := api.GetAlbum(ctx, browseID)
+		return albumResultMsg{album: album, err: err}
+	}
+}
+
+// GetArtistCmd fetches an artist page
+func GetArtistCmd(ctx context.Context, api *api.YouTubeMusicAPI, channelID string) tea.Cmd {
+	return func() tea.Msg {
+		artist, err := api.GetArtist(ctx, channelID)
+		return artistResultMsg{artist: artist, err: err}
+	}
+}
+
+// GetArtistRadioCmd fetches the tracks of an artist radio
+func GetArtistRadioCmd(ctx context.Context, api *api.YouTubeMusicAPI, radioID string) tea.Cmd {
+	return func() tea.Msg {
+		tracks, err := api.GetArtistRadio(ctx, radioID)
+		return radioResultMsg{tracks: tracks, err: err}
+	}
+}
+
+// GetRadioCmd fetches the radio of related tracks for a track
+func GetRadioCmd(ctx context.Context, api *api.YouTubeMusicAPI, videoID string) tea.Cmd {
+	return func() tea.Msg {
+		tracks, err := api.GetWatchPlaylist(ctx, videoID)
+		return radioResultMsg{tracks: tracks, err: err}
+	}
+}
+
+// GetAutoplayCmd fetches related tracks to continue after the queue runs dry,
+// or ahead of time, while its last track is still playing
+func GetAutoplayCmd(ctx context.Context, api *api.YouTubeMusicAPI, videoID string, ahead bool) tea.Cmd {
+	return func() tea.Msg {
+		tracks, err := api.GetWatchPlaylist(ctx, videoID)
+		return autoplayResultMsg{tracks: tracks, ahead: ahead, err: err}
+	}
+}
+
+// GetLyricsCmd fetches the lyrics of a track
+func GetLyricsCmd(ctx context.Context, api *api.YouTubeMusicAPI, videoID string) tea.Cmd {
+	return func() tea.Msg {
+		lyrics, err := api.GetLyrics(ctx, videoID)
+		return lyricsResultMsg{videoID: videoID, lyrics: lyrics, err: err}
 	}
 }
 
 // GetStreamURLCmd gets a stream URL for a track
-func GetStreamURLCmd(api *api.YouTubeMusicAPI, trackID string) tea.Cmd {
+func GetStreamURLCmd(ctx context.Context, api *api.YouTubeMusicAPI, trackID string) tea.Cmd {
 	return func() tea.Msg {
-		url, err := api.GetStreamURL(trackID)
-		return streamURLMsg{url: url, err: err}
+		info, err := api.GetStreamInfo(ctx, trackID)
+		if err != nil {
+			return streamURLMsg{err: err}
+		}
+		return streamURLMsg{url: info.URL, duration: info.Duration}
 	}
 }
 
-// ResetCookiesCmd resets cookies
+// ResetCookiesCmd logs out, removing the saved cookies and ytmusicapi credentials
 func ResetCookiesCmd(api *api.YouTubeMusicAPI) tea.Cmd {
 	return func() tea.Msg {
-		err := api.ResetCookies()
+		err := api.Logout()
 		return cookieResetMsg{
 			success: err == nil,
 			err:     err,
@@ -279,9 +835,20 @@ func ResetCookiesCmd(api *api.YouTubeMusicAPI) tea.Cmd {
 	}
 }
 
-// ProgressTickCmd ticks the progress bar
-func ProgressTickCmd() tea.Cmd {
-	return tea.Tick(1*time.Second, func(t time.Time) tea.Msg {
+// WaitForPlayerEventCmd waits for the next asynchronous player event
+func WaitForPlayerEventCmd(p *player.Player) tea.Cmd {
+	return func() tea.Msg {
+		return playerEventMsg{event: <-p.Events()}
+	}
+}
+
+// pollProgress schedules the next poll of the player's position unless one is already pending
+func (m *Model) pollProgress() tea.Cmd {
+	if m.progressPolling {
+		return nil
+	}
+	m.progressPolling = true
+	return tea.Tick(progressInterval, func(t time.Time) tea.Msg {
 		return progressMsg{}
 	})
 }
@@ -0,0 +1,42 @@
+package ui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// loadMoreMargin is how close to the end of the track list the cursor gets before its next page is fetched
+const loadMoreMargin = 3
+
+// loadMore fetches the next page of the search results or playlist in the track list
+// once the cursor nears its end
+func (m *Model) loadMore() tea.Cmd {
+	if m.listMore == "" || m.loadingMore || m.ViewMode != ViewTracks {
+		return nil
+	}
+	// Wait for a new search or playlist that will replace the list
+	if m.cancelSearch != nil || m.LoadingPlaylist != nil {
+		return nil
+	}
+	if m.TrackList.Index() < len(m.TrackList.Items())-1-loadMoreMargin {
+		return nil
+	}
+
+	continuation := m.listMore
+	if playlist := m.OpenPlaylist; playlist != nil {
+		ctx := m.startPlaylistLoad()
+		m.LoadingPlaylist = playlist
+		m.loadingMore = true
+		return GetPlaylistContinuationCmd(ctx, m.Api, playlist.ID, continuation)
+	}
+	ctx := m.startSearch()
+	m.loadingMore = true
+	return SearchMoreCmd(ctx, m.Api, m.searchQuery, continuation)
+}
+
+// renderLoadingMore shows a row under the track list while its next page is fetched
+func renderLoadingMore(m *Model) string {
+	if !m.loadingMore {
+		return ""
+	}
+	return "\n" + resultInfoStyle.Render("  Loading more…")
+}
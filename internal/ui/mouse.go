@@ -0,0 +1,87 @@
+package ui
+
+import (
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// progressArea is where the progress bar was last drawn, for seeking with the mouse
+type progressArea struct {
+	row, col int // Screen cell of the bar's left end
+	width    int // Cells the bar spans, 0 while it isn't shown
+}
+
+// progressCells returns how many cells of a progress bar the bar itself takes, without its percentage
+func progressCells(bar progress.Model) int {
+	if bar.ShowPercentage {
+		return bar.Width - lipgloss.Width(fmt.Sprintf(bar.PercentFormat, 100.0))
+	}
+	return bar.Width
+}
+
+// contains reports whether a screen cell lies on the bar
+func (a progressArea) contains(x, y int) bool {
+	return a.width > 0 && y == a.row && x >= a.col && x < a.col+a.width
+}
+
+// position returns the point of a track, in seconds, at a screen column, the
+// bar's ends for columns past them
+func (a progressArea) position(x, duration int) int {
+	if a.width <= 1 {
+		return 0
+	}
+	offset := x - a.col
+	if offset < 0 {
+		offset = 0
+	} else if offset > a.width-1 {
+		offset = a.width - 1
+	}
+	return offset * duration / (a.width - 1)
+}
+
+// mouseCmd turns reporting mouse clicks and drags on or off
+func mouseCmd(on bool) tea.Cmd {
+	if on {
+		return tea.EnableMouseCellMotion
+	}
+	return tea.DisableMouse
+}
+
+// handleMouse seeks to where the progress bar is clicked. Dragging along it
+// previews the point it would seek to, until the button is let go.
+func (m *Model) handleMouse(msg tea.MouseMsg) {
+	if m.Player.Queue.GetCurrentTrack() == nil || m.Player.Duration <= 0 {
+		m.scrubbing = false
+		return
+	}
+
+	switch msg.Type {
+	case tea.MouseLeft:
+		// Terminals report a drag as the button pressed again where the mouse moved to
+		if !m.scrubbing && !m.progressArea.contains(msg.X, msg.Y) {
+			return
+		}
+		m.scrubbing = true
+		m.scrubPos = m.progressArea.position(msg.X, m.Player.Duration)
+	case tea.MouseRelease:
+		if !m.scrubbing {
+			return
+		}
+		m.scrubbing = false
+		if err := m.Player.SeekTo(m.scrubPos); err != nil {
+			m.showError("Error seeking: " + err.Error())
+		}
+	}
+}
+
+// shownPosition returns the point of the playing track to draw: where a drag
+// along the progress bar would seek to, or where playback is
+func (m *Model) shownPosition() int {
+	if m.scrubbing {
+		return m.scrubPos
+	}
+	return m.Player.CurrentPos
+}
@@ -0,0 +1,227 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/mattn/go-runewidth"
+
+	"ytmusic/internal/api"
+)
+
+// maxViewHistory bounds how many views Esc can go back through
+const maxViewHistory = 20
+
+// viewState is a view left for a drill-down, restored when going back to it
+type viewState struct {
+	mode     ViewMode
+	title    string      // Title of the view's list
+	items    []list.Item // Items of the view's list, nil for views whose list isn't replaced by drilling down
+	cursor   int         // Index of the selected item
+	album    *api.Album
+	artist   *api.Artist
+	section  ArtistSection
+	podcast  *api.Podcast
+	playlist *api.Playlist // Playlist shown in the track list, nil for search results
+	query    string        // Search the track list shows
+	results  int           // Number of tracks in the track list
+	more     string        // Continuation of the track list's next page
+}
+
+// setView switches to a view and its list
+func (m *Model) setView(mode ViewMode) {
+	// Marks belong to the list they were made in
+	m.Selection.clear()
+	m.ViewMode = mode
+	switch mode {
+	case ViewPlaylists:
+		m.ActiveList = &m.PlaylistList
+	case ViewQueue:
+		m.ActiveList = &m.QueueList
+	case ViewLibrary:
+		m.ActiveList = &m.LibraryList
+	case ViewAlbum:
+		m.ActiveList = &m.AlbumList
+	case ViewArtist:
+		m.ActiveList = &m.ArtistList
+	case ViewHome:
+		m.ActiveList = &m.HomeList
+	case ViewExplore:
+		m.ActiveList = &m.ExploreList
+	case ViewPodcast:
+		m.ActiveList = &m.EpisodeList
+	case ViewUploads:
+		m.ActiveList = &m.UploadsList
+	case ViewNowPlaying:
+		// Track actions act on the playing track; the queue takes list keys
+		m.ActiveList = &m.QueueList
+	case ViewLyrics, ViewStats, ViewLogs:
+		// The lyrics pane, stats and logs have no list; keep the previous one for track actions
+	default:
+		m.ViewMode = ViewTracks
+		m.ActiveList = &m.TrackList
+	}
+}
+
+// pushView switches to a drill-down view, remembering the current one and its list for Esc
+func (m *Model) pushView(mode ViewMode) {
+	// A further page still loading would land in a list that's restored without it
+	if m.loadingMore {
+		m.stopSearch()
+		m.stopPlaylistLoad()
+	}
+	m.ViewHistory = append(m.ViewHistory, m.saveView())
+	if len(m.ViewHistory) > maxViewHistory {
+		m.ViewHistory = m.ViewHistory[len(m.ViewHistory)-maxViewHistory:]
+	}
+	m.setView(mode)
+}
+
+// popView returns to the view a drill-down was opened from, with its list and cursor as they were
+func (m *Model) popView() {
+	if len(m.ViewHistory) == 0 {
+		m.setView(tabs[m.Tab].view)
+		return
+	}
+	previous := m.ViewHistory[len(m.ViewHistory)-1]
+	m.ViewHistory = m.ViewHistory[:len(m.ViewHistory)-1]
+	m.restoreView(previous)
+}
+
+// saveView captures the current view for going back to it
+func (m *Model) saveView() viewState {
+	state := viewState{mode: m.ViewMode}
+	if m.ActiveList != nil {
+		state.cursor = m.ActiveList.Index()
+	}
+	switch m.ViewMode {
+	case ViewTracks:
+		state.playlist, state.query, state.results, state.more = m.OpenPlaylist, m.searchQuery, m.SearchResults, m.listMore
+	case ViewAlbum:
+		state.album = m.CurrentAlbum
+	case ViewArtist:
+		state.artist, state.section = m.CurrentArtist, m.ArtistSection
+	case ViewPodcast:
+		state.podcast = m.CurrentPodcast
+	default:
+		return state
+	}
+	// These views' lists are refilled by the next album, artist, podcast or playlist opened
+	state.title = m.ActiveList.Title
+	state.items = m.ActiveList.Items()
+	return state
+}
+
+// restoreView switches back to a saved view
+func (m *Model) restoreView(state viewState) {
+	m.setView(state.mode)
+	switch state.mode {
+	case ViewTracks:
+		m.OpenPlaylist, m.searchQuery, m.SearchResults, m.listMore = state.playlist, state.query, state.results, state.more
+	case ViewAlbum:
+		m.CurrentAlbum = state.album
+	case ViewArtist:
+		m.CurrentArtist, m.ArtistSection = state.artist, state.section
+	case ViewPodcast:
+		m.CurrentPodcast = state.podcast
+	}
+	if state.items != nil {
+		m.ActiveList.Title = state.title
+		m.ActiveList.SetItems(state.items)
+	}
+	if m.ActiveList != nil && state.cursor < len(m.ActiveList.Items()) {
+		m.ActiveList.Select(state.cursor)
+	}
+}
+
+// name names a view for the breadcrumbs
+func (state viewState) name() string {
+	switch state.mode {
+	case ViewTracks:
+		if state.playlist != nil {
+			return state.playlist.PlaylistTitle
+		}
+		if state.query != "" {
+			return "Search: " + state.query
+		}
+		return "Search"
+	case ViewAlbum:
+		if state.album != nil {
+			return state.album.AlbumTitle
+		}
+	case ViewArtist:
+		if state.artist != nil {
+			return state.artist.Name + " (" + state.section.String() + ")"
+		}
+	case ViewPodcast:
+		if state.podcast != nil {
+			return state.podcast.PodcastTitle
+		}
+	case ViewExplore:
+		return "Charts"
+	case ViewUploads:
+		return "Uploads"
+	case ViewLyrics:
+		return "Lyrics"
+	case ViewStats:
+		return "Stats"
+	case ViewLogs:
+		return "Logs"
+	}
+	if tab := tabForView(state.mode); tab >= 0 {
+		return tabs[tab].name
+	}
+	return "?"
+}
+
+// renderBreadcrumbs shows the views Esc goes back through, ending with the current one,
+// or nothing at the top of a tab
+func renderBreadcrumbs(m *Model) string {
+	if len(m.ViewHistory) == 0 {
+		return ""
+	}
+	crumbs := make([]string, 0, len(m.ViewHistory)+1)
+	for _, state := range m.ViewHistory {
+		crumbs = append(crumbs, state.name())
+	}
+	// The oldest steps give way to the current one
+	current := fit(m.saveView().name(), m.contentWidth())
+	trail := fitLeft(strings.Join(crumbs, " › ")+" › ", m.contentWidth()-runewidth.StringWidth(current))
+	return resultInfoStyle.Render(trail) + modeStyle.Render(current)
+}
+
+// targetTrack returns the track that track actions apply to: the playing one in the now playing view,
+// otherwise the one under the cursor
+func (m *Model) targetTrack() (api.Track, bool) {
+	if m.ViewMode == ViewNowPlaying {
+		if current := m.Player.Queue.GetCurrentTrack(); current != nil {
+			return *current, true
+		}
+		return api.Track{}, false
+	}
+	return selectedTrack(m.ActiveList)
+}
+
+// isBrowseView reports whether a view lists tracks and other items that Enter and a/A act on
+func isBrowseView(mode ViewMode) bool {
+	switch mode {
+	case ViewTracks, ViewLibrary, ViewAlbum, ViewArtist, ViewHome, ViewExplore, ViewPodcast, ViewUploads:
+		return true
+	}
+	return false
+}
+
+// openStartView fetches what the start view shows beyond the playlists and home feed loaded at login
+func (m *Model) openStartView() tea.Cmd {
+	switch m.ViewMode {
+	case ViewLibrary:
+		return m.showLibrarySection(m.LibrarySection)
+	case ViewExplore:
+		return m.loadExplore(m.ChartsCountry)
+	case ViewUploads:
+		m.IsLoading = true
+		return GetUploadsCmd(m.ctx, m.Api)
+	}
+	return nil
+}
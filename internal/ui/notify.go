@@ -0,0 +1,158 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"ytmusic/internal/api"
+)
+
+// notifyLevel is how important a notification is
+type notifyLevel int
+
+const (
+	levelInfo notifyLevel = iota
+	levelWarning
+	levelError
+)
+
+// timeout returns how long a notification stays in the status line; errors stay longest
+func (l notifyLevel) timeout() time.Duration {
+	switch l {
+	case levelWarning:
+		return 6 * time.Second
+	case levelError:
+		return 10 * time.Second
+	}
+	return 4 * time.Second
+}
+
+// render styles a notification's text for its level
+func (l notifyLevel) render(text string) string {
+	switch l {
+	case levelWarning:
+		return warningStyle.Render("! " + text)
+	case levelError:
+		return errorStyle.Render("✗ " + text)
+	}
+	return modeStyle.Render(text)
+}
+
+// notificationHistory is how many notifications the message log keeps
+const notificationHistory = 100
+
+// notification is a message shown in the status line and kept in the message log
+type notification struct {
+	id    int
+	level notifyLevel
+	text  string
+	time  time.Time
+}
+
+// dismissMsg clears a notification from the status line once its time is up
+type dismissMsg struct {
+	id int
+}
+
+// notify shows a notification in the status line and adds it to the message log.
+// Update schedules its dismissal.
+func (m *Model) notify(level notifyLevel, text string) {
+	m.notifySeq++
+	n := notification{id: m.notifySeq, level: level, text: text, time: time.Now()}
+	m.Status = &n
+
+	m.Notifications = append(m.Notifications, n)
+	if len(m.Notifications) > notificationHistory {
+		m.Notifications = m.Notifications[len(m.Notifications)-notificationHistory:]
+	}
+}
+
+// showInfo reports progress or a completed action
+func (m *Model) showInfo(text string) {
+	m.notify(levelInfo, text)
+}
+
+// showWarning reports something the user can fix or that needs no fix
+func (m *Model) showWarning(text string) {
+	m.notify(levelWarning, text)
+}
+
+// showError reports a failed action
+func (m *Model) showError(text string) {
+	m.notify(levelError, text)
+}
+
+// showAPIError reports a failed request, with advice on what to do for the
+// errors that have a known cause
+func (m *Model) showAPIError(context string, err error) {
+	resetKey := m.Config.Key("reset_cookie")
+	switch {
+	case canceled(err):
+		// Nobody is waiting for the answer any more
+	case errors.Is(err, api.ErrSessionExpired):
+		m.showError(fmt.Sprintf("%s: your session has expired - press %s to log in again", context, resetKey))
+	case errors.Is(err, api.ErrNotLoggedIn):
+		m.showError(fmt.Sprintf("%s: not logged in - press %s to log in", context, resetKey))
+	case errors.Is(err, api.ErrRateLimited):
+		m.showWarning(context + ": YouTube Music is limiting requests, try again in a minute")
+	case errors.Is(err, api.ErrGeoBlocked):
+		m.showWarning(context + ": not available in your region")
+	case errors.Is(err, api.ErrBackendUnavailable):
+		m.showError(context + ": " + err.Error() + " - install ytmusicapi or log in with a cookie that has SAPISID")
+	default:
+		m.showError(context + ": " + err.Error())
+	}
+}
+
+// clearStatus empties the status line, keeping the message log
+func (m *Model) clearStatus() {
+	m.Status = nil
+}
+
+// dismissCmd clears the current notification once its level's timeout passes
+func (m *Model) dismissCmd() tea.Cmd {
+	if m.Status == nil {
+		return nil
+	}
+	id, timeout := m.Status.id, m.Status.level.timeout()
+	return tea.Tick(timeout, func(time.Time) tea.Msg {
+		return dismissMsg{id: id}
+	})
+}
+
+// renderStatus renders the current notification, or nothing
+func renderStatus(m *Model) string {
+	if m.Status == nil {
+		return ""
+	}
+	// Long messages wrap inside the border rather than pushing it out
+	return lipgloss.NewStyle().Width(m.contentWidth()).Render(m.Status.level.render(m.Status.text)) + "\n\n"
+}
+
+// renderMessageLog renders the most recent notifications that fit the window, newest first
+func renderMessageLog(m *Model) string {
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("YouTube Music - Messages") + "\n\n")
+
+	if len(m.Notifications) == 0 {
+		s.WriteString(resultInfoStyle.Render("No messages yet") + "\n")
+	}
+
+	// Inside the app border and padding, below the title and above the hint
+	rows := m.Height - 10
+	if rows < 5 {
+		rows = 5
+	}
+	for i := len(m.Notifications) - 1; i >= 0 && rows > 0; i, rows = i-1, rows-1 {
+		n := m.Notifications[i]
+		s.WriteString(fmt.Sprintf("%s  %s\n", resultInfoStyle.Render(n.time.Format("15:04:05")), n.level.render(n.text)))
+	}
+
+	s.WriteString("\n" + resultInfoStyle.Render("Press any key to close"))
+	return s.String()
+}
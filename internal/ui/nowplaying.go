@@ -0,0 +1,165 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"ytmusic/internal/api"
+)
+
+// upNextCount is how many upcoming tracks the now playing view lists
+const upNextCount = 5
+
+// renderNowPlaying renders the playing track's details and what plays after it
+func renderNowPlaying(m *Model) string {
+	track := m.Player.Queue.GetCurrentTrack()
+	if track == nil {
+		return titleStyle.Render("YouTube Music - Now Playing") + "\n\n" +
+			resultInfoStyle.Render("Nothing playing. Press Enter on a track to start.")
+	}
+
+	var s strings.Builder
+	s.WriteString(titleStyle.Render("YouTube Music - Now Playing") + "\n\n")
+
+	width := m.contentWidth()
+	explicit := ""
+	if track.Explicit {
+		explicit = " " + resultInfoStyle.Render("[E]")
+	}
+	s.WriteString(playingStyle.Render(fit(track.TrackTitle, width-lipgloss.Width(explicit))) + explicit + "\n")
+	s.WriteString(infoStyle.Render(fit(track.Artist, width)) + "\n")
+
+	var details []string
+	for _, part := range []string{track.Album, track.Year} {
+		if part != "" {
+			details = append(details, part)
+		}
+	}
+	if len(details) > 0 {
+		s.WriteString(resultInfoStyle.Render(fit(strings.Join(details, " • "), width)) + "\n")
+	}
+
+	autoplay := "Off"
+	if m.Player.Queue.Autoplay {
+		autoplay = "On"
+	}
+	s.WriteString("\n" + resultInfoStyle.Render(fmt.Sprintf("Volume %d%%  •  Autoplay %s  •  [y] Lyrics  [g/G] Album/Artist  [m] Menu",
+		m.Player.Volume, autoplay)) + "\n\n")
+
+	if m.VisualizerOn && m.Player.IsPlaying && m.visLevels != nil {
+		s.WriteString(renderVisualizer(m.visLevels, width) + "\n\n")
+	}
+
+	s.WriteString(modeStyle.Render("Up next") + "\n")
+	upcoming := m.Player.Queue.Upcoming(upNextCount)
+	if len(upcoming) == 0 {
+		s.WriteString(resultInfoStyle.Render("End of the queue") + "\n")
+	}
+	for i, next := range upcoming {
+		number := fmt.Sprintf("%d. ", i+1)
+		name, artist := fitPair(next.TrackTitle, next.Artist, width-len(number)-len(" - "))
+		s.WriteString(number + name + " - " + resultInfoStyle.Render(artist) + "\n")
+	}
+
+	return strings.TrimSuffix(s.String(), "\n")
+}
+
+// playingPageMsg carries the playing track looked up for the album or artist it was queued without
+type playingPageMsg struct {
+	track  api.Track
+	artist bool // Whether the artist page was asked for, rather than the album
+	err    error
+}
+
+// openPlaying opens the album or artist page of the playing track, from any view.
+// Tracks queued without one, such as from a radio, are looked up first.
+func (m *Model) openPlaying(artist bool) tea.Cmd {
+	current := m.Player.Queue.GetCurrentTrack()
+	if current == nil {
+		m.showWarning("Nothing is playing")
+		return nil
+	}
+	track := *current
+	if m.Offline || track.Episode {
+		return m.openTrackPage(track, artist, true)
+	}
+	if cmd := m.openTrackPage(track, artist, false); cmd != nil {
+		return cmd
+	}
+	return PlayingPageCmd(m.ctx, m.Api, track, artist)
+}
+
+// openTrackPage opens a track's album or artist page, warning that it has none
+// only when final is set; otherwise it returns nil for the caller to look further
+func (m *Model) openTrackPage(track api.Track, artist, final bool) tea.Cmd {
+	switch {
+	case artist && track.ArtistID != "":
+		return m.openArtist(track.ArtistID)
+	case !artist && track.AlbumID != "":
+		return m.openAlbum(track.AlbumID)
+	case !final:
+		return nil
+	case artist:
+		m.showWarning("No artist page available for " + track.Artist)
+	default:
+		m.showWarning("No album available for " + track.TrackTitle)
+	}
+	return nil
+}
+
+// PlayingPageCmd looks up the album and artist of a track
+func PlayingPageCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, track api.Track, artist bool) tea.Cmd {
+	return func() tea.Msg {
+		found, err := ytApi.GetTrackMetadata(ctx, track.ID)
+		if err != nil {
+			return playingPageMsg{track: track, artist: artist, err: err}
+		}
+		return playingPageMsg{track: fillMissing(track, found), artist: artist}
+	}
+}
+
+// handlePlayingPage opens the page of a looked up track, keeping what was found in the queue
+func (m *Model) handlePlayingPage(msg playingPageMsg) tea.Cmd {
+	if msg.err != nil {
+		m.showAPIError("Error looking up "+msg.track.TrackTitle, msg.err)
+		return nil
+	}
+	for i, track := range m.Player.Queue.Tracks {
+		if track.ID == msg.track.ID {
+			m.Player.Queue.Tracks[i] = fillMissing(track, msg.track)
+		}
+	}
+	return m.openTrackPage(msg.track, msg.artist, true)
+}
+
+// jumpToPlaying moves the cursor to the playing track in the list shown, to the
+// next copy of it below the cursor when the list has it more than once
+func (m *Model) jumpToPlaying() {
+	current := m.Player.Queue.GetCurrentTrack()
+	if current == nil {
+		m.showWarning("Nothing is playing")
+		return
+	}
+	if m.ViewMode == ViewQueue {
+		m.QueueList.Select(m.Player.Queue.CurrentIndex)
+		return
+	}
+	if !isBrowseView(m.ViewMode) {
+		return
+	}
+
+	l := m.ActiveList
+	items := l.Items()
+	for i := 1; i <= len(items); i++ {
+		index := (l.Index() + i) % len(items)
+		if track, ok := itemTrack(items[index]); ok && track.ID == current.ID {
+			l.Select(index)
+			return
+		}
+	}
+	m.showWarning(current.TrackTitle + " isn't in this list")
+}
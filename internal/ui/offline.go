@@ -0,0 +1,120 @@
+package ui
+
+import (
+	"fmt"
+	"net"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/download"
+	"ytmusic/internal/player"
+)
+
+// offlineRecheckInterval is how often offline mode checks whether the network is back
+const offlineRecheckInterval = 30 * time.Second
+
+// networkStatusMsg reports whether YouTube Music could be reached
+type networkStatusMsg struct {
+	online  bool
+	startup bool // The check made before logging in
+}
+
+// online reports whether YouTube Music can be reached
+func online() bool {
+	conn, err := net.DialTimeout("tcp", "music.youtube.com:443", 3*time.Second)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+	return true
+}
+
+// CheckNetworkCmd checks whether YouTube Music can be reached before logging in
+func CheckNetworkCmd() tea.Cmd {
+	return func() tea.Msg {
+		return networkStatusMsg{online: online(), startup: true}
+	}
+}
+
+// checkStartupCmd checks the network before logging in, unless offline mode was asked for
+func (m *Model) checkStartupCmd() tea.Cmd {
+	if m.Offline {
+		return CheckLoginCmd(m.Api)
+	}
+	return CheckNetworkCmd()
+}
+
+// RecheckNetworkCmd checks again after a while whether the network is back
+func RecheckNetworkCmd() tea.Cmd {
+	return tea.Tick(offlineRecheckInterval, func(time.Time) tea.Msg {
+		return networkStatusMsg{online: online()}
+	})
+}
+
+// OfflineSearchCmd searches the downloaded tracks
+func OfflineSearchCmd(downloads *download.Index, query string) tea.Cmd {
+	return func() tea.Msg {
+		return searchResultMsg{tracks: downloads.Search(query)}
+	}
+}
+
+// updateNetworkStatus enters offline mode when the network is gone and there are
+// downloads to play, and leaves it once the network is back
+func (m *Model) updateNetworkStatus(msg networkStatusMsg) tea.Cmd {
+	var cmds []tea.Cmd
+	if msg.startup {
+		cmds = append(cmds, CheckLoginCmd(m.Api))
+	}
+
+	switch {
+	case !msg.online && !m.Offline:
+		if m.Downloads.Len() == 0 {
+			m.Api.LogDebug("YouTube Music is unreachable and nothing is downloaded")
+			return tea.Batch(cmds...)
+		}
+		m.Offline = true
+		m.LibrarySection = LibraryDownloads
+		m.showWarning(fmt.Sprintf("No network connection - offline mode, playing your %d downloads", m.Downloads.Len()))
+		if !msg.startup {
+			cmds = append(cmds, m.switchTab(tabLibrary))
+		}
+		cmds = append(cmds, RecheckNetworkCmd())
+
+	case !msg.online:
+		cmds = append(cmds, RecheckNetworkCmd())
+
+	case m.Offline && !m.Config.Offline:
+		m.Offline = false
+		m.showInfo("Back online")
+		cmds = append(cmds, CheckLoginCmd(m.Api))
+	}
+	return tea.Batch(cmds...)
+}
+
+// getStreamCmd finds what mpv plays for a track: its download if there is one, otherwise its stream
+func (m *Model) getStreamCmd(trackID string) tea.Cmd {
+	// Without mpv there's no point finding a stream
+	if err := m.checkPlayable(""); err != nil {
+		return func() tea.Msg {
+			return streamURLMsg{err: err}
+		}
+	}
+	if path := m.Downloads.Path(trackID); path != "" {
+		return func() tea.Msg {
+			return streamURLMsg{url: path}
+		}
+	}
+	if m.Offline {
+		return func() tea.Msg {
+			return streamURLMsg{err: fmt.Errorf("this track isn't downloaded, so it can't play offline")}
+		}
+	}
+	if m.Player.Video {
+		// mpv's yt-dlp hook finds the video itself
+		return func() tea.Msg {
+			return streamURLMsg{url: player.WatchURL(trackID)}
+		}
+	}
+	return GetStreamURLCmd(m.ctx, m.Api, trackID)
+}
@@ -0,0 +1,380 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/config"
+	"ytmusic/internal/paths"
+	"ytmusic/internal/player"
+)
+
+// onboardingStep is a page of the first-run setup
+type onboardingStep int
+
+const (
+	stepBackend onboardingStep = iota
+	stepLogin
+	stepPlayer
+)
+
+// onboardingChoice is an option of the setup's backend or login page
+type onboardingChoice struct {
+	label       string
+	description string
+}
+
+// backendChoices are the backend orders offered, matching backendOrders
+var (
+	backendChoices = []onboardingChoice{
+		{"Native (recommended)", "Talks to YouTube Music directly, falling back to the Python bridge when a request fails"},
+		{"Native only", "Never starts Python"},
+		{"Python bridge first", "Goes through ytmusicapi, which needs Python, and falls back to native"},
+	}
+	backendOrders = [][]string{
+		{"native", "bridge"},
+		{"native"},
+		{"bridge", "native"},
+	}
+)
+
+// Login methods offered by the setup, in the order of loginChoices
+const (
+	loginCookie = iota
+	loginOAuth
+	loginBrowser
+	loginLater
+)
+
+var loginChoices = []onboardingChoice{
+	{"Paste a browser cookie", "Copy the Cookie header of music.youtube.com from your browser's developer tools"},
+	{"OAuth with ytmusicapi", "Sign in with a Google device code, using the ytmusicapi command"},
+	{"Import browser headers with ytmusicapi", "Paste a request from music.youtube.com into the ytmusicapi command"},
+	{"Later", "Skip logging in for now"},
+}
+
+// onboarding is the first-run setup, shown when there is no config file and no login
+type onboarding struct {
+	step     onboardingStep
+	backend  int  // Cursor on the backend page
+	login    int  // Cursor on the login page
+	waiting  bool // Whether the ytmusicapi instructions of the chosen login method are shown
+	loggedIn bool // Whether credentials were imported during the setup
+
+	deps        []player.Dependency // mpv and yt-dlp, nil until looked for
+	bridgeReady bool                // Whether the Python bridge has ytmusicapi
+	bridgeSetup bool                // Whether ytmusicapi could be installed into a virtualenv
+	installing  bool                // Whether ytmusicapi is being installed
+}
+
+// busy reports whether the setup is waiting on something, and needs the spinner
+func (o *onboarding) busy() bool {
+	return o != nil && (o.deps == nil || o.installing)
+}
+
+// usesBridge reports whether the chosen backends include the Python bridge
+func (o *onboarding) usesBridge() bool {
+	for _, backend := range backendOrders[o.backend] {
+		if backend == "bridge" {
+			return true
+		}
+	}
+	return false
+}
+
+// canInstall reports whether the setup offers to install ytmusicapi for the chosen backends
+func (o *onboarding) canInstall() bool {
+	return o.usesBridge() && o.bridgeSetup && !o.installing
+}
+
+// needsOnboarding reports whether this is the first run: nothing is configured and nobody is logged in
+func needsOnboarding(cfg *config.Config, ytApi *api.YouTubeMusicAPI) bool {
+	if cfg.Offline || ytApi.IsLoggedIn {
+		return false
+	}
+	_, err := os.Stat(config.Path())
+	return os.IsNotExist(err)
+}
+
+// dependenciesMsg reports what the setup found installed
+type dependenciesMsg struct {
+	deps        []player.Dependency
+	bridgeReady bool
+	bridgeSetup bool
+}
+
+// CheckDependenciesCmd looks for mpv, yt-dlp and ytmusicapi, which takes a Python start-up
+func CheckDependenciesCmd(ytApi *api.YouTubeMusicAPI, mpvPath string) tea.Cmd {
+	return func() tea.Msg {
+		ready := ytApi.BridgeReady()
+		return dependenciesMsg{
+			deps:        player.Dependencies(mpvPath),
+			bridgeReady: ready,
+			bridgeSetup: !ready && ytApi.BridgeNeedsSetup(),
+		}
+	}
+}
+
+// credentialsMsg reports whether ytmusicapi credentials were found
+type credentialsMsg struct {
+	loggedIn bool
+}
+
+// ImportCredentialsCmd looks for the files the ytmusicapi command writes
+func ImportCredentialsCmd(ytApi *api.YouTubeMusicAPI) tea.Cmd {
+	return func() tea.Msg {
+		return credentialsMsg{loggedIn: ytApi.ImportCredentials()}
+	}
+}
+
+// updateOnboarding handles keys while the first-run setup is shown
+func (m *Model) updateOnboarding(msg tea.KeyMsg) tea.Cmd {
+	o := m.Onboarding
+	switch msg.String() {
+	case "ctrl+c":
+		return tea.Quit
+
+	case "s":
+		// Skipping keeps the defaults, and still writes the file so the setup doesn't come back
+		if !o.waiting {
+			return m.finishOnboarding(0, loginLater)
+		}
+	}
+
+	switch o.step {
+	case stepBackend:
+		switch msg.String() {
+		case "up", "k":
+			o.backend = (o.backend + len(backendChoices) - 1) % len(backendChoices)
+		case "down", "j":
+			o.backend = (o.backend + 1) % len(backendChoices)
+		case "enter":
+			o.step = stepLogin
+		case "q":
+			return tea.Quit
+		}
+
+	case stepLogin:
+		if o.waiting {
+			switch msg.String() {
+			case "enter":
+				return ImportCredentialsCmd(m.Api)
+			case "esc":
+				o.waiting = false
+				m.clearStatus()
+			}
+			return nil
+		}
+		switch msg.String() {
+		case "up", "k":
+			o.login = (o.login + len(loginChoices) - 1) % len(loginChoices)
+		case "down", "j":
+			o.login = (o.login + 1) % len(loginChoices)
+		case "enter":
+			if o.login == loginOAuth || o.login == loginBrowser {
+				o.waiting = true
+				return nil
+			}
+			o.step = stepPlayer
+		case "esc":
+			o.step = stepBackend
+		}
+
+	case stepPlayer:
+		switch msg.String() {
+		case "i":
+			if o.canInstall() {
+				o.installing = true
+				return tea.Batch(m.Spinner.Tick, SetupBridgeCmd(m.ctx, m.Api))
+			}
+		case "enter":
+			return m.finishOnboarding(o.backend, o.login)
+		case "esc":
+			o.step = stepLogin
+		}
+	}
+	return nil
+}
+
+// handleDependencies records what the setup found installed
+func (m *Model) handleDependencies(msg dependenciesMsg) {
+	m.setDependencies(msg.deps)
+	if o := m.Onboarding; o != nil {
+		o.deps = msg.deps
+		o.bridgeReady = msg.bridgeReady
+		o.bridgeSetup = msg.bridgeSetup
+	}
+}
+
+// handleCredentials moves on from the ytmusicapi instructions once its credentials are found
+func (m *Model) handleCredentials(msg credentialsMsg) {
+	o := m.Onboarding
+	if o == nil {
+		return
+	}
+	if !msg.loggedIn {
+		m.showWarning("No credentials in " + paths.Tilde(paths.Config()) + " yet - run the command first")
+		return
+	}
+	m.clearStatus()
+	o.waiting = false
+	o.loggedIn = true
+	o.step = stepPlayer
+}
+
+// handleOnboardingSetup looks again for ytmusicapi once the setup has tried to install it
+func (m *Model) handleOnboardingSetup(err error) tea.Cmd {
+	m.Onboarding.installing = false
+	if err != nil {
+		m.showError("Could not set up the Python bridge: " + err.Error())
+		return nil
+	}
+	m.showInfo("Installed ytmusicapi - the Python bridge is ready")
+	return CheckDependenciesCmd(m.Api, m.Config.MPV.Path)
+}
+
+// finishOnboarding writes the config file with the chosen backends and goes on to log in
+func (m *Model) finishOnboarding(backend, login int) tea.Cmd {
+	loggedIn := m.Onboarding.loggedIn
+	m.Onboarding = nil
+
+	backends := backendOrders[backend]
+	if err := config.Create(config.Path(), backends); err != nil {
+		m.showError("Config: " + err.Error())
+	} else {
+		m.showInfo("Saved your settings to " + paths.Tilde(config.Path()))
+		m.configModTime = configModTime() // Already applied, no reload needed
+	}
+	m.Config.Backends = backends
+	m.Api.SetBackends(backends)
+
+	if loggedIn {
+		return CheckLoginCmd(m.Api)
+	}
+	m.LoginMode = true
+	if login == loginCookie {
+		// Open YouTube Music and focus the cookie field, as 'l' does on the login screen
+		m.LoginInput.SetValue("")
+		m.LoginInput.Focus()
+		return tea.Batch(textinput.Blink, OpenBrowserCmd("https://music.youtube.com"))
+	}
+	return nil
+}
+
+// renderOnboarding renders the page of the first-run setup
+func renderOnboarding(m *Model) string {
+	o := m.Onboarding
+	var s strings.Builder
+
+	pages := []string{"Backend", "Login", "Player"}
+	s.WriteString(titleStyle.Render(fmt.Sprintf("Welcome to YouTube Music TUI - %d/%d %s", o.step+1, len(pages), pages[o.step])) + "\n\n")
+
+	switch o.step {
+	case stepBackend:
+		s.WriteString("Where should searches, playlists and the home feed come from?\n\n")
+		s.WriteString(renderChoices(backendChoices, o.backend))
+		s.WriteString(resultInfoStyle.Render("[↑/↓] Move  [Enter] Next  [s] Skip setup  [q] Quit"))
+
+	case stepLogin:
+		if o.waiting {
+			s.WriteString(renderCredentialsHelp(o))
+			s.WriteString(renderStatus(m))
+			s.WriteString(resultInfoStyle.Render("[Enter] Check for credentials  [Esc] Back"))
+			break
+		}
+		s.WriteString("How do you want to log in to YouTube Music?\n\n")
+		s.WriteString(renderChoices(loginChoices, o.login))
+		s.WriteString(resultInfoStyle.Render("[↑/↓] Move  [Enter] Next  [Esc] Back  [s] Skip setup"))
+
+	case stepPlayer:
+		if o.loggedIn {
+			s.WriteString(infoStyle.Render("✓ Logged in with your ytmusicapi credentials") + "\n\n")
+		}
+		s.WriteString(renderDependencies(m))
+		s.WriteString(renderStatus(m))
+		hints := "[Enter] Save and start  [Esc] Back"
+		if o.canInstall() {
+			hints = "[i] Install ytmusicapi  " + hints
+		}
+		s.WriteString(resultInfoStyle.Render(hints))
+	}
+
+	return s.String()
+}
+
+// renderChoices renders the options of a setup page with the cursor on one
+func renderChoices(choices []onboardingChoice, cursor int) string {
+	var s strings.Builder
+	for i, choice := range choices {
+		if i == cursor {
+			s.WriteString(modeStyle.Render("> "+choice.label) + "\n")
+		} else {
+			s.WriteString(infoStyle.Render("  "+choice.label) + "\n")
+		}
+		s.WriteString(resultInfoStyle.Render("    "+choice.description) + "\n")
+	}
+	return s.String() + "\n"
+}
+
+// renderCredentialsHelp explains how to write the credentials of the chosen ytmusicapi login
+func renderCredentialsHelp(o *onboarding) string {
+	var s strings.Builder
+	if !o.bridgeReady {
+		s.WriteString("Install ytmusicapi first: pip install ytmusicapi\n")
+	}
+	s.WriteString("In another terminal, run:\n\n")
+	if o.login == loginOAuth {
+		s.WriteString(warningStyle.Render("  ytmusicapi oauth --file "+paths.Tilde(filepath.Join(paths.Config(), "oauth_auth.json"))) + "\n\n")
+		s.WriteString("and follow the link it prints to sign in with your Google account.\n")
+		s.WriteString("It needs the client_secret.json of a Google Cloud OAuth client in the same\n")
+		s.WriteString("directory; see Authentication Setup in the README.\n\n")
+	} else {
+		s.WriteString(warningStyle.Render("  ytmusicapi browser --file "+paths.Tilde(filepath.Join(paths.Config(), "headers_auth.json"))) + "\n\n")
+		s.WriteString("and paste the request headers of an authenticated POST request to\n")
+		s.WriteString("music.youtube.com, copied from your browser's developer tools.\n\n")
+	}
+	return s.String()
+}
+
+// renderDependencies lists the programs the setup found, and where to get the missing ones
+func renderDependencies(m *Model) string {
+	o := m.Onboarding
+	if o.deps == nil {
+		return m.Spinner.View() + " Looking for mpv, yt-dlp and Python...\n\n"
+	}
+
+	var s strings.Builder
+	for _, dep := range o.deps {
+		switch {
+		case dep.Found():
+			s.WriteString(infoStyle.Render("✓ "+dep.Name) + resultInfoStyle.Render(" "+dep.Path) + "\n")
+		case dep.Required:
+			s.WriteString(errorStyle.Render("✗ "+dep.Name+" not found - it "+dep.Purpose) + "\n")
+			s.WriteString("  " + installHint(dep) + ", or set path under [mpv] in the config file\n")
+		default:
+			s.WriteString(warningStyle.Render("✗ "+dep.Name+" not found - optional, it "+dep.Purpose) + "\n")
+			s.WriteString("  " + installHint(dep) + "\n")
+		}
+	}
+
+	switch {
+	case o.bridgeReady:
+		s.WriteString(infoStyle.Render("✓ ytmusicapi") + resultInfoStyle.Render(" for the Python bridge") + "\n")
+	case o.installing:
+		s.WriteString(m.Spinner.View() + " Installing ytmusicapi into " + paths.Tilde(filepath.Join(paths.Data(), "venv")) + "...\n")
+	case !o.usesBridge():
+		s.WriteString(resultInfoStyle.Render("- ytmusicapi not needed without the Python bridge") + "\n")
+	case o.bridgeSetup:
+		s.WriteString(warningStyle.Render("✗ ytmusicapi not installed - the Python bridge needs it") + "\n")
+		s.WriteString("  Press 'i' to install it into a virtualenv\n")
+	default:
+		s.WriteString(warningStyle.Render("✗ Python not found - the Python bridge needs it, with ytmusicapi") + "\n")
+	}
+	return s.String() + "\n"
+}
@@ -0,0 +1,81 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/playlistfile"
+)
+
+// openPlaylist starts loading a playlist's tracks into the track list
+func (m *Model) openPlaylist(playlist api.Playlist) tea.Cmd {
+	m.stopSearch() // The playlist replaces search results still streaming in
+	m.stopEnrich()
+	m.IsLoading = true
+	ctx := m.startPlaylistLoad()
+	m.LoadingPlaylist = &playlist
+	return tea.Batch(
+		m.Spinner.Tick,
+		GetPlaylistTracksCmd(ctx, m.Api, playlist.ID),
+	)
+}
+
+// appendPlaylistPage adds a continuation page to the track list
+func (m *Model) appendPlaylistPage(playlist *api.Playlist, msg playlistTracksResultMsg) tea.Cmd {
+	m.stopPlaylistLoad()
+	if msg.err != nil {
+		m.listMore = ""
+		m.showWarning(fmt.Sprintf("Loaded %d tracks of %s (stopped: %v)", m.SearchResults, playlist.PlaylistTitle, msg.err))
+		return nil
+	}
+
+	items := m.TrackList.Items()
+	for _, track := range msg.tracks {
+		items = append(items, track)
+	}
+	cmd := tea.Batch(m.TrackList.SetItems(items), m.enrichTracks(playlist.ID, msg.tracks))
+	m.SearchResults = len(items)
+
+	m.listMore = msg.continuation
+	if msg.continuation == "" {
+		m.showInfo(fmt.Sprintf("Loaded %s with %d tracks", playlist.PlaylistTitle, m.SearchResults))
+		return cmd
+	}
+	return tea.Batch(cmd, m.loadMore())
+}
+
+// playlistProgress describes how much of a playlist whose pages load on scrolling is shown
+func playlistProgress(playlist *api.Playlist, loaded int) string {
+	if playlist.TrackCount > loaded {
+		return fmt.Sprintf("Showing %d of %d tracks of %s, more load as you scroll", loaded, playlist.TrackCount, playlist.PlaylistTitle)
+	}
+	return fmt.Sprintf("Showing %d tracks of %s, more load as you scroll", loaded, playlist.PlaylistTitle)
+}
+
+// exportPlaylist writes a playlist to a file, in the format its extension names
+func (m *Model) exportPlaylist(playlist api.Playlist, path string) tea.Cmd {
+	path = playlistfile.ExpandHome(path)
+	format := playlistfile.FormatOf(path)
+	if format == "" {
+		m.showWarning("Export to a .m3u, .csv or .json file")
+		return nil
+	}
+
+	m.IsLoading = true
+	m.showInfo("Exporting " + playlist.PlaylistTitle + "...")
+	return tea.Batch(m.Spinner.Tick, ExportPlaylistCmd(m.ctx, m.Api, playlist, path, format))
+}
+
+// ExportPlaylistCmd fetches all of a playlist's tracks and writes them to a file
+func ExportPlaylistCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, playlist api.Playlist, path, format string) tea.Cmd {
+	return func() tea.Msg {
+		tracks, err := ytApi.GetPlaylistTracks(ctx, playlist.ID)
+		if err == nil {
+			err = playlistfile.WriteFile(path, format, playlist, tracks)
+		}
+		return playlistExportedMsg{playlist: playlist, path: path, count: len(tracks), err: err}
+	}
+}
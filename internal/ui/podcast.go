@@ -0,0 +1,90 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+)
+
+// SearchPodcastsCmd searches for podcasts and episodes
+func SearchPodcastsCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, query string) tea.Cmd {
+	return func() tea.Msg {
+		shelves, err := ytApi.SearchPodcasts(ctx, query)
+		if ctx.Err() != nil {
+			err = ctx.Err()
+		}
+		if err != nil {
+			return podcastSearchMsg{err: err}
+		}
+
+		// Shows come first, then episodes, in one list
+		var items []list.Item
+		for _, shelf := range shelves {
+			for _, item := range shelf.Items {
+				items = append(items, item)
+			}
+		}
+		return podcastSearchMsg{items: items}
+	}
+}
+
+// GetPodcastCmd fetches a podcast with its episodes
+func GetPodcastCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, browseID string) tea.Cmd {
+	return func() tea.Msg {
+		podcast, err := ytApi.GetPodcast(ctx, browseID)
+		return podcastResultMsg{podcast: podcast, err: err}
+	}
+}
+
+// openPodcast starts loading a podcast
+func (m *Model) openPodcast(browseID string) tea.Cmd {
+	m.IsLoading = true
+	m.clearStatus()
+	return tea.Batch(
+		m.Spinner.Tick,
+		GetPodcastCmd(m.ctx, m.Api, browseID),
+	)
+}
+
+// showPodcast switches to the podcast view with a loaded podcast
+func (m *Model) showPodcast(podcast *api.Podcast) {
+	if m.ViewMode != ViewPodcast || m.CurrentPodcast == nil || m.CurrentPodcast.ID != podcast.ID {
+		m.pushView(ViewPodcast)
+	}
+	m.CurrentPodcast = podcast
+
+	items := make([]list.Item, len(podcast.Episodes))
+	for i, episode := range podcast.Episodes {
+		items[i] = episode
+	}
+
+	m.EpisodeList.Title = podcast.PodcastTitle
+	m.EpisodeList.SetItems(items)
+	m.EpisodeList.Select(0)
+}
+
+// podcastSummary renders the podcast's author and episode count
+func podcastSummary(podcast *api.Podcast) string {
+	summary := fmt.Sprintf("%d episodes", len(podcast.Episodes))
+	if podcast.Author != "" {
+		summary = podcast.Author + " • " + summary
+	}
+	return summary
+}
+
+// episodeResumeHint tells where the selected episode will resume, if it was started before
+func (m *Model) episodeResumeHint() string {
+	episode, ok := m.ActiveList.SelectedItem().(api.Episode)
+	if !ok {
+		return ""
+	}
+	position := m.Player.EpisodePosition(episode.ID)
+	if position <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("Resumes at %s of %s", formatDuration(position), formatDuration(episode.Duration))
+}
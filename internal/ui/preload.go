@@ -0,0 +1,160 @@
+package ui
+
+import (
+	"context"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/player"
+)
+
+// preloadNext resolves the next track's stream shortly before the playing one
+// ends, so mpv can go on to it without a gap. A preload the queue no longer
+// leads to, because tracks were added, removed or reordered, is dropped.
+func (m *Model) preloadNext() tea.Cmd {
+	if !m.Player.NearEnd() {
+		return nil
+	}
+
+	next := m.Player.Queue.PeekNext()
+	if preloaded := m.Player.Preloaded(); preloaded != nil {
+		if next != nil && preloaded.ID == next.ID {
+			return nil
+		}
+		m.Player.DropPreload()
+	}
+	if next == nil {
+		return m.autoplayAhead()
+	}
+	if m.Preloading == next.ID {
+		return nil
+	}
+
+	if path := m.Downloads.Path(next.ID); path != "" {
+		m.Preloading = next.ID
+		track := *next
+		return func() tea.Msg {
+			return preloadMsg{track: track, url: path}
+		}
+	}
+	if m.Offline {
+		// Not downloaded, so it can't play; the track end shows why
+		return nil
+	}
+	m.Preloading = next.ID
+	if m.Player.Video {
+		track := *next
+		return func() tea.Msg {
+			return preloadMsg{track: track, url: player.WatchURL(track.ID)}
+		}
+	}
+	return PreloadStreamCmd(m.ctx, m.Api, *next)
+}
+
+// autoplayAhead fetches the tracks autoplay continues with while the last
+// queued track still plays, so they can be preloaded like the rest of the queue
+func (m *Model) autoplayAhead() tea.Cmd {
+	current := m.Player.Queue.GetCurrentTrack()
+	if !m.Player.Queue.Autoplay || current == nil || m.AutoplayAhead == current.ID {
+		return nil
+	}
+	m.AutoplayAhead = current.ID
+	return GetAutoplayCmd(m.ctx, m.Api, current.ID, true)
+}
+
+// PreloadStreamCmd resolves the stream of the track that plays next
+func PreloadStreamCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, track api.Track) tea.Cmd {
+	return func() tea.Msg {
+		info, err := ytApi.GetStreamInfo(ctx, track.ID)
+		if err != nil {
+			return preloadMsg{track: track, err: err}
+		}
+		return preloadMsg{track: track, url: info.URL, duration: info.Duration}
+	}
+}
+
+// finishPreload hands a resolved stream to the player, if its track is still the next one.
+// Failures are only logged: the track then starts the usual way when the current one ends.
+func (m *Model) finishPreload(msg preloadMsg) tea.Cmd {
+	if m.Preloading == msg.track.ID {
+		m.Preloading = ""
+	}
+	if msg.err != nil {
+		if !canceled(msg.err) {
+			m.Api.LogDebug("Could not preload %s: %v", msg.track.ID, msg.err)
+		}
+		return nil
+	}
+
+	next := m.Player.Queue.PeekNext()
+	if next == nil || next.ID != msg.track.ID || !m.Player.NearEnd() {
+		return nil
+	}
+
+	duration := next.Duration
+	if msg.duration > 0 {
+		duration = msg.duration
+	}
+	if err := m.Player.Preload(*next, msg.url, duration); err != nil {
+		m.Api.LogDebug("Could not preload %s: %v", msg.track.ID, err)
+	}
+	return nil
+}
+
+// trackAdvanced follows mpv onto the preloaded track. Should the queue have
+// changed in the meantime, it plays the track the queue leads to instead.
+func (m *Model) trackAdvanced(track api.Track) tea.Cmd {
+	trackingCmd := m.finishTracking(m.CurrentTrack.Duration)
+
+	next, ok := m.Player.Queue.NextTrack()
+	if !ok || next == nil {
+		m.Player.Stop()
+		return trackingCmd
+	}
+	if next.ID != track.ID {
+		m.IsLoading = true
+		return tea.Batch(trackingCmd, m.Spinner.Tick, m.getStreamCmd(next.ID))
+	}
+
+	return tea.Batch(trackingCmd, m.trackStarted(*next))
+}
+
+// trackStarted shows a track that just started playing and reports the play
+func (m *Model) trackStarted(track api.Track) tea.Cmd {
+	m.CurrentTrack = track
+	if m.ViewMode == ViewQueue {
+		m.refreshQueueList()
+	}
+
+	// Recently Played is stale once something new plays; refetch it on the next visit
+	delete(m.LibraryItems, LibraryRecentlyPlayed)
+
+	// The lyrics pane follows the playing track
+	var lyricsCmd tea.Cmd
+	if m.ViewMode == ViewLyrics {
+		lyricsCmd = m.loadLyrics(track)
+	}
+
+	m.syncDuration()
+
+	m.Playing = api.NewPlay(m.CurrentTrack)
+	return tea.Batch(
+		lyricsCmd,
+		StartPlaybackCmd(m.ctx, m.Api, m.CurrentTrack.ID),
+	)
+}
+
+// syncDuration prefers the player's duration, which comes from the stream, over the listing's
+func (m *Model) syncDuration() {
+	if m.Player.Duration <= 0 || m.Player.Duration == m.CurrentTrack.Duration {
+		return
+	}
+	m.CurrentTrack.Duration = m.Player.Duration
+	for i, queued := range m.Player.Queue.Tracks {
+		if queued.ID == m.CurrentTrack.ID {
+			m.Player.Queue.Tracks[i].Duration = m.Player.Duration
+			break
+		}
+	}
+}
@@ -0,0 +1,288 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/paths"
+)
+
+// promptKind identifies what an open prompt is asking for
+type promptKind int
+
+const (
+	promptNone promptKind = iota
+	promptCreatePlaylist
+	promptRenamePlaylist
+	promptDeletePlaylist     // Yes/no confirmation, no text
+	promptAddToPlaylist      // Picks a playlist from PickerList
+	promptRemoveFromPlaylist // Yes/no confirmation, no text
+	promptChartsCountry
+	promptSetupBridge // Yes/no confirmation, no text
+	promptAccount     // Picks an account from PickerList
+	promptExportPlaylist
+	promptResume // Yes/no confirmation, no text
+)
+
+// openPrompt shows a prompt, with a text field unless it's a confirmation or picker
+func (m *Model) openPrompt(kind promptKind, value string) tea.Cmd {
+	m.Prompt = kind
+	m.clearStatus()
+	if kind == promptDeletePlaylist || kind == promptRemoveFromPlaylist || kind == promptSetupBridge || kind == promptResume {
+		return nil
+	}
+	if kind == promptAddToPlaylist {
+		return m.PickerList.SetItems(playlistPickerItems(m.Playlists))
+	}
+	if kind == promptAccount {
+		// The caller fills the picker
+		return nil
+	}
+
+	m.PromptInput.SetValue(value)
+	m.PromptInput.CursorEnd()
+	m.PromptInput.Focus()
+	return textinput.Blink
+}
+
+// pickPlaylist opens the playlist picker for one or more tracks
+func (m *Model) pickPlaylist(tracks ...api.Track) tea.Cmd {
+	if len(tracks) == 0 {
+		return nil
+	}
+	if len(playlistPickerItems(m.Playlists)) == 0 {
+		m.showWarning("No playlists to add to - create one in the playlists view")
+		return nil
+	}
+
+	m.PromptTracks = tracks
+	return m.openPrompt(promptAddToPlaylist, "")
+}
+
+// closePrompt hides the prompt
+func (m *Model) closePrompt() {
+	m.Prompt = promptNone
+	m.PromptInput.Blur()
+	m.PromptInput.SetValue("")
+}
+
+// updatePrompt handles keys while a prompt is open
+func (m *Model) updatePrompt(msg tea.KeyMsg) tea.Cmd {
+	switch m.Prompt {
+	case promptDeletePlaylist, promptRemoveFromPlaylist, promptSetupBridge, promptResume:
+		return m.updateConfirmation(msg)
+	case promptAddToPlaylist, promptAccount:
+		return m.updatePicker(msg)
+	}
+
+	switch msg.String() {
+	case "esc":
+		m.closePrompt()
+		return nil
+
+	case "enter":
+		value := strings.TrimSpace(m.PromptInput.Value())
+		if value == "" && m.Prompt == promptChartsCountry {
+			value = api.GlobalCharts
+		} else if value == "" && m.Prompt == promptExportPlaylist {
+			m.showWarning("Please enter a file to export to")
+			return nil
+		} else if value == "" {
+			m.showWarning("Please enter a playlist name")
+			return nil
+		}
+
+		kind, target := m.Prompt, m.PromptTarget
+		m.closePrompt()
+		if kind == promptChartsCountry {
+			return m.loadExplore(value)
+		}
+		if kind == promptExportPlaylist {
+			return m.exportPlaylist(target, value)
+		}
+		m.IsLoading = true
+
+		if kind == promptRenamePlaylist {
+			return tea.Batch(m.Spinner.Tick, RenamePlaylistCmd(m.ctx, m.Api, target, value))
+		}
+		return tea.Batch(m.Spinner.Tick, CreatePlaylistCmd(m.ctx, m.Api, value))
+	}
+
+	var cmd tea.Cmd
+	m.PromptInput, cmd = m.PromptInput.Update(msg)
+	return cmd
+}
+
+// updateConfirmation handles the yes/no prompts
+func (m *Model) updateConfirmation(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "y", "Y":
+		kind, target, track := m.Prompt, m.PromptTarget, m.PromptTrack
+		m.closePrompt()
+		if kind == promptResume {
+			return m.togglePlayback()
+		}
+		m.IsLoading = true
+
+		if kind == promptSetupBridge {
+			m.showInfo("Installing ytmusicapi, this can take a minute...")
+			return tea.Batch(m.Spinner.Tick, SetupBridgeCmd(m.ctx, m.Api))
+		}
+		if kind == promptRemoveFromPlaylist {
+			return tea.Batch(m.Spinner.Tick, RemoveFromPlaylistCmd(m.ctx, m.Api, target, track))
+		}
+		return tea.Batch(m.Spinner.Tick, DeletePlaylistCmd(m.ctx, m.Api, target))
+
+	case "n", "N", "esc", "q":
+		if m.Prompt == promptResume {
+			// The queue stays, but its track starts over when played
+			m.Player.ResumePos = 0
+			m.Player.CurrentPos = 0
+		}
+		if m.Prompt == promptSetupBridge {
+			m.SetupDeclined = true
+			m.showWarning("The Python bridge needs ytmusicapi - run: pip install ytmusicapi")
+		}
+		m.closePrompt()
+	}
+	return nil
+}
+
+// updatePicker handles keys while the playlist or account picker is open
+func (m *Model) updatePicker(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc", "q":
+		m.closePrompt()
+		return nil
+
+	case "enter":
+		if account, ok := m.PickerList.SelectedItem().(api.Account); ok {
+			m.closePrompt()
+			return m.switchAccount(account)
+		}
+
+		playlist, ok := m.PickerList.SelectedItem().(api.Playlist)
+		if !ok {
+			return nil
+		}
+
+		tracks := m.PromptTracks
+		m.closePrompt()
+		m.IsLoading = true
+		return tea.Batch(m.Spinner.Tick, AddToPlaylistCmd(m.ctx, m.Api, playlist, tracks))
+	}
+
+	var cmd tea.Cmd
+	m.PickerList, cmd = m.PickerList.Update(msg)
+	return cmd
+}
+
+// playlistPickerItems lists the playlists a track can be added to
+func playlistPickerItems(playlists []api.Playlist) []list.Item {
+	items := []list.Item{}
+	for _, playlist := range playlists {
+		// Liked Songs is edited by rating tracks, not through playlist edits
+		if playlist.ID == "LM" || playlist.ID == "VLLM" {
+			continue
+		}
+		items = append(items, playlist)
+	}
+	return items
+}
+
+// renderPrompt renders the open prompt
+func renderPrompt(m *Model) string {
+	switch m.Prompt {
+	case promptCreatePlaylist:
+		return titleStyle.Render("New Playlist") + "\n\n" + m.PromptInput.View() + "\n" +
+			resultInfoStyle.Render("[Enter] Create  [Esc] Cancel")
+	case promptRenamePlaylist:
+		return titleStyle.Render("Rename "+m.PromptTarget.PlaylistTitle) + "\n\n" + m.PromptInput.View() + "\n" +
+			resultInfoStyle.Render("[Enter] Rename  [Esc] Cancel")
+	case promptDeletePlaylist:
+		return warningStyle.Render("Delete playlist \""+m.PromptTarget.PlaylistTitle+"\"?") + "\n" +
+			"This cannot be undone. Press 'y' to confirm or 'n' to cancel."
+	case promptChartsCountry:
+		return titleStyle.Render("Charts Country") + "\n\n" + m.PromptInput.View() + "\n" +
+			resultInfoStyle.Render("[Enter] Show charts (two-letter code such as US, empty for global)  [Esc] Cancel")
+	case promptAddToPlaylist:
+		return titleStyle.Render("Add "+tracksName(m.PromptTracks)+" to...") + "\n\n" + m.PickerList.View() + "\n" +
+			resultInfoStyle.Render("[Enter] Add  [Esc] Cancel")
+	case promptAccount:
+		return titleStyle.Render("Switch Account") + "\n\n" + m.PickerList.View() + "\n" +
+			resultInfoStyle.Render("[Enter] Use this account  [Esc] Cancel")
+	case promptRemoveFromPlaylist:
+		return warningStyle.Render("Remove \""+m.PromptTrack.TrackTitle+"\" from "+m.PromptTarget.PlaylistTitle+"?") + "\n" +
+			"Press 'y' to confirm or 'n' to cancel."
+	case promptExportPlaylist:
+		return titleStyle.Render("Export "+m.PromptTarget.PlaylistTitle) + "\n\n" + m.PromptInput.View() + "\n" +
+			resultInfoStyle.Render("[Enter] Export (the extension picks M3U, CSV or JSON)  [Esc] Cancel")
+	case promptResume:
+		return titleStyle.Render("Resume where you left off?") + "\n" +
+			fmt.Sprintf("%s - %s at %s of %s. Press 'y' to resume or 'n' to keep the queue and start over.",
+				m.CurrentTrack.TrackTitle, m.CurrentTrack.Artist, formatDuration(m.Player.ResumePos), formatDuration(m.CurrentTrack.Duration))
+	case promptSetupBridge:
+		return warningStyle.Render("The Python bridge needs ytmusicapi, which isn't installed") + "\n" +
+			"Create a virtualenv in " + paths.Tilde(filepath.Join(paths.Data(), "venv")) + " and install it there? Press 'y' to install or 'n' to skip."
+	}
+	return ""
+}
+
+// CreatePlaylistCmd creates a playlist
+func CreatePlaylistCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, title string) tea.Cmd {
+	return func() tea.Msg {
+		_, err := ytApi.CreatePlaylist(ctx, title, "")
+		return playlistEditedMsg{status: "Created playlist " + title, err: err}
+	}
+}
+
+// RenamePlaylistCmd renames a playlist
+func RenamePlaylistCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, playlist api.Playlist, title string) tea.Cmd {
+	return func() tea.Msg {
+		err := ytApi.RenamePlaylist(ctx, playlist.ID, title)
+		return playlistEditedMsg{status: "Renamed " + playlist.PlaylistTitle + " to " + title, err: err}
+	}
+}
+
+// DeletePlaylistCmd deletes a playlist
+func DeletePlaylistCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, playlist api.Playlist) tea.Cmd {
+	return func() tea.Msg {
+		err := ytApi.DeletePlaylist(ctx, playlist.ID)
+		return playlistEditedMsg{status: "Deleted playlist " + playlist.PlaylistTitle, err: err}
+	}
+}
+
+// AddToPlaylistCmd adds tracks to a playlist
+func AddToPlaylistCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, playlist api.Playlist, tracks []api.Track) tea.Cmd {
+	ids := make([]string, len(tracks))
+	for i, track := range tracks {
+		ids[i] = track.ID
+	}
+	return func() tea.Msg {
+		err := ytApi.AddPlaylistItems(ctx, playlist.ID, ids)
+		return playlistEditedMsg{status: "Added " + tracksName(tracks) + " to " + playlist.PlaylistTitle, err: err}
+	}
+}
+
+// tracksName names a track by its title, or several by their number
+func tracksName(tracks []api.Track) string {
+	if len(tracks) == 1 {
+		return tracks[0].TrackTitle
+	}
+	return fmt.Sprintf("%d tracks", len(tracks))
+}
+
+// RemoveFromPlaylistCmd removes a track from a playlist
+func RemoveFromPlaylistCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, playlist api.Playlist, track api.Track) tea.Cmd {
+	return func() tea.Msg {
+		err := ytApi.RemovePlaylistItems(ctx, playlist.ID, []api.Track{track})
+		return playlistItemRemovedMsg{playlistID: playlist.ID, track: track, err: err}
+	}
+}
@@ -0,0 +1,243 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/player"
+)
+
+// queueItem is a queue entry shown in the queue view
+type queueItem struct {
+	track     api.Track
+	index     int   // Position in Player.Queue.Tracks
+	current   bool  // Whether this is the queue's current track
+	duplicate []int // Other positions the same track is queued at
+}
+
+// FilterValue implements list.Item interface for filtering
+func (q queueItem) FilterValue() string {
+	return q.track.FilterValue()
+}
+
+// Title implements list.Item interface for displaying in the list
+func (q queueItem) Title() string {
+	if q.current {
+		return "▶ " + q.track.TrackTitle
+	}
+	return fmt.Sprintf("%d. %s", q.index+1, q.track.TrackTitle)
+}
+
+// Description implements list.Item interface for displaying in the list
+func (q queueItem) Description() string {
+	description := fmt.Sprintf("%s • %s", q.track.Artist, formatDuration(q.track.Duration))
+	if len(q.duplicate) > 0 {
+		positions := make([]string, len(q.duplicate))
+		for i, index := range q.duplicate {
+			positions[i] = fmt.Sprintf("#%d", index+1)
+		}
+		description += " • ⧉ Also at " + strings.Join(positions, ", ")
+	}
+	return description
+}
+
+// refreshQueueList rebuilds the queue view from the live player queue
+func (m *Model) refreshQueueList() {
+	queue := m.Player.Queue
+	duplicates := queue.Duplicates()
+
+	items := make([]list.Item, len(queue.Tracks))
+	for i, track := range queue.Tracks {
+		item := queueItem{
+			track:   track,
+			index:   i,
+			current: i == queue.CurrentIndex,
+		}
+		for _, index := range duplicates[track.ID] {
+			if index != i {
+				item.duplicate = append(item.duplicate, index)
+			}
+		}
+		items[i] = item
+	}
+
+	m.QueueList.SetItems(items)
+}
+
+// moveQueueTrack moves the selected queue track for a reordering key, keeping it selected
+func (m *Model) moveQueueTrack(key string) tea.Cmd {
+	selectedItem, ok := m.QueueList.SelectedItem().(queueItem)
+	if !ok {
+		return nil
+	}
+	queue := m.Player.Queue
+	from := selectedItem.index
+
+	to, moved := from, false
+	switch key {
+	case "J", "ctrl+down":
+		to = from + 1
+		moved = to < len(queue.Tracks) && queue.Move(from, to)
+	case "K", "ctrl+up":
+		to = from - 1
+		moved = to >= 0 && queue.Move(from, to)
+	case "T":
+		to = 0
+		moved = from != 0 && queue.Move(from, to)
+	case "A":
+		to, moved = queue.MoveAfterCurrent(from)
+		if moved {
+			m.showInfo("Playing next: " + selectedItem.track.TrackTitle)
+		}
+	}
+	if !moved {
+		return nil
+	}
+
+	m.refreshQueueList()
+	m.QueueList.Select(to)
+	return nil
+}
+
+// playFromList replaces the queue with the tracks of a list, starting at the selected one
+func (m *Model) playFromList(l *list.Model) tea.Cmd {
+	// Only tracks and episodes are queued; other rows in mixed lists are skipped
+	var allTracks []api.Track
+	selectedIndex := 0
+	for i, item := range l.Items() {
+		var track api.Track
+		switch item := item.(type) {
+		case api.Track:
+			track = item
+		case api.Episode:
+			track = item.Track()
+		default:
+			continue
+		}
+		if i == l.Index() {
+			selectedIndex = len(allTracks)
+		}
+		allTracks = append(allTracks, track)
+	}
+	if len(allTracks) == 0 {
+		return nil
+	}
+
+	// Set the queue to all tracks, starting from the selected one
+	m.Player.Queue.Clear()
+	m.Player.Queue.AddTracks(allTracks[selectedIndex:])
+
+	// Add tracks before the selected one to the end if repeat all is enabled
+	if m.Player.Queue.RepeatMode == player.RepeatAll && selectedIndex > 0 {
+		m.Player.Queue.AddTracks(allTracks[:selectedIndex])
+	}
+
+	// Play the first track in the queue (which is the selected one)
+	m.IsLoading = true
+	return tea.Batch(
+		m.Spinner.Tick,
+		m.getStreamCmd(allTracks[selectedIndex].ID),
+	)
+}
+
+// queueTrack appends a track to the queue, or inserts it after the current one, without interrupting playback
+func (m *Model) queueTrack(track api.Track, next bool) tea.Cmd {
+	wasEmpty := m.Player.Queue.GetCurrentTrack() == nil
+	if next {
+		m.Player.Queue.InsertNext(track)
+		m.showInfo("Playing next: " + track.TrackTitle)
+	} else if m.Player.Queue.Add(track) {
+		m.showInfo("Added to queue: " + track.TrackTitle)
+	} else {
+		m.showInfo("Already in queue: " + track.TrackTitle)
+		return nil
+	}
+
+	// Nothing was queued before, so start playing right away
+	if !wasEmpty {
+		return nil
+	}
+	m.IsLoading = true
+	return tea.Batch(
+		m.Spinner.Tick,
+		m.getStreamCmd(track.ID),
+	)
+}
+
+// queueTracks appends tracks to the queue, or inserts them after the current one in order,
+// without interrupting playback
+func (m *Model) queueTracks(tracks []api.Track, next bool) tea.Cmd {
+	if len(tracks) == 1 {
+		return m.queueTrack(tracks[0], next)
+	}
+	if len(tracks) == 0 {
+		return nil
+	}
+
+	wasEmpty := m.Player.Queue.GetCurrentTrack() == nil
+	if next {
+		// Inserting the last first leaves them in list order after the current track
+		for i := len(tracks) - 1; i >= 0; i-- {
+			m.Player.Queue.InsertNext(tracks[i])
+		}
+		m.showInfo(fmt.Sprintf("Playing %d tracks next", len(tracks)))
+	} else {
+		added := m.Player.Queue.AddTracks(tracks)
+		if skipped := len(tracks) - added; skipped > 0 {
+			m.showInfo(fmt.Sprintf("Added %d tracks to queue, %d already in it", added, skipped))
+		} else {
+			m.showInfo(fmt.Sprintf("Added %d tracks to queue", added))
+		}
+	}
+
+	// Nothing was queued before, so start playing right away
+	current := m.Player.Queue.GetCurrentTrack()
+	if !wasEmpty || current == nil {
+		return nil
+	}
+	m.IsLoading = true
+	return tea.Batch(
+		m.Spinner.Tick,
+		m.getStreamCmd(current.ID),
+	)
+}
+
+// startRadio replaces the queue with a radio of tracks related to one track
+func (m *Model) startRadio(track api.Track) tea.Cmd {
+	m.IsLoading = true
+	m.clearStatus()
+	return tea.Batch(
+		m.Spinner.Tick,
+		GetRadioCmd(m.ctx, m.Api, track.ID),
+	)
+}
+
+// selectedTrack returns the track under the cursor of a track, episode or queue list
+func selectedTrack(l *list.Model) (api.Track, bool) {
+	return itemTrack(l.SelectedItem())
+}
+
+// itemTrack returns the track a list item plays, if it's a track, episode or queue entry
+func itemTrack(item list.Item) (api.Track, bool) {
+	switch item := item.(type) {
+	case api.Track:
+		return item, true
+	case api.Episode:
+		return item.Track(), true
+	case queueItem:
+		return item.track, true
+	}
+	return api.Track{}, false
+}
+
+// formatDuration formats seconds as M:SS, or H:MM:SS for an hour or more
+func formatDuration(seconds int) string {
+	if seconds >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", seconds/3600, (seconds%3600)/60, seconds%60)
+	}
+	return fmt.Sprintf("%d:%02d", seconds/60, seconds%60)
+}
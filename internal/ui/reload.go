@@ -0,0 +1,114 @@
+package ui
+
+import (
+	"errors"
+	"os"
+	"time"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/config"
+)
+
+// configPollInterval is how often config.toml is checked for changes
+const configPollInterval = 2 * time.Second
+
+// configStatMsg carries the config file's modification time, zero if it doesn't exist
+type configStatMsg struct {
+	modTime time.Time
+}
+
+// configModTime returns when the config file was last written, zero if it doesn't exist
+func configModTime() time.Time {
+	info, err := os.Stat(config.Path())
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+// WatchConfigCmd checks the config file for changes after a short wait
+func WatchConfigCmd() tea.Cmd {
+	return tea.Tick(configPollInterval, func(time.Time) tea.Msg {
+		return configStatMsg{modTime: configModTime()}
+	})
+}
+
+// handleConfigStat reloads the config file once it has been saved again
+func (m *Model) handleConfigStat(msg configStatMsg) tea.Cmd {
+	if msg.modTime.IsZero() || msg.modTime.Equal(m.configModTime) {
+		return WatchConfigCmd()
+	}
+	m.configModTime = msg.modTime
+	mouse := m.Config.Mouse
+	m.reloadConfig()
+	if m.Config.Mouse != mouse {
+		return tea.Batch(mouseCmd(m.Config.Mouse), WatchConfigCmd())
+	}
+	return WatchConfigCmd()
+}
+
+// reloadConfig applies the config file's theme, keys and behavior without
+// restarting. Settings that flags can also give, and those only read at
+// startup, keep their current values until the next start.
+func (m *Model) reloadConfig() {
+	cfg, err := config.Load(config.Path())
+	var parseErr *config.ParseError
+	if errors.As(err, &parseErr) {
+		// Keep everything as it is until the file is fixed, rather than the defaults
+		m.showWarning("Config not reloaded: " + err.Error())
+		return
+	}
+
+	old := m.Config
+	cfg.DefaultView = old.DefaultView
+	cfg.Audio.Quality, cfg.Audio.Codec = old.Audio.Quality, old.Audio.Codec
+	cfg.Locale = old.Locale
+	cfg.HTTP = old.HTTP
+	cfg.MediaControls = old.MediaControls
+	cfg.Hotkeys = old.Hotkeys
+	cfg.Offline = old.Offline
+	cfg.Cache.ArtSizeMB = old.Cache.ArtSizeMB
+	cfg.Visualizer = old.Visualizer
+	m.Config = cfg
+
+	m.applyTheme(newTheme(cfg.ThemeColors()))
+	m.Keys = newKeyBindings(cfg)
+	m.SeekStep = cfg.SeekStep
+	m.SeekStepLong = cfg.SeekStepLong
+
+	m.Player.MPVPath = cfg.MPV.Path
+	m.Player.MPVArgs = cfg.MPV.Args
+	m.Player.YtdlFormat = cfg.MPV.YtdlFormat
+	m.Player.MaxRate = cfg.Audio.MaxRate
+	m.Player.Queue.SmartShuffle = cfg.Shuffle == "smart"
+	m.Player.Queue.SkipDuplicates = cfg.SkipDuplicates
+	m.Api.SetMaxBitrate(cfg.Audio.MaxBitrate())
+	m.Api.SetMaxRate(cfg.Audio.MaxRate)
+	m.Api.SetCacheSize(cfg.Cache.SizeMB)
+	m.Api.SetBackends(cfg.Backends)
+
+	if err != nil {
+		m.showWarning("Reloaded config, with problems: " + err.Error())
+		return
+	}
+	m.showInfo("Reloaded config")
+}
+
+// applyTheme restyles the shared styles and every list with a theme
+func (m *Model) applyTheme(t Theme) {
+	applyTheme(t)
+
+	delegate := list.NewDefaultDelegate()
+	delegate.Styles = t.delegateStyles()
+	for _, l := range []*list.Model{&m.TrackList, &m.LibraryList, &m.AlbumList, &m.ArtistList,
+		&m.HomeList, &m.ExploreList, &m.EpisodeList, &m.UploadsList} {
+		l.SetDelegate(markDelegate{delegate, m.Selection, m.Likes, m.PlayingRow})
+		l.Styles.Title = titleStyle
+	}
+	for _, l := range []*list.Model{&m.PlaylistList, &m.QueueList, &m.PickerList} {
+		l.SetDelegate(delegate)
+		l.Styles.Title = titleStyle
+	}
+}
@@ -0,0 +1,41 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// appendSearchPage adds a further page of search results to the track list
+func (m *Model) appendSearchPage(msg searchResultMsg) tea.Cmd {
+	// Drop pages of a search that's been replaced or whose list now shows something else
+	if m.searchCtx == nil || msg.query != m.searchQuery || m.OpenPlaylist != nil {
+		return nil
+	}
+	m.stopSearch()
+	if msg.err != nil {
+		m.listMore = ""
+		m.showWarning(fmt.Sprintf("Showing the first %d results (no more: %v)", m.SearchResults, msg.err))
+		return nil
+	}
+
+	// Pages can overlap; keep a track's first place
+	items := m.TrackList.Items()
+	seen := make(map[string]bool, len(items))
+	for _, item := range items {
+		if track, ok := itemTrack(item); ok {
+			seen[track.ID] = true
+		}
+	}
+	for _, track := range msg.tracks {
+		if !seen[track.ID] {
+			seen[track.ID] = true
+			items = append(items, track)
+		}
+	}
+	cmd := m.TrackList.SetItems(items)
+	m.SearchResults = len(items)
+
+	m.listMore = msg.continuation
+	return tea.Batch(cmd, m.loadMore())
+}
@@ -0,0 +1,245 @@
+package ui
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+)
+
+// selection is the tracks marked for a batch action in select mode. The list
+// delegates share it to draw the marks.
+type selection struct {
+	active bool
+	marked map[string]bool // Marked tracks, by video ID
+	anchor int             // Row visual mode started at, marking every row up to the cursor, or -1
+}
+
+// newSelection returns an inactive selection
+func newSelection() *selection {
+	return &selection{marked: map[string]bool{}, anchor: -1}
+}
+
+// start enters select mode, marking rows from the cursor on as it moves when visual is set
+func (s *selection) start(l *list.Model, visual bool) {
+	s.active = true
+	if visual {
+		s.anchor = l.Index()
+	}
+}
+
+// clear leaves select mode and drops the marks
+func (s *selection) clear() {
+	s.active = false
+	s.marked = map[string]bool{}
+	s.anchor = -1
+}
+
+// inRange reports whether a row lies between the visual mode anchor and the cursor
+func (s *selection) inRange(l list.Model, index int) bool {
+	if s.anchor < 0 {
+		return false
+	}
+	from, to := s.anchor, l.Index()
+	if from > to {
+		from, to = to, from
+	}
+	return index >= from && index <= to
+}
+
+// isMarked reports whether a row is marked, on its own or by the visual range
+func (s *selection) isMarked(l list.Model, index int, item list.Item) bool {
+	if !s.active {
+		return false
+	}
+	track, ok := itemTrack(item)
+	if !ok {
+		return false
+	}
+	return s.marked[track.ID] || s.inRange(l, index)
+}
+
+// fixRange keeps the rows of the visual range marked and ends visual mode
+func (s *selection) fixRange(l *list.Model) {
+	if s.anchor < 0 {
+		return
+	}
+	for i, item := range l.Items() {
+		if track, ok := itemTrack(item); ok && s.inRange(*l, i) {
+			s.marked[track.ID] = true
+		}
+	}
+	s.anchor = -1
+}
+
+// toggle marks or unmarks the row under the cursor
+func (s *selection) toggle(l *list.Model) {
+	s.fixRange(l)
+	if track, ok := itemTrack(l.SelectedItem()); ok {
+		s.marked[track.ID] = !s.marked[track.ID]
+		if !s.marked[track.ID] {
+			delete(s.marked, track.ID)
+		}
+	}
+}
+
+// markAll marks every track of the list
+func (s *selection) markAll(l *list.Model) {
+	s.anchor = -1
+	for _, item := range l.Items() {
+		if track, ok := itemTrack(item); ok {
+			s.marked[track.ID] = true
+		}
+	}
+}
+
+// tracks returns the marked tracks in list order, or the one under the cursor when none are
+func (s *selection) tracks(l *list.Model) []api.Track {
+	var tracks []api.Track
+	seen := map[string]bool{}
+	for i, item := range l.Items() {
+		track, ok := itemTrack(item)
+		if !ok || seen[track.ID] || !s.isMarked(*l, i, item) {
+			continue
+		}
+		seen[track.ID] = true
+		tracks = append(tracks, track)
+	}
+	if len(tracks) == 0 {
+		if track, ok := itemTrack(l.SelectedItem()); ok {
+			tracks = append(tracks, track)
+		}
+	}
+	return tracks
+}
+
+// count returns how many tracks are marked
+func (s *selection) count(l *list.Model) int {
+	count := 0
+	seen := map[string]bool{}
+	for i, item := range l.Items() {
+		if track, ok := itemTrack(item); ok && !seen[track.ID] && s.isMarked(*l, i, item) {
+			seen[track.ID] = true
+			count++
+		}
+	}
+	return count
+}
+
+// markDelegate draws a list like the default delegate, with a check mark
+// before marked rows, a play sign before the playing track and a heart
+// before liked tracks
+type markDelegate struct {
+	list.DefaultDelegate
+	selection *selection
+	likes     *likes
+	playing   *playingRow
+}
+
+// playingRow is the track playing, which the delegates of the browse lists mark
+type playingRow struct {
+	videoID string
+}
+
+// Render implements list.ItemDelegate
+func (d markDelegate) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	if defaultItem, ok := item.(list.DefaultItem); ok {
+		prefix := ""
+		if d.selection.isMarked(m, index, item) {
+			prefix += "✓ "
+		}
+		if track, ok := itemTrack(item); ok {
+			if track.ID == d.playing.videoID {
+				prefix += "▶ "
+			}
+			if d.likes.ids[track.ID] {
+				prefix += "♥ "
+			}
+		}
+		if prefix != "" {
+			item = markedItem{defaultItem, prefix}
+		}
+	}
+	d.DefaultDelegate.Render(w, m, index, item)
+}
+
+// markedItem shows a marked, playing or liked row
+type markedItem struct {
+	list.DefaultItem
+	prefix string
+}
+
+// Title implements list.DefaultItem
+func (i markedItem) Title() string {
+	return i.prefix + i.DefaultItem.Title()
+}
+
+// updateSelection handles keys in select mode, reporting whether the key was used.
+// Other keys, such as moving the cursor, work as usual.
+func (m *Model) updateSelection(key string) (tea.Cmd, bool) {
+	l := m.ActiveList
+	switch key {
+	case " ":
+		m.Selection.toggle(l)
+		l.CursorDown()
+	case "v":
+		// A second v keeps the range marked, so another can start elsewhere
+		if m.Selection.anchor >= 0 {
+			m.Selection.fixRange(l)
+		} else {
+			m.Selection.anchor = l.Index()
+		}
+	case "ctrl+a":
+		m.Selection.markAll(l)
+	case "esc":
+		m.Selection.clear()
+	case "a", "A", playNextKey:
+		tracks := m.Selection.tracks(l)
+		m.Selection.clear()
+		return m.queueTracks(tracks, key != "a"), true
+	case "P":
+		tracks := m.Selection.tracks(l)
+		m.Selection.clear()
+		return m.pickPlaylist(tracks...), true
+	case "l":
+		tracks := m.Selection.tracks(l)
+		m.Selection.clear()
+		if len(tracks) == 0 {
+			return nil, true
+		}
+		for _, track := range tracks {
+			m.Likes.mark(track.ID, true)
+		}
+		delete(m.LibraryItems, LibraryLikedSongs)
+		m.showInfo(fmt.Sprintf("Liking %d tracks...", len(tracks)))
+		return RateSongsCmd(m.ctx, m.Api, tracks, api.RatingLike), true
+	default:
+		return nil, false
+	}
+	return nil, true
+}
+
+// renderSelection renders the select mode line shown under the list
+func renderSelection(m *Model) string {
+	return modeStyle.Render(fmt.Sprintf("-- SELECT -- %d marked", m.Selection.count(m.ActiveList))) + "  " +
+		resultInfoStyle.Render("[Space] Mark  [v] Range  [Ctrl+A] All  [a] Queue  [A] Play next  [P] Add to playlist  [l] Like  [Esc] Done")
+}
+
+// RateSongsCmd likes or dislikes tracks one after another in the background
+func RateSongsCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, tracks []api.Track, rating api.Rating) tea.Cmd {
+	return func() tea.Msg {
+		for i, track := range tracks {
+			if err := ytApi.RateSong(ctx, track.ID, rating); err != nil {
+				return statusMsg{level: levelError, text: fmt.Sprintf("Error rating %s after %d of %d tracks: %v", track.TrackTitle, i, len(tracks), err)}
+			}
+		}
+		if rating == api.RatingLike {
+			return statusMsg{text: fmt.Sprintf("Liked %d tracks", len(tracks))}
+		}
+		return statusMsg{text: fmt.Sprintf("Rated %d tracks", len(tracks))}
+	}
+}
@@ -0,0 +1,107 @@
+package ui
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+)
+
+// sessionCheckInterval is how often the stored login is checked while the app runs
+const sessionCheckInterval = 15 * time.Minute
+
+// credentialsPollInterval is how often the saved login is checked for
+// `ytmusic login` and `ytmusic logout` run in another terminal
+const credentialsPollInterval = 2 * time.Second
+
+// credentialsStatMsg carries when the saved login last changed, zero if there is none
+type credentialsStatMsg struct {
+	modTime time.Time
+}
+
+// WatchCredentialsCmd checks the saved login for changes after a short wait
+func WatchCredentialsCmd(ytApi *api.YouTubeMusicAPI) tea.Cmd {
+	return tea.Tick(credentialsPollInterval, func(time.Time) tea.Msg {
+		return credentialsStatMsg{modTime: ytApi.CredentialsModTime()}
+	})
+}
+
+// handleCredentialsStat picks up a login or logout made outside the app,
+// opening the library or going back to the login screen
+func (m *Model) handleCredentialsStat(msg credentialsStatMsg) tea.Cmd {
+	// While logging in or out here, the change is this app's own
+	if msg.modTime.Equal(m.loginModTime) || m.IsLoading {
+		return WatchCredentialsCmd(m.Api)
+	}
+	m.loginModTime = msg.modTime
+	// Downloads play the same either way, and the login is checked when going back online
+	if m.Offline || m.Onboarding != nil {
+		return WatchCredentialsCmd(m.Api)
+	}
+
+	wasLoggedIn := !m.LoginMode
+	loggedIn := m.Api.ReloadCredentials()
+	switch {
+	case loggedIn && !wasLoggedIn:
+		m.SessionExpired = false
+		m.showInfo("Logged in from another terminal")
+		return tea.Batch(CheckLoginCmd(m.Api), WatchCredentialsCmd(m.Api))
+	case !loggedIn && wasLoggedIn:
+		m.SessionChecking = false
+		m.LoginMode = true
+		m.showInfo("Logged out from another terminal")
+	}
+	// A new login replacing the old one is used from the next request on
+	return WatchCredentialsCmd(m.Api)
+}
+
+// sessionStatusMsg reports the result of checking the stored login
+type sessionStatusMsg struct {
+	err error
+}
+
+// ValidateSessionCmd checks that the stored login still works
+func ValidateSessionCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI) tea.Cmd {
+	return func() tea.Msg {
+		return sessionStatusMsg{err: ytApi.ValidateSession(ctx)}
+	}
+}
+
+// recheckSessionCmd checks the stored login again after a while
+func recheckSessionCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI) tea.Cmd {
+	return tea.Tick(sessionCheckInterval, func(time.Time) tea.Msg {
+		return sessionStatusMsg{err: ytApi.ValidateSession(ctx)}
+	})
+}
+
+// startSessionChecks checks the login now and then periodically, unless that's already happening
+func (m *Model) startSessionChecks() tea.Cmd {
+	if m.SessionChecking {
+		return nil
+	}
+	m.SessionChecking = true
+	return ValidateSessionCmd(m.ctx, m.Api)
+}
+
+// updateSessionStatus sends the user back to the login screen once the session expires
+func (m *Model) updateSessionStatus(msg sessionStatusMsg) tea.Cmd {
+	switch {
+	case errors.Is(msg.err, api.ErrSessionExpired):
+		m.SessionChecking = false
+		m.SessionExpired = true
+		m.LoginMode = true
+		m.clearStatus() // The login screen explains what happened
+		return nil
+
+	case errors.Is(msg.err, api.ErrNotLoggedIn), m.Offline:
+		// Logging in or going back online starts the checks again
+		m.SessionChecking = false
+		return nil
+	}
+
+	// Other failures, such as a dropped connection, say nothing about the session
+	return recheckSessionCmd(m.ctx, m.Api)
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"context"
+	"errors"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+)
+
+// bridgeSetupMsg reports the result of installing ytmusicapi
+type bridgeSetupMsg struct {
+	err error
+}
+
+// SetupBridgeCmd installs ytmusicapi into a virtualenv for the Python bridge
+func SetupBridgeCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI) tea.Cmd {
+	return func() tea.Msg {
+		return bridgeSetupMsg{err: ytApi.SetupBridge(ctx)}
+	}
+}
+
+// offerBridgeSetup asks to install ytmusicapi when err says it's missing,
+// unless the offer was already turned down. It reports whether it asked.
+func (m *Model) offerBridgeSetup(err error) bool {
+	if !errors.Is(err, api.ErrBridgeSetup) || m.SetupDeclined {
+		return false
+	}
+	if m.Prompt == promptNone {
+		m.openPrompt(promptSetupBridge, "")
+	}
+	return true
+}
@@ -0,0 +1,123 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+	"ytmusic/internal/stats"
+)
+
+// statsTop is how many tracks and artists the stats view ranks
+const statsTop = 10
+
+// statsBarWidth is the width of the longest bar of the hour-of-day chart
+const statsBarWidth = 30
+
+// GetPlaysCmd reads the local listening history for the stats view
+func GetPlaysCmd(ytApi *api.YouTubeMusicAPI) tea.Cmd {
+	return func() tea.Msg {
+		plays, err := ytApi.GetPlays()
+		return playsMsg{plays: plays, err: err}
+	}
+}
+
+// toggleStats opens the listening statistics, reading the history afresh, or closes them
+func (m *Model) toggleStats() tea.Cmd {
+	if m.ViewMode == ViewStats {
+		m.popView()
+		return nil
+	}
+
+	m.pushView(ViewStats)
+	m.StatsView.SetContent(resultInfoStyle.Render("Reading the listening history..."))
+	m.StatsView.GotoTop()
+	return GetPlaysCmd(m.Api)
+}
+
+// showStatsPeriod switches the stats view to another period
+func (m *Model) showStatsPeriod(period stats.Period) {
+	m.StatsPeriod = period
+	m.refreshStats()
+	m.StatsView.GotoTop()
+}
+
+// refreshStats renders the statistics of the selected period
+func (m *Model) refreshStats() {
+	if m.Plays == nil {
+		return
+	}
+	summary := stats.Summarize(m.Plays, m.StatsPeriod, time.Now(), statsTop)
+	m.StatsView.SetContent(renderStats(summary))
+}
+
+// renderStatsTabs renders the period tabs
+func renderStatsTabs(active stats.Period) string {
+	var names []string
+	for p := stats.Week; p <= stats.AllTime; p++ {
+		names = append(names, p.String())
+	}
+	return renderTabs(names, int(active))
+}
+
+// renderStats renders a summary: totals, top artists and tracks, and plays by hour of day
+func renderStats(summary stats.Summary) string {
+	if summary.Plays == 0 && summary.Skipped == 0 {
+		return resultInfoStyle.Render("Nothing played " + strings.ToLower(summary.Period.String()) + " yet")
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s played • %s listened", plural(summary.Plays, "track"), formatListeningTime(summary.Seconds))
+	if summary.Skipped > 0 {
+		fmt.Fprintf(&b, " • %d skipped", summary.Skipped)
+	}
+	b.WriteString("\n\n")
+
+	b.WriteString(modeStyle.Render("Top Artists") + "\n")
+	for i, artist := range summary.Artists {
+		fmt.Fprintf(&b, "%2d. %s • %s\n", i+1, artist.Name,
+			resultInfoStyle.Render(plural(artist.Plays, "play")+" • "+formatListeningTime(artist.Seconds)))
+	}
+
+	b.WriteString("\n" + modeStyle.Render("Top Tracks") + "\n")
+	for i, track := range summary.Tracks {
+		fmt.Fprintf(&b, "%2d. %s - %s • %s\n", i+1, track.Name, track.Detail,
+			resultInfoStyle.Render(plural(track.Plays, "play")))
+	}
+
+	b.WriteString("\n" + modeStyle.Render("By Hour of Day") + "\n")
+	most := 0
+	for _, plays := range summary.Hours {
+		if plays > most {
+			most = plays
+		}
+	}
+	for hour, plays := range summary.Hours {
+		bar := ""
+		if most > 0 {
+			bar = strings.Repeat("█", (plays*statsBarWidth+most-1)/most)
+		}
+		fmt.Fprintf(&b, "%02d:00 %s %s\n", hour, playingStyle.Render(bar), resultInfoStyle.Render(fmt.Sprint(plays)))
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// formatListeningTime formats seconds as hours and minutes, such as "2h 05m" or "42m"
+func formatListeningTime(seconds int) string {
+	minutes := seconds / 60
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%dh %02dm", minutes/60, minutes%60)
+}
+
+// plural formats a count with a noun, adding an s unless there's exactly one
+func plural(n int, noun string) string {
+	if n == 1 {
+		return "1 " + noun
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
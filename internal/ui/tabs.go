@@ -0,0 +1,104 @@
+package ui
+
+import (
+	"fmt"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// tab is a top-level section of the tab bar
+type tab struct {
+	name string
+	view ViewMode
+}
+
+// Tabs in the order of the tab bar, selected with the number keys
+const (
+	tabHome = iota
+	tabSearch
+	tabLibrary
+	tabPlaylists
+	tabQueue
+	tabNowPlaying
+)
+
+var tabs = []tab{
+	tabHome:       {"Home", ViewHome},
+	tabSearch:     {"Search", ViewTracks},
+	tabLibrary:    {"Library", ViewLibrary},
+	tabPlaylists:  {"Playlists", ViewPlaylists},
+	tabQueue:      {"Queue", ViewQueue},
+	tabNowPlaying: {"Now Playing", ViewNowPlaying},
+}
+
+// tabForView returns the tab that shows a view at its top level, or -1 for drill-down views
+func tabForView(mode ViewMode) int {
+	for i, t := range tabs {
+		if t.view == mode {
+			return i
+		}
+	}
+	return -1
+}
+
+// selectTab shows a tab's view, dropping the drill-down views opened from the previous tab
+func (m *Model) selectTab(index int) {
+	m.Tab = index
+	m.ViewHistory = nil
+	m.setView(tabs[index].view)
+}
+
+// switchTab selects a tab and loads what it shows if that hasn't happened yet
+func (m *Model) switchTab(index int) tea.Cmd {
+	m.selectTab(index)
+
+	switch index {
+	case tabHome:
+		if len(m.HomeShelves) == 0 && m.Offline {
+			m.showWarning("The home feed isn't available offline")
+		} else if len(m.HomeShelves) == 0 {
+			m.IsLoading = true
+			return tea.Batch(m.Spinner.Tick, GetHomeCmd(m.ctx, m.Api))
+		}
+	case tabLibrary:
+		return m.showLibrarySection(m.LibrarySection)
+	case tabPlaylists:
+		if len(m.Playlists) == 0 && m.Offline {
+			m.showWarning("Playlists aren't available offline")
+		} else if len(m.Playlists) == 0 {
+			m.IsLoading = true
+			return tea.Batch(m.Spinner.Tick, GetPlaylistsCmd(m.ctx, m.Api))
+		}
+	case tabQueue:
+		m.refreshQueueList()
+		if m.Player.Queue.CurrentIndex >= 0 {
+			m.QueueList.Select(m.Player.Queue.CurrentIndex)
+		}
+	}
+	return nil
+}
+
+// cycleTab switches to the next tab, or the previous one
+func (m *Model) cycleTab(back bool) tea.Cmd {
+	if back {
+		return m.switchTab((m.Tab + len(tabs) - 1) % len(tabs))
+	}
+	return m.switchTab((m.Tab + 1) % len(tabs))
+}
+
+// renderTabBar renders the tabs with their number keys, highlighting the current one
+func renderTabBar(active int) string {
+	names := make([]string, len(tabs))
+	for i, t := range tabs {
+		names[i] = fmt.Sprintf("%d %s", i+1, t.name)
+	}
+	return renderTabs(names, active)
+}
+
+// tabKey returns the tab a number key selects
+func tabKey(key string) (int, bool) {
+	if len(key) != 1 || key[0] < '1' || int(key[0]-'1') >= len(tabs) {
+		return 0, false
+	}
+	return int(key[0] - '1'), true
+}
@@ -0,0 +1,64 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/mattn/go-runewidth"
+)
+
+// ellipsis ends text cut short to fit
+const ellipsis = "…"
+
+// fit cuts plain text down to a number of terminal columns, ending it with an
+// ellipsis when it's cut. CJK characters and most emoji take two columns each.
+func fit(s string, width int) string {
+	if width < 1 {
+		return ""
+	}
+	// Terminals disagree on how wide an emoji asked to be drawn as a picture
+	// is, so it's drawn as text, whose width they agree on
+	s = strings.ReplaceAll(s, "\uFE0F", "")
+	return runewidth.Truncate(s, width, ellipsis)
+}
+
+// fitLeft cuts plain text down to a number of terminal columns from its start,
+// beginning it with an ellipsis when it's cut
+func fitLeft(s string, width int) string {
+	if width < 1 {
+		return ""
+	}
+	s = strings.ReplaceAll(s, "\uFE0F", "")
+	if over := runewidth.StringWidth(s) - width; over > 0 {
+		return runewidth.TruncateLeft(s, over+runewidth.StringWidth(ellipsis), ellipsis)
+	}
+	return s
+}
+
+// fitPair cuts a title and an artist down to share a number of terminal
+// columns, the artist giving up to a third of them when both are too long
+func fitPair(title, artist string, width int) (string, string) {
+	titleWidth, artistWidth := runewidth.StringWidth(title), runewidth.StringWidth(artist)
+	if titleWidth+artistWidth <= width {
+		return fit(title, width), fit(artist, width)
+	}
+	artistMax := width / 3
+	if titleWidth < width-artistMax {
+		artistMax = width - titleWidth
+	}
+	if artistWidth < artistMax {
+		artistMax = artistWidth
+	}
+	return fit(title, width-artistMax), fit(artist, artistMax)
+}
+
+// contentWidth returns the columns inside the app's border and padding
+func (m *Model) contentWidth() int {
+	width := m.Width - 6
+	if m.Width == 0 {
+		width = 80 - 6 // Before the first window size arrives
+	}
+	if width < 20 {
+		width = 20
+	}
+	return width
+}
@@ -0,0 +1,162 @@
+package ui
+
+import (
+	"os"
+
+	"github.com/charmbracelet/bubbles/list"
+	"github.com/charmbracelet/bubbles/progress"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+
+	"ytmusic/internal/config"
+)
+
+// Theme is the set of colors the interface is drawn with
+type Theme struct {
+	Accent           lipgloss.TerminalColor // Borders, titles, and the selected row
+	Text             lipgloss.TerminalColor
+	Muted            lipgloss.TerminalColor
+	Playing          lipgloss.TerminalColor
+	Error            lipgloss.TerminalColor
+	Warning          lipgloss.TerminalColor
+	Active           lipgloss.TerminalColor // Highlighted tabs and modes
+	Selected         lipgloss.TerminalColor // Text on the accent color
+	StatusText       lipgloss.TerminalColor
+	StatusBackground lipgloss.TerminalColor
+	NoColor          bool // Set when NO_COLOR asks for plain text
+}
+
+// Styling, built from the theme by applyTheme
+var (
+	appStyle        lipgloss.Style
+	titleStyle      lipgloss.Style
+	statusBarStyle  lipgloss.Style
+	playingStyle    lipgloss.Style
+	infoStyle       lipgloss.Style
+	errorStyle      lipgloss.Style
+	warningStyle    lipgloss.Style
+	resultInfoStyle lipgloss.Style
+	modeStyle       lipgloss.Style
+)
+
+func init() {
+	applyTheme(newTheme(config.Themes["default"]))
+}
+
+// newTheme builds a theme from configured colors, dropping them all if NO_COLOR is set
+func newTheme(colors config.Colors) Theme {
+	if os.Getenv("NO_COLOR") != "" {
+		none := lipgloss.NoColor{}
+		return Theme{
+			Accent:           none,
+			Text:             none,
+			Muted:            none,
+			Playing:          none,
+			Error:            none,
+			Warning:          none,
+			Active:           none,
+			Selected:         none,
+			StatusText:       none,
+			StatusBackground: none,
+			NoColor:          true,
+		}
+	}
+
+	return Theme{
+		Accent:           lipgloss.Color(colors.Accent),
+		Text:             lipgloss.Color(colors.Text),
+		Muted:            lipgloss.Color(colors.Muted),
+		Playing:          lipgloss.Color(colors.Playing),
+		Error:            lipgloss.Color(colors.Error),
+		Warning:          lipgloss.Color(colors.Warning),
+		Active:           lipgloss.Color(colors.Active),
+		Selected:         lipgloss.Color(colors.Selected),
+		StatusText:       lipgloss.Color(colors.StatusText),
+		StatusBackground: lipgloss.Color(colors.StatusBackground),
+	}
+}
+
+// applyTheme rebuilds the shared styles with a theme's colors
+func applyTheme(t Theme) {
+	appStyle = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Accent).
+		Padding(1, 2).
+		AlignHorizontal(lipgloss.Left).
+		AlignVertical(lipgloss.Top)
+
+	titleStyle = lipgloss.NewStyle().
+		Foreground(t.Text).
+		Background(t.Accent).
+		Bold(true).
+		Padding(0, 1)
+
+	statusBarStyle = lipgloss.NewStyle().
+		Foreground(t.StatusText).
+		Background(t.StatusBackground).
+		Padding(0, 1)
+
+	playingStyle = lipgloss.NewStyle().
+		Foreground(t.Playing).
+		Bold(true)
+
+	infoStyle = lipgloss.NewStyle().
+		Foreground(t.Text)
+
+	errorStyle = lipgloss.NewStyle().
+		Foreground(t.Error).
+		Bold(true)
+
+	warningStyle = lipgloss.NewStyle().
+		Foreground(t.Warning).
+		Bold(true)
+
+	resultInfoStyle = lipgloss.NewStyle().
+		Foreground(t.Muted).
+		Italic(true)
+
+	modeStyle = lipgloss.NewStyle().
+		Foreground(t.Active).
+		Bold(true)
+
+	// Without colors the title would vanish into the text around it
+	if t.NoColor {
+		titleStyle = titleStyle.Reverse(true)
+	}
+}
+
+// delegateStyles returns the list row styles for a theme
+func (t Theme) delegateStyles() list.DefaultItemStyles {
+	styles := list.NewDefaultItemStyles()
+
+	styles.NormalTitle = styles.NormalTitle.
+		Foreground(t.Text).
+		Bold(true)
+
+	styles.NormalDesc = styles.NormalDesc.
+		Foreground(t.Muted)
+
+	styles.SelectedTitle = styles.SelectedTitle.
+		Foreground(t.Selected).
+		Background(t.Accent).
+		BorderForeground(t.Accent).
+		Bold(true)
+
+	styles.SelectedDesc = styles.SelectedDesc.
+		Foreground(t.Selected).
+		Background(t.Accent).
+		BorderForeground(t.Accent)
+
+	styles.DimmedTitle = styles.DimmedTitle.Foreground(t.Muted)
+	styles.DimmedDesc = styles.DimmedDesc.Foreground(t.Muted)
+
+	return styles
+}
+
+// progressOption colors the progress bar to match a theme
+func (t Theme) progressOption() progress.Option {
+	if t.NoColor {
+		return progress.WithColorProfile(termenv.Ascii)
+	}
+	return progress.WithDefaultGradient()
+}
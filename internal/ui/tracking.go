@@ -0,0 +1,36 @@
+package ui
+
+import (
+	"context"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+)
+
+// StartPlaybackCmd reports a track as played so the account's history and recommendations see it
+func StartPlaybackCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI, videoID string) tea.Cmd {
+	return func() tea.Msg {
+		session, err := ytApi.StartPlayback(ctx, videoID)
+		return playbackStartedMsg{videoID: videoID, session: session, err: err}
+	}
+}
+
+// finishTracking records how far the play got, reports it to the account and stops tracking it
+func (m *Model) finishTracking(position int) tea.Cmd {
+	recordCmd := RecordPlayCmd(m.Api, m.Playing, position)
+	m.Playing = nil
+
+	session := m.Tracking
+	m.Tracking = nil
+	if session == nil {
+		return recordCmd
+	}
+
+	ctx, ytApi := m.ctx, m.Api
+	return tea.Batch(recordCmd, func() tea.Msg {
+		if err := session.ReportWatchtime(ctx, position); err != nil {
+			ytApi.LogDebug("Error reporting watchtime: %v", err)
+		}
+		return nil
+	})
+}
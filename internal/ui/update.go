@@ -2,39 +2,103 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
+	"strings"
+	
 	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	
 	"ytmusic/internal/api"
 	"ytmusic/internal/player"
+	"ytmusic/internal/playlistfile"
 )
 
-// Update updates the model based on messages
+// Update updates the model based on messages, scheduling the dismissal of any notification it shows
 func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	defer m.recoverCrash()
+	if msg, ok := msg.(crashMsg); ok {
+		panic(msg.crash)
+	}
+	if msg, ok := msg.(dismissMsg); ok {
+		if m.Status != nil && m.Status.id == msg.id {
+			m.clearStatus()
+		}
+		return m, nil
+	}
+	
+	seq := m.notifySeq
+	model, cmd := m.update(msg)
+	cmd = tea.Batch(cmd, m.syncVisualizer())
+	if m.notifySeq != seq && m.Status != nil {
+		cmd = tea.Batch(cmd, m.dismissCmd())
+	}
+	cmd = tea.Batch(cmd, m.fetchArt(), m.loadLikes())
+	m.PlayingRow.videoID = ""
+	if current := m.Player.Queue.GetCurrentTrack(); current != nil {
+		m.PlayingRow.videoID = current.ID
+	}
+	m.Media.Update(m.nowPlaying())
+	return model, catchPanics(cmd)
+}
+
+// update updates the model based on messages
+func (m *Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 	var cmds []tea.Cmd
 	
 	switch msg := msg.(type) {
 	case loginStatusMsg:
+		if m.Offline {
+			// Downloads play without an account or a network
+			m.LoginMode = false
+			return m, m.switchTab(tabLibrary)
+		}
+		
 		m.LoginMode = !msg.isLoggedIn
 		if m.LoginMode {
 			return m, nil
 		}
 		
-		// If we've just logged in, fetch playlists
+		// If we've just logged in, fetch playlists and the home feed
 		if msg.isLoggedIn {
 			m.IsLoading = true
 			return m, tea.Batch(
 				m.Spinner.Tick,
-				GetPlaylistsCmd(m.Api),
+				GetPlaylistsCmd(m.ctx, m.Api),
+				GetHomeCmd(m.ctx, m.Api),
+				m.openStartView(),
+				m.startSessionChecks(),
+				m.offerAccountsCmd(),
 			)
 		}
 		
 		return m, nil
 		
+	case playerDepsMsg:
+		m.handlePlayerDeps(msg)
+		return m, nil
+		
+	case configStatMsg:
+		return m, m.handleConfigStat(msg)
+		
+	case artMsg:
+		m.handleArt(msg)
+		return m, nil
+		
+	case dependenciesMsg:
+		m.handleDependencies(msg)
+		return m, nil
+		
+	case credentialsMsg:
+		m.handleCredentials(msg)
+		return m, nil
+		
 	case tea.KeyMsg:
-		if m.ResetMode {
+		if m.Onboarding != nil {
+			return m, m.updateOnboarding(msg)
+		} else if m.ResetMode {
 			// Handle reset mode input
 			switch msg.String() {
 			case "y", "Y":
@@ -48,31 +112,91 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		} else if m.LoginMode {
 			// Handle login mode input
+			if m.LoginInput.Focused() {
+				switch msg.String() {
+				case "esc":
+					m.LoginInput.Blur()
+					return m, nil
+					
+				case "enter":
+					cookie := strings.TrimSpace(m.LoginInput.Value())
+					if cookie == "" {
+						m.showWarning("Please paste a cookie value")
+						return m, nil
+					}
+					m.LoginInput.Blur()
+					m.IsLoading = true
+					m.clearStatus()
+					return m, tea.Batch(
+						m.Spinner.Tick,
+						LoginCmd(m.Api, cookie),
+					)
+					
+				case "ctrl+c":
+					return m, tea.Quit
+					
+				default:
+					m.LoginInput, cmd = m.LoginInput.Update(msg)
+					return m, cmd
+				}
+			}
+			
 			switch msg.String() {
 			case "l":
-				// Use a background routine to handle the login process
-				go func() {
-					err := m.Api.InitiateLogin()
-					if err != nil {
-						// Handle login error
-					} else {
-						// Force a refresh of the UI
-						p := tea.NewProgram(m)
-						p.Send(loginStatusMsg{isLoggedIn: true})
-					}
-				}()
-				return m, nil
+				// Open YouTube Music and focus the cookie field
+				m.clearStatus()
+				m.LoginInput.SetValue("")
+				m.LoginInput.Focus()
+				return m, tea.Batch(
+					textinput.Blink,
+					OpenBrowserCmd("https://music.youtube.com"),
+				)
 				
 			case "q", "ctrl+c":
 				return m, tea.Quit
 			}
 			return m, nil
 		} else if m.IsLoading {
-			// When loading, only handle quit
-			switch msg.String() {
+			// When loading, only handle quit and cancelling
+			switch m.Keys.resolve(msg.String()) {
 			case "ctrl+c", "q":
 				return m, tea.Quit
+			case "esc":
+				m.abortLoading()
+			}
+			return m, nil
+		} else if m.Prompt != promptNone {
+			// A prompt takes all keys until it's answered or cancelled
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			return m, m.updatePrompt(msg)
+		} else if m.Menu != nil {
+			// An open menu takes all keys until an action is picked or it's closed
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			return m, m.updateMenu(msg)
+		} else if m.Info != nil {
+			// Any key closes the track info popup
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
 			}
+			m.Info = nil
+			return m, nil
+		} else if m.ShowHelp {
+			// Any key closes the help overlay
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			m.ShowHelp = false
+			return m, nil
+		} else if m.ShowMessages {
+			// Any key closes the message log
+			if msg.String() == "ctrl+c" {
+				return m, tea.Quit
+			}
+			m.ShowMessages = false
 			return m, nil
 		} else if m.SearchMode {
 			// When in search mode, handle Esc, Enter, and pass other keys to input
@@ -82,24 +206,43 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.SearchInput.Blur()
 				return m, nil
 				
+			case "tab":
+				// Switch between searching songs and podcasts
+				m.SearchPodcasts = !m.SearchPodcasts
+				return m, nil
+				
 			case "enter":
 				m.SearchMode = false
 				m.IsLoading = true
-				m.ErrorMsg = "" // Clear previous errors
+				m.clearStatus() // Clear previous errors
 				query := m.SearchInput.Value()
 				if query == "" {
 					m.IsLoading = false
-					m.ErrorMsg = "Please enter a search term"
+					m.showWarning("Please enter a search term")
 					return m, nil
 				}
 				
-				// Switch to tracks view when searching
-				m.ViewMode = ViewTracks
-				m.ActiveList = &m.TrackList
+				// Results show in the search tab
+				m.selectTab(tabSearch)
 				
+				if m.Offline {
+					if m.SearchPodcasts {
+						m.IsLoading = false
+						m.showWarning("Podcast search isn't available offline")
+						return m, nil
+					}
+					return m, OfflineSearchCmd(m.Downloads, query)
+				}
+				
+				if m.SearchPodcasts {
+					return m, tea.Batch(
+						m.Spinner.Tick,
+						SearchPodcastsCmd(m.startSearch(), m.Api, query),
+					)
+				}
 				return m, tea.Batch(
 					m.Spinner.Tick,
-					SearchCmd(m.Api, query),
+					SearchCmd(m.startSearch(), m.Api, query),
 				)
 				
 			default:
@@ -108,8 +251,31 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, cmd
 			}
 		} else {
-			// Not in special mode - handle normal commands
-			switch msg.String() {
+			// The lyrics pane takes the scrolling keys from the hidden list
+			if m.ViewMode == ViewLyrics && m.scrollLyrics(msg.String()) {
+				return m, nil
+			}
+			if m.ViewMode == ViewStats && scrollViewport(&m.StatsView, msg.String()) {
+				return m, nil
+			}
+			if m.ViewMode == ViewLogs && scrollViewport(&m.LogsView, msg.String()) {
+				return m, nil
+			}
+			
+			// Not in special mode - handle normal commands, as rebound in the config file
+			key := m.Keys.resolve(msg.String())
+			
+			// Select mode takes the keys that mark tracks and act on them
+			if m.Selection.active {
+				if cmd, ok := m.updateSelection(key); ok {
+					return m, cmd
+				}
+			}
+			
+			if index, ok := tabKey(key); ok {
+				return m, m.switchTab(index)
+			}
+			switch key {
 			case "ctrl+c", "q":
 				m.Player.Stop()
 				return m, tea.Quit
@@ -122,113 +288,476 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					player.RepeatOne:  "Repeat: One",
 					player.RepeatAll:  "Repeat: All",
 				}
-				m.ErrorMsg = modeNames[mode] // Use error message area to show mode change
+				m.showInfo(modeNames[mode])
 				return m, nil
 				
 			case "s":
 				// Toggle shuffle mode
 				m.Player.ToggleShuffle()
 				if m.Player.Queue.ShuffleMode {
-					m.ErrorMsg = "Shuffle: On"
+					m.showInfo("Shuffle: On")
 				} else {
-					m.ErrorMsg = "Shuffle: Off"
+					m.showInfo("Shuffle: Off")
 				}
 				return m, nil
 				
 			case "n":
 				// Play next track
-				m.ErrorMsg = "" // Clear previous errors
-				if err := m.Player.PlayNext(); err != nil {
-					m.ErrorMsg = "Error playing next track: " + err.Error()
-				}
-				return m, ProgressTickCmd()
+				return m, m.skipTrack(true)
 				
 			case "b":
 				// Play previous track
-				m.ErrorMsg = "" // Clear previous errors
-				if err := m.Player.PlayPrevious(); err != nil {
-					m.ErrorMsg = "Error playing previous track: " + err.Error()
-				}
-				return m, ProgressTickCmd()
+				return m, m.skipTrack(false)
 				
 			case "p":
-				// Toggle between tracks and playlists views
-				if m.ViewMode == ViewTracks {
-					m.ViewMode = ViewPlaylists
-					m.ActiveList = &m.PlaylistList
-					
-					// If we haven't loaded playlists yet, load them now
-					if len(m.Playlists) == 0 {
-						m.IsLoading = true
-						return m, tea.Batch(
-							m.Spinner.Tick,
-							GetPlaylistsCmd(m.Api),
-						)
+				// Go to the playlists tab
+				return m, m.switchTab(tabPlaylists)
+				
+			case "a", "A", playNextKey:
+				// In the queue, "A" moves the selected track to play next
+				if m.ViewMode == ViewQueue && key != "a" {
+					return m, m.moveQueueTrack("A")
+				}
+				
+				// Append to the queue ("a") or play next ("A", Ctrl+Enter) without interrupting playback
+				if !isBrowseView(m.ViewMode) {
+					break
+				}
+				
+				selectedItem, ok := selectedTrack(m.ActiveList)
+				if !ok {
+					return m, nil
+				}
+				return m, m.queueTrack(selectedItem, key != "a")
+				
+			case "m":
+				// Open the actions menu for the selected track
+				track, ok := m.targetTrack()
+				if !ok {
+					return m, nil
+				}
+				m.openMenu(track.TrackTitle, trackMenu(track, m.Likes.ids[track.ID]))
+				return m, nil
+				
+			case "l":
+				// Like the selected (or playing) track, or unlike it if it's liked; other lists keep l for paging
+				track, ok := m.targetTrack()
+				if !ok {
+					break
+				}
+				return m, m.toggleLike(track)
+				
+			case "Q":
+				// Go to the queue tab
+				return m, m.switchTab(tabQueue)
+				
+			case "H":
+				// Go to the home tab, fetching the feed if launch didn't
+				return m, m.switchTab(tabHome)
+				
+			case "C":
+				// Toggle charts and new releases over the current tab
+				if m.ViewMode == ViewExplore {
+					m.popView()
+					return m, nil
+				}
+				m.pushView(ViewExplore)
+				if len(m.ExploreShelves) == 0 {
+					return m, m.loadExplore(m.ChartsCountry)
+				}
+				return m, nil
+				
+			case "U":
+				// Toggle the uploads over the current tab, fetching them on first use
+				if m.ViewMode == ViewUploads {
+					m.popView()
+					return m, nil
+				}
+				
+				m.pushView(ViewUploads)
+				if len(m.UploadShelves) == 0 {
+					m.IsLoading = true
+					return m, tea.Batch(
+						m.Spinner.Tick,
+						GetUploadsCmd(m.ctx, m.Api),
+					)
+				}
+				return m, nil
+				
+			case "L":
+				// Go to the library tab
+				return m, m.switchTab(tabLibrary)
+				
+			case "tab", "shift+tab":
+				// Cycle through the library, artist or home sections, or the tabs in views without sections
+				if m.ViewMode == ViewHome && len(m.HomeShelves) > 0 {
+					m.showHomeShelf(cycleShelf(m.HomeShelves, m.HomeShelf, key == "shift+tab"))
+					return m, nil
+				}
+				if m.ViewMode == ViewExplore && len(m.ExploreShelves) > 0 {
+					m.showExploreShelf(cycleShelf(m.ExploreShelves, m.ExploreShelf, key == "shift+tab"))
+					return m, nil
+				}
+				if m.ViewMode == ViewUploads && len(m.UploadShelves) > 0 {
+					m.showUploadShelf(cycleShelf(m.UploadShelves, m.UploadShelf, key == "shift+tab"))
+					return m, nil
+				}
+				if m.ViewMode == ViewStats {
+					m.showStatsPeriod(m.StatsPeriod.Next(key == "shift+tab"))
+					return m, nil
+				}
+				if m.ViewMode == ViewLogs {
+					m.showLogLevel(m.LogsLevel.Next(key == "shift+tab"))
+					return m, nil
+				}
+				if m.ViewMode == ViewArtist {
+					section := (m.ArtistSection + 1) % artistSectionCount
+					if key == "shift+tab" {
+						section = (m.ArtistSection + artistSectionCount - 1) % artistSectionCount
 					}
+					m.showArtistSection(section)
+					return m, nil
+				}
+				if m.ViewMode != ViewLibrary {
+					return m, m.cycleTab(key == "shift+tab")
+				}
+				
+				section := (m.LibrarySection + 1) % librarySectionCount
+				if key == "shift+tab" {
+					section = (m.LibrarySection + librarySectionCount - 1) % librarySectionCount
+				}
+				return m, m.showLibrarySection(section)
+				
+			case "g":
+				// Go to the selected track's album; other lists keep g for jumping to the top
+				track, ok := m.targetTrack()
+				if !ok {
+					break
+				}
+				
+				if track.AlbumID == "" {
+					m.showWarning("No album available for " + track.TrackTitle)
+					return m, nil
+				}
+				return m, m.openAlbum(track.AlbumID)
+				
+			case "G":
+				// Go to the selected track's artist; other lists keep G for jumping to the bottom
+				track, ok := m.targetTrack()
+				if !ok {
+					break
+				}
+				
+				if track.ArtistID == "" {
+					m.showWarning("No artist page available for " + track.Artist)
+					return m, nil
+				}
+				return m, m.openArtist(track.ArtistID)
+				
+			case "z":
+				// Toggle the visualizer of the now playing view
+				m.toggleVisualizer()
+				return m, nil
+				
+			case "ctrl+f":
+				// Find the playing track in the list shown
+				m.jumpToPlaying()
+				return m, nil
+				
+			case "alt+g", "alt+G":
+				// Go to the playing track's album or artist, whatever is selected
+				return m, m.openPlaying(key == "alt+G")
+				
+			case "ctrl+r":
+				// Start a radio from the artist page, the selected track, or the playing track
+				if m.ViewMode == ViewArtist {
+					if _, ok := m.ActiveList.SelectedItem().(api.Track); !ok {
+						return m, m.startArtistRadio()
+					}
+				}
+				
+				track, ok := m.targetTrack()
+				if !ok {
+					current := m.Player.Queue.GetCurrentTrack()
+					if current == nil {
+						return m, nil
+					}
+					track = *current
+				}
+				return m, m.startRadio(track)
+				
+			case "o":
+				// Toggle refilling the queue with related tracks when it runs dry
+				if m.Player.Queue.ToggleAutoplay() {
+					m.showInfo("Autoplay: On")
 				} else {
-					m.ViewMode = ViewTracks
-					m.ActiveList = &m.TrackList
+					m.showInfo("Autoplay: Off")
+				}
+				return m, nil
+				
+			case "y":
+				// Toggle the lyrics pane for the current track
+				return m, m.toggleLyrics()
+				
+			case "S":
+				// Toggle the listening statistics over the current tab
+				return m, m.toggleStats()
+				
+			case "ctrl+l":
+				// Toggle the debug log viewer over the current tab
+				return m, m.toggleLogs()
+				
+			case "i":
+				// Show everything known about the selected or playing track
+				return m, m.showTrackInfo()
+				
+			case "B":
+				// Cycle the stream quality, which applies from the next stream resolved
+				quality, codec := m.Api.StreamPreferences()
+				quality = quality.Next()
+				m.Api.SetStreamPreferences(quality, codec)
+				m.Config.Audio.Quality = string(quality)
+				m.showInfo(fmt.Sprintf("Stream quality: %s (%s), from the next track", quality, qualityBitrates[quality]))
+				return m, nil
+				
+			case "V":
+				// Switch between audio only and a video window for the rest of the session
+				m.toggleVideo()
+				return m, nil
+				
+			case "M":
+				// Switch between the full layout and the compact player bar
+				m.toggleCompact()
+				return m, nil
+				
+			case "v":
+				// Start marking tracks for a batch action, from the one under the cursor
+				if !isBrowseView(m.ViewMode) {
+					break
+				}
+				if _, ok := selectedTrack(m.ActiveList); !ok {
+					return m, nil
 				}
+				m.Selection.start(m.ActiveList, true)
 				return m, nil
 				
+			case "?":
+				// Show every key binding
+				m.ShowHelp = true
+				return m, nil
+				
+			case "N":
+				// Show recent notifications, including dismissed ones
+				m.ShowMessages = true
+				return m, nil
+				
+			case "I":
+				// Pick the Google or brand account to browse as
+				m.showInfo("Loading accounts...")
+				return m, GetAccountsCmd(m.ctx, m.Api, false)
+				
+			case "esc", "backspace":
+				// Esc first stops a playlist that's still streaming in
+				if key == "esc" && m.LoadingPlaylist != nil {
+					playlist := m.LoadingPlaylist
+					m.stopPlaylistLoad()
+					m.showInfo(fmt.Sprintf("Stopped loading %s at %d tracks", playlist.PlaylistTitle, m.SearchResults))
+					return m, nil
+				}
+				
+				// Go back from an album, artist, podcast, playlist, or lyrics page
+				if len(m.ViewHistory) > 0 {
+					m.popView()
+					return m, nil
+				}
+				
+			case "d":
+				// Remove the selected track from the open playlist
+				if m.ViewMode == ViewTracks && m.OpenPlaylist != nil {
+					track, ok := selectedTrack(&m.TrackList)
+					if !ok {
+						return m, nil
+					}
+					m.PromptTarget = *m.OpenPlaylist
+					m.PromptTrack = track
+					return m, m.openPrompt(promptRemoveFromPlaylist, "")
+				}
+				
+				// Remove the selected track from the queue
+				if m.ViewMode != ViewQueue {
+					break
+				}
+				
+				selectedItem, ok := m.QueueList.SelectedItem().(queueItem)
+				if !ok {
+					return m, nil
+				}
+				if selectedItem.current {
+					m.showWarning("Cannot remove the current track")
+					return m, nil
+				}
+				
+				m.Player.Queue.Remove(selectedItem.index)
+				m.refreshQueueList()
+				m.showInfo("Removed " + selectedItem.track.TrackTitle + " from queue")
+				return m, nil
+				
+			case "J", "K", "ctrl+down", "ctrl+up", "T":
+				// Reorder the queue: move the selected track down, up, or to the top
+				if m.ViewMode != ViewQueue {
+					break
+				}
+				return m, m.moveQueueTrack(key)
+				
+			case "P":
+				// Pick a playlist to add the selected (or playing) track to
+				track, ok := selectedTrack(m.ActiveList)
+				if !ok {
+					if current := m.Player.Queue.GetCurrentTrack(); current != nil {
+						track, ok = *current, true
+					}
+				}
+				if !ok {
+					return m, nil
+				}
+				return m, m.pickPlaylist(track)
+				
+			case "c":
+				// Pick the charts' country, or create a playlist from the playlists view
+				if m.ViewMode == ViewExplore {
+					return m, m.openPrompt(promptChartsCountry, m.ChartsCountry)
+				}
+				if m.ViewMode == ViewPlaylists {
+					return m, m.openPrompt(promptCreatePlaylist, "")
+				}
+				
+				// Elsewhere copy the link of the selected track, or of the playing one
+				track, ok := m.targetTrack()
+				if !ok || m.ViewMode == ViewLyrics {
+					current := m.Player.Queue.GetCurrentTrack()
+					if current == nil {
+						m.showWarning("No track to copy the link of")
+						return m, nil
+					}
+					track = *current
+				}
+				return m, CopyLinkCmd(track)
+				
+			case "e", "D":
+				// Rename or delete the selected playlist
+				if m.ViewMode != ViewPlaylists {
+					break
+				}
+				
+				selectedItem, ok := m.PlaylistList.SelectedItem().(api.Playlist)
+				if !ok {
+					return m, nil
+				}
+				m.PromptTarget = selectedItem
+				
+				if key == "e" {
+					return m, m.openPrompt(promptRenamePlaylist, selectedItem.PlaylistTitle)
+				}
+				return m, m.openPrompt(promptDeletePlaylist, "")
+				
+			case "x":
+				// Export the selected playlist to a file
+				if m.ViewMode != ViewPlaylists {
+					break
+				}
+				
+				selectedItem, ok := m.PlaylistList.SelectedItem().(api.Playlist)
+				if !ok {
+					return m, nil
+				}
+				m.PromptTarget = selectedItem
+				path := filepath.Join(playlistfile.Dir(), playlistfile.FileName(selectedItem.PlaylistTitle, playlistfile.FormatM3U))
+				return m, m.openPrompt(promptExportPlaylist, path)
+				
 			case "R":
 				// Enter reset mode to confirm cookie reset
 				m.ResetMode = true
 				return m, nil
 			
+			case "left", "right", ",", ".":
+				// Without a loaded track, let the list use ←/→ for paging
+				if m.Player.Queue.GetCurrentTrack() == nil {
+					break
+				}
+				
+				var offset int
+				switch key {
+				case "left":
+					offset = -m.SeekStep
+				case "right":
+					offset = m.SeekStep
+				case ",":
+					offset = -m.SeekStepLong
+				case ".":
+					offset = m.SeekStepLong
+				}
+				
+				if err := m.Player.Seek(offset); err != nil {
+					m.showError("Error seeking: " + err.Error())
+				}
+				return m, nil
+				
+			case "+", "=":
+				if err := m.Player.SetVolume(m.Player.Volume + 5); err != nil {
+					m.showError("Error setting volume: " + err.Error())
+				} else {
+					m.showInfo(fmt.Sprintf("Volume: %d%%", m.Player.Volume))
+				}
+				return m, nil
+				
+			case "-":
+				if err := m.Player.SetVolume(m.Player.Volume - 5); err != nil {
+					m.showError("Error setting volume: " + err.Error())
+				} else {
+					m.showInfo(fmt.Sprintf("Volume: %d%%", m.Player.Volume))
+				}
+				return m, nil
+				
 			case "/":
 				m.SearchMode = true
 				m.SearchInput.Focus()
 				return m, nil
 			
 			case " ":
-				if m.Player.IsPlaying || (!m.Player.IsPlaying && m.Player.Queue.GetCurrentTrack() != nil) {
-					m.Player.TogglePause()
-					if m.Player.IsPlaying {
-						return m, ProgressTickCmd()
-					}
-				}
-				return m, nil
+				return m, m.togglePlayback()
 			
 			case "enter":
 				if m.ActiveList.Items() == nil || len(m.ActiveList.Items()) == 0 {
 					return m, nil
 				}
 				
-				m.ErrorMsg = "" // Clear previous errors
+				m.clearStatus() // Clear previous errors
 				
-				if m.ViewMode == ViewTracks {
-					// Handle track selection
-					selectedItem, ok := m.ActiveList.SelectedItem().(api.Track)
-					if !ok {
-						return m, nil
+				if isBrowseView(m.ViewMode) {
+					switch selectedItem := m.ActiveList.SelectedItem().(type) {
+					case api.Track, api.Episode:
+						return m, m.playFromList(m.ActiveList)
+					case api.Album:
+						return m, m.openAlbum(selectedItem.ID)
+					case api.Artist:
+						return m, m.openArtist(selectedItem.ID)
+					case api.Playlist:
+						return m, m.openPlaylist(selectedItem)
+					case api.Podcast:
+						return m, m.openPodcast(selectedItem.ID)
 					}
-					
-					// Update the queue with the selected track and all following tracks
-					// First, get all tracks from the current list
-					allTracks := make([]api.Track, len(m.TrackList.Items()))
-					for i, item := range m.TrackList.Items() {
-						if track, ok := item.(api.Track); ok {
-							allTracks[i] = track
-						}
-					}
-					
-					// Set the queue to all tracks, starting from the selected one
-					selectedIndex := m.TrackList.Index()
-					m.Player.Queue.Clear()
-					m.Player.Queue.AddTracks(allTracks[selectedIndex:])
-					
-					// Add tracks before the selected one to the end if repeat all is enabled
-					if m.Player.Queue.RepeatMode == player.RepeatAll && selectedIndex > 0 {
-						m.Player.Queue.AddTracks(allTracks[:selectedIndex])
+					return m, nil
+				} else if m.ViewMode == ViewQueue {
+					// Jump to the selected queued track
+					selectedItem, ok := m.ActiveList.SelectedItem().(queueItem)
+					if !ok || !m.Player.Queue.PlayTrack(selectedItem.index) {
+						return m, nil
 					}
 					
-					// Play the first track in the queue (which is the selected one)
 					m.IsLoading = true
-					
 					return m, tea.Batch(
 						m.Spinner.Tick,
-						GetStreamURLCmd(m.Api, selectedItem.ID),
+						m.getStreamCmd(selectedItem.track.ID),
 					)
 				} else if m.ViewMode == ViewPlaylists {
 					// Handle playlist selection
@@ -238,26 +767,56 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 					
 					// Load tracks from the selected playlist
-					m.IsLoading = true
-					return m, tea.Batch(
-						m.Spinner.Tick,
-						GetPlaylistTracksCmd(m.Api, selectedItem.ID),
-					)
+					return m, m.openPlaylist(selectedItem)
 				}
 			}
 		}
 		
+	case loginCompletedMsg:
+		m.IsLoading = false
+		
+		if msg.err != nil {
+			m.showError("Login failed: " + msg.err.Error())
+			return m, nil
+		}
+		
+		m.LoginInput.SetValue("")
+		m.LoginMode = false
+		m.SessionExpired = false
+		m.loginModTime = m.Api.CredentialsModTime()
+		m.IsLoading = true
+		return m, tea.Batch(
+			m.Spinner.Tick,
+			GetPlaylistsCmd(m.ctx, m.Api),
+			GetHomeCmd(m.ctx, m.Api),
+			m.openStartView(),
+			m.startSessionChecks(),
+			m.offerAccountsCmd(),
+		)
+		
 	case searchResultMsg:
+		if canceled(msg.err) {
+			return m, nil
+		}
+		if msg.more {
+			return m, m.appendSearchPage(msg)
+		}
+		m.stopSearch()
 		m.IsLoading = false
+		m.stopPlaylistLoad() // Search results replace any playlist still loading
+		m.OpenPlaylist = nil
 		
 		if msg.err != nil {
-			m.ErrorMsg = "Search error: " + msg.err.Error()
 			m.SearchResults = 0
+			if m.offerBridgeSetup(msg.err) {
+				return m, nil
+			}
+			m.showAPIError("Search error", msg.err)
 			return m, nil
 		}
 		
 		if len(msg.tracks) == 0 {
-			m.ErrorMsg = "No results found for: " + m.SearchInput.Value()
+			m.showWarning("No results found for: " + m.SearchInput.Value())
 			m.SearchResults = 0
 			return m, nil
 		}
@@ -274,18 +833,105 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.TrackList.SetItems(items)
 		m.SearchInput.SetValue("")
 		m.SearchResults = len(msg.tracks)
+		
+		// Further pages load as the cursor nears the end of the list
+		m.searchQuery, m.listMore = msg.query, msg.continuation
+		return m, m.loadMore()
+		
+	case trackInfoMsg:
+		m.updateTrackInfo(msg)
+		return m, nil
+		
+	case accountsMsg:
+		return m, m.updateAccounts(msg)
+		
+	case sessionStatusMsg:
+		return m, m.updateSessionStatus(msg)
+		
+	case credentialsStatMsg:
+		return m, m.handleCredentialsStat(msg)
+		
+	case bridgeSetupMsg:
+		m.IsLoading = false
+		
+		if m.Onboarding != nil {
+			return m, m.handleOnboardingSetup(msg.err)
+		}
+		
+		if msg.err != nil {
+			m.SetupDeclined = true
+			m.showError("Could not set up the Python bridge: " + msg.err.Error())
+			return m, nil
+		}
+		
+		// Reload everything fetched while the bridge was missing
+		m.showInfo("Installed ytmusicapi - the Python bridge is ready")
+		return m, CheckLoginCmd(m.Api)
+		
+	case homeResultMsg:
+		m.IsLoading = false
+		
+		if msg.err != nil {
+			m.showAPIError("Error fetching home feed", msg.err)
+			return m, nil
+		}
+		if len(msg.shelves) == 0 {
+			m.showWarning("Nothing on your home feed yet - press / to search")
+			return m, nil
+		}
+		
+		m.HomeShelves = msg.shelves
+		m.showHomeShelf(0)
+		return m, nil
+		
+	case uploadsResultMsg:
+		m.IsLoading = false
+		
+		if msg.err != nil && uploadsEmpty(msg.shelves) {
+			m.showAPIError("Error fetching uploads", msg.err)
+			return m, nil
+		}
+		if msg.err != nil {
+			m.showWarning("Some uploads could not be loaded: " + msg.err.Error())
+		} else if uploadsEmpty(msg.shelves) {
+			m.showWarning("You haven't uploaded any music yet")
+		}
+		
+		m.UploadShelves = msg.shelves
+		m.showUploadShelf(0)
+		return m, nil
+		
+	case exploreResultMsg:
+		m.IsLoading = false
+		
+		// A newer country was requested while this one loaded
+		if msg.country != m.ChartsCountry {
+			return m, nil
+		}
+		if msg.err != nil {
+			m.showAPIError("Error fetching charts", msg.err)
+			return m, nil
+		}
+		
+		m.ExploreShelves = msg.shelves
+		m.showExploreShelf(0)
 		return m, nil
 		
 	case playlistsResultMsg:
 		m.IsLoading = false
 		
 		if msg.err != nil {
-			m.ErrorMsg = "Error fetching playlists: " + msg.err.Error()
+			if m.offerBridgeSetup(msg.err) {
+				return m, nil
+			}
+			m.showAPIError("Error fetching playlists", msg.err)
 			return m, nil
 		}
 		
 		if len(msg.playlists) == 0 {
-			m.ErrorMsg = "No playlists found"
+			m.Playlists = nil
+			m.PlaylistList.SetItems([]list.Item{})
+			m.showWarning("No playlists found")
 			return m, nil
 		}
 		
@@ -303,15 +949,30 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, nil
 		
 	case playlistTracksResultMsg:
+		// Ignore pages of a playlist we've since navigated away from or stopped loading
+		if canceled(msg.err) || m.LoadingPlaylist == nil || m.LoadingPlaylist.ID != msg.playlistID {
+			return m, nil
+		}
+		playlist := m.LoadingPlaylist
+		
+		if msg.more {
+			return m, m.appendPlaylistPage(playlist, msg)
+		}
+		
 		m.IsLoading = false
 		
 		if msg.err != nil {
-			m.ErrorMsg = "Error fetching playlist tracks: " + msg.err.Error()
+			m.stopPlaylistLoad()
+			if m.offerBridgeSetup(msg.err) {
+				return m, nil
+			}
+			m.showAPIError("Error fetching playlist tracks", msg.err)
 			return m, nil
 		}
 		
 		if len(msg.tracks) == 0 {
-			m.ErrorMsg = "No tracks found in playlist"
+			m.stopPlaylistLoad()
+			m.showWarning("No tracks found in playlist")
 			return m, nil
 		}
 		
@@ -321,106 +982,400 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			items[i] = track
 		}
 		
-		// Switch to tracks view
-		m.ViewMode = ViewTracks
-		m.ActiveList = &m.TrackList
+		// Show the playlist's tracks, with Esc returning to where it was opened
+		if m.ViewMode != ViewTracks || m.OpenPlaylist == nil || m.OpenPlaylist.ID != playlist.ID {
+			m.pushView(ViewTracks)
+		}
 		m.TrackList.SetItems(items)
 		m.SearchResults = len(msg.tracks)
+		m.OpenPlaylist = playlist
+		m.stopPlaylistLoad()
 		
-		// Update error message to show success
-		selectedPlaylist, ok := m.PlaylistList.SelectedItem().(api.Playlist)
-		if ok {
-			m.ErrorMsg = "Loaded " + selectedPlaylist.PlaylistTitle + " with " + 
-				fmt.Sprintf("%d", m.SearchResults) + " tracks"
-		} else {
-			m.ErrorMsg = "Loaded playlist with " + fmt.Sprintf("%d", m.SearchResults) + " tracks"
+		// Tracks listed without their length or artwork fill in as they're looked up
+		enrichCmd := m.enrichTracks(playlist.ID, msg.tracks)
+		
+		// The remaining pages load as the cursor nears the end of the list
+		m.listMore = msg.continuation
+		if msg.continuation != "" {
+			m.showInfo(playlistProgress(playlist, m.SearchResults))
+			return m, tea.Batch(m.loadMore(), enrichCmd)
+		}
+		
+		m.showInfo("Loaded " + playlist.PlaylistTitle + " with " +
+			fmt.Sprintf("%d", m.SearchResults) + " tracks")
+		return m, enrichCmd
+		
+	case enrichedMsg:
+		return m, m.handleEnriched(msg)
+		
+	case visualizerFrameMsg:
+		m.handleVisualizerFrame(msg)
+		return m, nil
+		
+	case playingPageMsg:
+		return m, m.handlePlayingPage(msg)
+		
+	case likesMsg:
+		m.handleLikes(msg)
+		return m, nil
+		
+	case likeMsg:
+		m.handleLike(msg)
+		return m, nil
+		
+	case librarySectionMsg:
+		m.IsLoading = false
+		
+		if msg.err != nil {
+			m.showAPIError("Error fetching " + msg.section.String(), msg.err)
+			return m, nil
+		}
+		
+		// Cache the section so switching back doesn't refetch it
+		m.LibraryItems[msg.section] = msg.items
+		if msg.section == LibraryLikedSongs {
+			m.Likes.setItems(msg.items)
+		}
+		if m.LibrarySection == msg.section {
+			m.LibraryList.SetItems(msg.items)
+		}
+		if len(msg.items) == 0 {
+			m.showWarning("No " + msg.section.String() + " in your library")
+		}
+		return m, nil
+		
+	case podcastSearchMsg:
+		if canceled(msg.err) {
+			return m, nil
+		}
+		m.stopSearch()
+		m.IsLoading = false
+		m.stopPlaylistLoad()
+		m.OpenPlaylist = nil
+		
+		if msg.err != nil {
+			m.showAPIError("Podcast search error", msg.err)
+			m.SearchResults = 0
+			return m, nil
+		}
+		
+		if len(msg.items) == 0 {
+			m.showWarning("No podcasts found for: " + m.SearchInput.Value())
+			m.SearchResults = 0
+			return m, nil
+		}
+		
+		// Shows and episodes share the tracks view
+		m.setView(ViewTracks)
+		m.TrackList.SetItems(msg.items)
+		m.SearchInput.SetValue("")
+		m.SearchResults = len(msg.items)
+		m.listMore = ""
+		return m, nil
+		
+	case podcastResultMsg:
+		m.IsLoading = false
+		
+		if msg.err != nil {
+			m.showAPIError("Error fetching podcast", msg.err)
+			return m, nil
+		}
+		
+		m.showPodcast(msg.podcast)
+		return m, nil
+		
+	case albumResultMsg:
+		m.IsLoading = false
+		
+		if msg.err != nil {
+			m.showAPIError("Error fetching album", msg.err)
+			return m, nil
+		}
+		
+		m.showAlbum(msg.album)
+		return m, nil
+		
+	case artistResultMsg:
+		m.IsLoading = false
+		
+		if msg.err != nil {
+			m.showAPIError("Error fetching artist", msg.err)
+			return m, nil
+		}
+		
+		// Another artist opened from an artist page goes back to the first
+		if m.ViewMode != ViewArtist || m.CurrentArtist == nil || m.CurrentArtist.ID != msg.artist.ID {
+			m.pushView(ViewArtist)
+		}
+		m.CurrentArtist = msg.artist
+		m.showArtistSection(ArtistTopSongs)
+		return m, nil
+		
+	case radioResultMsg:
+		m.IsLoading = false
+		
+		if msg.err != nil {
+			m.showAPIError("Error starting radio", msg.err)
+			return m, nil
+		}
+		
+		if len(msg.tracks) == 0 {
+			m.showWarning("No radio tracks found")
+			return m, nil
+		}
+		
+		// The radio replaces the queue and starts playing right away
+		m.Player.Queue.Clear()
+		added := m.Player.Queue.AddTracks(msg.tracks)
+		m.showInfo(fmt.Sprintf("Started radio with %d tracks", added))
+		
+		m.IsLoading = true
+		return m, tea.Batch(
+			m.Spinner.Tick,
+			m.getStreamCmd(msg.tracks[0].ID),
+		)
+		
+	case autoplayResultMsg:
+		if msg.err != nil {
+			// A fetch ahead of time is tried again when the track ends
+			if !msg.ahead {
+				m.showAPIError("Autoplay failed", msg.err)
+			}
+			return m, nil
+		}
+		
+		// The radio starts with the seed track, so skip anything already queued
+		added := 0
+		for _, track := range msg.tracks {
+			if !m.Player.Queue.Contains(track.ID) {
+				m.Player.Queue.Add(track)
+				added++
+			}
+		}
+		if added > 0 && m.ViewMode == ViewQueue {
+			m.refreshQueueList()
 		}
+		if msg.ahead {
+			// The last track plays on; the new ones follow it, preloaded like any other
+			if added > 0 {
+				m.showInfo(fmt.Sprintf("Autoplay queued %d related tracks", added))
+			}
+			return m, nil
+		}
+		
+		// The fetch ahead of time may have queued the tracks already
+		nextTrack, ok := m.Player.Queue.NextTrack()
+		if !ok || nextTrack == nil {
+			m.showWarning("Autoplay found no new tracks")
+			return m, nil
+		}
+		
+		m.showInfo(fmt.Sprintf("Autoplay added %d related tracks", added))
+		m.IsLoading = true
+		return m, tea.Batch(
+			m.Spinner.Tick,
+			m.getStreamCmd(nextTrack.ID),
+		)
+		
+	case logsMsg:
+		return m, m.updateLogs(msg)
 		
+	case playsMsg:
+		if msg.err != nil {
+			m.StatsView.SetContent(errorStyle.Render("Could not read the listening history: " + msg.err.Error()))
+			return m, nil
+		}
+		
+		m.Plays = msg.plays
+		m.refreshStats()
+		return m, nil
+		
+	case lyricsResultMsg:
+		// Drop lyrics for a track that's no longer playing
+		if current := m.Player.Queue.GetCurrentTrack(); current == nil || current.ID != msg.videoID {
+			return m, nil
+		}
+		
+		if msg.err != nil {
+			m.LyricsView.SetContent(errorStyle.Render("Lyrics unavailable: " + msg.err.Error()))
+			return m, nil
+		}
+		
+		m.Lyrics = msg.lyrics
+		m.LyricsView.GotoTop()
+		m.refreshLyrics()
+		return m, nil
+		
+	case mediaCommandMsg:
+		return m, tea.Batch(WaitForMediaCommandCmd(msg.controls), m.handleMediaCommand(msg.command))
+		
+	case networkStatusMsg:
+		return m, m.updateNetworkStatus(msg)
+		
+	case statusMsg:
+		m.notify(msg.level, msg.text)
+		return m, nil
+		
+	case playlistExportedMsg:
+		m.IsLoading = false
+		
+		if msg.err != nil {
+			m.showAPIError("Error exporting "+msg.playlist.PlaylistTitle, msg.err)
+			return m, nil
+		}
+		m.showInfo(fmt.Sprintf("Exported %d tracks to %s", msg.count, msg.path))
+		return m, nil
+		
+	case playlistEditedMsg:
+		m.IsLoading = false
+		
+		if msg.err != nil {
+			m.showAPIError("Error editing playlist", msg.err)
+			return m, nil
+		}
+		
+		// Reload so the list reflects the change
+		m.showInfo(msg.status)
+		return m, GetPlaylistsCmd(m.ctx, m.Api)
+		
+	case playlistItemRemovedMsg:
+		m.IsLoading = false
+		
+		if msg.err != nil {
+			m.showAPIError("Error removing track", msg.err)
+			return m, nil
+		}
+		m.showInfo("Removed " + msg.track.TrackTitle + " from playlist")
+		
+		// Drop the entry from the track list if that playlist is still shown
+		if m.OpenPlaylist == nil || m.OpenPlaylist.ID != msg.playlistID {
+			return m, nil
+		}
+		for i, item := range m.TrackList.Items() {
+			if track, ok := item.(api.Track); ok && track.SetVideoID == msg.track.SetVideoID {
+				m.TrackList.RemoveItem(i)
+				m.SearchResults--
+				break
+			}
+		}
 		return m, nil
 		
 	case streamURLMsg:
 		m.IsLoading = false
 		
 		if msg.err != nil {
-			m.ErrorMsg = "Error getting stream: " + msg.err.Error()
+			m.showAPIError("Error getting stream", msg.err)
+			return m, nil
+		}
+		
+		if err := m.checkPlayable(msg.url); err != nil {
+			m.showError("Can't play: " + err.Error())
 			return m, nil
 		}
 		
 		// Get the current track from the queue
 		currentTrack := m.Player.Queue.GetCurrentTrack()
 		if currentTrack == nil {
-			m.ErrorMsg = "Error: No track in queue"
+			m.showError("Error: No track in queue")
 			return m, nil
 		}
 		
-		// Play the track
-		err := m.Player.Play(msg.url, currentTrack.Duration)
+		// Play the track, preferring the extractor's duration over the listing's
+		duration := currentTrack.Duration
+		if msg.duration > 0 {
+			duration = msg.duration
+		}
+		trackingCmd := m.finishTracking(m.Player.CurrentPos)
+		err := m.Player.Play(msg.url, duration)
 		if err != nil {
-			m.ErrorMsg = "Error playing track: " + err.Error()
-			return m, nil
+			m.showError("Error playing track: " + err.Error())
+			return m, trackingCmd
 		}
 		
-		// Update current track info
-		m.CurrentTrack = *currentTrack
+		return m, tea.Batch(m.pollProgress(), trackingCmd, m.trackStarted(*currentTrack))
 		
-		// Important! Update duration with the real duration from the player
-		if m.Player.Duration > 0 && m.Player.Duration != m.CurrentTrack.Duration {
-			updatedTrack := m.CurrentTrack
-			updatedTrack.Duration = m.Player.Duration
-			m.CurrentTrack = updatedTrack
-			
-			// Also update the track in the queue
-			for i, track := range m.Player.Queue.Tracks {
-				if track.ID == m.CurrentTrack.ID {
-					m.Player.Queue.Tracks[i].Duration = m.Player.Duration
-					break
-				}
-			}
+	case preloadMsg:
+		return m, m.finishPreload(msg)
+		
+	case playbackStartedMsg:
+		if msg.err != nil {
+			m.Api.LogDebug("Could not report playback of %s: %v", msg.videoID, msg.err)
+			return m, nil
 		}
 		
-		return m, ProgressTickCmd()
+		// Ignore sessions for a track that's already been skipped
+		if msg.videoID == m.CurrentTrack.ID {
+			m.Tracking = msg.session
+		}
+		return m, nil
 		
 	case cookieResetMsg:
 		m.IsLoading = false
 		m.ResetMode = false
 		
 		if msg.err != nil {
-			m.ErrorMsg = "Error resetting cookies: " + msg.err.Error()
+			m.showAPIError("Error resetting cookies", msg.err)
 			return m, nil
 		}
 		
 		m.LoginMode = true
+		m.loginModTime = m.Api.CredentialsModTime()
 		return m, nil
 		
-	case progressMsg:
-		if m.Player.IsPlaying {
-			m.Player.CurrentPos++
+	case playerEventMsg:
+		// Always keep listening for the next event
+		cmds = append(cmds, WaitForPlayerEventCmd(m.Player))
+		
+		// The player's state only changes here, on the UI goroutine
+		if !m.Player.HandleEvent(msg.event) {
+			return m, tea.Batch(cmds...)
+		}
+		if msg.event.Type == player.TrackAdvanced {
+			cmds = append(cmds, m.trackAdvanced(msg.event.Track))
+		}
+		if msg.event.Type == player.DurationFound {
+			// yt-dlp found the length after mpv started; the progress bar follows
+			m.syncDuration()
+		}
+		if msg.event.Type == player.TrackEnded {
+			cmds = append(cmds, m.finishTracking(m.Player.Duration))
+			m.Player.CurrentPos = 0
 			
-			if m.Player.CurrentPos >= m.Player.Duration {
-				// The track has ended
-				m.Player.CurrentPos = 0
-				
-				// Try to play the next track automatically
-				if nextTrack, ok := m.Player.Queue.NextTrack(); ok && nextTrack != nil {
-					// Get stream URL and play
-					go func() {
-						url, err := m.Api.GetStreamURL(nextTrack.ID)
-						if err == nil {
-							m.Player.Play(url, nextTrack.Duration)
-							
-							// Update current track info
-							m.CurrentTrack = *nextTrack
-						}
-					}()
-				} else {
-					m.Player.IsPlaying = false
+			// Auto-advance to the next track in the queue
+			nextTrack, ok := m.Player.Queue.NextTrack()
+			if !ok || nextTrack == nil {
+				// The queue ran dry; keep going with tracks related to the last one
+				if current := m.Player.Queue.GetCurrentTrack(); m.Player.Queue.Autoplay && current != nil {
+					cmds = append(cmds, GetAutoplayCmd(m.ctx, m.Api, current.ID, false))
 				}
+				return m, tea.Batch(cmds...)
 			}
 			
-			if m.Player.IsPlaying {
-				return m, ProgressTickCmd()
-			}
+			m.IsLoading = true
+			cmds = append(cmds, m.Spinner.Tick, m.getStreamCmd(nextTrack.ID))
+		}
+		return m, tea.Batch(cmds...)
+		
+	case progressMsg:
+		m.progressPolling = false
+		if !m.Player.Active() {
+			return m, nil
 		}
+		
+		// The position, pause state and buffering all come from mpv, so seeks and
+		// pauses made outside the app show up too
+		m.Player.SyncPosition()
+		
+		// Timed lyrics highlight the line being sung
+		if m.Player.IsPlaying && m.ViewMode == ViewLyrics && m.Lyrics != nil && m.Lyrics.Timed() {
+			m.refreshLyrics()
+		}
+		
+		// Track end is reported by the player, polling only drives the display
+		return m, tea.Batch(m.pollProgress(), m.preloadNext())
+		
+	case tea.MouseMsg:
+		m.handleMouse(msg)
 		return m, nil
 		
 	case tea.WindowSizeMsg:
@@ -429,7 +1384,7 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		
 		// Update list sizes more conservatively
 		listWidth := msg.Width - 6  // Account for borders and padding
-		listHeight := msg.Height - 12  // Reserve space for other UI elements
+		listHeight := msg.Height - 14  // Reserve space for the tab bar and other UI elements
 		
 		// Ensure minimum sizes
 		if listWidth < 20 {
@@ -442,6 +1397,21 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Update both lists using SetSize instead of separate Width/Height calls
 		m.TrackList.SetSize(listWidth, listHeight)
 		m.PlaylistList.SetSize(listWidth, listHeight)
+		m.QueueList.SetSize(listWidth, listHeight)
+		m.LibraryList.SetSize(listWidth, listHeight)
+		m.AlbumList.SetSize(listWidth, listHeight)
+		m.ArtistList.SetSize(listWidth, listHeight)
+		m.PickerList.SetSize(listWidth, listHeight)
+		m.HomeList.SetSize(listWidth, listHeight)
+		m.ExploreList.SetSize(listWidth, listHeight)
+		m.EpisodeList.SetSize(listWidth, listHeight)
+		m.UploadsList.SetSize(listWidth, listHeight)
+		m.LyricsView.Width = listWidth
+		m.LyricsView.Height = listHeight
+		m.StatsView.Width = listWidth
+		m.StatsView.Height = listHeight - 2 // The period tabs take two lines
+		m.LogsView.Width = listWidth
+		m.LogsView.Height = listHeight - 2 // The level tabs take two lines
 		
 		// Update progress bar width
 		progressWidth := msg.Width - 10
@@ -455,20 +1425,26 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case spinner.TickMsg:
 		var spinnerCmd tea.Cmd
 		m.Spinner, spinnerCmd = m.Spinner.Update(msg)
-		if m.IsLoading {
+		if m.IsLoading || m.Onboarding.busy() {
 			cmds = append(cmds, spinnerCmd)
 		}
 	}
 	
 	// Handle list and input updates
-	if m.SearchMode {
+	if m.LoginMode {
+		m.LoginInput, cmd = m.LoginInput.Update(msg)
+		cmds = append(cmds, cmd)
+	} else if m.Prompt != promptNone {
+		m.PromptInput, cmd = m.PromptInput.Update(msg)
+		cmds = append(cmds, cmd)
+	} else if m.SearchMode {
 		m.SearchInput, cmd = m.SearchInput.Update(msg)
 		cmds = append(cmds, cmd)
 	} else {
-		// Update the active list
-		if m.ActiveList != nil {
+		// Update the active list, which is hidden behind the lyrics pane and stats
+		if m.ActiveList != nil && m.ViewMode != ViewLyrics && m.ViewMode != ViewStats && m.ViewMode != ViewLogs {
 			*m.ActiveList, cmd = m.ActiveList.Update(msg)
-			cmds = append(cmds, cmd)
+			cmds = append(cmds, cmd, m.loadMore())
 		}
 	}
 	
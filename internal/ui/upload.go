@@ -0,0 +1,57 @@
+package ui
+
+import (
+	"context"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/api"
+)
+
+// GetUploadsCmd fetches the user's uploaded songs, albums and artists as one shelf each
+func GetUploadsCmd(ctx context.Context, ytApi *api.YouTubeMusicAPI) tea.Cmd {
+	return func() tea.Msg {
+		songs := api.Shelf{Title: "Songs"}
+		albums := api.Shelf{Title: "Albums"}
+		artists := api.Shelf{Title: "Artists"}
+
+		// Keep whatever loaded; the first failure is reported alongside it
+		tracks, firstErr := ytApi.GetUploadedSongs(ctx)
+		for _, track := range tracks {
+			songs.Items = append(songs.Items, track)
+		}
+
+		uploadedAlbums, err := ytApi.GetUploadedAlbums(ctx)
+		if firstErr == nil {
+			firstErr = err
+		}
+		for _, album := range uploadedAlbums {
+			albums.Items = append(albums.Items, album)
+		}
+
+		uploadedArtists, err := ytApi.GetUploadedArtists(ctx)
+		if firstErr == nil {
+			firstErr = err
+		}
+		for _, artist := range uploadedArtists {
+			artists.Items = append(artists.Items, artist)
+		}
+
+		return uploadsResultMsg{shelves: []api.Shelf{songs, albums, artists}, err: firstErr}
+	}
+}
+
+// showUploadShelf fills the uploads list with one of its tabs
+func (m *Model) showUploadShelf(index int) {
+	m.UploadShelf = showShelf(&m.UploadsList, m.UploadShelves, index)
+	m.UploadsList.Title = "YouTube Music - Uploads: " + m.UploadShelves[m.UploadShelf].Title
+}
+
+// uploadsEmpty reports whether none of the upload tabs have anything in them
+func uploadsEmpty(shelves []api.Shelf) bool {
+	for _, shelf := range shelves {
+		if len(shelf.Items) > 0 {
+			return false
+		}
+	}
+	return true
+}
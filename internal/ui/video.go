@@ -0,0 +1,37 @@
+package ui
+
+import (
+	"ytmusic/internal/player"
+)
+
+// toggleVideo switches mpv between audio only and a video window, restarting the
+// playing track where it was. Without yt-dlp or a display, playback stays audio only.
+func (m *Model) toggleVideo() {
+	if !m.Player.Video {
+		if err := player.VideoAvailable(); err != nil {
+			m.showWarning("Staying audio only: " + err.Error())
+			return
+		}
+	}
+	m.Player.Video = !m.Player.Video
+	state := "Off"
+	if m.Player.Video {
+		state = "On"
+	}
+
+	// A preloaded stream is of the other kind
+	m.Player.DropPreload()
+	m.Preloading = ""
+
+	// Downloads have no video, and a paused track is left as it is until the next one
+	current := m.Player.Queue.GetCurrentTrack()
+	if current == nil || !m.Player.IsPlaying || m.Downloads.Path(current.ID) != "" {
+		m.showInfo("Video: " + state + ", from the next track")
+		return
+	}
+	if err := m.Player.Restart(player.WatchURL(current.ID), m.Player.CurrentPos); err != nil {
+		m.showError("Error restarting playback: " + err.Error())
+		return
+	}
+	m.showInfo("Video: " + state)
+}
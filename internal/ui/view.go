@@ -2,33 +2,41 @@ package ui
 
 import (
 	"fmt"
+	"path/filepath"
 	"strings"
 	
+	"github.com/charmbracelet/lipgloss"
+	"github.com/mattn/go-runewidth"
+	
+	"ytmusic/internal/api"
+	"ytmusic/internal/paths"
 	"ytmusic/internal/player"
 )
 
 // View renders the UI and returns it as a string
 func (m *Model) View() string {
+	defer m.recoverCrash()
+	m.progressArea = progressArea{} // Until a view draws it
 	if m.ResetMode {
 		return appStyle.Render(
 			titleStyle.Render("Reset YouTube Music Cookie") + "\n\n" +
 			warningStyle.Render("Are you sure you want to reset your login credentials?") + "\n" +
-			"This will remove the current cookie and require you to log in again.\n\n" +
+			"This will remove the current cookie, and any ytmusicapi credentials,\n" +
+			"and require you to log in again, as ytmusic logout does.\n\n" +
 			"Press 'y' to confirm or 'n' to cancel.")
 	}
 	
-	if m.LoginMode {
-		return appStyle.Render(
-			titleStyle.Render("YouTube Music TUI") + "\n\n" +
-			"You need to authenticate with YouTube Music to use this application.\n\n" +
-			warningStyle.Render("Recommended: OAuth Authentication") + "\n" +
-			"1. Follow the OAuth setup guide in the README.md\n" +
-			"2. Run: ytmusicapi oauth --file ~/.ytmusic/oauth_auth.json\n\n" +
-			warningStyle.Render("Alternative: Browser Authentication") + "\n" +
-			"1. Run: ytmusicapi browser --file ~/.ytmusic/headers_auth.json\n" +
-			"2. Follow the browser header copying instructions\n\n" +
-			"Then restart this application.\n\n" +
-			"Press 'q' to quit.")
+	if m.Onboarding != nil {
+		return appStyle.Render(renderOnboarding(m))
+	}
+	
+	if m.LoginMode && !m.IsLoading {
+		return appStyle.Render(renderLoginView(m))
+	}
+	
+	// Tiny windows and tmux splits get a player bar of one or two lines
+	if m.compact() && !m.ShowHelp && !m.ShowMessages && m.Menu == nil && m.Info == nil && m.Prompt == promptNone && !m.SearchMode {
+		return renderCompact(m)
 	}
 	
 	if m.IsLoading {
@@ -37,13 +45,20 @@ func (m *Model) View() string {
 			m.Spinner.View() + " Loading...")
 	}
 	
-	var s strings.Builder
+	if m.ShowHelp {
+		return appStyle.Render(renderHelp(m))
+	}
 	
-	// Error message
-	if m.ErrorMsg != "" {
-		s.WriteString(errorStyle.Render(m.ErrorMsg) + "\n\n")
+	if m.ShowMessages {
+		return appStyle.Render(renderMessageLog(m))
 	}
 	
+	var s strings.Builder
+	
+	// Latest notification
+	s.WriteString(renderStatus(m))
+	s.WriteString(renderMissingPlayer(m))
+	
 	// Currently active list
 	var listView string
 	if m.ViewMode == ViewTracks {
@@ -51,18 +66,101 @@ func (m *Model) View() string {
 		if m.SearchResults > 0 && !m.SearchMode {
 			s.WriteString(resultInfoStyle.Render(fmt.Sprintf("Found %d tracks. Use ↑/↓ to navigate and Enter to play.\n\n", m.SearchResults)))
 		}
-		listView = m.TrackList.View()
+		listView = m.TrackList.View() + renderLoadingMore(m)
+		if m.OpenPlaylist != nil {
+			listView += "\n" + resultInfoStyle.Render(fit("[d] Remove from "+m.OpenPlaylist.PlaylistTitle, m.contentWidth()))
+		}
+	} else if m.ViewMode == ViewQueue {
+		// Show the live queue with its total length
+		queue := m.Player.Queue
+		summary := fmt.Sprintf("%d tracks in queue • total %s", len(queue.Tracks), formatDuration(queue.TotalDuration()))
+		if duplicates := len(queue.Duplicates()); duplicates > 0 {
+			summary += fmt.Sprintf(" • ⧉ %d queued more than once", duplicates)
+		}
+		listView = m.QueueList.View() + "\n" + resultInfoStyle.Render(
+			summary+" • [Enter] Jump  [d] Remove  [J/K] Move  [T] To top  [A] Play next")
+	} else if m.ViewMode == ViewHome {
+		// Show the home feed with a tab per shelf
+		listView = renderShelfTabs(m.HomeShelves, m.HomeShelf) + "\n\n" + m.HomeList.View() + "\n" +
+			resultInfoStyle.Render("[Tab/Shift+Tab] Switch shelf  [Enter] Play/Open")
+	} else if m.ViewMode == ViewExplore {
+		// Show new releases and the charts with a tab per shelf
+		listView = resultInfoStyle.Render("Charts: "+chartsCountryName(m.ChartsCountry)) + "\n" +
+			renderShelfTabs(m.ExploreShelves, m.ExploreShelf) + "\n\n" + m.ExploreList.View() + "\n" +
+			resultInfoStyle.Render("[Tab/Shift+Tab] Switch shelf  [Enter] Play/Open  [c] Country")
+	} else if m.ViewMode == ViewUploads {
+		// Show the uploads with a tab each for songs, albums and artists
+		listView = renderShelfTabs(m.UploadShelves, m.UploadShelf) + "\n\n" + m.UploadsList.View() + "\n" +
+			resultInfoStyle.Render("[Tab/Shift+Tab] Switch tab  [Enter] Play/Open")
+	} else if m.ViewMode == ViewLibrary {
+		// Show the library with its section tabs
+		listView = renderLibraryTabs(m.LibrarySection) + "\n\n" + m.LibraryList.View() + "\n" +
+			resultInfoStyle.Render("[Tab/Shift+Tab] Switch section")
+	} else if m.ViewMode == ViewAlbum && m.CurrentAlbum != nil {
+		// Show the album's tracks under its details
+		listView = resultInfoStyle.Render(fit(albumSummary(m.CurrentAlbum), m.contentWidth())) + "\n\n" + m.AlbumList.View() + "\n" +
+			resultInfoStyle.Render("[Enter] Play from here  [Esc] Back")
+	} else if m.ViewMode == ViewPodcast && m.CurrentPodcast != nil {
+		// Show the podcast's episodes under its details
+		hints := "[Enter] Play from here  [Esc] Back"
+		if resume := m.episodeResumeHint(); resume != "" {
+			hints = resume + "  " + hints
+		}
+		listView = resultInfoStyle.Render(fit(podcastSummary(m.CurrentPodcast), m.contentWidth())) + "\n\n" + m.EpisodeList.View() + "\n" +
+			resultInfoStyle.Render(hints)
+	} else if m.ViewMode == ViewNowPlaying {
+		// Show the playing track's details and what comes next
+		listView = renderNowPlaying(m)
+	} else if m.ViewMode == ViewLyrics {
+		// Show the lyrics of the playing track
+		listView = titleStyle.Render(fit("Lyrics - "+m.CurrentTrack.TrackTitle, m.contentWidth())) + "\n\n" + m.LyricsView.View() + "\n" +
+			resultInfoStyle.Render("[↑/↓/PgUp/PgDn] Scroll  [y/Esc] Close")
+	} else if m.ViewMode == ViewStats {
+		// Show the listening statistics of the selected period
+		listView = titleStyle.Render("Listening Stats") + "\n" + renderStatsTabs(m.StatsPeriod) + "\n\n" +
+			m.StatsView.View() + "\n" +
+			resultInfoStyle.Render("[Tab/Shift+Tab] Switch period  [↑/↓/PgUp/PgDn] Scroll  [S/Esc] Close")
+	} else if m.ViewMode == ViewLogs {
+		// Show the tail of today's debug logs, refreshed while open
+		listView = titleStyle.Render("Logs") + "\n" + renderLogLevels(m.LogsLevel) + "\n\n" +
+			m.LogsView.View() + "\n" +
+			resultInfoStyle.Render("[Tab/Shift+Tab] Filter level  [↑/↓/PgUp/PgDn/Home/End] Scroll  [Ctrl+L/Esc] Close")
+	} else if m.ViewMode == ViewArtist && m.CurrentArtist != nil {
+		// Show the artist's sections as tabs
+		listView = resultInfoStyle.Render(fit(m.CurrentArtist.Subtitle, m.contentWidth())) + "\n" +
+			renderArtistTabs(m.ArtistSection) + "\n\n" + m.ArtistList.View() + "\n" +
+			resultInfoStyle.Render("[Tab/Shift+Tab] Switch section  [Enter] Open/Play  [Ctrl+R] Artist radio  [Esc] Back")
 	} else {
-		// Show playlist list
-		listView = m.PlaylistList.View()
+		// Show playlist list with its management keys
+		listView = m.PlaylistList.View() + "\n" +
+			resultInfoStyle.Render("[c] New playlist  [e] Rename  [D] Delete")
+	}
+	
+	// Select mode shows what's marked and what can be done with it
+	if m.Selection.active {
+		listView += "\n" + renderSelection(m)
 	}
 	
 	// Search input
-	if m.SearchMode {
+	if m.Menu != nil {
+		s.WriteString(m.Menu.view(m.contentWidth()) + "\n\n" + listView)
+	} else if m.Info != nil {
+		s.WriteString(m.Info.view(m.contentWidth()) + "\n\n" + listView)
+	} else if m.Prompt == promptAddToPlaylist {
+		// The picker replaces the list it was opened from
+		s.WriteString(renderPrompt(m))
+	} else if m.Prompt != promptNone {
+		s.WriteString(renderPrompt(m) + "\n\n" + listView)
+	} else if m.SearchMode {
 		searchView := m.SearchInput.View()
-		s.WriteString(fmt.Sprintf("%s\n\n%s\n\n%s",
-			titleStyle.Render("YouTube Music - Search"),
+		searchTitle := "YouTube Music - Search"
+		if m.SearchPodcasts {
+			searchTitle = "YouTube Music - Search Podcasts"
+		}
+		s.WriteString(fmt.Sprintf("%s\n\n%s\n%s\n\n%s",
+			titleStyle.Render(searchTitle),
 			searchView,
+			resultInfoStyle.Render("[Tab] Switch between songs and podcasts"),
 			listView))
 	} else {
 		// Current playing info
@@ -71,13 +169,70 @@ func (m *Model) View() string {
 		// Status bar with controls
 		statusBar := renderStatusBar(m)
 		
-		s.WriteString(fmt.Sprintf("%s\n\n%s\n\n%s",
+		// Drill-down views say how they were reached
+		tabBar := renderTabBar(m.Tab)
+		if crumbs := renderBreadcrumbs(m); crumbs != "" {
+			tabBar += "\n" + crumbs
+		}
+		
+		above := s.String() + tabBar + "\n\n" + listView + "\n\n"
+		s.WriteString(fmt.Sprintf("%s\n\n%s\n\n%s\n\n%s",
+			tabBar,
 			listView,
 			currentlyPlaying,
 			statusBar))
+		
+		// The bar is under the playing track's title, inside the border and padding
+		if m.Player.Queue.GetCurrentTrack() != nil {
+			m.progressArea = progressArea{row: 2 + strings.Count(above, "\n") + 1, col: 3, width: progressCells(m.Progress)}
+		}
 	}
 	
-	return appStyle.Render(s.String())
+	view := appStyle.Render(s.String())
+	// Bubble Tea cuts a view taller than the window from the top
+	if over := lipgloss.Height(view) - m.Height; over > 0 && m.Height > 0 {
+		m.progressArea.row -= over
+	}
+	return view
+}
+
+// renderLoginView renders the in-app login instructions and cookie field
+func renderLoginView(m *Model) string {
+	var s strings.Builder
+	
+	s.WriteString(titleStyle.Render("YouTube Music TUI - Login") + "\n\n")
+	if m.SessionExpired {
+		s.WriteString(errorStyle.Render("Your session has expired - press 'l' to log in again") + "\n\n")
+	}
+	s.WriteString("You need to authenticate with YouTube Music to use this application.\n\n")
+	
+	s.WriteString(warningStyle.Render("Paste a browser cookie") + "\n")
+	s.WriteString("1. Press 'l' to open https://music.youtube.com and log in\n")
+	s.WriteString("2. Open developer tools (F12) > Network and reload the page\n")
+	s.WriteString("3. Select a request to music.youtube.com (not google.com)\n")
+	s.WriteString("4. Copy the whole 'Cookie' request header, which works for every feature\n")
+	s.WriteString("   (or only the '__Secure-3PSID' cookie from Application/Storage > Cookies)\n")
+	s.WriteString("5. Paste it below and press Enter\n\n")
+	
+	if m.LoginInput.Focused() {
+		s.WriteString(m.LoginInput.View() + "\n\n")
+	}
+	
+	s.WriteString(renderStatus(m))
+	
+	s.WriteString(warningStyle.Render("Alternative: log in from another terminal") + "\n")
+	s.WriteString("Run: ytmusic login -from-browser firefox   (or chrome, edge, safari...)\n")
+	s.WriteString("or:  ytmusic login -oauth\n")
+	s.WriteString("or:  ytmusicapi browser --file " + paths.Tilde(filepath.Join(paths.Config(), "headers_auth.json")) + "\n")
+	s.WriteString("This screen moves on by itself once you're logged in.\n\n")
+	
+	if m.LoginInput.Focused() {
+		s.WriteString("Press Enter to log in, Esc to cancel.")
+	} else {
+		s.WriteString("Press 'l' to log in, 'q' to quit.")
+	}
+	
+	return s.String()
 }
 
 // renderPlayingInfo renders the currently playing track info with progress bar
@@ -86,9 +241,11 @@ func renderPlayingInfo(m *Model) string {
 	
 	if currentTrack != nil {
 		// Get status icons
-		playStatus := "⏸️"
-		if m.Player.IsPlaying {
-			playStatus = "▶️"
+		playStatus := "⏸"
+		if m.Player.Buffering {
+			playStatus = "⏳"
+		} else if m.Player.IsPlaying {
+			playStatus = "▶"
 		}
 		
 		// Get repeat mode icon
@@ -109,18 +266,35 @@ func renderPlayingInfo(m *Model) string {
 		}
 		
 		// Format time as MM:SS
-		currentMinutes := m.Player.CurrentPos / 60
-		currentSeconds := m.Player.CurrentPos % 60
+		// Dragging along the bar shows where letting go seeks to
+		position := m.shownPosition()
+		currentMinutes := position / 60
+		currentSeconds := position % 60
 		totalMinutes := m.Player.Duration / 60
 		totalSeconds := m.Player.Duration % 60
 		
 		timeInfo := fmt.Sprintf("%02d:%02d / %02d:%02d", 
 			currentMinutes, currentSeconds,
 			totalMinutes, totalSeconds)
+		if m.Player.Duration >= 3600 {
+			// Podcasts and long mixes read better with hours
+			timeInfo = formatDuration(position) + " / " + formatDuration(m.Player.Duration)
+		}
+		if m.scrubbing {
+			timeInfo = "Seek to " + timeInfo
+		}
 		
-		progressBar := m.Progress.ViewAs(float64(m.Player.CurrentPos) / float64(m.Player.Duration))
+		progressBar := m.Progress.ViewAs(float64(position) / float64(m.Player.Duration))
 		
-		playbackControls := fmt.Sprintf("  %s  %s", repeatIcon, shuffleIcon)
+		// Autoplay only continues a queue that ends, so repeat modes leave it idle
+		autoplayIcon := "📻 Autoplay Off"
+		if m.Player.Queue.Autoplay && m.Player.Queue.RepeatMode == player.RepeatNone {
+			autoplayIcon = "📻 Autoplay On"
+		} else if m.Player.Queue.Autoplay {
+			autoplayIcon = "📻 Autoplay (idle while repeating)"
+		}
+		
+		playbackControls := fmt.Sprintf("  %s  %s  %s", repeatIcon, shuffleIcon, autoplayIcon)
 		
 		// Add queue position info
 		queueInfo := ""
@@ -138,15 +312,18 @@ func renderPlayingInfo(m *Model) string {
 			queueInfo = fmt.Sprintf(" (%d/%d in queue)", currentIndex, totalTracks)
 		}
 		
+		// Long titles give way to the queue position rather than wrapping
+		title, artist := fitPair(currentTrack.TrackTitle, currentTrack.Artist,
+			m.contentWidth()-runewidth.StringWidth(playStatus+"  - "+queueInfo))
+		
 		return fmt.Sprintf(
-			"%s %s - %s%s\n%s\n%s%s",
+			"%s %s - %s%s\n%s\n%s",
 			playStatus,
-			playingStyle.Render(currentTrack.TrackTitle),
-			infoStyle.Render(currentTrack.Artist),
+			playingStyle.Render(title),
+			infoStyle.Render(artist),
 			queueInfo,
 			progressBar,
-			timeInfo,
-			playbackControls,
+			fit(timeInfo+playbackControls, m.contentWidth()),
 		)
 	} else {
 		return "No song playing"
@@ -155,32 +332,82 @@ func renderPlayingInfo(m *Model) string {
 
 // renderStatusBar renders the status bar with controls
 func renderStatusBar(m *Model) string {
-	// Basic controls
+	// Basic controls, most useful first, since narrow windows only show the first few
 	controls := []string{
 		"[q] Quit",
-		"[↑/↓] Navigate",
-		"[Enter] Play/Select",
+		"[?] Help",
 		"[Space] Pause/Play",
 		"[/] Search",
+		"[Enter] Play/Select",
+		"[a/A] Queue/Play Next",
+		"[P] Add to Playlist",
+		"[m] Menu",
+		"[g/G] Go to Album/Artist",
+		"[y] Lyrics",
+		"[↑/↓] Navigate",
 	}
 	
 	// Add playback controls
 	controls = append(controls, 
 		"[n] Next",
 		"[b] Previous",
+		"[←/→] Seek",
 		"[r] Repeat Mode",
 		"[s] Shuffle",
+		"[Ctrl+R] Radio",
+		"[o] Autoplay",
 	)
 	
-	// Add view toggle
-	viewToggle := "[p] Show Playlists"
-	if m.ViewMode == ViewPlaylists {
-		viewToggle = "[p] Show Tracks"
+	// Add tab switching
+	controls = append(controls, "[1-6/Tab] Switch Tab")
+	if len(m.ViewHistory) > 0 {
+		controls = append(controls, "[Esc] Back")
+	}
+	
+	exploreToggle := "[C] Charts"
+	if m.ViewMode == ViewExplore {
+		exploreToggle = "[C] Hide Charts"
+	}
+	controls = append(controls, exploreToggle)
+	
+	uploadsToggle := "[U] Uploads"
+	if m.ViewMode == ViewUploads {
+		uploadsToggle = "[U] Hide Uploads"
 	}
-	controls = append(controls, viewToggle)
+	controls = append(controls, uploadsToggle)
 	
 	// Add reset cookie
 	controls = append(controls, "[R] Reset Cookie")
 	
-	return statusBarStyle.Render(strings.Join(controls, "  "))
+	// Say where results come from, loudly when they're made up
+	var tags []string
+	if m.Offline {
+		tags = append(tags, "OFFLINE")
+	}
+	if backend := m.Api.Backend(); backend == api.BackendMock {
+		tags = append(tags, "DEMO DATA")
+	} else if backend != "" && !m.Offline {
+		tags = append(tags, "via "+backend)
+	}
+	
+	// The bar keeps to one line, so narrow windows don't push the layout around:
+	// the controls that fit, in order, then where to find the rest
+	width := m.contentWidth() - 2 // Inside the bar's padding
+	line := strings.Join(append(tags, controls...), "  ")
+	if runewidth.StringWidth(line) <= width {
+		return statusBarStyle.Render(line)
+	}
+	hint := "Press " + keyName(m.Config.Key("help")) + " for help"
+	shown := tags
+	for _, control := range controls {
+		if control == "[?] Help" {
+			continue // The hint says it
+		}
+		next := append(append([]string{}, shown...), control)
+		if runewidth.StringWidth(strings.Join(append(next, hint), "  ")) > width {
+			break
+		}
+		shown = next
+	}
+	return statusBarStyle.Render(fit(strings.Join(append(shown, hint), "  "), width))
 }
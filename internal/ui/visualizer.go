@@ -0,0 +1,151 @@
+package ui
+
+import (
+	"os"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"ytmusic/internal/player"
+)
+
+// visualizerRows is how many lines the visualizer's bars rise over
+const visualizerRows = 4
+
+// A frame drawn more than visualizerMaxLag after mpv measured it is late, and
+// visualizerLateFrames late frames in a row turn the visualizer off, as the
+// terminal can't draw it as fast as it changes
+const (
+	visualizerMaxLag     = 200 * time.Millisecond
+	visualizerLateFrames = 30
+)
+
+// visualizerBlocks are the eighths a bar's top line is drawn with
+var visualizerBlocks = []string{" ", "▁", "▂", "▃", "▄", "▅", "▆", "▇", "█"}
+
+// visualizerFrameMsg carries the levels of the audio playing, measured by mpv
+type visualizerFrameMsg struct {
+	frame  player.VisualizerFrame
+	frames <-chan player.VisualizerFrame
+}
+
+// WaitForVisualizerFrameCmd waits for the next levels measured for the visualizer
+func WaitForVisualizerFrameCmd(frames <-chan player.VisualizerFrame) tea.Cmd {
+	return func() tea.Msg {
+		frame, ok := <-frames
+		if !ok {
+			return nil
+		}
+		return visualizerFrameMsg{frame: frame, frames: frames}
+	}
+}
+
+// slowTerminal reports whether the terminal is one that redraws too slowly for the visualizer
+func slowTerminal() bool {
+	switch os.Getenv("TERM") {
+	case "linux", "dumb", "vt100", "vt220":
+		return true
+	}
+	return false
+}
+
+// toggleVisualizer turns the visualizer of the now playing view on or off
+func (m *Model) toggleVisualizer() {
+	if m.VisualizerOn {
+		m.VisualizerOn = false
+		m.stopVisualizer()
+		m.showInfo("Visualizer: Off")
+		return
+	}
+	m.VisualizerOn = true
+	m.showInfo("Visualizer: On, in the now playing view")
+}
+
+// syncVisualizer starts mpv measuring the audio once the visualizer is on, and
+// waits for its levels while the now playing view shows them
+func (m *Model) syncVisualizer() tea.Cmd {
+	if !m.VisualizerOn {
+		return nil
+	}
+	if m.visFrames == nil {
+		frames, err := m.Player.StartVisualizer()
+		if err != nil {
+			m.VisualizerOn = false
+			m.showWarning("Visualizer off: " + err.Error())
+			return nil
+		}
+		m.visFrames = frames
+	}
+	// Elsewhere the frames wait, rather than redrawing a view that doesn't show them
+	if m.visWaiting || m.ViewMode != ViewNowPlaying || m.compact() {
+		return nil
+	}
+	m.visWaiting = true
+	return WaitForVisualizerFrameCmd(m.visFrames)
+}
+
+// stopVisualizer stops mpv measuring the audio
+func (m *Model) stopVisualizer() {
+	m.Player.StopVisualizer()
+	m.visFrames = nil
+	m.visWaiting = false
+	m.visLevels = nil
+	m.visLate = 0
+}
+
+// handleVisualizerFrame keeps the levels to draw, turning the visualizer off
+// when the terminal keeps falling behind
+func (m *Model) handleVisualizerFrame(msg visualizerFrameMsg) {
+	if msg.frames != m.visFrames {
+		return // From before the visualizer was turned off
+	}
+	m.visWaiting = false
+	if time.Since(msg.frame.At) > visualizerMaxLag {
+		m.visLate++
+	} else {
+		m.visLate = 0
+	}
+	if m.visLate >= visualizerLateFrames {
+		m.VisualizerOn = false
+		m.stopVisualizer()
+		m.showWarning("Visualizer turned off: the terminal can't draw it fast enough")
+		return
+	}
+	m.visLevels = msg.frame.Levels
+}
+
+// renderVisualizer draws the levels as bars spread across width columns
+func renderVisualizer(levels []float64, width int) string {
+	if len(levels) == 0 {
+		return ""
+	}
+	barWidth := width/len(levels) - 1
+	if barWidth < 1 {
+		barWidth = 1
+	}
+
+	lines := make([]string, visualizerRows)
+	for row := range lines {
+		// Rows count from the top, the bars rise from the bottom
+		floor := (visualizerRows - 1 - row) * 8
+		var line strings.Builder
+		for i, level := range levels {
+			if i > 0 && (i+1)*(barWidth+1) > width+1 {
+				break
+			}
+			eighths := int(level*visualizerRows*8) - floor
+			if eighths < 0 {
+				eighths = 0
+			} else if eighths > 8 {
+				eighths = 8
+			}
+			if i > 0 {
+				line.WriteString(" ")
+			}
+			line.WriteString(strings.Repeat(visualizerBlocks[eighths], barWidth))
+		}
+		lines[row] = playingStyle.Render(line.String())
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,116 @@
+# Registers global hotkeys for ytmusic, so playback can be controlled while
+# another window is focused.
+#
+# Takes the hotkeys as arguments such as toggle=ctrl+alt+space, and writes the
+# command of each one pressed (toggle, next, previous) as a line on stdout.
+# RegisterHotKey only sees the combinations registered, not other typing.
+# Exits when stdin closes.
+
+$ErrorActionPreference = 'Stop'
+
+Add-Type -TypeDefinition @'
+using System;
+using System.Runtime.InteropServices;
+
+public static class Hotkeys {
+    [StructLayout(LayoutKind.Sequential)]
+    struct MSG {
+        public IntPtr hwnd;
+        public uint message;
+        public IntPtr wParam;
+        public IntPtr lParam;
+        public uint time;
+        public int x;
+        public int y;
+    }
+
+    const uint WM_HOTKEY = 0x0312;
+    const uint PM_REMOVE = 1;
+
+    [DllImport("user32.dll", SetLastError = true)]
+    public static extern bool RegisterHotKey(IntPtr hWnd, int id, uint modifiers, uint vk);
+
+    [DllImport("user32.dll")]
+    public static extern bool UnregisterHotKey(IntPtr hWnd, int id);
+
+    [DllImport("user32.dll")]
+    static extern bool PeekMessage(out MSG msg, IntPtr hWnd, uint min, uint max, uint remove);
+
+    // Pressed returns the id of a hotkey pressed since the last call, or -1
+    public static int Pressed() {
+        MSG msg;
+        if (PeekMessage(out msg, IntPtr.Zero, WM_HOTKEY, WM_HOTKEY, PM_REMOVE)) {
+            return msg.wParam.ToInt32();
+        }
+        return -1;
+    }
+}
+'@
+
+$modifiers = @{ alt = 0x1; ctrl = 0x2; shift = 0x4; super = 0x8 }
+$noRepeat = 0x4000
+
+$keys = @{
+    space = 0x20; pageup = 0x21; pagedown = 0x22; end = 0x23; home = 0x24
+    left = 0x25; up = 0x26; right = 0x27; down = 0x28
+}
+foreach ($c in [char[]]'abcdefghijklmnopqrstuvwxyz0123456789') {
+    $keys[[string]$c] = [int][char]::ToUpper($c)
+}
+for ($i = 1; $i -le 12; $i++) {
+    $keys["f$i"] = 0x6F + $i
+}
+
+# Hotkeys are posted to this thread's message queue, having no window
+$commands = @{}
+$id = 0
+foreach ($binding in $args) {
+    $command, $combo = $binding -split '=', 2
+    $mods = $noRepeat
+    $vk = $null
+    foreach ($part in $combo -split '\+') {
+        if ($modifiers.ContainsKey($part)) {
+            $mods = $mods -bor $modifiers[$part]
+        } else {
+            $vk = $keys[$part]
+        }
+    }
+    if ($null -eq $vk) {
+        [Console]::Out.WriteLine("error: $combo is not a key combination")
+        continue
+    }
+    $id++
+    if (-not [Hotkeys]::RegisterHotKey([IntPtr]::Zero, $id, $mods, $vk)) {
+        [Console]::Out.WriteLine("error: $combo is taken by another app")
+        continue
+    }
+    $commands[$id] = $command
+}
+[Console]::Out.Flush()
+
+$stdin = [Console]::In
+$pending = $stdin.ReadLineAsync()
+
+while ($true) {
+    # Nothing is read from stdin; it closing means ytmusic has exited
+    if ($pending.IsCompleted) {
+        if ($null -eq $pending.Result) {
+            break
+        }
+        $pending = $stdin.ReadLineAsync()
+        continue
+    }
+
+    while (($pressed = [Hotkeys]::Pressed()) -ge 0) {
+        if ($commands.ContainsKey($pressed)) {
+            [Console]::Out.WriteLine($commands[$pressed])
+            [Console]::Out.Flush()
+        }
+    }
+
+    Start-Sleep -Milliseconds 100
+}
+
+foreach ($registered in $commands.Keys) {
+    [void][Hotkeys]::UnregisterHotKey([IntPtr]::Zero, $registered)
+}
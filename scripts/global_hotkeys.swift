@@ -0,0 +1,93 @@
+// Registers global hotkeys for ytmusic, so playback can be controlled while
+// another app is focused.
+//
+// Takes the hotkeys as arguments such as toggle=ctrl+alt+space, and writes the
+// command of each one pressed (toggle, next, previous) as a line on stdout.
+// Carbon hot keys only see the combinations registered, not other typing, so
+// no Accessibility or Input Monitoring permission is needed. Exits when stdin
+// closes.
+
+import AppKit
+import Carbon
+import Foundation
+
+func send(_ line: String) {
+    print(line)
+    fflush(stdout)
+}
+
+let modifiers: [String: Int] = [
+    "ctrl": controlKey, "alt": optionKey, "shift": shiftKey, "super": cmdKey,
+]
+
+let keys: [String: Int] = [
+    "a": kVK_ANSI_A, "b": kVK_ANSI_B, "c": kVK_ANSI_C, "d": kVK_ANSI_D, "e": kVK_ANSI_E,
+    "f": kVK_ANSI_F, "g": kVK_ANSI_G, "h": kVK_ANSI_H, "i": kVK_ANSI_I, "j": kVK_ANSI_J,
+    "k": kVK_ANSI_K, "l": kVK_ANSI_L, "m": kVK_ANSI_M, "n": kVK_ANSI_N, "o": kVK_ANSI_O,
+    "p": kVK_ANSI_P, "q": kVK_ANSI_Q, "r": kVK_ANSI_R, "s": kVK_ANSI_S, "t": kVK_ANSI_T,
+    "u": kVK_ANSI_U, "v": kVK_ANSI_V, "w": kVK_ANSI_W, "x": kVK_ANSI_X, "y": kVK_ANSI_Y,
+    "z": kVK_ANSI_Z,
+    "0": kVK_ANSI_0, "1": kVK_ANSI_1, "2": kVK_ANSI_2, "3": kVK_ANSI_3, "4": kVK_ANSI_4,
+    "5": kVK_ANSI_5, "6": kVK_ANSI_6, "7": kVK_ANSI_7, "8": kVK_ANSI_8, "9": kVK_ANSI_9,
+    "f1": kVK_F1, "f2": kVK_F2, "f3": kVK_F3, "f4": kVK_F4, "f5": kVK_F5, "f6": kVK_F6,
+    "f7": kVK_F7, "f8": kVK_F8, "f9": kVK_F9, "f10": kVK_F10, "f11": kVK_F11, "f12": kVK_F12,
+    "space": kVK_Space, "left": kVK_LeftArrow, "right": kVK_RightArrow,
+    "up": kVK_UpArrow, "down": kVK_DownArrow, "home": kVK_Home, "end": kVK_End,
+    "pageup": kVK_PageUp, "pagedown": kVK_PageDown,
+]
+
+// Commands of the registered hotkeys, by hot key ID
+var commands: [UInt32: String] = [:]
+
+var pressed = EventTypeSpec(eventClass: OSType(kEventClassKeyboard), eventKind: UInt32(kEventHotKeyPressed))
+InstallEventHandler(GetApplicationEventTarget(), { _, event, _ in
+    var id = EventHotKeyID()
+    GetEventParameter(event, EventParamName(kEventParamDirectObject), EventParamType(typeEventHotKeyID),
+                      nil, MemoryLayout<EventHotKeyID>.size, nil, &id)
+    if let command = commands[id.id] {
+        send(command)
+    }
+    return noErr
+}, 1, &pressed, nil, nil)
+
+// "ytmu", telling these hot keys apart from any other app's
+let signature = OSType(0x7974_6D75)
+
+for (index, binding) in CommandLine.arguments.dropFirst().enumerated() {
+    let parts = binding.split(separator: "=", maxSplits: 1).map(String.init)
+    guard parts.count == 2 else {
+        send("error: \(binding) is not a hotkey")
+        continue
+    }
+    var mask = 0
+    var code: Int?
+    for part in parts[1].split(separator: "+").map(String.init) {
+        if let modifier = modifiers[part] {
+            mask |= modifier
+        } else {
+            code = keys[part]
+        }
+    }
+    guard let key = code else {
+        send("error: \(parts[1]) is not a key combination")
+        continue
+    }
+    let id = EventHotKeyID(signature: signature, id: UInt32(index + 1))
+    var ref: EventHotKeyRef?
+    if RegisterEventHotKey(UInt32(key), UInt32(mask), id, GetApplicationEventTarget(), 0, &ref) != noErr {
+        send("error: \(parts[1]) is taken by another app")
+        continue
+    }
+    commands[id.id] = parts[0]
+}
+
+DispatchQueue.global().async {
+    // Nothing is read from stdin; it closing means ytmusic has exited
+    while readLine() != nil {}
+    exit(0)
+}
+
+// Hot key events arrive through the application's event loop, without a Dock icon
+let app = NSApplication.shared
+app.setActivationPolicy(.prohibited)
+app.run()
@@ -0,0 +1,116 @@
+# Connects ytmusic to the Windows System Media Transport Controls, so media keys
+# and the volume flyout control playback.
+#
+# Reads the now playing state as JSON lines on stdin and writes the buttons
+# pressed (play, pause, next, previous, stop) as lines on stdout. Exits when
+# stdin closes.
+
+$ErrorActionPreference = 'Stop'
+
+[void][Windows.Media.Playback.MediaPlayer, Windows.Media, ContentType = WindowsRuntime]
+[void][Windows.Media.SystemMediaTransportControlsTimelineProperties, Windows.Media, ContentType = WindowsRuntime]
+[void][Windows.Storage.Streams.RandomAccessStreamReference, Windows.Storage.Streams, ContentType = WindowsRuntime]
+[void][Windows.Storage.StorageFile, Windows.Storage, ContentType = WindowsRuntime]
+Add-Type -AssemblyName System.Runtime.WindowsRuntime
+
+# Await waits for a WinRT async operation returning a ResultType
+$asTask = [System.WindowsRuntimeSystemExtensions].GetMethods() | Where-Object {
+    $_.Name -eq 'AsTask' -and $_.GetParameters().Count -eq 1 -and $_.GetParameters()[0].ParameterType.Name -eq 'IAsyncOperation`1'
+} | Select-Object -First 1
+function Await($operation, [Type]$resultType) {
+    $task = $asTask.MakeGenericMethod($resultType).Invoke($null, @($operation))
+    $task.Wait() | Out-Null
+    $task.Result
+}
+
+# A MediaPlayer that never plays anything gives this process its own controls
+$player = [Windows.Media.Playback.MediaPlayer]::new()
+$player.CommandManager.IsEnabled = $false
+
+$smtc = $player.SystemMediaTransportControls
+$smtc.IsEnabled = $true
+$smtc.IsPlayEnabled = $true
+$smtc.IsPauseEnabled = $true
+$smtc.IsNextEnabled = $true
+$smtc.IsPreviousEnabled = $true
+$smtc.IsStopEnabled = $true
+$smtc.PlaybackStatus = 'Closed'
+
+Register-ObjectEvent -InputObject $smtc -EventName ButtonPressed -SourceIdentifier Button | Out-Null
+
+$script:artUrl = ''
+
+function Update-Display($np) {
+    $updater = $smtc.DisplayUpdater
+
+    if (-not $np.title) {
+        $updater.ClearAll()
+        $updater.Update()
+        $smtc.PlaybackStatus = 'Closed'
+        $script:artUrl = ''
+        return
+    }
+
+    $updater.Type = 'Music'
+    $updater.MusicProperties.Title = $np.title
+    $updater.MusicProperties.Artist = $np.artist
+    $updater.MusicProperties.AlbumTitle = $np.album
+    # Prefer the copy ytmusic cached on disk over downloading it again
+    $art = if ($np.art_path) { $np.art_path } else { $np.art_url }
+    if ($art -ne $script:artUrl) {
+        $script:artUrl = $art
+        if ($np.art_path) {
+            $file = Await ([Windows.Storage.StorageFile]::GetFileFromPathAsync($np.art_path)) ([Windows.Storage.StorageFile])
+            $updater.Thumbnail = [Windows.Storage.Streams.RandomAccessStreamReference]::CreateFromFile($file)
+        } elseif ($np.art_url) {
+            $updater.Thumbnail = [Windows.Storage.Streams.RandomAccessStreamReference]::CreateFromUri([Uri]$np.art_url)
+        } else {
+            $updater.Thumbnail = $null
+        }
+    }
+    $updater.Update()
+
+    $timeline = [Windows.Media.SystemMediaTransportControlsTimelineProperties]::new()
+    $timeline.StartTime = [TimeSpan]::Zero
+    $timeline.MinSeekTime = [TimeSpan]::Zero
+    $timeline.EndTime = [TimeSpan]::FromSeconds($np.duration)
+    $timeline.MaxSeekTime = [TimeSpan]::FromSeconds($np.duration)
+    $timeline.Position = [TimeSpan]::FromSeconds($np.position)
+    $smtc.UpdateTimelineProperties($timeline)
+
+    if ($np.playing) {
+        $smtc.PlaybackStatus = 'Playing'
+    } else {
+        $smtc.PlaybackStatus = 'Paused'
+    }
+}
+
+$stdin = [Console]::In
+$pending = $stdin.ReadLineAsync()
+
+while ($true) {
+    if ($pending.IsCompleted) {
+        $line = $pending.Result
+        if ($null -eq $line) {
+            break
+        }
+        try {
+            Update-Display ($line | ConvertFrom-Json)
+        } catch {
+            [Console]::Error.WriteLine("Error updating media controls: $_")
+        }
+        $pending = $stdin.ReadLineAsync()
+        continue
+    }
+
+    foreach ($event in @(Get-Event -SourceIdentifier Button -ErrorAction SilentlyContinue)) {
+        [Console]::Out.WriteLine($event.SourceEventArgs.Button.ToString().ToLower())
+        [Console]::Out.Flush()
+        Remove-Event -EventIdentifier $event.EventIdentifier
+    }
+
+    Start-Sleep -Milliseconds 100
+}
+
+Unregister-Event -SourceIdentifier Button
+$player.Dispose()
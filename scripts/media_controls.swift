@@ -0,0 +1,115 @@
+// Connects ytmusic to macOS Now Playing, so media keys, Control Center and the
+// Touch Bar control playback.
+//
+// Reads the now playing state as JSON lines on stdin and writes the remote
+// commands received (play, pause, toggle, next, previous, stop) as lines on
+// stdout. Exits when stdin closes.
+
+import AppKit
+import Foundation
+import MediaPlayer
+
+struct NowPlaying: Decodable {
+    let title: String
+    let artist: String
+    let album: String
+    let artURL: String
+    let artPath: String?
+    let duration: Int
+    let position: Int
+    let playing: Bool
+
+    enum CodingKeys: String, CodingKey {
+        case title, artist, album, duration, position, playing
+        case artURL = "art_url"
+        case artPath = "art_path"
+    }
+}
+
+func send(_ command: String) {
+    print(command)
+    fflush(stdout)
+}
+
+let commands = MPRemoteCommandCenter.shared()
+commands.playCommand.addTarget { _ in send("play"); return .success }
+commands.pauseCommand.addTarget { _ in send("pause"); return .success }
+commands.togglePlayPauseCommand.addTarget { _ in send("toggle"); return .success }
+commands.nextTrackCommand.addTarget { _ in send("next"); return .success }
+commands.previousTrackCommand.addTarget { _ in send("previous"); return .success }
+commands.stopCommand.addTarget { _ in send("stop"); return .success }
+
+let info = MPNowPlayingInfoCenter.default()
+var current: NowPlaying?
+var artwork: MPMediaItemArtwork?
+var artURL = ""
+
+// loadArtwork downloads a track's artwork in the background and shows it once it arrives
+func loadArtwork(_ url: String) {
+    artURL = url
+    artwork = nil
+    guard let source = URL(string: url) else {
+        return
+    }
+    URLSession.shared.dataTask(with: source) { data, _, _ in
+        guard let data = data, let image = NSImage(data: data) else {
+            return
+        }
+        DispatchQueue.main.async {
+            guard url == artURL else {
+                return
+            }
+            artwork = MPMediaItemArtwork(boundsSize: image.size) { _ in image }
+            if let np = current {
+                update(np)
+            }
+        }
+    }.resume()
+}
+
+func update(_ np: NowPlaying) {
+    current = np
+    if np.title.isEmpty {
+        info.nowPlayingInfo = nil
+        info.playbackState = .stopped
+        return
+    }
+
+    // Prefer the copy ytmusic cached on disk over downloading it again
+    var source = np.artURL
+    if let path = np.artPath, !path.isEmpty {
+        source = URL(fileURLWithPath: path).absoluteString
+    }
+    if source != artURL {
+        loadArtwork(source)
+    }
+
+    var properties: [String: Any] = [
+        MPMediaItemPropertyTitle: np.title,
+        MPMediaItemPropertyArtist: np.artist,
+        MPMediaItemPropertyAlbumTitle: np.album,
+        MPMediaItemPropertyPlaybackDuration: Double(np.duration),
+        MPNowPlayingInfoPropertyElapsedPlaybackTime: Double(np.position),
+        MPNowPlayingInfoPropertyPlaybackRate: np.playing ? 1.0 : 0.0,
+    ]
+    if let artwork = artwork {
+        properties[MPMediaItemPropertyArtwork] = artwork
+    }
+    info.nowPlayingInfo = properties
+    info.playbackState = np.playing ? .playing : .paused
+}
+
+DispatchQueue.global().async {
+    let decoder = JSONDecoder()
+    while let line = readLine() {
+        guard let data = line.data(using: .utf8),
+              let np = try? decoder.decode(NowPlaying.self, from: data) else {
+            FileHandle.standardError.write("Error reading now playing state: \(line)\n".data(using: .utf8)!)
+            continue
+        }
+        DispatchQueue.main.async { update(np) }
+    }
+    exit(0)
+}
+
+RunLoop.main.run()
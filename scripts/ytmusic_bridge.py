@@ -15,6 +15,10 @@ from typing import List, Dict, Optional, Any
 # Add the current directory to path to import our module
 sys.path.insert(0, os.path.dirname(os.path.abspath(__file__)))
 
+# Where the OAuth and headers files are; the app passes its config directory
+CONFIG_DIR = os.environ.get("YTMUSIC_CONFIG_DIR") or os.path.join(
+    os.environ.get("XDG_CONFIG_HOME") or os.path.expanduser("~/.config"), "ytmusic")
+
 # Try multiple ways to import ytmusicapi
 ytmusicapi_available = False
 YTMusic = None
@@ -80,10 +84,13 @@ if not ytmusicapi_available:
 
 
 class YouTubeMusicBridge:
-    def __init__(self, cookie: str = None):
-        """Initialize the bridge with optional cookie authentication"""
+    def __init__(self, cookie: str = None, user: str = None, language: str = None, location: str = None):
+        """Initialize the bridge with optional cookie authentication, acting as a brand account if given"""
         self.ytmusic = None
         self.authenticated = False
+        self.user = user
+        self.language = language
+        self.location = location
         
         if cookie:
             try:
@@ -100,11 +107,28 @@ class YouTubeMusicBridge:
         # Fall back to no authentication
         if not self.authenticated:
             try:
-                self.ytmusic = YTMusic()
+                self.ytmusic = self._create_ytmusic()
                 logging.warning("Running without authentication - limited functionality")
             except Exception as e:
                 raise Exception(f"Failed to initialize YTMusic: {e}")
     
+    def _create_ytmusic(self, auth=None, **kwargs):
+        """Create the YTMusic client for the bridge's account and locale.
+        A locale this ytmusicapi version doesn't support falls back to its default."""
+        locale = {}
+        if self.language:
+            locale['language'] = self.language
+        if self.location:
+            locale['location'] = self.location
+        
+        try:
+            return YTMusic(auth, user=self.user, **locale, **kwargs)
+        except Exception as e:
+            if not locale:
+                raise
+            logging.warning(f"ytmusicapi rejected locale {locale}, using its default: {e}")
+            return YTMusic(auth, user=self.user, **kwargs)
+    
     def _authenticate_with_cookie(self, cookie: str):
         """Try to authenticate with a cookie (this is a simplified approach)"""
         # For now, we'll skip cookie auth since ytmusicapi prefers headers/oauth
@@ -114,8 +138,8 @@ class YouTubeMusicBridge:
     def _authenticate_with_oauth_or_headers(self):
         """Try to authenticate with OAuth or headers file"""
         # Try OAuth first (more stable) - with client credentials support
-        oauth_path = os.path.expanduser("~/.ytmusic/oauth_auth.json")
-        client_secret_path = os.path.expanduser("~/.ytmusic/client_secret.json")
+        oauth_path = os.path.join(CONFIG_DIR, "oauth_auth.json")
+        client_secret_path = os.path.join(CONFIG_DIR, "client_secret.json")
         
         if os.path.exists(oauth_path):
             try:
@@ -140,14 +164,14 @@ class YouTubeMusicBridge:
                         client_id=client_id,
                         client_secret=client_secret
                     )
-                    self.ytmusic = YTMusic(oauth_path, oauth_credentials=oauth_credentials)
+                    self.ytmusic = self._create_ytmusic(oauth_path, oauth_credentials=oauth_credentials)
                     self.authenticated = True
                     logging.info(f"Authenticated using OAuth with client credentials: {oauth_path}")
                     return
                 else:
                     # Try OAuth without client credentials (legacy)
                     logging.warning("No client credentials found, trying OAuth without them")
-                    self.ytmusic = YTMusic(oauth_path)
+                    self.ytmusic = self._create_ytmusic(oauth_path)
                     self.authenticated = True
                     logging.info(f"Authenticated using OAuth (legacy): {oauth_path}")
                     return
@@ -158,10 +182,10 @@ class YouTubeMusicBridge:
                 logging.error("Please set up OAuth with TV device credentials")
         
         # Fall back to browser headers
-        headers_path = os.path.expanduser("~/.ytmusic/headers_auth.json")
+        headers_path = os.path.join(CONFIG_DIR, "headers_auth.json")
         if os.path.exists(headers_path):
             try:
-                self.ytmusic = YTMusic(headers_path)
+                self.ytmusic = self._create_ytmusic(headers_path)
                 self.authenticated = True
                 logging.info(f"Authenticated using headers: {headers_path}")
             except Exception as e:
@@ -287,6 +311,9 @@ class YouTubeMusicBridge:
             
             logging.info(f"Fetching tracks for playlist: {playlist_id}")
             
+            # A limit of 0 means the whole playlist; ytmusicapi follows continuations for None
+            limit = limit or None
+            
             # Handle special playlists
             if playlist_id == 'LM':  # Liked songs
                 result = self.ytmusic.get_liked_songs(limit=limit)
@@ -336,6 +363,622 @@ class YouTubeMusicBridge:
             logging.error(f"Get liked songs error: {e}")
             raise
     
+    def get_home(self, limit: int = 6) -> List[Dict[str, Any]]:
+        """Get the home feed's shelves, such as Quick Picks and mixes"""
+        try:
+            if not self.ytmusic:
+                raise Exception("YTMusic client not initialized")
+            
+            logging.info("Fetching home feed...")
+            formatted_shelves = []
+            for shelf in self.ytmusic.get_home(limit=limit) or []:
+                formatted_shelf = self._format_shelf(shelf.get('title', ''), shelf.get('contents'))
+                if formatted_shelf:
+                    formatted_shelves.append(formatted_shelf)
+            
+            logging.info(f"Found {len(formatted_shelves)} home shelves")
+            return formatted_shelves
+        except Exception as e:
+            logging.error(f"Get home error: {e}")
+            raise
+    
+    def get_charts(self, country: str = 'ZZ') -> List[Dict[str, Any]]:
+        """Get a country's charts as shelves of songs, videos and artists"""
+        try:
+            if not self.ytmusic:
+                raise Exception("YTMusic client not initialized")
+            
+            logging.info(f"Fetching charts for {country}...")
+            charts = self.ytmusic.get_charts(country=country) or {}
+            
+            # Older ytmusicapi versions wrap each chart in {'items': [...]}, newer ones return the list
+            formatted_shelves = []
+            for key, title in [('songs', 'Top songs'), ('videos', 'Top music videos'),
+                               ('trending', 'Trending'), ('artists', 'Top artists')]:
+                section = charts.get(key)
+                contents = section.get('items') if isinstance(section, dict) else section
+                formatted_shelf = self._format_shelf(title, contents)
+                if formatted_shelf:
+                    formatted_shelves.append(formatted_shelf)
+            
+            logging.info(f"Found {len(formatted_shelves)} chart shelves")
+            return formatted_shelves
+        except Exception as e:
+            logging.error(f"Get charts error: {e}")
+            raise
+    
+    def get_new_releases(self) -> List[Dict[str, Any]]:
+        """Get newly released albums and singles"""
+        try:
+            if not self.ytmusic:
+                raise Exception("YTMusic client not initialized")
+            
+            logging.info("Fetching new releases...")
+            explore = self.ytmusic.get_explore() if hasattr(self.ytmusic, 'get_explore') else {}
+            
+            formatted_albums = []
+            for content in explore.get('new_releases') or []:
+                item = self._format_shelf_item(content)
+                if item and 'album' in item:
+                    formatted_albums.append(item['album'])
+            
+            logging.info(f"Found {len(formatted_albums)} new releases")
+            return formatted_albums
+        except Exception as e:
+            logging.error(f"Get new releases error: {e}")
+            raise
+    
+    def _format_shelf(self, title: str, contents) -> Optional[Dict[str, Any]]:
+        """Format a titled list of mixed entries, dropping it if nothing is usable"""
+        items = []
+        for content in contents or []:
+            item = self._format_shelf_item(content)
+            if item:
+                items.append(item)
+        return {'title': title, 'items': items} if items else None
+    
+    def _format_shelf_item(self, content: Dict) -> Optional[Dict[str, Any]]:
+        """Format a shelf entry as a track, album, playlist or artist"""
+        if not isinstance(content, dict):
+            return None
+        
+        if content.get('videoId') and (content.get('resultType') == 'episode' or isinstance(content.get('podcast'), dict)):
+            episode = self._format_episode(content)
+            return {'episode': episode} if episode else None
+        if content.get('videoId'):
+            track = self._format_track(content)
+            return {'track': track} if track else None
+        
+        browse_id = content.get('browseId') or ''
+        if browse_id.startswith('MPRE'):
+            artists = content.get('artists') or []
+            return {'album': {
+                'id': browse_id,
+                'title': content.get('title', 'Unknown Album'),
+                'artist': ', '.join(a.get('name', '') for a in artists if isinstance(a, dict)),
+                'year': str(content.get('year') or '')
+            }}
+        if browse_id.startswith('MPSPP'):
+            podcast = self._format_podcast(content)
+            return {'podcast': podcast} if podcast else None
+        if browse_id.startswith('UC'):
+            return {'artist': {
+                'id': browse_id,
+                'name': content.get('title', 'Unknown Artist'),
+                'subtitle': str(content.get('subscribers') or '')
+            }}
+        if content.get('playlistId'):
+            return {'playlist': {
+                'id': content['playlistId'],
+                'title': content.get('title', 'Unknown Playlist'),
+                'description': content.get('description') or '',
+                'track_count': 0,
+                'author': ''
+            }}
+        return None
+    
+    def get_history(self) -> List[Dict[str, Any]]:
+        """Get the account's recently played tracks"""
+        if not self.ytmusic or not self.authenticated:
+            raise Exception("Not authenticated - cannot fetch history")
+        
+        logging.info("Fetching history...")
+        formatted_tracks = []
+        for track in self.ytmusic.get_history():
+            formatted_track = self._format_track(track)
+            if formatted_track:
+                formatted_tracks.append(formatted_track)
+        
+        logging.info(f"Found {len(formatted_tracks)} history tracks")
+        return formatted_tracks
+    
+    def add_history_item(self, video_id: str):
+        """Report a track as played so it appears in the account's history"""
+        if not self.ytmusic or not self.authenticated:
+            raise Exception("Not authenticated - cannot report playback")
+        
+        logging.info(f"Adding {video_id} to history")
+        song = self.ytmusic.get_song(video_id)
+        response = self.ytmusic.add_history_item(song)
+        if getattr(response, 'status_code', 204) not in (200, 204):
+            raise Exception(f"History request returned status {response.status_code}")
+    
+    def get_library_albums(self, limit: int = 100) -> List[Dict[str, Any]]:
+        """Get albums saved to the user's library"""
+        try:
+            if not self.ytmusic:
+                raise Exception("YTMusic client not initialized")
+            
+            if not self.authenticated:
+                logging.warning("Not authenticated - cannot fetch library albums")
+                return []
+            
+            logging.info("Fetching library albums...")
+            albums = self.ytmusic.get_library_albums(limit=limit) or []
+            
+            formatted_albums = [self._format_library_album(album) for album in albums]
+            formatted_albums = [album for album in formatted_albums if album]
+            
+            logging.info(f"Found {len(formatted_albums)} library albums")
+            return formatted_albums
+        except Exception as e:
+            logging.error(f"Get library albums error: {e}")
+            raise
+    
+    def get_library_artists(self, subscriptions: bool = False, limit: int = 100) -> List[Dict[str, Any]]:
+        """Get the artists in the user's library, or the ones they subscribe to"""
+        try:
+            if not self.ytmusic:
+                raise Exception("YTMusic client not initialized")
+            
+            if not self.authenticated:
+                logging.warning("Not authenticated - cannot fetch library artists")
+                return []
+            
+            if subscriptions:
+                logging.info("Fetching library subscriptions...")
+                artists = self.ytmusic.get_library_subscriptions(limit=limit) or []
+            else:
+                logging.info("Fetching library artists...")
+                artists = self.ytmusic.get_library_artists(limit=limit) or []
+            
+            formatted_artists = [self._format_library_artist(artist) for artist in artists]
+            formatted_artists = [artist for artist in formatted_artists if artist]
+            
+            logging.info(f"Found {len(formatted_artists)} artists")
+            return formatted_artists
+        except Exception as e:
+            logging.error(f"Get library artists error: {e}")
+            raise
+    
+    def get_uploads(self, kind: str, limit: int = 0) -> List[Dict[str, Any]]:
+        """Get the user's uploaded songs, albums or artists"""
+        try:
+            if not self.ytmusic:
+                raise Exception("YTMusic client not initialized")
+            
+            if not self.authenticated:
+                logging.warning("Not authenticated - cannot fetch uploads")
+                return []
+            
+            logging.info(f"Fetching uploaded {kind}...")
+            if kind == 'songs':
+                items = self.ytmusic.get_library_upload_songs(limit=limit or None) or []
+                formatted = [self._format_track(item) for item in items]
+            elif kind == 'albums':
+                items = self.ytmusic.get_library_upload_albums(limit=limit or None) or []
+                formatted = [self._format_library_album(item) for item in items]
+            else:
+                items = self.ytmusic.get_library_upload_artists(limit=limit or None) or []
+                formatted = [self._format_library_artist(item) for item in items]
+            formatted = [item for item in formatted if item]
+            
+            logging.info(f"Found {len(formatted)} uploaded {kind}")
+            return formatted
+        except Exception as e:
+            logging.error(f"Get uploads error: {e}")
+            raise
+    
+    def _format_library_album(self, album: Dict) -> Optional[Dict[str, Any]]:
+        """Format an album card from the library or the uploads"""
+        if not isinstance(album, dict) or not album.get('browseId'):
+            return None
+        artists = album.get('artists') or []
+        if isinstance(artists, dict):
+            artists = [artists]
+        return {
+            'id': album['browseId'],
+            'title': album.get('title', 'Unknown Album'),
+            'artist': ', '.join(a.get('name', '') for a in artists if isinstance(a, dict)),
+            'year': str(album.get('year') or '')
+        }
+    
+    def _format_library_artist(self, artist: Dict) -> Optional[Dict[str, Any]]:
+        """Format an artist row from the library, subscriptions or uploads"""
+        if not isinstance(artist, dict) or not artist.get('browseId'):
+            return None
+        subtitle = ''
+        if artist.get('subscribers'):
+            subtitle = f"{artist['subscribers']} subscribers"
+        elif artist.get('songs'):
+            subtitle = str(artist['songs'])
+        return {
+            'id': artist['browseId'],
+            'name': artist.get('artist', 'Unknown Artist'),
+            'subtitle': subtitle
+        }
+    
+    def get_album(self, browse_id: str) -> Dict[str, Any]:
+        """Get an album's details and track list"""
+        try:
+            if not self.ytmusic:
+                raise Exception("YTMusic client not initialized")
+            
+            logging.info(f"Fetching album: {browse_id}")
+            if browse_id.startswith('FEmusic_library_privately_owned'):
+                album = self.ytmusic.get_library_upload_album(browse_id)
+            else:
+                album = self.ytmusic.get_album(browse_id)
+            
+            artists = album.get('artists') or []
+            artist_str = ', '.join(a.get('name', '') for a in artists if isinstance(a, dict))
+            
+            formatted_tracks = []
+            for track in album.get('tracks', []):
+                formatted_track = self._format_track(track)
+                if formatted_track:
+                    if formatted_track['artist'] == 'Unknown Artist' and artist_str:
+                        formatted_track['artist'] = artist_str
+                    formatted_track['album_id'] = browse_id
+                    formatted_track['album'] = album.get('title', '')
+                    formatted_track['year'] = str(album.get('year') or '')
+                    formatted_tracks.append(formatted_track)
+            
+            logging.info(f"Found {len(formatted_tracks)} album tracks")
+            return {
+                'id': browse_id,
+                'title': album.get('title', 'Unknown Album'),
+                'artist': artist_str,
+                'year': str(album.get('year') or ''),
+                'tracks': formatted_tracks
+            }
+        except Exception as e:
+            logging.error(f"Get album error: {e}")
+            raise
+    
+    def get_artist(self, channel_id: str) -> Dict[str, Any]:
+        """Get an artist's top songs, albums, and singles"""
+        try:
+            if not self.ytmusic:
+                raise Exception("YTMusic client not initialized")
+            
+            logging.info(f"Fetching artist: {channel_id}")
+            if channel_id.startswith('FEmusic_library_privately_owned'):
+                return self._get_upload_artist(channel_id)
+            artist = self.ytmusic.get_artist(channel_id)
+            name = artist.get('name', 'Unknown Artist')
+            
+            def format_albums(section):
+                formatted = []
+                for album in (artist.get(section) or {}).get('results', []):
+                    if not isinstance(album, dict) or not album.get('browseId'):
+                        continue
+                    formatted.append({
+                        'id': album['browseId'],
+                        'title': album.get('title', 'Unknown Album'),
+                        'artist': name,
+                        'year': str(album.get('year') or '')
+                    })
+                return formatted
+            
+            top_songs = []
+            for track in (artist.get('songs') or {}).get('results', []):
+                formatted_track = self._format_track(track)
+                if formatted_track:
+                    top_songs.append(formatted_track)
+            
+            subtitle = ''
+            if artist.get('subscribers'):
+                subtitle = f"{artist['subscribers']} subscribers"
+            
+            return {
+                'id': channel_id,
+                'name': name,
+                'subtitle': subtitle,
+                'radio_id': artist.get('radioId') or '',
+                'top_songs': top_songs,
+                'albums': format_albums('albums'),
+                'singles': format_albums('singles')
+            }
+        except Exception as e:
+            logging.error(f"Get artist error: {e}")
+            raise
+    
+    def _get_upload_artist(self, browse_id: str) -> Dict[str, Any]:
+        """Get an uploaded artist, whose page is just a list of their uploaded songs"""
+        songs = []
+        for track in self.ytmusic.get_library_upload_artist(browse_id, limit=None) or []:
+            formatted_track = self._format_track(track)
+            if formatted_track:
+                songs.append(formatted_track)
+        
+        return {
+            'id': browse_id,
+            'name': songs[0]['artist'] if songs else 'Unknown Artist',
+            'subtitle': f"{len(songs)} uploaded songs",
+            'radio_id': '',
+            'top_songs': songs,
+            'albums': [],
+            'singles': []
+        }
+    
+    def search_podcasts(self, query: str, limit: int = 20) -> List[Dict[str, Any]]:
+        """Search for podcasts and episodes, returning a shelf of each"""
+        try:
+            if not self.ytmusic:
+                raise Exception("YTMusic client not initialized")
+            
+            logging.info(f"Searching podcasts for: {query}")
+            shelves = []
+            for search_filter, title in [('podcasts', 'Podcasts'), ('episodes', 'Episodes')]:
+                items = []
+                for result in self.ytmusic.search(query, filter=search_filter, limit=limit) or []:
+                    if search_filter == 'podcasts':
+                        podcast = self._format_podcast(result)
+                        if podcast:
+                            items.append({'podcast': podcast})
+                    else:
+                        episode = self._format_episode(result)
+                        if episode:
+                            items.append({'episode': episode})
+                if items:
+                    shelves.append({'title': title, 'items': items})
+            
+            logging.info(f"Found {len(shelves)} podcast shelves")
+            return shelves
+        except Exception as e:
+            logging.error(f"Podcast search error: {e}")
+            raise
+    
+    def get_podcast(self, browse_id: str, limit: int = 0) -> Dict[str, Any]:
+        """Get a podcast's details and episodes"""
+        try:
+            if not self.ytmusic:
+                raise Exception("YTMusic client not initialized")
+            
+            logging.info(f"Fetching podcast: {browse_id}")
+            podcast = self.ytmusic.get_podcast(browse_id, limit=limit or None)
+            
+            formatted_podcast = {
+                'id': browse_id,
+                'title': podcast.get('title', 'Unknown Podcast'),
+                'author': self._podcast_author(podcast),
+                'description': podcast.get('description') or '',
+                'episodes': []
+            }
+            for episode in podcast.get('episodes') or []:
+                formatted_episode = self._format_episode(episode, formatted_podcast)
+                if formatted_episode:
+                    formatted_podcast['episodes'].append(formatted_episode)
+            
+            logging.info(f"Found {len(formatted_podcast['episodes'])} episodes")
+            return formatted_podcast
+        except Exception as e:
+            logging.error(f"Get podcast error: {e}")
+            raise
+    
+    def get_library_podcasts(self, limit: int = 100) -> List[Dict[str, Any]]:
+        """Get the podcasts saved to the user's library"""
+        try:
+            if not self.ytmusic:
+                raise Exception("YTMusic client not initialized")
+            
+            if not self.authenticated:
+                logging.warning("Not authenticated - cannot fetch library podcasts")
+                return []
+            
+            logging.info("Fetching library podcasts...")
+            formatted_podcasts = []
+            for podcast in self.ytmusic.get_library_podcasts(limit=limit) or []:
+                formatted_podcast = self._format_podcast(podcast)
+                if formatted_podcast:
+                    formatted_podcasts.append(formatted_podcast)
+            
+            logging.info(f"Found {len(formatted_podcasts)} library podcasts")
+            return formatted_podcasts
+        except Exception as e:
+            logging.error(f"Get library podcasts error: {e}")
+            raise
+    
+    def _format_podcast(self, podcast: Dict) -> Optional[Dict[str, Any]]:
+        """Format a podcast show, skipping entries without a browse ID"""
+        if not isinstance(podcast, dict):
+            return None
+        
+        browse_id = podcast.get('browseId') or podcast.get('podcastId') or ''
+        if not browse_id:
+            return None
+        return {
+            'id': browse_id,
+            'title': podcast.get('title', 'Unknown Podcast'),
+            'author': self._podcast_author(podcast)
+        }
+    
+    def _format_episode(self, episode: Dict, podcast: Dict = None) -> Optional[Dict[str, Any]]:
+        """Format a podcast episode, taking the show from the episode or the page it's on"""
+        if not isinstance(episode, dict) or not episode.get('videoId'):
+            return None
+        
+        show = episode.get('podcast') if isinstance(episode.get('podcast'), dict) else {}
+        if podcast:
+            show = {'name': podcast['title'], 'id': podcast['id']}
+        
+        duration = episode.get('duration')
+        if isinstance(duration, dict):
+            duration = duration.get('text')
+        
+        return {
+            'id': episode['videoId'],
+            'title': episode.get('title', 'Unknown Episode'),
+            'podcast': show.get('name') or '',
+            'podcast_id': show.get('id') or '',
+            'date': str(episode.get('date') or ''),
+            'duration': self._parse_episode_duration(duration)
+        }
+    
+    def _podcast_author(self, podcast: Dict) -> str:
+        """Get a podcast's author, which ytmusicapi returns in several shapes"""
+        author = podcast.get('author') or podcast.get('channel')
+        if isinstance(author, list):
+            return ', '.join(a.get('name', '') for a in author if isinstance(a, dict))
+        if isinstance(author, dict):
+            return author.get('name') or ''
+        return str(author or '')
+    
+    def _parse_episode_duration(self, duration_str) -> int:
+        """Parse episode lengths like '1 hr 5 min', '38 min' or '1:05:00' into seconds"""
+        if not duration_str or not isinstance(duration_str, str):
+            return 0
+        if ':' in duration_str:
+            return self._parse_duration_string(duration_str)
+        
+        seconds = 0
+        words = duration_str.lower().replace(',', ' ').split()
+        for value, unit in zip(words, words[1:]):
+            if not value.isdigit():
+                continue
+            if unit.startswith('h'):
+                seconds += int(value) * 3600
+            elif unit.startswith('m'):
+                seconds += int(value) * 60
+            elif unit.startswith('s'):
+                seconds += int(value)
+        return seconds
+    
+    def get_watch_playlist(self, video_id: str = None, playlist_id: str = None, limit: int = 25) -> List[Dict[str, Any]]:
+        """Get the up-next list for a track or a radio playlist"""
+        try:
+            if not self.ytmusic:
+                raise Exception("YTMusic client not initialized")
+            
+            logging.info(f"Fetching watch playlist: video={video_id} playlist={playlist_id}")
+            # A video on its own gets its radio rather than a one-track list
+            radio = bool(video_id) and not playlist_id
+            result = self.ytmusic.get_watch_playlist(videoId=video_id, playlistId=playlist_id, limit=limit, radio=radio)
+            
+            formatted_tracks = []
+            for track in result.get('tracks', []):
+                # Watch playlist tracks use 'length' for the duration text
+                if 'length' in track and 'duration' not in track:
+                    track['duration'] = track['length']
+                formatted_track = self._format_track(track)
+                if formatted_track:
+                    formatted_tracks.append(formatted_track)
+            
+            logging.info(f"Found {len(formatted_tracks)} watch playlist tracks")
+            return formatted_tracks
+        except Exception as e:
+            logging.error(f"Get watch playlist error: {e}")
+            raise
+    
+    def get_lyrics(self, video_id: str) -> Dict[str, Any]:
+        """Get a track's lyrics, with line timings when available"""
+        try:
+            if not self.ytmusic:
+                raise Exception("YTMusic client not initialized")
+            
+            logging.info(f"Fetching lyrics for: {video_id}")
+            watch = self.ytmusic.get_watch_playlist(videoId=video_id, limit=1)
+            browse_id = watch.get('lyrics')
+            if not browse_id:
+                raise Exception("no lyrics available for this track")
+            
+            # Timed lyrics need a newer ytmusicapi
+            try:
+                result = self.ytmusic.get_lyrics(browse_id, timestamps=True)
+            except TypeError:
+                result = self.ytmusic.get_lyrics(browse_id)
+            if not result or not result.get('lyrics'):
+                raise Exception("no lyrics available for this track")
+            
+            lyrics = result['lyrics']
+            if isinstance(lyrics, str):
+                return {'text': lyrics, 'source': result.get('source') or ''}
+            
+            lines = []
+            for line in lyrics:
+                lines.append({
+                    'text': getattr(line, 'text', ''),
+                    'start': getattr(line, 'start_time', 0),
+                    'end': getattr(line, 'end_time', 0)
+                })
+            return {
+                'text': '\n'.join(line['text'] for line in lines),
+                'source': result.get('source') or '',
+                'lines': lines
+            }
+        except Exception as e:
+            logging.error(f"Get lyrics error: {e}")
+            raise
+    
+    def create_playlist(self, title: str, description: str = '') -> str:
+        """Create a private playlist and return its ID"""
+        if not self.ytmusic or not self.authenticated:
+            raise Exception("Not authenticated - cannot create playlists")
+        
+        logging.info(f"Creating playlist: {title}")
+        result = self.ytmusic.create_playlist(title, description or '', privacy_status='PRIVATE')
+        if not isinstance(result, str):
+            raise Exception(f"Playlist was not created: {result}")
+        return result
+    
+    def edit_playlist(self, playlist_id: str, title: str):
+        """Rename a playlist"""
+        if not self.ytmusic or not self.authenticated:
+            raise Exception("Not authenticated - cannot edit playlists")
+        
+        logging.info(f"Renaming playlist {playlist_id} to: {title}")
+        result = self.ytmusic.edit_playlist(playlist_id, title=title)
+        if result != 'STATUS_SUCCEEDED':
+            raise Exception(f"Playlist edit failed: {result}")
+    
+    def delete_playlist(self, playlist_id: str):
+        """Delete a playlist"""
+        if not self.ytmusic or not self.authenticated:
+            raise Exception("Not authenticated - cannot delete playlists")
+        
+        logging.info(f"Deleting playlist: {playlist_id}")
+        self.ytmusic.delete_playlist(playlist_id)
+    
+    def add_playlist_items(self, playlist_id: str, video_ids: List[str]):
+        """Add videos to a playlist, skipping ones already in it"""
+        if not self.ytmusic or not self.authenticated:
+            raise Exception("Not authenticated - cannot edit playlists")
+        
+        logging.info(f"Adding {len(video_ids)} videos to playlist {playlist_id}")
+        result = self.ytmusic.add_playlist_items(playlist_id, video_ids, duplicates=False)
+        status = result.get('status') if isinstance(result, dict) else result
+        if status != 'STATUS_SUCCEEDED':
+            raise Exception(f"Adding to playlist failed: {result}")
+    
+    def remove_playlist_items(self, playlist_id: str, videos: List[Dict[str, str]]):
+        """Remove entries (videoId and setVideoId pairs) from a playlist"""
+        if not self.ytmusic or not self.authenticated:
+            raise Exception("Not authenticated - cannot edit playlists")
+        
+        logging.info(f"Removing {len(videos)} videos from playlist {playlist_id}")
+        result = self.ytmusic.remove_playlist_items(playlist_id, videos)
+        if result != 'STATUS_SUCCEEDED':
+            raise Exception(f"Removing from playlist failed: {result}")
+    
+    def rate_song(self, video_id: str, rating: str):
+        """Like, dislike, or clear the rating of a song"""
+        if not self.ytmusic or not self.authenticated:
+            raise Exception("Not authenticated - cannot rate songs")
+        
+        logging.info(f"Rating {video_id}: {rating}")
+        self.ytmusic.rate_song(video_id, rating)
+    
     def _format_track(self, track: Dict) -> Optional[Dict[str, Any]]:
         """Format a track with proper duration parsing"""
         try:
@@ -389,19 +1032,40 @@ class YouTubeMusicBridge:
             # Parse duration
             duration_seconds = self._parse_duration(track)
             
-            # Get thumbnail
+            # Get the largest thumbnail, which ytmusicapi lists last
             thumbnail = ""
             if 'thumbnails' in track and track['thumbnails']:
                 thumbnails = track['thumbnails']
                 if isinstance(thumbnails, list) and len(thumbnails) > 0:
-                    thumbnail = thumbnails[0].get('url', '') if isinstance(thumbnails[0], dict) else ''
+                    thumbnail = thumbnails[-1].get('url', '') if isinstance(thumbnails[-1], dict) else ''
+            
+            # Get album and artist browse IDs for navigation
+            album_id = ""
+            album_name = ""
+            if isinstance(track.get('album'), dict):
+                album_id = track['album'].get('id') or ''
+                album_name = track['album'].get('name') or ''
+            elif isinstance(track.get('album'), str):
+                album_name = track['album']
+            
+            artist_id = ""
+            for artist in track.get('artists') or []:
+                if isinstance(artist, dict) and artist.get('id'):
+                    artist_id = artist['id']
+                    break
             
             formatted_track = {
                 'id': video_id,
                 'title': title,
                 'artist': artist_str,
                 'duration': duration_seconds,
-                'thumbnail': thumbnail
+                'thumbnail': thumbnail,
+                'album': album_name,
+                'album_id': album_id,
+                'year': str(track.get('year') or ''),
+                'artist_id': artist_id,
+                'explicit': bool(track.get('isExplicit')),
+                'set_video_id': track.get('setVideoId') or ''
             }
             
             logging.debug(f"Successfully formatted track: {title} - {artist_str}")
@@ -452,27 +1116,40 @@ class YouTubeMusicBridge:
         return 180
 
 
-def main():
-    """Main command-line interface"""
+def build_parser():
+    """Build the command-line parser, also used for requests in serve mode"""
     parser = argparse.ArgumentParser(description='YouTube Music API Bridge')
-    parser.add_argument('command', choices=['search', 'playlists', 'playlist_tracks', 'liked_songs'],
+    parser.add_argument('command', choices=['serve', 'ping', 'search', 'playlists', 'playlist_tracks', 'liked_songs',
+                                            'home', 'charts', 'new_releases', 'history', 'add_history_item',
+                                            'library_albums', 'library_artists', 'library_subscriptions',
+                                            'album', 'artist', 'watch_playlist', 'lyrics',
+                                            'search_podcasts', 'podcast', 'library_podcasts',
+                                            'upload_songs', 'upload_albums', 'upload_artists',
+                                            'create_playlist', 'rename_playlist', 'delete_playlist',
+                                            'add_playlist_items', 'remove_playlist_items', 'rate_song'],
                        help='Command to execute')
-    parser.add_argument('--query', help='Search query (for search command)')
+    parser.add_argument('--query', help='Search query (for search and search_podcasts commands)')
     parser.add_argument('--playlist-id', help='Playlist ID (for playlist_tracks command)')
+    parser.add_argument('--browse-id', help='Browse ID (for album, artist and podcast commands)')
+    parser.add_argument('--video-id', help='Video ID (for watch_playlist, lyrics, add_history_item and rate_song commands)')
+    parser.add_argument('--video-ids', help='Comma-separated video IDs (for add/remove_playlist_items)')
+    parser.add_argument('--set-video-ids', help='Comma-separated playlist entry IDs matching --video-ids (for remove_playlist_items)')
+    parser.add_argument('--rating', choices=['LIKE', 'DISLIKE', 'INDIFFERENT'], help='Rating (for rate_song)')
+    parser.add_argument('--title', help='Playlist title (for create_playlist and rename_playlist)')
+    parser.add_argument('--description', default='', help='Playlist description (for create_playlist)')
+    parser.add_argument('--country', default='ZZ', help='Country code (for charts, default: ZZ for global)')
     parser.add_argument('--filter', default='songs', help='Search filter (default: songs)')
-    parser.add_argument('--limit', type=int, default=20, help='Result limit (default: 20)')
+    parser.add_argument('--limit', type=int, default=20, help='Result limit (default: 20, 0 for no limit where supported)')
     parser.add_argument('--cookie', help='Authentication cookie')
+    parser.add_argument('--user', help='Brand account ID to act as')
+    parser.add_argument('--language', help='Language of titles and metadata, such as en or de')
+    parser.add_argument('--location', help='Country results are tailored to, such as US')
     parser.add_argument('--debug', action='store_true', help='Enable debug logging')
-    
-    args = parser.parse_args()
-    
-    # Set up logging
-    log_level = logging.DEBUG if args.debug else logging.WARNING
-    logging.basicConfig(
-        level=log_level,
-        format='%(asctime)s - %(levelname)s - %(message)s'
-    )
-    
+    return parser
+
+
+def run_command(args, bridges: Dict[tuple, 'YouTubeMusicBridge']) -> Dict[str, Any]:
+    """Run one command, reusing the bridge already authenticated with the same cookie, account and locale"""
     # Create response structure
     response = {
         "success": False,
@@ -481,8 +1158,17 @@ def main():
     }
     
     try:
-        # Initialize the bridge
-        bridge = YouTubeMusicBridge(cookie=args.cookie)
+        if args.command == 'ping':
+            response["success"] = True
+            return response
+        
+        # Initialize the bridge once per cookie and account; authenticating is the slow part
+        key = (args.cookie, args.user, args.language, args.location)
+        bridge = bridges.get(key)
+        if bridge is None:
+            bridge = YouTubeMusicBridge(cookie=args.cookie, user=args.user,
+                                        language=args.language, location=args.location)
+            bridges[key] = bridge
         
         # Execute the command
         if args.command == 'search':
@@ -510,6 +1196,151 @@ def main():
             tracks = bridge.get_liked_songs(args.limit)
             response["success"] = True
             response["tracks"] = tracks
+            
+        elif args.command == 'home':
+            response["shelves"] = bridge.get_home(args.limit)
+            response["success"] = True
+            
+        elif args.command == 'charts':
+            response["shelves"] = bridge.get_charts(args.country)
+            response["success"] = True
+            
+        elif args.command == 'new_releases':
+            response["albums"] = bridge.get_new_releases()
+            response["success"] = True
+            
+        elif args.command == 'history':
+            tracks = bridge.get_history()
+            response["success"] = True
+            response["tracks"] = tracks
+            
+        elif args.command == 'add_history_item':
+            if not args.video_id:
+                raise ValueError("Video ID is required")
+            
+            bridge.add_history_item(args.video_id)
+            response["success"] = True
+            
+        elif args.command == 'library_albums':
+            albums = bridge.get_library_albums(args.limit)
+            response["success"] = True
+            response["albums"] = albums
+            
+        elif args.command in ('library_artists', 'library_subscriptions'):
+            artists = bridge.get_library_artists(args.command == 'library_subscriptions', args.limit)
+            response["success"] = True
+            response["artists"] = artists
+            
+        elif args.command == 'upload_songs':
+            tracks = bridge.get_uploads('songs', args.limit)
+            response["success"] = True
+            response["tracks"] = tracks
+            
+        elif args.command == 'upload_albums':
+            albums = bridge.get_uploads('albums', args.limit)
+            response["success"] = True
+            response["albums"] = albums
+            
+        elif args.command == 'upload_artists':
+            artists = bridge.get_uploads('artists', args.limit)
+            response["success"] = True
+            response["artists"] = artists
+            
+        elif args.command == 'album':
+            if not args.browse_id:
+                raise ValueError("Browse ID is required")
+            
+            album = bridge.get_album(args.browse_id)
+            response["success"] = True
+            response["album"] = album
+            
+        elif args.command == 'artist':
+            if not args.browse_id:
+                raise ValueError("Browse ID is required")
+            
+            artist = bridge.get_artist(args.browse_id)
+            response["success"] = True
+            response["artist"] = artist
+            
+        elif args.command == 'watch_playlist':
+            if not args.video_id and not args.playlist_id:
+                raise ValueError("Video ID or playlist ID is required")
+            
+            tracks = bridge.get_watch_playlist(args.video_id, args.playlist_id, args.limit)
+            response["success"] = True
+            response["tracks"] = tracks
+            
+        elif args.command == 'lyrics':
+            if not args.video_id:
+                raise ValueError("Video ID is required")
+            
+            lyrics = bridge.get_lyrics(args.video_id)
+            response["success"] = True
+            response["lyrics"] = lyrics
+            
+        elif args.command == 'search_podcasts':
+            if not args.query:
+                raise ValueError("Search query is required")
+            
+            response["shelves"] = bridge.search_podcasts(args.query, args.limit)
+            response["success"] = True
+            
+        elif args.command == 'podcast':
+            if not args.browse_id:
+                raise ValueError("Browse ID is required")
+            
+            podcast = bridge.get_podcast(args.browse_id, args.limit)
+            response["success"] = True
+            response["podcast"] = podcast
+            
+        elif args.command == 'library_podcasts':
+            podcasts = bridge.get_library_podcasts(args.limit)
+            response["success"] = True
+            response["podcasts"] = podcasts
+            
+        elif args.command == 'create_playlist':
+            if not args.title:
+                raise ValueError("Title is required")
+            
+            response["playlist_id"] = bridge.create_playlist(args.title, args.description)
+            response["success"] = True
+            
+        elif args.command == 'rename_playlist':
+            if not args.playlist_id or not args.title:
+                raise ValueError("Playlist ID and title are required")
+            
+            bridge.edit_playlist(args.playlist_id, args.title)
+            response["success"] = True
+            
+        elif args.command == 'delete_playlist':
+            if not args.playlist_id:
+                raise ValueError("Playlist ID is required")
+            
+            bridge.delete_playlist(args.playlist_id)
+            response["success"] = True
+            
+        elif args.command == 'add_playlist_items':
+            if not args.playlist_id or not args.video_ids:
+                raise ValueError("Playlist ID and video IDs are required")
+            
+            bridge.add_playlist_items(args.playlist_id, args.video_ids.split(','))
+            response["success"] = True
+            
+        elif args.command == 'remove_playlist_items':
+            if not args.playlist_id or not args.video_ids or not args.set_video_ids:
+                raise ValueError("Playlist ID, video IDs and set video IDs are required")
+            
+            videos = [{'videoId': video_id, 'setVideoId': set_video_id}
+                      for video_id, set_video_id in zip(args.video_ids.split(','), args.set_video_ids.split(','))]
+            bridge.remove_playlist_items(args.playlist_id, videos)
+            response["success"] = True
+            
+        elif args.command == 'rate_song':
+            if not args.video_id or not args.rating:
+                raise ValueError("Video ID and rating are required")
+            
+            bridge.rate_song(args.video_id, args.rating)
+            response["success"] = True
     
     except Exception as e:
         response["success"] = False
@@ -517,7 +1348,58 @@ def main():
         response["traceback"] = str(e)
         logging.error(f"Command failed: {e}")
     
+    return response
+
+
+def serve(parser):
+    """Answer requests read from stdin until it closes.
+    
+    Each request is a line like {"id": 1, "args": ["search", "--query", "..."]},
+    answered with a line like {"id": 1, "response": {...}}.
+    """
+    out = sys.stdout
+    # Stray prints from ytmusicapi would corrupt the responses
+    sys.stdout = sys.stderr
+    bridges = {}
+    
+    for line in sys.stdin:
+        line = line.strip()
+        if not line:
+            continue
+        
+        request_id = None
+        try:
+            request = json.loads(line)
+            request_id = request.get("id")
+            response = run_command(parser.parse_args(request.get("args", [])), bridges)
+        except SystemExit:
+            # argparse exits on invalid arguments after printing them to stderr
+            response = {"success": False, "error": "invalid arguments", "traceback": None}
+        except Exception as e:
+            response = {"success": False, "error": str(e), "traceback": str(e)}
+        
+        out.write(json.dumps({"id": request_id, "response": response}) + "\n")
+        out.flush()
+
+
+def main():
+    """Main command-line interface"""
+    parser = build_parser()
+    args = parser.parse_args()
+    
+    # Set up logging
+    log_level = logging.DEBUG if args.debug else logging.WARNING
+    logging.basicConfig(
+        level=log_level,
+        format='%(asctime)s - %(levelname)s - %(message)s'
+    )
+    
+    if args.command == 'serve':
+        serve(parser)
+        return
+    
     # Output JSON response
+    response = run_command(args, {})
     print(json.dumps(response, indent=2 if args.debug else None))
 
 